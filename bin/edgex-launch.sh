@@ -71,6 +71,14 @@ cd $CMD/export-distro
 exec -a edgex-export-distro ./export-distro &
 cd $DIR
 
+###
+# Support Rules Engine
+###
+printf "\n### Starting edgex-support-rulesengine\n"
+cd $CMD/support-rulesengine
+exec -a edgex-support-rulesengine ./support-rulesengine &
+cd $DIR
+
 
 trap cleanup EXIT
 
@@ -0,0 +1,119 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: config-seed
+ * @version: 0.5.0
+ *******************************************************************************/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
+)
+
+const usage = `Usage: config-seed [options] export|diff
+
+  export  store the configuration file in the registry, keeping the values already
+          there unless -overwrite is set
+  diff    show the values of the configuration file that differ in the registry
+
+Options:
+`
+
+func main() {
+	var (
+		registryType = flag.String("registry", registry.CONSUL, "Type of the registry, consul or file")
+		directory    = flag.String("dir", "/tmp/edgex/registry", "Shared directory of the file registry")
+		consulHost   = flag.String("consul", "localhost", "Host of the consul agent")
+		consulPort   = flag.Int("port", 8500, "Port of the consul agent")
+		app          = flag.String("app", "", "Name of the application the configuration is for, such as core-data")
+		profiles     = flag.String("profiles", "go", "Profiles of the configuration in the registry, separated by ;")
+		file         = flag.String("file", "res/configuration.toml", "TOML configuration file")
+		overwrite    = flag.Bool("overwrite", false, "Replace the values already in the registry on export")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *app == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var document map[string]interface{}
+	if _, err := toml.DecodeFile(*file, &document); err != nil {
+		fail(err)
+	}
+	local, err := consulclient.EncodeKeyValuePairs(document, *app, strings.Split(*profiles, ";"))
+	if err != nil {
+		fail(err)
+	}
+
+	r, err := registry.New(registry.Config{
+		Type:       *registryType,
+		ConsulHost: *consulHost,
+		ConsulPort: *consulPort,
+		Directory:  *directory,
+	})
+	if err != nil {
+		fail(err)
+	}
+	remote, err := r.KeyValuePairs(*app, strings.Split(*profiles, ";"))
+	if err != nil {
+		fail(err)
+	}
+
+	switch flag.Arg(0) {
+	case "export":
+		pairs := make(map[string]string)
+		for key, value := range local {
+			if _, ok := remote[key]; !ok || *overwrite {
+				pairs[key] = value
+			}
+		}
+		if err := r.PutKeyValuePairs(pairs); err != nil {
+			fail(err)
+		}
+		fmt.Printf("%d of %d pairs stored in the %s registry\n", len(pairs), len(local), *registryType)
+	case "diff":
+		diffs := consulclient.DiffKeyValuePairs(local, remote)
+		for _, d := range diffs {
+			switch {
+			case !d.InRemote:
+				fmt.Printf("%s: %q only in %s\n", d.Key, d.Local, *file)
+			case !d.InLocal:
+				fmt.Printf("%s: %q only in the registry\n", d.Key, d.Remote)
+			default:
+				fmt.Printf("%s: %q in %s, %q in the registry\n", d.Key, d.Local, *file, d.Remote)
+			}
+		}
+		if len(diffs) > 0 {
+			os.Exit(1)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "config-seed:", err)
+	os.Exit(1)
+}
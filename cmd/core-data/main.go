@@ -27,10 +27,15 @@ import (
 	"github.com/edgexfoundry/edgex-go"
 	"github.com/edgexfoundry/edgex-go/core/data"
 	localcfg "github.com/edgexfoundry/edgex-go/core/data/config"
+	"github.com/edgexfoundry/edgex-go/core/data/messaging"
 	"github.com/edgexfoundry/edgex-go/core/data/routers"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 	"github.com/edgexfoundry/edgex-go/pkg/config"
 	"github.com/edgexfoundry/edgex-go/pkg/heartbeat"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
 	"github.com/edgexfoundry/edgex-go/support/logging-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 //var loggingClient logger.LoggingClient
@@ -38,37 +43,41 @@ import (
 func main() {
 	start := time.Now()
 	var (
-		useConsul = flag.String("consul", "", "Should the service use consul?")
+		useRegistry = flag.String("consul", "", "Set to y to use the registry, consul or file depending on RegistryType")
 		useProfile = flag.String("profile", "default", "Specify a profile other than default.")
 	)
 	flag.Parse()
 
 	//Read Configuration
 	configuration := &localcfg.ConfigurationStruct{}
-	err := config.LoadFromFile(*useProfile, configuration)
+	report, err := config.Load(*useProfile, configuration)
 	if err != nil {
 		logBeforeTermination(err)
 		return
 	}
 
-	//Determine if configuration should be overridden from Consul
-	var consulMsg string
-	if *useConsul == "y" {
-		consulMsg = "Loading configuration from Consul..."
-		err := data.ConnectToConsul(*configuration)
+	//Determine if configuration should be overridden from the registry
+	var registryMsg string
+	if *useRegistry == "y" {
+		registryMsg = "Loading configuration from the registry..."
+		err := data.ConnectToRegistry(configuration)
 		if err != nil {
 			logBeforeTermination(err)
-			return //end program since user explicitly told us to use Consul.
+			return //end program since user explicitly told us to use the registry.
 		}
 	} else {
-		consulMsg = "Bypassing Consul configuration..."
+		registryMsg = "Bypassing the registry configuration..."
 	}
 
-	// Setup Logging
-	logTarget := setLoggingTarget(*configuration)
-	loggingClient := logger.NewClient(configuration.ApplicationName, configuration.EnableRemoteLogging, logTarget)
+	// Credentials of the calls to the other services
+	auth.SetClientCredentials(auth.Credentials{APIKey: configuration.AuthClientAPIKey, Token: configuration.AuthClientToken})
 
-	loggingClient.Info(consulMsg)
+	// Setup Logging, the client follows the reloads of the logging settings
+	logTarget := data.LoggingTarget(configuration)
+	loggingClient := logger.NewReloadableClient(logger.NewClient(configuration.ApplicationName, configuration.EnableRemoteLogging, logTarget))
+
+	loggingClient.Info(registryMsg)
+	loggingClient.Debug("Configuration loaded from the files and environment:\n" + report.String())
 	loggingClient.Info(fmt.Sprintf("Starting %s %s ", data.COREDATASERVICENAME, edgex.Version))
 
 	err = data.Init(configuration, loggingClient)
@@ -77,33 +86,95 @@ func main() {
 		return
 	}
 
-	r, err := routers.NewRouter(routers.Gorilla)
+	tracer, err := tracing.Init(data.COREDATASERVICENAME, tracing.Config{
+		Enabled:     configuration.TracingEnabled,
+		Exporter:    configuration.TracingExporter,
+		Destination: configuration.TracingDestination,
+	})
+	if err != nil {
+		loggingClient.Error(fmt.Sprintf("could not initialize tracing: %v", err.Error()))
+		return
+	}
+
+	routerType := routers.Gorilla
+	if configuration.TracingEnabled {
+		routerType = routers.Opentrace
+	}
+	r, err := routers.NewRouter(routerType)
 	if err != nil {
 		loggingClient.Error(fmt.Sprintf("could not initialize router: %v", err.Error()))
 		return
 	}
 
+	policy := routers.AuthPolicy()
+	authenticator, err := auth.Setup(auth.Config{
+		Enabled:          configuration.AuthEnabled,
+		APIKeys:          configuration.AuthAPIKeys,
+		JWTSecret:        configuration.AuthJWTSecret,
+		JWTPublicKeyFile: configuration.AuthJWTPublicKeyFile,
+		Scopes:           configuration.AuthScopes,
+	}, policy)
+	if err != nil {
+		loggingClient.Error(fmt.Sprintf("could not initialize authentication: %v", err.Error()))
+		return
+	}
+	r.Use(auth.Middleware(authenticator, policy))
+
 	srv := r.LoadRoutes()
 	http.TimeoutHandler(nil, time.Millisecond*time.Duration(configuration.ServiceTimeout), "Request timed out")
 	loggingClient.Info(configuration.AppOpenMsg, "")
 
-	heartbeat.Start(configuration.HeartBeatMsg, configuration.HeartBeatTime, loggingClient)
+	// Drain requests and events, flush and close connections on SIGINT/SIGTERM
+	svc := lifecycle.New(time.Millisecond*time.Duration(configuration.ServiceTimeout), loggingClient)
+	data.RegisterLifecycle(svc)
+	data.WatchConfiguration(svc, *useProfile, *useRegistry == "y", loggingClient)
+	svc.OnShutdown("tracer", tracer.Close)
+	if *useRegistry == "y" {
+		svc.OnShutdown("registry registration", func() error {
+			return registry.Disconnect(configuration.ServiceName)
+		})
+	}
+
+	// The heartbeats stop with the workers, before the shutdown of their message bus
+	sinkConfig := heartbeat.SinkConfig{
+		Type:  configuration.HeartBeatSink,
+		URL:   configuration.HeartBeatURL,
+		Topic: configuration.HeartBeatTopic,
+	}
+	if configuration.HeartBeatSink == heartbeat.SinkBus {
+		publisher, err := messaging.NewTopicPublisher("tcp://*:" + strconv.Itoa(configuration.HeartBeatBusPort))
+		if err != nil {
+			loggingClient.Error(fmt.Sprintf("could not initialize the heartbeat publisher: %v", err.Error()))
+			return
+		}
+		svc.OnShutdown("heartbeat publisher", publisher.Close)
+		sinkConfig.Publisher = publisher
+	}
+	sink, err := heartbeat.NewSink(sinkConfig, loggingClient)
+	if err != nil {
+		loggingClient.Error(fmt.Sprintf("could not initialize the heartbeat: %v", err.Error()))
+		return
+	}
+	svc.Go(func(stop <-chan struct{}) {
+		beat := heartbeat.Start(heartbeat.Config{
+			ServiceName: configuration.ServiceName,
+			Message:     configuration.HeartBeatMsg,
+			Interval:    time.Millisecond * time.Duration(configuration.HeartBeatTime),
+			Sink:        sink,
+		})
+		<-stop
+		beat.Stop()
+	})
 
 	// Time it took to start service
 	loggingClient.Info("Service started in: "+time.Since(start).String(), "")
 	loggingClient.Info("Listening on port: " + strconv.Itoa(configuration.ServicePort))
-	loggingClient.Error(http.ListenAndServe(":"+strconv.Itoa(configuration.ServicePort), srv).Error())
+	if err = svc.ListenAndServe(":"+strconv.Itoa(configuration.ServicePort), srv); err != nil {
+		loggingClient.Error(err.Error())
+	}
 }
 
 func logBeforeTermination(err error) {
 	loggingClient := logger.NewClient(data.COREDATASERVICENAME, false, "")
 	loggingClient.Error(err.Error())
 }
-
-func setLoggingTarget(conf localcfg.ConfigurationStruct) string {
-	logTarget := conf.LoggingRemoteURL
-	if !conf.EnableRemoteLogging {
-		return conf.LoggingFile
-	}
-	return logTarget
-}
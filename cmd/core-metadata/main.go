@@ -25,9 +25,12 @@ import (
 
 	"github.com/edgexfoundry/edgex-go"
 	"github.com/edgexfoundry/edgex-go/core/metadata"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 	"github.com/edgexfoundry/edgex-go/pkg/config"
 	"github.com/edgexfoundry/edgex-go/pkg/heartbeat"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
 	logger "github.com/edgexfoundry/edgex-go/support/logging-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 	"strconv"
 )
 
@@ -36,37 +39,41 @@ var loggingClient logger.LoggingClient
 func main() {
 	start := time.Now()
 	var (
-		useConsul = flag.String("consul", "", "Should the service use consul?")
+		useRegistry = flag.String("consul", "", "Set to y to use the registry, consul or file depending on RegistryType")
 		useProfile = flag.String("profile", "default", "Specify a profile other than default.")
 	)
 	flag.Parse()
 
 	//Read Configuration
 	configuration := &metadata.ConfigurationStruct{}
-	err := config.LoadFromFile(*useProfile, configuration)
+	report, err := config.Load(*useProfile, configuration)
 	if err != nil {
 		logBeforeTermination(err)
 		return
 	}
 
-	//Determine if configuration should be overridden from Consul
-	var consulMsg string
-	if *useConsul == "y" {
-		consulMsg = "Loading configuration from Consul..."
-		err := metadata.ConnectToConsul(*configuration)
+	//Determine if configuration should be overridden from the registry
+	var registryMsg string
+	if *useRegistry == "y" {
+		registryMsg = "Loading configuration from the registry..."
+		err := metadata.ConnectToRegistry(configuration)
 		if err != nil {
 			logBeforeTermination(err)
-			return //end program since user explicitly told us to use Consul.
+			return //end program since user explicitly told us to use the registry.
 		}
 	} else {
-		consulMsg = "Bypassing Consul configuration..."
+		registryMsg = "Bypassing the registry configuration..."
 	}
 
+	// Credentials of the calls to the other services
+	auth.SetClientCredentials(auth.Credentials{APIKey: configuration.AuthClientAPIKey, Token: configuration.AuthClientToken})
+
 	// Setup Logging
 	logTarget := setLoggingTarget(*configuration)
 	loggingClient = logger.NewClient(configuration.ApplicationName, configuration.EnableRemoteLogging, logTarget)
 
-	loggingClient.Info(consulMsg)
+	loggingClient.Info(registryMsg)
+	loggingClient.Debug("Configuration loaded from the files and environment:\n" + report.String())
 	loggingClient.Info(fmt.Sprintf("Starting %s %s ", metadata.METADATASERVICENAME, edgex.Version))
 
 	err = metadata.Init(*configuration, loggingClient)
@@ -79,12 +86,40 @@ func main() {
 	http.TimeoutHandler(nil, time.Millisecond*time.Duration(configuration.ServiceTimeout), "Request timed out")
 	loggingClient.Info(configuration.AppOpenMsg, "")
 
-	heartbeat.Start(configuration.HeartBeatMsg, configuration.HeartBeatTime, loggingClient)
+	// Drain requests and close connections on SIGINT/SIGTERM
+	svc := lifecycle.New(time.Millisecond*time.Duration(configuration.ServiceTimeout), loggingClient)
+	metadata.RegisterLifecycle(svc)
+	if *useRegistry == "y" {
+		svc.OnShutdown("registry registration", func() error {
+			return registry.Disconnect(configuration.ServiceName)
+		})
+	}
+
+	sink, err := heartbeat.NewSink(heartbeat.SinkConfig{
+		Type: configuration.HeartBeatSink,
+		URL:  configuration.HeartBeatURL,
+	}, loggingClient)
+	if err != nil {
+		loggingClient.Error(fmt.Sprintf("could not initialize the heartbeat: %v", err.Error()))
+		return
+	}
+	svc.Go(func(stop <-chan struct{}) {
+		beat := heartbeat.Start(heartbeat.Config{
+			ServiceName: configuration.ServiceName,
+			Message:     configuration.HeartBeatMsg,
+			Interval:    time.Millisecond * time.Duration(configuration.HeartBeatTime),
+			Sink:        sink,
+		})
+		<-stop
+		beat.Stop()
+	})
 
 	// Time it took to start service
 	loggingClient.Info("Service started in: "+time.Since(start).String(), "")
 	fmt.Println("Listening on port: " + strconv.Itoa(configuration.ServicePort))
-	loggingClient.Error(http.ListenAndServe(":"+strconv.Itoa(configuration.ServicePort), r).Error())
+	if err = svc.ListenAndServe(":"+strconv.Itoa(configuration.ServicePort), r); err != nil {
+		loggingClient.Error(err.Error())
+	}
 }
 
 func logBeforeTermination(err error) {
@@ -19,7 +19,10 @@ import (
 	"github.com/edgexfoundry/edgex-go"
 	"github.com/edgexfoundry/edgex-go/export/client"
 	"github.com/edgexfoundry/edgex-go/export/mongo"
-	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	edgexconfig "github.com/edgexfoundry/edgex-go/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 
 	"go.uber.org/zap"
 	"gopkg.in/mgo.v2"
@@ -35,22 +38,18 @@ const (
 	defMongoPort           int    = 27017
 	defMongoConnectTimeout int    = 5000
 	defMongoSocketTimeout  int    = 5000
+	defRegistryType        string = registry.CONSUL
+	defRegistryDirectory   string = "/tmp/edgex/registry"
 	defConsulHost          string = "127.0.0.1"
 	defConsulPort          int    = 8500
 
-	envClientHost string = "EXPORT_CLIENT_HOST"
-	envMongoURL   string = "EXPORT_CLIENT_MONGO_URL"
-	envDistroHost string = "EXPORT_CLIENT_DISTRO_HOST"
-	envConsulHost string = "EXPORT_CLIENT_CONSUL_HOST"
-	envConsulPort string = "EXPORT_CLIENT_CONSUL_PORT"
-
 	applicationName string = "export-client"
 	consulProfile   string = "go"
 )
 
 type config struct {
 	Port                int
-	MongoURL            string
+	MongoURL            string `env:"EXPORT_CLIENT_MONGO_URL"`
 	MongoUser           string
 	MongoPass           string
 	MongoDatabase       string
@@ -58,9 +57,11 @@ type config struct {
 	MongoConnectTimeout int
 	MongoSocketTimeout  int
 
-	ConsulHost string
-	ConsulPort int
-	Hostname   string
+	RegistryType      string `env:"EXPORT_CLIENT_REGISTRY_TYPE"`
+	RegistryDirectory string `env:"EXPORT_CLIENT_REGISTRY_DIRECTORY"`
+	ConsulHost        string `env:"EXPORT_CLIENT_CONSUL_HOST"`
+	ConsulPort        int    `env:"EXPORT_CLIENT_CONSUL_PORT"`
+	Hostname          string `env:"EXPORT_CLIENT_HOST"`
 }
 
 var logger *zap.Logger
@@ -75,36 +76,38 @@ func main() {
 
 	cfg, clientCfg := loadConfig()
 
-	// Initialize service on Consul
-	err := consulclient.ConsulInit(consulclient.ConsulConfig{
-		ServiceName:    applicationName,
-		ServicePort:    cfg.Port,
-		ServiceAddress: cfg.Hostname,
-		CheckAddress:   "http://" + cfg.Hostname + ":" + strconv.Itoa(cfg.Port) + "/api/v1/ping",
-		CheckInterval:  "10s",
-		ConsulAddress:  cfg.ConsulHost,
-		ConsulPort:     cfg.ConsulPort,
+	// Initialize service on the registry
+	r, err := registry.Connect(registry.Config{
+		Type:       cfg.RegistryType,
+		ConsulHost: cfg.ConsulHost,
+		ConsulPort: cfg.ConsulPort,
+		Directory:  cfg.RegistryDirectory,
 	})
+	if err == nil {
+		err = r.Register(registry.Service{
+			Name:          applicationName,
+			Address:       cfg.Hostname,
+			Port:          cfg.Port,
+			CheckAddress:  "http://" + cfg.Hostname + ":" + strconv.Itoa(cfg.Port) + health.ApiReadyRoute,
+			CheckInterval: "10s",
+		})
+	}
 
 	if err == nil {
-		logger.Info("Registered microservice in consul",
-			zap.String("consulHost", cfg.ConsulHost),
-			zap.Int("consulPort", cfg.ConsulPort))
+		logger.Info("Registered microservice in the registry",
+			zap.String("registryType", cfg.RegistryType))
 
 		consulProfiles := []string{consulProfile}
-		if err := consulclient.CheckKeyValuePairs(clientCfg, applicationName, consulProfiles); err != nil {
-			logger.Warn("Error getting key/values from Consul", zap.Error(err),
-				zap.String("consulHost", cfg.ConsulHost),
-				zap.Int("consulPort", cfg.ConsulPort))
+		if err := r.CheckKeyValuePairs(clientCfg, applicationName, consulProfiles); err != nil {
+			logger.Warn("Error getting key/values from the registry", zap.Error(err),
+				zap.String("registryType", cfg.RegistryType))
 		} else {
-			logger.Info("Updated configuration from consul",
-				zap.String("consulHost", cfg.ConsulHost),
-				zap.Int("consulPort", cfg.ConsulPort))
+			logger.Info("Updated configuration from the registry",
+				zap.String("registryType", cfg.RegistryType))
 		}
 	} else {
-		logger.Warn("Error registering to consul", zap.Error(err),
-			zap.String("consulHost", cfg.ConsulHost),
-			zap.Int("consulPort", cfg.ConsulPort))
+		logger.Warn("Error registering to the registry", zap.Error(err),
+			zap.String("registryType", cfg.RegistryType))
 	}
 
 	ms, err := connectToMongo(cfg)
@@ -117,6 +120,9 @@ func main() {
 	repo := mongo.NewRepository(ms)
 	client.InitMongoRepository(repo)
 
+	// Credentials of the calls to the other services
+	auth.SetClientCredentials(auth.Credentials{APIKey: clientCfg.AuthClientAPIKey, Token: clientCfg.AuthClientToken})
+
 	errs := make(chan error, 2)
 
 	client.StartHTTPServer(*clientCfg, errs)
@@ -133,17 +139,19 @@ func main() {
 
 func loadConfig() (*config, *client.Config) {
 	clientCfg := client.GetDefaultConfig()
-	clientCfg.DistroHost = env(envDistroHost, clientCfg.DistroHost)
+	if err := edgexconfig.ApplyEnvironment(&clientCfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
+	}
 
 	cfg := config{
-		MongoURL:            env(envMongoURL, defMongoURL),
+		MongoURL:            defMongoURL,
 		MongoUser:           defMongoUsername,
 		MongoPass:           defMongoPassword,
 		MongoDatabase:       defMongoDatabase,
 		MongoPort:           defMongoPort,
 		MongoConnectTimeout: defMongoConnectTimeout,
 		MongoSocketTimeout:  defMongoSocketTimeout,
-		ConsulHost:          env(envConsulHost, defConsulHost),
+		ConsulHost:          defConsulHost,
 		ConsulPort:          defConsulPort,
 	}
 
@@ -151,28 +159,12 @@ func loadConfig() (*config, *client.Config) {
 	if err == nil {
 		cfg.Hostname = hostname
 	}
-	cfg.Hostname = env(envClientHost, cfg.Hostname)
-
-	portStr := env(envConsulPort, strconv.Itoa(cfg.ConsulPort))
-
-	port, err := strconv.Atoi(portStr)
-	if err == nil {
-		cfg.ConsulPort = port
-	} else {
-		logger.Warn("Could not parse port", zap.String("port", portStr), zap.Error(err))
+	if err := edgexconfig.ApplyEnvironment(&cfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
 	}
 	return &cfg, &clientCfg
 }
 
-func env(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
-	}
-
-	return value
-}
-
 func connectToMongo(cfg *config) (*mgo.Session, error) {
 	mongoDBDialInfo := &mgo.DialInfo{
 		Addrs:    []string{cfg.MongoURL + ":" + strconv.Itoa(cfg.MongoPort)},
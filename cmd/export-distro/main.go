@@ -16,34 +16,33 @@ import (
 	"syscall"
 
 	"github.com/edgexfoundry/edgex-go"
-	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/edgexfoundry/edgex-go/export/distro"
-	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	edgexconfig "github.com/edgexfoundry/edgex-go/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 
 	"go.uber.org/zap"
 )
 
 const (
-	envDistroHost string = "EXPORT_DISTRO_HOST"
-	envClientHost string = "EXPORT_DISTRO_CLIENT_HOST"
-	envDataHost   string = "EXPORT_DISTRO_DATA_HOST"
-	envConsulHost string = "EXPORT_DISTRO_CONSUL_HOST"
-	envConsulPort string = "EXPORT_DISTRO_CONSUL_PORT"
-	envMQTTSCert  string = "EXPORT_DISTRO_MQTTS_CERT_FILE"
-	envMQTTSKey   string = "EXPORT_DISTRO_MQTTS_KEY_FILE"
-
 	applicationName string = "export-distro"
 	consulProfile   string = "go"
 
-	defConsulHost string = "127.0.0.1"
-	defConsulPort int    = 8500
-	defHostname   string = "127.0.0.1"
+	defRegistryType      string = registry.CONSUL
+	defRegistryDirectory string = "/tmp/edgex/registry"
+	defConsulHost        string = "127.0.0.1"
+	defConsulPort        int    = 8500
+	defHostname          string = "127.0.0.1"
 )
 
 type config struct {
-	ConsulHost string
-	ConsulPort int
-	Hostname   string
+	RegistryType      string `env:"EXPORT_DISTRO_REGISTRY_TYPE"`
+	RegistryDirectory string `env:"EXPORT_DISTRO_REGISTRY_DIRECTORY"`
+	ConsulHost        string `env:"EXPORT_DISTRO_CONSUL_HOST"`
+	ConsulPort        int    `env:"EXPORT_DISTRO_CONSUL_PORT"`
+	Hostname          string `env:"EXPORT_DISTRO_HOST"`
 }
 
 var logger *zap.Logger
@@ -58,41 +57,57 @@ func main() {
 
 	distroCfg, cfg := loadConfig()
 
-	// Initialize service on Consul
-	err := consulclient.ConsulInit(consulclient.ConsulConfig{
-		ServiceName:    applicationName,
-		ServicePort:    distroCfg.Port,
-		ServiceAddress: cfg.Hostname,
-		CheckAddress:   "http://" + cfg.Hostname + ":" + strconv.Itoa(distroCfg.Port) + "/api/v1/ping",
-		CheckInterval:  "10s",
-		ConsulAddress:  cfg.ConsulHost,
-		ConsulPort:     cfg.ConsulPort,
+	// Initialize service on the registry
+	r, err := registry.Connect(registry.Config{
+		Type:       cfg.RegistryType,
+		ConsulHost: cfg.ConsulHost,
+		ConsulPort: cfg.ConsulPort,
+		Directory:  cfg.RegistryDirectory,
 	})
+	if err == nil {
+		err = r.Register(registry.Service{
+			Name:          applicationName,
+			Address:       cfg.Hostname,
+			Port:          distroCfg.Port,
+			CheckAddress:  "http://" + cfg.Hostname + ":" + strconv.Itoa(distroCfg.Port) + health.ApiReadyRoute,
+			CheckInterval: "10s",
+		})
+	}
 
 	if err == nil {
-		logger.Info("Registered microservice in consul",
-			zap.String("consulHost", cfg.ConsulHost),
-			zap.Int("consulPort", cfg.ConsulPort))
+		logger.Info("Registered microservice in the registry",
+			zap.String("registryType", cfg.RegistryType))
 
 		consulProfiles := []string{consulProfile}
-		if err := consulclient.CheckKeyValuePairs(&distroCfg, applicationName, consulProfiles); err != nil {
-			logger.Warn("Error getting key/values from Consul", zap.Error(err),
-				zap.String("consulHost", cfg.ConsulHost),
-				zap.Int("consulPort", cfg.ConsulPort))
+		if err := r.CheckKeyValuePairs(&distroCfg, applicationName, consulProfiles); err != nil {
+			logger.Warn("Error getting key/values from the registry", zap.Error(err),
+				zap.String("registryType", cfg.RegistryType))
 		} else {
-			logger.Info("Updated configuration from consul",
-				zap.String("consulHost", cfg.ConsulHost),
-				zap.Int("consulPort", cfg.ConsulPort))
+			logger.Info("Updated configuration from the registry",
+				zap.String("registryType", cfg.RegistryType))
 		}
 	} else {
-		logger.Warn("Error registering to consul", zap.Error(err),
-			zap.String("consulHost", cfg.ConsulHost),
-			zap.Int("consulPort", cfg.ConsulPort))
+		logger.Warn("Error registering to the registry", zap.Error(err),
+			zap.String("registryType", cfg.RegistryType))
 	}
 
+	tracer, err := tracing.Init(applicationName, tracing.Config{
+		Enabled:     distroCfg.TracingExporter != "",
+		Exporter:    distroCfg.TracingExporter,
+		Destination: distroCfg.TracingDestination,
+	})
+	if err != nil {
+		logger.Error("Could not initialize tracing", zap.Error(err))
+		return
+	}
+	defer tracer.Close()
+
+	// Credentials of the calls to the other services
+	auth.SetClientCredentials(auth.Credentials{APIKey: distroCfg.AuthClientAPIKey, Token: distroCfg.AuthClientToken})
+
 	logger.Info("Starting distro")
 	errs := make(chan error, 2)
-	eventCh := make(chan *models.Event, 10)
+	eventCh := make(chan distro.EventMessage, 10)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -101,7 +116,7 @@ func main() {
 	}()
 
 	// There can be another receivers that can be initialiced here
-	distro.ZeroMQReceiver(eventCh)
+	distro.ZeroMQReceiver(distroCfg, eventCh)
 
 	distro.Loop(distroCfg, errs, eventCh)
 
@@ -110,37 +125,23 @@ func main() {
 
 func loadConfig() (distro.Config, config) {
 	distroCfg := distro.GetDefaultConfig()
-
-	distroCfg.ClientHost = env(envClientHost, distroCfg.ClientHost)
-	distroCfg.DataHost = env(envDataHost, distroCfg.DataHost)
-	distroCfg.MQTTSCert = env(envMQTTSCert, distroCfg.MQTTSCert)
-	distroCfg.MQTTSKey = env(envMQTTSKey, distroCfg.MQTTSKey)
+	if err := edgexconfig.ApplyEnvironment(&distroCfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
+	}
 
 	cfg := config{
-		ConsulHost: env(envConsulHost, defConsulHost),
-		ConsulPort: defConsulPort,
-		Hostname:   env(envDistroHost, defHostname),
+		RegistryType:      defRegistryType,
+		RegistryDirectory: defRegistryDirectory,
+		ConsulHost:        defConsulHost,
+		ConsulPort:        defConsulPort,
+		Hostname:          defHostname,
+	}
+	if err := edgexconfig.ApplyEnvironment(&cfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
 	}
 	hostname, err := os.Hostname()
 	if err == nil {
 		cfg.Hostname = hostname
 	}
-
-	portStr := env(envConsulPort, strconv.Itoa(cfg.ConsulPort))
-	port, err := strconv.Atoi(portStr)
-	if err == nil {
-		cfg.ConsulPort = port
-	} else {
-		logger.Warn("Could not parse port", zap.String("port", portStr))
-	}
 	return distroCfg, cfg
 }
-
-func env(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
-	}
-
-	return value
-}
@@ -12,18 +12,66 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	edgex "github.com/edgexfoundry/edgex-go"
+	edgexconfig "github.com/edgexfoundry/edgex-go/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
 	"github.com/edgexfoundry/edgex-go/support/logging"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
-const ()
+const (
+	applicationName string = "support-logging"
+	consulProfile   string = "go"
+
+	defRegistryType      string = registry.CONSUL
+	defRegistryDirectory string = "/tmp/edgex/registry"
+	defConsulHost        string = "127.0.0.1"
+	defConsulPort        int    = 8500
+	defHostname          string = "127.0.0.1"
+)
+
+type config struct {
+	RegistryType      string `env:"SUPPORT_LOGGING_REGISTRY_TYPE"`
+	RegistryDirectory string `env:"SUPPORT_LOGGING_REGISTRY_DIRECTORY"`
+	ConsulHost        string `env:"SUPPORT_LOGGING_CONSUL_HOST"`
+	ConsulPort        int    `env:"SUPPORT_LOGGING_CONSUL_PORT"`
+	Hostname          string `env:"SUPPORT_LOGGING_HOST"`
+}
 
 func main() {
-	cfg := loadConfig()
+	loggingCfg, cfg := loadConfig()
 
 	fmt.Printf("Starting support-logging %s\n", edgex.Version)
+
+	// Initialize service on the registry, the key/value pairs there override the configuration
+	r, err := registry.Connect(registry.Config{
+		Type:       cfg.RegistryType,
+		ConsulHost: cfg.ConsulHost,
+		ConsulPort: cfg.ConsulPort,
+		Directory:  cfg.RegistryDirectory,
+	})
+	if err == nil {
+		err = r.Register(registry.Service{
+			Name:          applicationName,
+			Address:       cfg.Hostname,
+			Port:          loggingCfg.Port,
+			CheckAddress:  "http://" + cfg.Hostname + ":" + strconv.Itoa(loggingCfg.Port) + health.ApiReadyRoute,
+			CheckInterval: "10s",
+		})
+	}
+	if err == nil {
+		if err := r.CheckKeyValuePairs(&loggingCfg, applicationName, []string{consulProfile}); err != nil {
+			fmt.Println("Error getting key/values from the registry: ", err)
+		} else {
+			fmt.Println("Updated configuration from the registry")
+		}
+	} else {
+		fmt.Println("Error registering to the registry: ", err)
+	}
+
 	errs := make(chan error, 2)
 
 	go func() {
@@ -32,22 +80,30 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	logging.StartHTTPServer(cfg, errs)
+	logging.StartHTTPServer(loggingCfg, errs)
 
 	c := <-errs
 	fmt.Println("terminated: ", c)
 }
 
-func loadConfig() logging.Config {
-	cfg := logging.GetDefaultConfig()
-	return cfg
-}
-
-func env(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
+func loadConfig() (logging.Config, config) {
+	loggingCfg := logging.GetDefaultConfig()
+	if err := edgexconfig.ApplyEnvironment(&loggingCfg); err != nil {
+		fmt.Println("Could not apply the environment: ", err)
 	}
 
-	return value
+	cfg := config{
+		RegistryType:      defRegistryType,
+		RegistryDirectory: defRegistryDirectory,
+		ConsulHost:        defConsulHost,
+		ConsulPort:        defConsulPort,
+		Hostname:          defHostname,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		cfg.Hostname = hostname
+	}
+	if err := edgexconfig.ApplyEnvironment(&cfg); err != nil {
+		fmt.Println("Could not apply the environment: ", err)
+	}
+	return loggingCfg, cfg
 }
@@ -0,0 +1,120 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	edgex "github.com/edgexfoundry/edgex-go"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/support/registry"
+	"github.com/edgexfoundry/edgex-go/support/rules"
+	"go.uber.org/zap"
+)
+
+const (
+	applicationName string = "support-rulesengine"
+	consulProfile   string = "go"
+)
+
+// Registry of the service, the configuration of the rules engine itself is rules.Config
+type registryConfig struct {
+	RegistryType      string `env:"SUPPORT_RULESENGINE_REGISTRY_TYPE"`
+	RegistryDirectory string `env:"SUPPORT_RULESENGINE_REGISTRY_DIRECTORY"`
+	ConsulHost        string `env:"SUPPORT_RULESENGINE_CONSUL_HOST"`
+	ConsulPort        int    `env:"SUPPORT_RULESENGINE_CONSUL_PORT"`
+	Hostname          string `env:"SUPPORT_RULESENGINE_HOST"`
+}
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	logger.Info("Starting support-rulesengine", zap.String("version", edgex.Version))
+	rules.InitLogger(logger)
+
+	cfg := rules.GetDefaultConfig()
+	if err := config.ApplyEnvironment(&cfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
+	}
+
+	regCfg := registryConfig{
+		RegistryType:      registry.CONSUL,
+		RegistryDirectory: "/tmp/edgex/registry",
+		ConsulHost:        "127.0.0.1",
+		ConsulPort:        8500,
+		Hostname:          "127.0.0.1",
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		regCfg.Hostname = hostname
+	}
+	if err := config.ApplyEnvironment(&regCfg); err != nil {
+		logger.Warn("Could not apply the environment", zap.Error(err))
+	}
+
+	// Initialize service on the registry, the key/value pairs there override the configuration
+	r, err := registry.Connect(registry.Config{
+		Type:       regCfg.RegistryType,
+		ConsulHost: regCfg.ConsulHost,
+		ConsulPort: regCfg.ConsulPort,
+		Directory:  regCfg.RegistryDirectory,
+	})
+	if err == nil {
+		err = r.Register(registry.Service{
+			Name:          applicationName,
+			Address:       regCfg.Hostname,
+			Port:          cfg.Port,
+			CheckAddress:  "http://" + regCfg.Hostname + ":" + strconv.Itoa(cfg.Port) + health.ApiReadyRoute,
+			CheckInterval: "10s",
+		})
+	}
+	if err == nil {
+		if err := r.CheckKeyValuePairs(&cfg, applicationName, []string{consulProfile}); err != nil {
+			logger.Warn("Error getting key/values from the registry", zap.Error(err),
+				zap.String("registryType", regCfg.RegistryType))
+		}
+	} else {
+		logger.Warn("Error registering to the registry", zap.Error(err),
+			zap.String("registryType", regCfg.RegistryType))
+	}
+
+	// Credentials of the calls to the other services
+	auth.SetClientCredentials(auth.Credentials{APIKey: cfg.AuthClientAPIKey, Token: cfg.AuthClientToken})
+
+	errs := make(chan error, 2)
+	eventCh := make(chan *models.Event, 10)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	rules.StartHTTPServer(cfg, errs)
+	rules.ZeroMQReceiver(cfg, eventCh)
+
+	rules.Loop(cfg, errs, eventCh)
+
+	logger.Info("terminated")
+}
@@ -25,20 +25,33 @@ import (
 	"fmt"
 )
 
-func init() {
-	go func() {
-		evt := <- events.EventAggregateEvents
-		switch evt.(type) {
-		case aggregates.DeviceLastReported:
-			e := evt.(aggregates.DeviceLastReported)
-			updateDeviceLastReportedConnected(e.DeviceName)
-			break;
-		case aggregates.DeviceServiceLastReported:
-			e := evt.(aggregates.DeviceServiceLastReported)
-			updateDeviceServiceLastReportedConnected(e.DeviceName)
-			break;
+// Apply the last reported/connected updates queued by the events aggregate.
+// Once stop is closed the updates still queued are applied before returning.
+func Worker(stop <-chan struct{}) {
+	for {
+		select {
+		case evt := <-events.EventAggregateEvents:
+			handleAggregateEvent(evt)
+		case <-stop:
+			for {
+				select {
+				case evt := <-events.EventAggregateEvents:
+					handleAggregateEvent(evt)
+				default:
+					return
+				}
+			}
 		}
-	}()
+	}
+}
+
+func handleAggregateEvent(evt interface{}) {
+	switch e := evt.(type) {
+	case aggregates.DeviceLastReported:
+		updateDeviceLastReportedConnected(e.DeviceName)
+	case aggregates.DeviceServiceLastReported:
+		updateDeviceServiceLastReportedConnected(e.DeviceName)
+	}
 }
 
 func updateDeviceLastReportedConnected(device string) error {
@@ -22,7 +22,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/edgexfoundry/edgex-go/core/aggregates"
 	"github.com/edgexfoundry/edgex-go/core/aggregates/devices/mocks"
+	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
 	"github.com/edgexfoundry/edgex-go/core/data/log"
@@ -200,4 +202,17 @@ func newMockDevice() models.Device {
 		Service:mockDeviceService}
 
 	return mockDevice
-}
\ No newline at end of file
+}
+
+func TestWorkerDrainsOnStop(t *testing.T) {
+	events.EventAggregateEvents <- aggregates.DeviceLastReported{DeviceName: mockParams.DeviceName}
+	events.EventAggregateEvents <- aggregates.DeviceServiceLastReported{DeviceName: mockParams.DeviceName}
+
+	stop := make(chan struct{})
+	close(stop)
+	Worker(stop)
+
+	if n := len(events.EventAggregateEvents); n != 0 {
+		t.Errorf("%d updates left in the queue after stop", n)
+	}
+}
@@ -31,7 +31,7 @@ var dc deviceClient
 var sc serviceClient
 
 func getConfiguration() *config.ConfigurationStruct {
-	return config.Configuration
+	return config.Current()
 }
 
 func getDatabase() clients.DBClient {
@@ -0,0 +1,385 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package events
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// An alert rule applied to the readings of a device and value descriptor
+type alertKey struct {
+	rule   bson.ObjectId
+	device string
+	name   string
+}
+
+type alertState struct {
+	raised   bool
+	hasValue bool
+	value    float64 // Last numeric value
+	origin   int64   // Origin of the last numeric value, its ingestion time when the reading has none
+	seen     int64   // Ingestion time of the last reading
+}
+
+// Change of an alert, notified to support notifications
+type alertTransition struct {
+	rule   models.AlertRule
+	device string
+	name   string
+	raised bool
+	reason string
+}
+
+// Evaluates the alert rules on the ingested readings. Only the changes of an alert are
+// reported, an alert raised by every reading above a threshold is notified once.
+type alertEngine struct {
+	mutex  sync.Mutex
+	rules  []models.AlertRule
+	states map[alertKey]*alertState
+	now    func() time.Time
+}
+
+var alerts = newAlertEngine()
+
+func newAlertEngine() *alertEngine {
+	return &alertEngine{states: make(map[alertKey]*alertState), now: time.Now}
+}
+
+func (e *alertEngine) millis() int64 {
+	return e.now().UnixNano() / int64(time.Millisecond)
+}
+
+// Replace the rules. The alerts of the rules removed or changed are forgotten.
+func (e *alertEngine) setRules(rules []models.AlertRule) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	old := make(map[bson.ObjectId]models.AlertRule, len(e.rules))
+	for _, r := range e.rules {
+		old[r.Id] = r
+	}
+	unchanged := make(map[bson.ObjectId]bool, len(rules))
+	for _, r := range rules {
+		unchanged[r.Id] = reflect.DeepEqual(old[r.Id], r)
+	}
+	for k := range e.states {
+		if !unchanged[k.rule] {
+			delete(e.states, k)
+		}
+	}
+	e.rules = rules
+
+	// A stale rule on a device and value descriptor is watched from now on, even if no
+	// reading ever arrives. A stale rule on any device or any value descriptor only watches
+	// those that reported since: a device that never reports is never flagged.
+	now := e.millis()
+	for _, r := range rules {
+		if r.Kind != models.AlertStale || r.Device == "" || r.ValueDescriptor == "" {
+			continue
+		}
+		k := alertKey{rule: r.Id, device: r.Device, name: r.ValueDescriptor}
+		if _, ok := e.states[k]; !ok {
+			e.states[k] = &alertState{seen: now}
+		}
+	}
+}
+
+// Apply the rules matching the reading, the device of the reading is its name
+func (e *alertEngine) evaluate(r models.Reading) []alertTransition {
+	if r.Device == "" || r.Name == "" {
+		return nil
+	}
+	now := e.millis()
+	origin := r.Origin
+	if origin == 0 {
+		origin = now
+	}
+	value, err := strconv.ParseFloat(r.Value, 64)
+	numeric := err == nil
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var transitions []alertTransition
+	for _, rule := range e.rules {
+		if !rule.Matches(r.Device, r.Name) {
+			continue
+		}
+		k := alertKey{rule: rule.Id, device: r.Device, name: r.Name}
+		s, ok := e.states[k]
+		if !ok {
+			s = &alertState{}
+			e.states[k] = s
+		}
+		// Readings delivered out of order don't take part in the numeric rules
+		inOrder := numeric && (!s.hasValue || origin > s.origin)
+
+		switch rule.Kind {
+		case models.AlertThreshold:
+			if inOrder {
+				transitions = e.threshold(transitions, rule, k, s, value)
+			}
+		case models.AlertRateOfChange:
+			if inOrder && s.hasValue {
+				rate := math.Abs(value-s.value) / (float64(origin-s.origin) / 1000)
+				transitions = e.rate(transitions, rule, k, s, rate)
+			}
+		case models.AlertStale:
+			if s.raised {
+				transitions = e.change(transitions, rule, k, s, false, "readings resumed")
+			}
+		}
+
+		if inOrder {
+			s.hasValue, s.value, s.origin = true, value, origin
+		}
+		s.seen = now
+	}
+	return transitions
+}
+
+func (e *alertEngine) threshold(transitions []alertTransition, rule models.AlertRule, k alertKey, s *alertState, value float64) []alertTransition {
+	if !s.raised {
+		if rule.Max != nil && value > *rule.Max {
+			return e.change(transitions, rule, k, s, true, fmt.Sprintf("value %v above the maximum %v", value, *rule.Max))
+		}
+		if rule.Min != nil && value < *rule.Min {
+			return e.change(transitions, rule, k, s, true, fmt.Sprintf("value %v below the minimum %v", value, *rule.Min))
+		}
+		return transitions
+	}
+	if (rule.Max == nil || value <= *rule.Max-rule.Hysteresis) && (rule.Min == nil || value >= *rule.Min+rule.Hysteresis) {
+		return e.change(transitions, rule, k, s, false, fmt.Sprintf("value %v back within the limits", value))
+	}
+	return transitions
+}
+
+func (e *alertEngine) rate(transitions []alertTransition, rule models.AlertRule, k alertKey, s *alertState, rate float64) []alertTransition {
+	if !s.raised && rate > rule.MaxRate {
+		return e.change(transitions, rule, k, s, true, fmt.Sprintf("value changing by %.4g per second, more than %v", rate, rule.MaxRate))
+	}
+	if s.raised && rate <= rule.MaxRate-rule.Hysteresis {
+		return e.change(transitions, rule, k, s, false, fmt.Sprintf("value changing by %.4g per second", rate))
+	}
+	return transitions
+}
+
+func (e *alertEngine) change(transitions []alertTransition, rule models.AlertRule, k alertKey, s *alertState, raised bool, reason string) []alertTransition {
+	s.raised = raised
+	return append(transitions, alertTransition{rule: rule, device: k.device, name: k.name, raised: raised, reason: reason})
+}
+
+// Raise the stale alerts of the devices and value descriptors without recent reading
+func (e *alertEngine) checkStale() []alertTransition {
+	now := e.millis()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rules := make(map[bson.ObjectId]models.AlertRule, len(e.rules))
+	for _, r := range e.rules {
+		rules[r.Id] = r
+	}
+	var transitions []alertTransition
+	for k, s := range e.states {
+		rule := rules[k.rule]
+		if rule.Kind != models.AlertStale || s.raised || now-s.seen <= rule.Timeout {
+			continue
+		}
+		transitions = e.change(transitions, rule, k, s, true, fmt.Sprintf("no reading for %d ms", now-s.seen))
+	}
+	return transitions
+}
+
+func (t alertTransition) notification(now int64) notifications.Notification {
+	state, severity := "cleared", notifications.NORMAL
+	if t.raised {
+		state, severity = "raised", notifications.CRITICAL
+		if t.rule.Severity == models.AlertNormal {
+			severity = notifications.NORMAL
+		}
+	}
+	return notifications.Notification{
+		Slug:        fmt.Sprintf("alert-%s-%s-%s-%s-%d", t.rule.Name, t.device, t.name, state, now),
+		Sender:      getConfiguration().ServiceName,
+		Category:    notifications.HW_HEALTH,
+		Severity:    severity,
+		Content:     fmt.Sprintf("Alert %s %s for %s of device %s: %s", t.rule.Name, state, t.name, t.device, t.reason),
+		Description: t.rule.Description,
+		Labels:      []string{"alert", state, t.rule.Name, t.device, t.name},
+	}
+}
+
+func notifyAlerts(transitions []alertTransition) {
+	now := alerts.millis()
+	for _, t := range transitions {
+		n := t.notification(now)
+		getLogger().Warn(n.Content)
+		if err := getNotifier().RecieveNotification(n); err != nil {
+			getLogger().Error("error sending the alert notification: " + err.Error())
+		}
+	}
+}
+
+// Alert changes of the ingested readings waiting for AlertWorker, the ingestion doesn't
+// wait for support notifications
+var alertQueue = make(chan []alertTransition, alertQueueSize)
+
+const alertQueueSize = 100
+
+// Evaluate the alert rules on an ingested reading and queue the changes
+func evaluateAlerts(r models.Reading) {
+	transitions := alerts.evaluate(r)
+	if len(transitions) == 0 {
+		return
+	}
+	select {
+	case alertQueue <- transitions:
+	default:
+		for _, t := range transitions {
+			getLogger().Error("alert notification queue full, dropped: " + t.notification(alerts.millis()).Content)
+		}
+	}
+}
+
+// Notify the queued alert changes, and raise the stale alerts every AlertCheckInterval,
+// until stop is closed
+func AlertWorker(stop <-chan struct{}) {
+	var stale <-chan time.Time
+	interval := time.Millisecond * time.Duration(getConfiguration().AlertCheckInterval)
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		stale = ticker.C
+	} else {
+		getLogger().Info("stale data alerts are disabled")
+	}
+	for {
+		select {
+		case transitions := <-alertQueue:
+			notifyAlerts(transitions)
+		case <-stale:
+			notifyAlerts(alerts.checkStale())
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Load the alert rules from the database, done at startup and after every change of the rules
+func LoadAlertRules() error {
+	rules, err := getDatabase().AlertRules()
+	if err != nil {
+		getLogger().Error(err.Error())
+		return err
+	}
+	alerts.setRules(rules)
+	return nil
+}
+
+func validateAlertRule(r models.AlertRule) error {
+	if err := r.Validate(); err != nil {
+		return errors.InvalidRequest{Field: "alertRule", Message: err.Error()}
+	}
+	return nil
+}
+
+func AddAlertRule(r models.AlertRule) (string, error) {
+	if err := validateAlertRule(r); err != nil {
+		return "", err
+	}
+	id, err := getDatabase().AddAlertRule(r)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return "", err
+	}
+	LoadAlertRules()
+	return id.Hex(), nil
+}
+
+func GetAllAlertRules() ([]models.AlertRule, error) {
+	rules, err := getDatabase().AlertRules()
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return rules, nil
+}
+
+func GetAlertRuleById(id string) (models.AlertRule, error) {
+	r, err := getDatabase().AlertRuleById(id)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return models.AlertRule{}, err
+	}
+	return r, nil
+}
+
+func GetAlertRuleByName(name string) (models.AlertRule, error) {
+	r, err := getDatabase().AlertRuleByName(name)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return models.AlertRule{}, err
+	}
+	return r, nil
+}
+
+// Replace an alert rule, found by its id or else by its name
+func UpdateAlertRule(from models.AlertRule) error {
+	to, err := getDatabase().AlertRuleById(from.Id.Hex())
+	if err != nil {
+		to, err = getDatabase().AlertRuleByName(from.Name)
+		if err != nil {
+			if err == errs.ErrNotFound {
+				getLogger().Error(fmt.Sprintf("alert rule not found %s %s", from.Id.Hex(), from.Name))
+			} else {
+				getLogger().Error(err.Error())
+			}
+			return err
+		}
+	}
+	if err := validateAlertRule(from); err != nil {
+		return err
+	}
+
+	from.Id, from.Created = to.Id, to.Created
+	if err = getDatabase().UpdateAlertRule(from); err != nil {
+		getLogger().Error(err.Error())
+		return err
+	}
+	LoadAlertRules()
+	return nil
+}
+
+func DeleteAlertRuleById(id string) error {
+	if err := getDatabase().DeleteAlertRuleById(id); err != nil {
+		getLogger().Error(err.Error())
+		return err
+	}
+	LoadAlertRules()
+	return nil
+}
@@ -0,0 +1,238 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type mockNotifier struct {
+	sent []notifications.Notification
+}
+
+func (m *mockNotifier) RecieveNotification(n notifications.Notification) error {
+	m.sent = append(m.sent, n)
+	return nil
+}
+
+// Engine with a clock moved by the tests
+func newTestAlertEngine(rules ...models.AlertRule) (*alertEngine, *time.Time) {
+	now := time.Unix(1000, 0)
+	e := newAlertEngine()
+	e.now = func() time.Time { return now }
+	for i := range rules {
+		rules[i].Id = bson.NewObjectId()
+	}
+	e.setRules(rules)
+	return e, &now
+}
+
+// States of the transitions, "+" when raised and "-" when cleared
+func alertChanges(transitions []alertTransition) string {
+	changes := ""
+	for _, t := range transitions {
+		if t.raised {
+			changes += "+"
+		} else {
+			changes += "-"
+		}
+	}
+	return changes
+}
+
+func tempReading(value string, origin int64) models.Reading {
+	return models.Reading{Device: "dev1", Name: "Temperature", Value: value, Origin: origin}
+}
+
+func TestAlertThreshold(t *testing.T) {
+	max := 100.0
+	e, _ := newTestAlertEngine(models.AlertRule{Name: "hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature", Max: &max, Hysteresis: 5})
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"90", ""},
+		{"101", "+"},
+		{"120", ""}, // Already raised
+		{"98", ""},  // Within the hysteresis
+		{"95", "-"},
+		{"99", ""},
+		{"text", ""},
+		{"100.5", "+"},
+	}
+	for i, tt := range tests {
+		if got := alertChanges(e.evaluate(tempReading(tt.value, int64(i+1)))); got != tt.want {
+			t.Errorf("reading %s: changes %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	// Another device has its own alert
+	other := tempReading("101", 1)
+	other.Device = "dev2"
+	if got := alertChanges(e.evaluate(other)); got != "+" {
+		t.Errorf("other device: changes %q, want +", got)
+	}
+}
+
+func TestAlertRateOfChange(t *testing.T) {
+	e, _ := newTestAlertEngine(models.AlertRule{Name: "jump", Kind: models.AlertRateOfChange, Device: "dev1", MaxRate: 2, Hysteresis: 1})
+
+	tests := []struct {
+		value  string
+		origin int64
+		want   string
+	}{
+		{"10", 1000, ""},
+		{"11", 2000, ""},   // 1 per second
+		{"20", 3000, "+"},  // 9 per second
+		{"10", 2500, ""},   // Out of order
+		{"21.5", 4000, ""}, // 1.5 per second, within the hysteresis
+		{"22", 5000, "-"},
+	}
+	for _, tt := range tests {
+		if got := alertChanges(e.evaluate(tempReading(tt.value, tt.origin))); got != tt.want {
+			t.Errorf("reading %s at %d: changes %q, want %q", tt.value, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestAlertStale(t *testing.T) {
+	e, now := newTestAlertEngine(models.AlertRule{Name: "silent", Kind: models.AlertStale, Device: "dev1", ValueDescriptor: "Temperature", Timeout: 60000})
+
+	*now = now.Add(30 * time.Second)
+	if got := alertChanges(e.checkStale()); got != "" {
+		t.Errorf("changes %q before the timeout", got)
+	}
+	// Watched before the first reading
+	*now = now.Add(40 * time.Second)
+	if got := alertChanges(e.checkStale()); got != "+" {
+		t.Errorf("changes %q after the timeout, want +", got)
+	}
+	if got := alertChanges(e.checkStale()); got != "" {
+		t.Errorf("changes %q while raised", got)
+	}
+	if got := alertChanges(e.evaluate(tempReading("20", 0))); got != "-" {
+		t.Errorf("changes %q on a new reading, want -", got)
+	}
+}
+
+func TestAlertSetRulesForgetsChangedRules(t *testing.T) {
+	max := 100.0
+	e, _ := newTestAlertEngine(models.AlertRule{Name: "hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature", Max: &max})
+	e.evaluate(tempReading("101", 1))
+
+	// Unchanged rules keep their alerts
+	e.setRules(append([]models.AlertRule{}, e.rules...))
+	if got := alertChanges(e.evaluate(tempReading("102", 2))); got != "" {
+		t.Errorf("changes %q with an unchanged rule", got)
+	}
+
+	changed := e.rules[0]
+	changed.Max = new(float64)
+	*changed.Max = 101.5
+	e.setRules([]models.AlertRule{changed})
+	if got := alertChanges(e.evaluate(tempReading("102", 3))); got != "+" {
+		t.Errorf("changes %q with a changed rule, want +", got)
+	}
+}
+
+func TestAlertNotifications(t *testing.T) {
+	m := &mockNotifier{}
+	nc = m
+	defer func() { nc = nil }()
+	getConfiguration().ServiceName = "core-data"
+
+	max := 100.0
+	rule := models.AlertRule{Name: "hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature", Max: &max, Severity: models.AlertNormal}
+	notifyAlerts([]alertTransition{
+		{rule: rule, device: "dev1", name: "Temperature", raised: true, reason: "value 101 above the maximum 100"},
+		{rule: rule, device: "dev1", name: "Temperature", raised: false, reason: "value 90 back within the limits"},
+	})
+
+	if len(m.sent) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(m.sent))
+	}
+	for _, n := range m.sent {
+		if n.Category != notifications.HW_HEALTH || n.Severity != notifications.NORMAL || n.Sender != "core-data" {
+			t.Errorf("unexpected notification %v", n)
+		}
+	}
+	if m.sent[0].Slug == m.sent[1].Slug || m.sent[0].Labels[1] != "raised" || m.sent[1].Labels[1] != "cleared" {
+		t.Errorf("unexpected notifications %v", m.sent)
+	}
+}
+
+func TestEvaluateAlertsQueues(t *testing.T) {
+	m := &mockNotifier{}
+	nc = m
+	defer func() { nc = nil }()
+	max := 100.0
+	alerts.setRules([]models.AlertRule{{Id: bson.NewObjectId(), Name: "hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature", Max: &max}})
+	defer alerts.setRules(nil)
+
+	evaluateAlerts(models.Reading{Device: "dev1", Name: "Temperature", Value: "101"})
+	if len(m.sent) != 0 {
+		t.Fatalf("the ingestion sent %d notifications", len(m.sent))
+	}
+	if len(alertQueue) != 1 {
+		t.Fatalf("expected 1 queued change, got %d", len(alertQueue))
+	}
+	notifyAlerts(<-alertQueue)
+	if len(m.sent) != 1 || m.sent[0].Labels[1] != "raised" {
+		t.Errorf("unexpected notifications %v", m.sent)
+	}
+}
+
+func TestAddAlertRule(t *testing.T) {
+	defer alerts.setRules(nil)
+	max := 100.0
+	if _, err := AddAlertRule(models.AlertRule{Name: "Hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature", Max: &max}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddAlertRule(models.AlertRule{Name: "Hot", Kind: models.AlertThreshold, ValueDescriptor: "Temperature"}); err == nil {
+		t.Error("expected an error for an invalid rule")
+	} else if _, ok := err.(errors.InvalidRequest); !ok {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := AddAlertRule(models.AlertRule{Name: mockParams.AlertRuleName, Kind: models.AlertThreshold, Device: "dev", Max: &max}); err != errs.ErrNotUnique {
+		t.Errorf("unexpected error %v for a duplicate name", err)
+	}
+	// The rules of the database are loaded
+	if len(alerts.rules) != 1 || alerts.rules[0].Name != mockParams.AlertRuleName {
+		t.Errorf("unexpected rules %v", alerts.rules)
+	}
+}
+
+func TestUpdateAlertRule(t *testing.T) {
+	defer alerts.setRules(nil)
+	if err := UpdateAlertRule(models.AlertRule{Name: mockParams.AlertRuleName, Kind: models.AlertStale, Device: "dev", Timeout: 1000}); err != nil {
+		t.Error(err)
+	}
+	if err := UpdateAlertRule(models.AlertRule{Name: "unknown", Kind: models.AlertStale, Device: "dev", Timeout: 1000}); err != errs.ErrNotFound {
+		t.Errorf("unexpected error %v for an unknown rule", err)
+	}
+	if err := UpdateAlertRule(models.AlertRule{Id: mockParams.AlertRuleId, Name: mockParams.AlertRuleName, Kind: models.AlertStale, Device: "dev"}); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+}
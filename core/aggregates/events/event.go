@@ -18,10 +18,14 @@
  package events
 
  import (
+	 "context"
 	 "fmt"
+	 "math"
 	 "time"
 
 	 "github.com/edgexfoundry/edgex-go/core/aggregates"
+	 "github.com/edgexfoundry/edgex-go/core/data/clients"
+	 "github.com/edgexfoundry/edgex-go/core/data/errors"
 	 "github.com/edgexfoundry/edgex-go/core/domain/errs"
 	 "github.com/edgexfoundry/edgex-go/core/domain/models" //for now
 	 "gopkg.in/mgo.v2/bson"
@@ -31,7 +35,7 @@ func CountEvents() (int, error) {
 	count, err := getDatabase().EventCount()
 	if err != nil {
 		getLogger().Error(err.Error())
-		return -1, fmt.Errorf(err.Error())
+		return -1, err
 	}
 	return count, nil
 }
@@ -46,7 +50,7 @@ func CountByDevice(device string) (int, error) {
 		d, err = getDeviceClient().DeviceForName(device)
 		if err != nil {
 			getLogger().Error("error finding device " + device + ": " + err.Error(), "")
-			return -1, fmt.Errorf("error finding device %s: %v", device, err)
+			return -1, errors.DeviceNotFound{Device: device}
 		}
 	}
 
@@ -62,17 +66,19 @@ func DeleteByAge(age int64) (int, error) {
 	events, err := getDatabase().EventsOlderThanAge(age)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return -1, fmt.Errorf(err.Error())
+		return -1, err
 	}
 
 	// Delete all the events
 	count := len(events)
-	for _, event := range events {
+	for i, event := range events {
 		if err = deleteEvent(event); err != nil {
+			evictLatest(events[:i])
 			getLogger().Error(err.Error())
-			return -1, fmt.Errorf(err.Error())
+			return -1, err
 		}
 	}
+	evictLatest(events)
 	return count, nil
 }
 
@@ -94,14 +100,14 @@ func DeleteByDevice(deviceId string) (int, error) {
 	// See if you need to check metadata
 	if getConfiguration().MetaDataCheck && !deviceFound {
 		getLogger().Error("Device not found for event: "+err.Error(), "")
-		return -1, fmt.Errorf("device not found %s: %v ", deviceId, err)
+		return -1, errors.DeviceNotFound{Device: deviceId}
 	}
 
 	// Get the events by the device name
 	events, err := getDatabase().EventsForDevice(deviceId)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return -1, fmt.Errorf(err.Error())
+		return -1, err
 	}
 
 	getLogger().Info("Deleting the events for device: " + deviceId)
@@ -111,9 +117,10 @@ func DeleteByDevice(deviceId string) (int, error) {
 	for _, event := range events {
 		if err = deleteEvent(event); err != nil {
 			getLogger().Error(err.Error())
-			return -1, fmt.Errorf(err.Error())
+			return -1, err
 		}
 	}
+	latest.removeDevice(deviceId)
 	return count, nil
 }
 
@@ -121,7 +128,7 @@ func DeleteEventById(id string) error {
 	e, err := getDatabase().EventById(id)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	getLogger().Info("Deleting event: " + e.ID.Hex())
@@ -129,8 +136,9 @@ func DeleteEventById(id string) error {
 	err = deleteEvent(e)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
+	evictLatest([]models.Event{e})
 	return nil
 }
 
@@ -139,7 +147,7 @@ func GetAllEvents() ([]models.Event, error) {
 
 	if err != nil {
 		getLogger().Error(err.Error())
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 	return events, err
 }
@@ -152,7 +160,7 @@ func GetEventsByCreateTime(startTime, endTime int64, limit int) ([]models.Event,
 	e, err := getDatabase().EventsByCreationTime(startTime, endTime, limit)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 	return e, nil
 }
@@ -177,9 +185,8 @@ func GetEventsByDevice(deviceId string, limitNum int) ([]models.Event, error) {
 
 	// See if you need to check metadata for the device
 	if getConfiguration().MetaDataCheck && !deviceFound {
-		msg := "error getting readings for non-existent device " + deviceId
-		getLogger().Error(msg)
-		return nil, fmt.Errorf(msg)
+		getLogger().Error("error getting readings for non-existent device " + deviceId)
+		return nil, errors.DeviceNotFound{Device: deviceId}
 	}
 
 	if limitNum > getConfiguration().ReadMaxLimit {
@@ -189,16 +196,96 @@ func GetEventsByDevice(deviceId string, limitNum int) ([]models.Event, error) {
 	eventList, err := getDatabase().EventsForDeviceLimit(deviceId, limitNum)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 	return eventList, nil
 }
 
+func GetEventsByTag(key, value string) ([]models.Event, error) {
+	e, err := getDatabase().EventsByTag(key, value)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return e, nil
+}
+
+func GetEventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	if limit > getConfiguration().ReadMaxLimit {
+		limit = getConfiguration().ReadMaxLimit
+	}
+
+	e, err := getDatabase().EventsBySchedule(schedule, startTime, endTime, limit)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return e, nil
+}
+
+func GetEventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	if limit > getConfiguration().ReadMaxLimit {
+		limit = getConfiguration().ReadMaxLimit
+	}
+
+	e, err := getDatabase().EventsByScheduleEvent(scheduleEvent, startTime, endTime, limit)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return e, nil
+}
+
+// Get the events located within radius meters of the point, nearest first
+func GetEventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	if err := point.Validate(); err != nil {
+		return nil, errors.InvalidRequest{Field: "location", Message: err.Error()}
+	}
+	if radius < 0 || math.IsNaN(radius) || math.IsInf(radius, 0) {
+		return nil, errors.InvalidRequest{Field: "radius", Message: "radius must be a non-negative number"}
+	}
+	if limit > getConfiguration().ReadMaxLimit {
+		limit = getConfiguration().ReadMaxLimit
+	}
+
+	e, err := getDatabase().EventsNear(point, radius, limit)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return e, nil
+}
+
+func GetEventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	if err := polygon.Validate(); err != nil {
+		return nil, errors.InvalidRequest{Field: "polygon", Message: err.Error()}
+	}
+	if limit > getConfiguration().ReadMaxLimit {
+		limit = getConfiguration().ReadMaxLimit
+	}
+
+	e, err := getDatabase().EventsWithin(polygon, limit)
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return e, nil
+}
+
+func GetLatestDevicePositions() ([]models.DevicePosition, error) {
+	p, err := getDatabase().LatestDevicePositions()
+	if err != nil {
+		getLogger().Error(err.Error())
+		return nil, err
+	}
+	return p, nil
+}
+
 func GetEventById(id string) (models.Event, error) {
 	evt, err := getDatabase().EventById(id)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return models.Event{}, fmt.Errorf(err.Error())
+		return models.Event{}, err
 	}
 	return evt, nil
 }
@@ -227,14 +314,14 @@ func GetReadingsByDeviceAndValueDescriptor(deviceId string, descriptor string, l
 	// See if you need to check metadata
 	if getConfiguration().MetaDataCheck && !deviceFound {
 		getLogger().Error("device " + deviceId + " not found for event: "+err.Error(), "")
-		return nil, fmt.Errorf("device %s not found: %v", deviceId, err)
+		return nil, errors.DeviceNotFound{Device: deviceId}
 	}
 
 	// Get all the events for the device
 	e, err := getDatabase().EventsForDevice(deviceId)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 
 	// Only pick the readings who match the value descriptor
@@ -257,14 +344,18 @@ func GetReadingsByDeviceAndValueDescriptor(deviceId string, descriptor string, l
 	return readings, nil
 }
 
-func AddNewEvent(evt models.Event) (string, error) {
+// The calls to metadata, the database and the message bus are recorded in the trace of ctx
+func AddNewEvent(ctx context.Context, evt models.Event) (string, error) {
+	dc := getDeviceClientFor(ctx)
+	db := clients.WithContext(ctx, getDatabase())
+
 	// Get device from metadata
 	deviceFound := true
 	// Try by ID
-	d, err := getDeviceClient().Device(evt.Device) //TODO: Why is this property double-purposed?
+	d, err := dc.Device(evt.Device) //TODO: Why is this property double-purposed?
 	if err != nil {
 		// Try by name
-		d, err = getDeviceClient().DeviceForName(evt.Device)
+		d, err = dc.DeviceForName(evt.Device)
 		if err != nil {
 			deviceFound = false
 		}
@@ -276,7 +367,25 @@ func AddNewEvent(evt models.Event) (string, error) {
 
 	// See if metadata checking is enabled
 	if getConfiguration().MetaDataCheck && !deviceFound {
-		return "", fmt.Errorf("device %s not found for new event", evt.Device)
+		return "", errors.DeviceNotFound{Device: evt.Device}
+	}
+
+	if err := validateTags(evt.Tags); err != nil {
+		return "", err
+	}
+	if err := validateLocation(evt.Location); err != nil {
+		return "", err
+	}
+	// Events taken without a position are located at their device
+	if evt.Location == nil && deviceFound {
+		if p, ok := models.GeoPointFromLocation(d.Location); ok {
+			evt.Location = &p
+		}
+	}
+	for _, reading := range evt.Readings {
+		if err := validateTags(reading.Tags); err != nil {
+			return "", err
+		}
 	}
 
 	if getConfiguration().ValidateCheck {
@@ -284,7 +393,7 @@ func AddNewEvent(evt models.Event) (string, error) {
 		for reading := range evt.Readings {
 			valid, err := isValidValueDescriptor(evt.Readings[reading], evt)
 			if !valid {
-				return "", fmt.Errorf("validation failed: %s", err.Error())
+				return "", errors.InvalidRequest{Field: "readings", Message: "validation failed: " + err.Error()}
 			}
 		}
 	}
@@ -294,11 +403,11 @@ func AddNewEvent(evt models.Event) (string, error) {
 	if getConfiguration().PersistData {
 		for i, reading := range evt.Readings {
 			// Check value descriptor
-			_, err := getDatabase().ValueDescriptorByName(reading.Name)
+			_, err := db.ValueDescriptorByName(reading.Name)
 			if err != nil {
 				getLogger().Error(err.Error())
 				if err == errs.ErrNotFound {
-					return "", fmt.Errorf("value descriptor not found for a reading (%s): %v", reading.Name, err)
+					return "", errors.NoValueDescriptor{Id: reading.Name}
 				} else {
 					return "", err
 				}
@@ -307,25 +416,33 @@ func AddNewEvent(evt models.Event) (string, error) {
 			reading.Device = evt.Device // Update the device for the reading
 
 			// Add the reading
-			id, err := getDatabase().AddReading(reading)
+			id, err := db.AddReading(reading)
 			if err != nil {
 				getLogger().Error(err.Error())
-				return "", fmt.Errorf(err.Error())
+				return "", err
 			}
 
 			evt.Readings[i].Id = id // Set the ID for referencing later
 		}
 
 		// Add the event to the database
-		id, err := getDatabase().AddEvent(&evt)
+		id, err := db.AddEvent(&evt)
 		if err != nil {
 			getLogger().Error(err.Error())
-			return "", fmt.Errorf(err.Error())
+			return "", err
 		}
 		retVal = id.Hex()
 	}
 
-	publishExternalEvent(evt)                                 // Push the aux struct to export service (It has the actual readings)
+	// The readings become the current values of the device
+	for _, reading := range evt.Readings {
+		reading.Device = evt.Device
+		latest.update(reading)
+		evaluateAlerts(reading)
+	}
+
+	eventsIngested.Inc()
+	publishExternalEvent(ctx, evt)                            // Push the aux struct to export service (It has the actual readings)
 	EventAggregateEvents <- aggregates.DeviceLastReported{DeviceName:evt.Device} // update last reported connected (device)
 	EventAggregateEvents <- aggregates.DeviceServiceLastReported{DeviceName:evt.Device} // update last reported connected (device service)
 
@@ -336,8 +453,9 @@ func Purge() error {
 	err := getDatabase().ScrubAllEvents()
 	if err != nil {
 		getLogger().Error("error purging all events/readings: " + err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
+	latest.reset(nil)
 	return nil
 }
 
@@ -347,17 +465,19 @@ func PurgeIfPublished() (int, error) {
 	events, err := getDatabase().EventsPushed()
 	if err != nil {
 		getLogger().Error(err.Error())
-		return -1, fmt.Errorf(err.Error())
+		return -1, err
 	}
 
 	// Delete all the events
 	count := len(events)
-	for _, event := range events {
+	for i, event := range events {
 		if err = deleteEvent(event); err != nil {
+			evictLatest(events[:i])
 			getLogger().Error(err.Error())
-			return -1, fmt.Errorf(err.Error())
+			return -1, err
 		}
 	}
+	evictLatest(events)
 	return count, nil
 }
 
@@ -365,13 +485,13 @@ func Touch(id string) error {
 	if !bson.IsObjectIdHex(id) {
 		msg := fmt.Sprintf("%s is not a valid bson objectId", id)
 		getLogger().Error(msg)
-		return fmt.Errorf(msg)
+		return errors.InvalidRequest{Field: "id", Message: msg}
 	}
 	// Check if the event exists
 	evt, err := getDatabase().EventById(id)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	getLogger().Info("Updating event: " + evt.ID.Hex())
@@ -380,7 +500,7 @@ func Touch(id string) error {
 	err = getDatabase().UpdateEvent(evt)
 	if err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
 	return nil
 }
@@ -390,7 +510,7 @@ func UpdateEvent(from models.Event) error {
 	to, err := getDatabase().EventById(from.ID.Hex())
 	if err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
 
 	getLogger().Info("Updating event: " + from.ID.Hex())
@@ -409,7 +529,7 @@ func UpdateEvent(from models.Event) error {
 		// See if we need to check metadata
 		if getConfiguration().MetaDataCheck && !deviceFound {
 			getLogger().Error("Error updating device, device " + from.Device + " doesn't exist")
-			return fmt.Errorf("device not found: %v", err)
+			return errors.DeviceNotFound{Device: from.Device}
 		}
 
 		if deviceFound {
@@ -424,11 +544,23 @@ func UpdateEvent(from models.Event) error {
 	if from.Origin != 0 {
 		to.Origin = from.Origin
 	}
+	if from.Tags != nil {
+		if err = validateTags(from.Tags); err != nil {
+			return err
+		}
+		to.Tags = from.Tags
+	}
+	if from.Location != nil {
+		if err = validateLocation(from.Location); err != nil {
+			return err
+		}
+		to.Location = from.Location
+	}
 
 	// Update
 	if err = getDatabase().UpdateEvent(to); err != nil {
 		getLogger().Error(err.Error())
-		return fmt.Errorf(err.Error())
+		return err
 	}
 	return nil
 }
@@ -448,11 +580,14 @@ func deleteEvent(e models.Event) error {
 }
 
 // Put event on the message queue to be processed by the rules engine
-func publishExternalEvent(e models.Event) {
+func publishExternalEvent(ctx context.Context, e models.Event) {
 	getLogger().Info("Putting event on message queue", "")
 	//	Have multiple implementations (start with ZeroMQ)
-	err := getMQPublisher().SendEventMessage(e)
+	err := getMQPublisher().SendEventMessage(ctx, e)
 	if err != nil {
+		eventsPublished.Inc("failure")
 		getLogger().Error("Unable to send message for event: " + e.String())
+		return
 	}
+	eventsPublished.Inc("success")
 }
\ No newline at end of file
@@ -18,7 +18,9 @@
 package events
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"sync"
@@ -29,6 +31,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/aggregates/events/mocks"
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/data/log"
 	"github.com/edgexfoundry/edgex-go/core/data/messaging"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
@@ -44,7 +47,7 @@ func TestMain(m *testing.M) {
 	mockParams = clients.GetMockParams()
 	dc = registerMockMethods()
 	_, _ = clients.NewDBClient(clients.DBConfiguration{DbType: clients.MOCK})
-	_ = messaging.NewMQPublisher("", messaging.MOCK)
+	_, _ = messaging.NewMQPublisher("", messaging.MOCK, nil)
 	log.Logger = logger.NewMockClient()
 	config.Configuration = &config.ConfigurationStruct{ MetaDataCheck:true}
 
@@ -237,6 +240,118 @@ func TestGetEventById(t *testing.T) {
 	testEventWithoutReadings(event, t)
 }
 
+func TestGetEventsByTag(t *testing.T) {
+	events, err := GetEventsByTag(mockParams.TagKey, mockParams.TagValue)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(events) != 1 || events[0].Tags[mockParams.TagKey] != mockParams.TagValue {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestGetEventsBySchedule(t *testing.T) {
+	events, err := GetEventsBySchedule(mockParams.ScheduleName, 0, mockParams.EventAgeInTicks, 1)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(events) != 1 || events[0].Schedule != mockParams.ScheduleName {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestGetEventsByScheduleEvent(t *testing.T) {
+	events, err := GetEventsByScheduleEvent(mockParams.ScheduleEventName, mockParams.EventAgeInTicks+1, mockParams.EventAgeInTicks+2, 10)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(events) != 0 {
+		t.Errorf("expected no events outside of the time range, got %v", events)
+	}
+}
+
+func TestGetEventsNear(t *testing.T) {
+	getConfiguration().ReadMaxLimit = 10
+	events, err := GetEventsNear(mockParams.Location, 1000, 10)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(events) != 1 || events[0].ID != mockParams.EventId {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestGetEventsNearInvalidRadius(t *testing.T) {
+	for _, radius := range []float64{-1, math.NaN(), math.Inf(1)} {
+		_, err := GetEventsNear(mockParams.Location, radius, 10)
+		if _, ok := err.(errors.InvalidRequest); !ok {
+			t.Errorf("expected an invalid request for the radius %v, got %v", radius, err)
+		}
+	}
+}
+
+func TestGetEventsWithin(t *testing.T) {
+	getConfiguration().ReadMaxLimit = 10
+	polygon := models.GeoPolygon{Type: models.GeoJSONPolygon, Coordinates: [][][]float64{
+		{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}},
+	}}
+	events, err := GetEventsWithin(polygon, 10)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(events) != 1 || events[0].Location.Longitude() != 0 {
+		t.Errorf("unexpected events %v", events)
+	}
+
+	polygon.Coordinates[0] = polygon.Coordinates[0][:4]
+	if _, err = GetEventsWithin(polygon, 10); err == nil {
+		t.Error("expected an error for an open polygon")
+	}
+}
+
+func TestGetLatestDevicePositions(t *testing.T) {
+	positions, err := GetLatestDevicePositions()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(positions) != 1 || positions[0].Device != mockParams.DeviceName || positions[0].Location.Latitude() != mockParams.Location.Latitude() {
+		t.Errorf("unexpected positions %v", positions)
+	}
+}
+
+func TestAddNewEventInvalidLocationFailure(t *testing.T) {
+	event, _ := getDatabase().EventById(mockParams.EventId.Hex()) //using this as a factory
+	location := models.NewGeoPoint(200, 0)
+	event.Location = &location
+
+	_, err := AddNewEvent(context.Background(), event)
+	if e, ok := err.(errors.InvalidRequest); !ok || e.Field != "location" {
+		t.Errorf("expected an invalid location, got %v", err)
+	}
+}
+
+func TestAddNewEventInvalidTagFailure(t *testing.T) {
+	event, _ := getDatabase().EventById(mockParams.EventId.Hex()) //using this as a factory
+	event.Tags = map[string]string{"site.code": "plant-1"}
+
+	_, err := AddNewEvent(context.Background(), event)
+	if _, ok := err.(errors.InvalidRequest); !ok {
+		t.Errorf("expected an invalid request, received %v", err)
+	}
+}
+
 func TestGetReadingsByDeviceAndValueDescriptor(t *testing.T) {
 	getConfiguration().ReadMaxLimit = 2
 	readings, err := GetReadingsByDeviceAndValueDescriptor(mockParams.DeviceName, mockParams.ReadingName, 3)
@@ -334,7 +449,7 @@ func TestAddNewEvent(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	id, err := AddNewEvent(event)
+	id, err := AddNewEvent(context.Background(), event)
 	if err != nil {
 		t.Error(err.Error())
 		return
@@ -363,7 +478,7 @@ func TestAddNewEventWithoutPersistence(t *testing.T) {
 	wg.Add(1)
 	go handleDomainEvents(bitEvents, &wg, t)
 
-	id, err := AddNewEvent(event)
+	id, err := AddNewEvent(context.Background(), event)
 	if err != nil {
 		t.Error(err.Error())
 		return
@@ -0,0 +1,215 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package events
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+// Latest reading of every value descriptor of every device, indexed both ways so the
+// current values of a device or of a value descriptor are found without a database query.
+// The table follows the ingested readings and the deletions; when a deletion removes current
+// values, the latest readings left in the database take their places.
+// Readings are ordered by creation time, like the rebuild from the database.
+type latestReadings struct {
+	mutex    sync.RWMutex
+	byDevice map[string]map[string]models.Reading
+	byName   map[string]map[string]models.Reading
+}
+
+var latest = newLatestReadings()
+
+func newLatestReadings() *latestReadings {
+	return &latestReadings{
+		byDevice: make(map[string]map[string]models.Reading),
+		byName:   make(map[string]map[string]models.Reading),
+	}
+}
+
+// Make the reading the current value of its device and value descriptor, unless the
+// current value was created later. Readings that aren't created yet are being ingested.
+func (l *latestReadings) update(r models.Reading) {
+	if r.Device == "" || r.Name == "" {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if old, ok := l.byDevice[r.Device][r.Name]; ok && r.Created != 0 && old.Created > r.Created {
+		return
+	}
+	l.set(r)
+}
+
+// Give the readings to the devices and value descriptors without current value
+func (l *latestReadings) fill(readings []models.Reading) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, r := range readings {
+		if _, ok := l.byDevice[r.Device][r.Name]; !ok && r.Device != "" && r.Name != "" {
+			l.set(r)
+		}
+	}
+}
+
+func (l *latestReadings) set(r models.Reading) {
+	if l.byDevice[r.Device] == nil {
+		l.byDevice[r.Device] = make(map[string]models.Reading)
+	}
+	if l.byName[r.Name] == nil {
+		l.byName[r.Name] = make(map[string]models.Reading)
+	}
+	l.byDevice[r.Device][r.Name] = r
+	l.byName[r.Name][r.Device] = r
+}
+
+// Forget the reading if it is a current value, telling whether it was
+func (l *latestReadings) remove(r models.Reading) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if old, ok := l.byDevice[r.Device][r.Name]; ok && old.Id == r.Id {
+		l.unset(r.Device, r.Name)
+		return true
+	}
+	return false
+}
+
+// Follow an update of the reading if it is a current value
+func (l *latestReadings) replace(old models.Reading, r models.Reading) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if current, ok := l.byDevice[old.Device][old.Name]; ok && current.Id == old.Id {
+		l.unset(old.Device, old.Name)
+		if r.Device != "" && r.Name != "" {
+			l.set(r)
+		}
+	}
+}
+
+func (l *latestReadings) unset(device, name string) {
+	delete(l.byDevice[device], name)
+	if len(l.byDevice[device]) == 0 {
+		delete(l.byDevice, device)
+	}
+	delete(l.byName[name], device)
+	if len(l.byName[name]) == 0 {
+		delete(l.byName, name)
+	}
+}
+
+// Forget the current values of the device
+func (l *latestReadings) removeDevice(device string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for name := range l.byDevice[device] {
+		l.unset(device, name)
+	}
+}
+
+// Replace all the current values
+func (l *latestReadings) reset(readings []models.Reading) {
+	fresh := newLatestReadings()
+	for _, r := range readings {
+		fresh.update(r)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.byDevice, l.byName = fresh.byDevice, fresh.byName
+}
+
+// Current values of the device sorted by value descriptor
+func (l *latestReadings) forDevice(device string) []models.Reading {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	readings := make([]models.Reading, 0, len(l.byDevice[device]))
+	for _, r := range l.byDevice[device] {
+		readings = append(readings, r)
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Name < readings[j].Name })
+	return readings
+}
+
+// Current values of the value descriptor sorted by device
+func (l *latestReadings) forName(name string) []models.Reading {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	readings := make([]models.Reading, 0, len(l.byName[name]))
+	for _, r := range l.byName[name] {
+		readings = append(readings, r)
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Device < readings[j].Device })
+	return readings
+}
+
+// Rebuild the latest readings from the database, done once at startup
+func LoadLatestReadings() error {
+	readings, err := getDatabase().LatestReadings()
+	if err != nil {
+		getLogger().Error(err.Error())
+		return err
+	}
+	latest.reset(readings)
+	return nil
+}
+
+// Forget the current values among the readings of the deleted events, and replace them
+// by the latest readings left in the database
+func evictLatest(deleted []models.Event) {
+	evicted := false
+	for _, e := range deleted {
+		for _, r := range e.Readings {
+			if r.Device == "" {
+				r.Device = e.Device
+			}
+			if latest.remove(r) {
+				evicted = true
+			}
+		}
+	}
+	if evicted {
+		refillLatest()
+	}
+}
+
+// Give the latest readings in the database to the devices and value descriptors without current value
+func refillLatest() {
+	readings, err := getDatabase().LatestReadings()
+	if err != nil {
+		getLogger().Error("could not refresh the latest readings: " + err.Error())
+		return
+	}
+	latest.fill(readings)
+}
+
+// Latest reading of every value descriptor of the device
+func GetLatestReadingsByDevice(device string) []models.Reading {
+	return latest.forDevice(device)
+}
+
+// Latest reading of the value descriptor for every device
+func GetLatestReadingsByValueDescriptor(name string) []models.Reading {
+	return latest.forName(name)
+}
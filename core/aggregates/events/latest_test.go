@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package events
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func newReading(device, name, value string, created int64) models.Reading {
+	return models.Reading{Id: bson.NewObjectId(), Device: device, Name: name, Value: value, Created: created}
+}
+
+func values(readings []models.Reading) []string {
+	v := []string{}
+	for _, r := range readings {
+		v = append(v, r.Device+"/"+r.Name+"="+r.Value)
+	}
+	return v
+}
+
+func checkValues(t *testing.T, readings []models.Reading, want ...string) {
+	got := values(readings)
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+func TestLatestReadings(t *testing.T) {
+	l := newLatestReadings()
+	pressure := newReading("dev1", "Pressure", "1.2", 10)
+	l.update(newReading("dev1", "Temperature", "20", 10))
+	l.update(pressure)
+	l.update(newReading("dev2", "Temperature", "25", 10))
+	l.update(newReading("dev1", "Temperature", "21", 20))
+	// Out of order delivery doesn't replace a later value, a reading being ingested does
+	l.update(newReading("dev1", "Temperature", "19", 15))
+	l.update(newReading("dev2", "Temperature", "26", 0))
+	l.update(newReading("", "Temperature", "0", 30))
+
+	checkValues(t, l.forDevice("dev1"), "dev1/Pressure=1.2", "dev1/Temperature=21")
+	checkValues(t, l.forName("Temperature"), "dev1/Temperature=21", "dev2/Temperature=26")
+	checkValues(t, l.forDevice("unknown"))
+
+	updated := pressure
+	updated.Value = "1.3"
+	l.replace(pressure, updated)
+	checkValues(t, l.forName("Pressure"), "dev1/Pressure=1.3")
+
+	// Removing a reading that isn't current keeps the current value
+	if l.remove(newReading("dev1", "Temperature", "21", 20)) {
+		t.Error("a reading that isn't current was removed")
+	}
+	checkValues(t, l.forDevice("dev1"), "dev1/Pressure=1.3", "dev1/Temperature=21")
+	if !l.remove(updated) {
+		t.Error("the current value was not removed")
+	}
+	checkValues(t, l.forDevice("dev1"), "dev1/Temperature=21")
+	checkValues(t, l.forName("Pressure"))
+
+	// Filling only gives values to the readings without current value
+	l.fill([]models.Reading{newReading("dev1", "Pressure", "1.1", 5), newReading("dev1", "Temperature", "18", 5)})
+	checkValues(t, l.forDevice("dev1"), "dev1/Pressure=1.1", "dev1/Temperature=21")
+
+	l.removeDevice("dev1")
+	checkValues(t, l.forName("Temperature"), "dev2/Temperature=26")
+	l.reset(nil)
+	checkValues(t, l.forDevice("dev2"))
+}
+
+func TestLoadLatestReadings(t *testing.T) {
+	if err := LoadLatestReadings(); err != nil {
+		t.Fatal(err)
+	}
+	readings := GetLatestReadingsByDevice(mockParams.DeviceName)
+	if len(readings) != 2 || readings[0].Name != "Pressure" || readings[1].Name != "Temperature" {
+		t.Errorf("unexpected readings %v", readings)
+	}
+	if readings := GetLatestReadingsByValueDescriptor("Temperature"); len(readings) != 1 || readings[0].Device != mockParams.DeviceName {
+		t.Errorf("unexpected readings %v", readings)
+	}
+}
+
+func TestAddNewReadingUpdatesLatest(t *testing.T) {
+	latest.reset(nil)
+	getConfiguration().PersistData = false
+	reading := newReading(mockParams.DeviceName, mockParams.ValueDescriptorName, "42", 0)
+
+	if _, err := AddNewReading(reading); err != nil {
+		t.Fatal(err)
+	}
+	readings := GetLatestReadingsByValueDescriptor(mockParams.ValueDescriptorName)
+	if len(readings) != 1 || readings[0].Value != "42" {
+		t.Errorf("unexpected readings %v", readings)
+	}
+}
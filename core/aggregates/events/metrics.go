@@ -0,0 +1,37 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package events
+
+import (
+	"github.com/edgexfoundry/edgex-go/pkg/heartbeat"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+)
+
+var (
+	eventsIngested  = metrics.NewCounter("edgex_core_data_events_ingested_total", "Number of events accepted by core data.")
+	eventsPublished = metrics.NewCounter("edgex_core_data_events_published_total",
+		"Number of events put on the message bus, by result.", "result")
+)
+
+func init() {
+	metrics.RegisterQueue("event_aggregate", func() int { return len(EventAggregateEvents) })
+	metrics.RegisterQueue("alert_notifications", func() int { return len(alertQueue) })
+
+	heartbeat.RegisterCounter("events_ingested", func() float64 { return eventsIngested.Value() })
+	heartbeat.RegisterCounter("events_published", func() float64 { return eventsPublished.Value("success") })
+	heartbeat.RegisterCounter("events_publish_failures", func() float64 { return eventsPublished.Value("failure") })
+}
@@ -18,6 +18,8 @@
 package events
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/edgex-go/core/clients/metadataclients"
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
@@ -25,10 +27,12 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/data/messaging"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/edgexfoundry/edgex-go/support/logging-client"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
 )
 
 //Only set this manually when providing a mock for unit testing
 var dc deviceClient
+var nc notifier
 
 var EventAggregateEvents chan interface{}
 
@@ -45,8 +49,16 @@ func getDeviceClient() deviceClient {
 	return dc
 }
 
+// Device client passing the trace of ctx on to metadata
+func getDeviceClientFor(ctx context.Context) deviceClient {
+	if c, ok := getDeviceClient().(*metadataclients.DeviceRestClient); ok {
+		return c.WithContext(ctx)
+	}
+	return getDeviceClient()
+}
+
 func getConfiguration() *config.ConfigurationStruct {
-	return config.Configuration
+	return config.Current()
 }
 
 func getDatabase() clients.DBClient {
@@ -61,7 +73,21 @@ func getMQPublisher() messaging.EventPublisher {
 	return messaging.CurrentPublisher
 }
 
+func getNotifier() notifier {
+	if nc == nil { //We check here in case a mock was supplied.
+		nc = notifications.NotificationsClient{
+			RemoteUrl:     getConfiguration().SupportNotificationsNotificationURL,
+			OwningService: getConfiguration().ServiceName,
+		}
+	}
+	return nc
+}
+
 type deviceClient interface {
 	Device(id string) (models.Device, error)
 	DeviceForName(name string) (models.Device, error)
+}
+
+type notifier interface {
+	RecieveNotification(n notifications.Notification) error
 }
\ No newline at end of file
@@ -21,6 +21,7 @@ package events
 import (
 	"fmt"
 
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/domain/models" //for now
 	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 )
@@ -29,23 +30,25 @@ func GetAllReadings() ([]models.Reading, error) {
 	r, err := getDatabase().Readings()
 	if err != nil {
 		getLogger().Error(err.Error())
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 
 	return r, err
 }
 
 func AddNewReading(reading models.Reading) (string, error) {
+	if err := validateTags(reading.Tags); err != nil {
+		return "", err
+	}
+
 	// Check the value descriptor
 	_, err := getDatabase().ValueDescriptorByName(reading.Name)
 	if err != nil {
-		var msg string
 		if err == errs.ErrNotFound {
-			msg = fmt.Sprintf("value descriptor not found: %s", reading.Name)
-		} else {
-			msg = err.Error()
+			getLogger().Error(fmt.Sprintf("value descriptor not found: %s", reading.Name))
+			return "", errors.NoValueDescriptor{Id: reading.Name}
 		}
-		getLogger().Error(msg)
+		getLogger().Error(err.Error())
 		return "", err
 	}
 
@@ -58,7 +61,7 @@ func AddNewReading(reading models.Reading) (string, error) {
 			d, err = getDeviceClient().Device(reading.Device)
 			if err != nil {
 				getLogger().Error(err.Error(), "")
-				return "", fmt.Errorf(err.Error())
+				return "", errors.DeviceNotFound{Device: reading.Device}
 			}
 		}
 		reading.Device = d.Name
@@ -69,10 +72,13 @@ func AddNewReading(reading models.Reading) (string, error) {
 		id, err := getDatabase().AddReading(reading)
 		if err != nil {
 			getLogger().Error(err.Error())
-			return "", fmt.Errorf(err.Error())
+			return "", err
 		}
+		reading.Id = id
 		retVal = id.Hex()
 	}
+	latest.update(reading)
+	evaluateAlerts(reading)
 	return retVal, nil
 }
 
@@ -103,6 +109,9 @@ func DeleteReadingById(id string) error {
 		getLogger().Error(err.Error())
 		return err
 	}
+	if latest.remove(reading) {
+		refillLatest()
+	}
 	return nil
 }
 
@@ -134,7 +143,7 @@ func GetReadingsByDevice(device string, limit int) ([]models.Reading, error) {
 		if err != nil {
 			if getConfiguration().MetaDataCheck {
 				getLogger().Error("Error getting readings for a non-existent device: " + device)
-				return nil, errs.ErrNotFound
+				return nil, errors.DeviceNotFound{Device: device}
 			}
 		}
 	}
@@ -277,6 +286,7 @@ func UpdateReading(from models.Reading) error {
 		getLogger().Error(msg)
 		return err
 	}
+	old := to
 
 	//Update the fields
 	if from.Value != "" {
@@ -285,13 +295,11 @@ func UpdateReading(from models.Reading) error {
 	if from.Name != "" {
 		_, err := getDatabase().ValueDescriptorByName(from.Name)
 		if err != nil {
-			var msg string
 			if err == errs.ErrNotFound {
-				msg = fmt.Sprintf("no value descriptor for reading %s", from.Name)
-			} else {
-				msg = err.Error()
+				getLogger().Error(fmt.Sprintf("no value descriptor for reading %s", from.Name))
+				return errors.NoValueDescriptor{Id: from.Name}
 			}
-			getLogger().Error(msg)
+			getLogger().Error(err.Error())
 			return err
 		}
 		to.Name = from.Name
@@ -299,10 +307,18 @@ func UpdateReading(from models.Reading) error {
 	if from.Origin != 0 {
 		to.Origin = from.Origin
 	}
+	if from.Tags != nil {
+		if err = validateTags(from.Tags); err != nil {
+			return err
+		}
+		to.Tags = from.Tags
+	}
 
 	err = getDatabase().UpdateReading(to)
 	if err != nil {
 		getLogger().Error(err.Error())
+		return err
 	}
-	return err
+	latest.replace(old, to)
+	return nil
 }
@@ -11,8 +11,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 )
 
@@ -183,3 +185,25 @@ func validJSON(ev models.Event) (bool, error) {
 	}
 	return true, nil
 }
+
+// Tag keys end up as field names in the database, so they can't be empty, hold dots or
+// start with a dollar sign
+func validateTags(tags map[string]string) error {
+	for k := range tags {
+		if k == "" || strings.Contains(k, ".") || strings.HasPrefix(k, "$") {
+			return errors.InvalidRequest{Field: "tags", Message: fmt.Sprintf("invalid tag key %q", k)}
+		}
+	}
+	return nil
+}
+
+// The location of an event must be a valid GeoJSON point
+func validateLocation(location *models.GeoPoint) error {
+	if location == nil {
+		return nil
+	}
+	if err := location.Validate(); err != nil {
+		return errors.InvalidRequest{Field: "location", Message: err.Error()}
+	}
+	return nil
+}
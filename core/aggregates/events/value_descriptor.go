@@ -18,10 +18,10 @@
 package events
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 	"github.com/edgexfoundry/edgex-go/core/domain/models" //for now
 )
@@ -53,7 +53,7 @@ func DeleteValueDescriptorById(id string) error {
 		return err
 	}
 	if len(readings) > 0 {
-		err = errors.New("Data integrity issue.  Value Descriptor is still referenced by existing readings.")
+		err = errors.ValueDescriptorStillInUse{}
 		getLogger().Error(err.Error())
 		return err
 	}
@@ -82,7 +82,7 @@ func GetValueDescriptorsByDeviceId(deviceId string) ([]models.ValueDescriptor, e
 	d, err := getDeviceClient().Device(deviceId)
 	if err != nil {
 		getLogger().Error("Device not found: " + err.Error())
-		return nil, errs.ErrNotFound
+		return nil, errors.DeviceNotFound{Device: deviceId}
 	}
 
 	// Get the names of the value descriptors
@@ -115,7 +115,7 @@ func GetValueDescriptorsByDeviceName(device string) ([]models.ValueDescriptor, e
 	d, err := getDeviceClient().DeviceForName(device)
 	if err != nil {
 		getLogger().Error("Device not found: " + err.Error())
-		return nil, errs.ErrNotFound
+		return nil, errors.DeviceNotFound{Device: device}
 	}
 
 	// Get the names of the value descriptors
@@ -208,7 +208,8 @@ func UpdateValueDescriptor(from models.ValueDescriptor) error {
 			return err
 		}
 		if !match {
-			return fmt.Errorf("Value descriptor's format string doesn't match the required pattern %s ", formatSpecifier)
+			getLogger().Error(fmt.Sprintf("Value descriptor's format string doesn't match the required pattern %s ", formatSpecifier))
+			return errors.BadValueDescriptor{VName: from.Name}
 		}
 		to.Formatting = from.Formatting
 	}
@@ -232,9 +233,8 @@ func UpdateValueDescriptor(from models.ValueDescriptor) error {
 			}
 			// Value descriptor is still in use
 			if len(r) != 0 {
-				msg := fmt.Sprintf("Data integrity issue. Value Descriptor %s is referenced by existing readings.", from.Name)
-				getLogger().Error(msg)
-				return fmt.Errorf(msg)
+				getLogger().Error(fmt.Sprintf("Data integrity issue. Value Descriptor %s is referenced by existing readings.", to.Name))
+				return errors.ValueDescriptorStillInUse{}
 			}
 		}
 		to.Name = from.Name
@@ -26,6 +26,8 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/clients/metadataclients"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 // CommandClient : client to interact with core command
@@ -38,12 +40,12 @@ type CommandClient interface {
 }
 
 type CommandRestClient struct {
-	url string
+	endpoint registry.Endpoint
 }
 
 // NewCommandClient : Create an instance of CommandClient
-func NewCommandClient(commandURL string) CommandClient {
-	c := CommandRestClient{url: commandURL}
+func NewCommandClient(command registry.Endpoint) CommandClient {
+	c := CommandRestClient{endpoint: command}
 	return &c
 }
 
@@ -68,7 +70,7 @@ func (cc *CommandRestClient) DeviceByName(n string) (models.Device, error) {
 
 // Get : issue GET command
 func (cc *CommandRestClient) Get(id string, cID string) (string, error) {
-	req, err := http.NewRequest(http.MethodGet, cc.url+"/"+id+"/"+COMMAND+"/"+cID, nil)
+	req, err := http.NewRequest(http.MethodGet, cc.endpoint.Resolve()+"/"+id+"/"+COMMAND+"/"+cID, nil)
 	if err != nil {
 		fmt.Println(err.Error())
 		return "", err
@@ -94,7 +96,7 @@ func (cc *CommandRestClient) Get(id string, cID string) (string, error) {
 
 // Put : Issue PUT command
 func (cc *CommandRestClient) Put(id string, cID string, body string) (string, error) {
-	req, err := http.NewRequest(http.MethodPut, cc.url+"/"+id+"/"+COMMAND+"/"+cID, strings.NewReader(body))
+	req, err := http.NewRequest(http.MethodPut, cc.endpoint.Resolve()+"/"+id+"/"+COMMAND+"/"+cID, strings.NewReader(body))
 	if err != nil {
 		fmt.Println(err.Error())
 		return "", err
@@ -124,7 +126,7 @@ func (cc *CommandRestClient) Put(id string, cID string, body string) (string, er
 }
 
 func doReq(req *http.Request) (*http.Response, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Println(err)
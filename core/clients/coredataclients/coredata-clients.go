@@ -29,7 +29,10 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 var (
@@ -53,11 +56,11 @@ type ValueDescriptorClient interface {
 }
 
 type ValueDescriptorRestClient struct {
-	url string
+	endpoint registry.Endpoint
 }
 
-func NewValueDescriptorClient(valueDescriptorUrl string) ValueDescriptorClient {
-	v := ValueDescriptorRestClient{url: valueDescriptorUrl}
+func NewValueDescriptorClient(valueDescriptor registry.Endpoint) ValueDescriptorClient {
+	v := ValueDescriptorRestClient{endpoint: valueDescriptor}
 	return &v
 }
 
@@ -70,9 +73,20 @@ func getBody(resp *http.Response) ([]byte, error) {
 	return body, err
 }
 
+// Error of a failed request. The JSON error envelope of core data is decoded into an
+// *errs.ServiceError, any other body becomes the message of the error.
+func responseError(statusCode int, body []byte) error {
+	e := &errs.ServiceError{}
+	if err := json.Unmarshal(body, e); err != nil || e.Code == "" {
+		return errors.New(string(body))
+	}
+	e.StatusCode = statusCode
+	return e
+}
+
 // Helper method to make the request and return the response
 func makeRequest(req *http.Request) (*http.Response, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Println(err)
@@ -108,7 +122,7 @@ func (v *ValueDescriptorRestClient) decodeValueDescriptor(resp *http.Response) (
 
 // Get a list of all value descriptors
 func (v *ValueDescriptorRestClient) ValueDescriptors() ([]models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url, nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve(), nil)
 	if err != nil {
 		fmt.Println(err.Error())
 		return []models.ValueDescriptor{}, err
@@ -133,8 +147,7 @@ func (v *ValueDescriptorRestClient) ValueDescriptors() ([]models.ValueDescriptor
 			fmt.Println(err.Error())
 			return []models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-		return []models.ValueDescriptor{}, errors.New(string(bodyString))
+		return []models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return v.decodeValueDescriptorSlice(resp)
@@ -142,7 +155,7 @@ func (v *ValueDescriptorRestClient) ValueDescriptors() ([]models.ValueDescriptor
 
 // Get the value descriptor by id
 func (v *ValueDescriptorRestClient) ValueDescriptor(id string) (models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/"+id, nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.ValueDescriptor{}, err
@@ -165,9 +178,7 @@ func (v *ValueDescriptorRestClient) ValueDescriptor(id string) (models.ValueDesc
 			fmt.Println(err.Error())
 			return models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return models.ValueDescriptor{}, errors.New(bodyString)
+		return models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return v.decodeValueDescriptor(resp)
@@ -175,7 +186,7 @@ func (v *ValueDescriptorRestClient) ValueDescriptor(id string) (models.ValueDesc
 
 // Get the value descriptor by name
 func (v *ValueDescriptorRestClient) ValueDescriptorForName(name string) (models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/name/"+url.QueryEscape(name), nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/name/"+url.QueryEscape(name), nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.ValueDescriptor{}, err
@@ -198,16 +209,14 @@ func (v *ValueDescriptorRestClient) ValueDescriptorForName(name string) (models.
 			fmt.Println(err.Error())
 			return models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return models.ValueDescriptor{}, errors.New(bodyString)
+		return models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 	return v.decodeValueDescriptor(resp)
 }
 
 // Get the value descriptors by label
 func (v *ValueDescriptorRestClient) ValueDescriptorsByLabel(label string) ([]models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/label/"+url.QueryEscape(label), nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/label/"+url.QueryEscape(label), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.ValueDescriptor{}, err
@@ -230,16 +239,14 @@ func (v *ValueDescriptorRestClient) ValueDescriptorsByLabel(label string) ([]mod
 			fmt.Println(err.Error())
 			return []models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return []models.ValueDescriptor{}, errors.New(bodyString)
+		return []models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 	return v.decodeValueDescriptorSlice(resp)
 }
 
 // Get the value descriptors for a device (by id)
 func (v *ValueDescriptorRestClient) ValueDescriptorsForDevice(deviceId string) ([]models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/deviceid/"+deviceId, nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/deviceid/"+deviceId, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.ValueDescriptor{}, err
@@ -262,16 +269,14 @@ func (v *ValueDescriptorRestClient) ValueDescriptorsForDevice(deviceId string) (
 			fmt.Println(err.Error())
 			return []models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return []models.ValueDescriptor{}, errors.New(bodyString)
+		return []models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 	return v.decodeValueDescriptorSlice(resp)
 }
 
 // Get the value descriptors for a device (by name)
 func (v *ValueDescriptorRestClient) ValueDescriptorsForDeviceByName(deviceName string) ([]models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/devicename/"+deviceName, nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/devicename/"+deviceName, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.ValueDescriptor{}, err
@@ -294,16 +299,14 @@ func (v *ValueDescriptorRestClient) ValueDescriptorsForDeviceByName(deviceName s
 			fmt.Println(err.Error())
 			return []models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return []models.ValueDescriptor{}, errors.New(bodyString)
+		return []models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 	return v.decodeValueDescriptorSlice(resp)
 }
 
 // Get the value descriptors for a uomLabel
 func (v *ValueDescriptorRestClient) ValueDescriptorsByUomLabel(uomLabel string) ([]models.ValueDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, v.url+"/uomlabel/"+uomLabel, nil)
+	req, err := http.NewRequest(http.MethodGet, v.endpoint.Resolve()+"/uomlabel/"+uomLabel, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.ValueDescriptor{}, err
@@ -326,9 +329,7 @@ func (v *ValueDescriptorRestClient) ValueDescriptorsByUomLabel(uomLabel string)
 			fmt.Println(err.Error())
 			return []models.ValueDescriptor{}, err
 		}
-		bodyString := string(bodyBytes)
-
-		return []models.ValueDescriptor{}, errors.New(bodyString)
+		return []models.ValueDescriptor{}, responseError(resp.StatusCode, bodyBytes)
 	}
 	return v.decodeValueDescriptorSlice(resp)
 }
@@ -341,7 +342,7 @@ func (v *ValueDescriptorRestClient) Add(vdr *models.ValueDescriptor) (string, er
 		return "", err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, v.url, bytes.NewReader(jsonStr))
+	req, err := http.NewRequest(http.MethodPost, v.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
@@ -368,7 +369,7 @@ func (v *ValueDescriptorRestClient) Add(vdr *models.ValueDescriptor) (string, er
 	bodyString := string(bodyBytes)
 
 	if resp.StatusCode != 200 {
-		return "", errors.New(bodyString)
+		return "", responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return bodyString, nil
@@ -382,7 +383,7 @@ func (v *ValueDescriptorRestClient) Update(vdr *models.ValueDescriptor) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, v.url, bytes.NewReader(jsonStr))
+	req, err := http.NewRequest(http.MethodPut, v.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -406,9 +407,7 @@ func (v *ValueDescriptorRestClient) Update(vdr *models.ValueDescriptor) error {
 			fmt.Println(err.Error())
 			return err
 		}
-		bodyString := string(bodyBytes)
-
-		return errors.New(bodyString)
+		return responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return nil
@@ -416,7 +415,7 @@ func (v *ValueDescriptorRestClient) Update(vdr *models.ValueDescriptor) error {
 
 // Delete a value descriptor (specified by id)
 func (v *ValueDescriptorRestClient) Delete(id string) error {
-	req, err := http.NewRequest(http.MethodDelete, v.url+"/id/"+id, nil)
+	req, err := http.NewRequest(http.MethodDelete, v.endpoint.Resolve()+"/id/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -439,9 +438,7 @@ func (v *ValueDescriptorRestClient) Delete(id string) error {
 			fmt.Println(err.Error())
 			return err
 		}
-		bodyString := string(bodyBytes)
-
-		return errors.New(bodyString)
+		return responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return nil
@@ -449,7 +446,7 @@ func (v *ValueDescriptorRestClient) Delete(id string) error {
 
 // Delete a value descriptor (specified by name)
 func (v *ValueDescriptorRestClient) DeleteByName(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, v.url+"/name/"+name, nil)
+	req, err := http.NewRequest(http.MethodDelete, v.endpoint.Resolve()+"/name/"+name, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -472,10 +469,108 @@ func (v *ValueDescriptorRestClient) DeleteByName(name string) error {
 			fmt.Println(err.Error())
 			return err
 		}
-		bodyString := string(bodyBytes)
-
-		return errors.New(bodyString)
+		return responseError(resp.StatusCode, bodyBytes)
 	}
 
 	return nil
 }
+
+// Reading client for interacting with the reading section of core data
+type ReadingClient interface {
+	LatestReadingsForDevice(deviceName string) ([]models.Reading, error)
+}
+
+type ReadingRestClient struct {
+	endpoint registry.Endpoint
+}
+
+func NewReadingClient(reading registry.Endpoint) ReadingClient {
+	r := ReadingRestClient{endpoint: reading}
+	return &r
+}
+
+// Get the latest reading of every value descriptor of the device
+func (r *ReadingRestClient) LatestReadingsForDevice(deviceName string) ([]models.Reading, error) {
+	req, err := http.NewRequest(http.MethodGet, r.endpoint.Resolve()+"/latest/device/"+url.QueryEscape(deviceName), nil)
+	if err != nil {
+		fmt.Println(err)
+		return []models.Reading{}, err
+	}
+
+	resp, err := makeRequest(req)
+	if err != nil {
+		fmt.Println(err.Error())
+		return []models.Reading{}, err
+	}
+	if resp == nil {
+		fmt.Println(ErrResponseNil)
+		return []models.Reading{}, ErrResponseNil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, err := getBody(resp)
+		if err != nil {
+			fmt.Println(err.Error())
+			return []models.Reading{}, err
+		}
+		return []models.Reading{}, responseError(resp.StatusCode, bodyBytes)
+	}
+
+	readings := make([]models.Reading, 0)
+	err = json.NewDecoder(resp.Body).Decode(&readings)
+	return readings, err
+}
+
+// Event client for interacting with the event section of core data
+type EventClient interface {
+	Add(event *models.Event) (string, error)
+}
+
+type EventRestClient struct {
+	endpoint registry.Endpoint
+}
+
+func NewEventClient(event registry.Endpoint) EventClient {
+	e := EventRestClient{endpoint: event}
+	return &e
+}
+
+// Add an event, returns its id
+func (e *EventRestClient) Add(event *models.Event) (string, error) {
+	jsonStr, err := json.Marshal(event)
+	if err != nil {
+		fmt.Println(err)
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint.Resolve(), bytes.NewReader(jsonStr))
+	if err != nil {
+		fmt.Println(err)
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := makeRequest(req)
+	if err != nil {
+		fmt.Println(err)
+		return "", err
+	}
+	if resp == nil {
+		fmt.Println(ErrResponseNil)
+		return "", ErrResponseNil
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := getBody(resp)
+	if err != nil {
+		fmt.Println(err.Error())
+		return "", err
+	}
+
+	if resp.StatusCode != 200 {
+		return "", responseError(resp.StatusCode, bodyBytes)
+	}
+
+	return string(bodyBytes), nil
+}
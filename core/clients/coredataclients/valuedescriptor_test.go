@@ -19,7 +19,12 @@ package coredataclients
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 func TestGetvaluedescriptors(t *testing.T) {
@@ -33,7 +38,24 @@ func TestGetvaluedescriptors(t *testing.T) {
 var vdc ValueDescriptorClient
 
 func TestMain(m *testing.M) {
-	vdc = NewValueDescriptorClient("http://localhost:48080/api/v1/valuedescriptor")
+	vdc = NewValueDescriptorClient(registry.Endpoint{URL: "http://localhost:48080/api/v1/valuedescriptor"})
 
 	m.Run()
 }
+
+func TestErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":404,"code":"NOT_FOUND","message":"item not found","requestId":"abc123"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewValueDescriptorClient(registry.Endpoint{URL: server.URL}).ValueDescriptor("id")
+	e, ok := err.(*errs.ServiceError)
+	if !ok {
+		t.Fatalf("expected a service error, received %v", err)
+	}
+	if e.StatusCode != http.StatusNotFound || e.Code != errs.CodeNotFound || e.RequestID != "abc123" {
+		t.Errorf("unexpected error %+v", e)
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"os"
 	"testing"
 
-	"github.com/edgexfoundry/edgex-go/core/data/config"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 )
 
@@ -61,10 +60,10 @@ var dp models.DeviceProfile
 func TestMain(m *testing.M) {
 	//These are more properly integration tests
 	//TODO: Refactor using mocks for metadataclients
-	config.Configuration = &config.ConfigurationStruct{MetaDeviceURL:deviceUrl,
-													   MetaAddressableURL:addressableUrl,
-													   MetaDeviceServiceURL:deviceServiceUrl,
-													   MetaDeviceProfileURL:deviceProfileUrl}
+	SetEndpoints(Endpoints{DeviceURL: deviceUrl,
+		AddressableURL:   addressableUrl,
+		DeviceServiceURL: deviceServiceUrl,
+		DeviceProfileURL: deviceProfileUrl})
 
 	a = models.Addressable{
 		Address: "localhost",
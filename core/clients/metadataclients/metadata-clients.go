@@ -19,16 +19,20 @@ package metadataclients
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 
-	"github.com/edgexfoundry/edgex-go/core/data/config"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 var (
@@ -45,7 +49,7 @@ type AddressableClient interface {
 }
 
 type AddressableRestClient struct {
-	url string
+	endpoint registry.Endpoint
 }
 
 /*
@@ -77,7 +81,8 @@ type DeviceClient interface {
 }
 
 type DeviceRestClient struct {
-	url string
+	endpoint registry.Endpoint
+	ctx context.Context
 }
 
 /*
@@ -93,7 +98,7 @@ type CommandClient interface {
 }
 
 type CommandRestClient struct {
-	url string
+	endpoint registry.Endpoint
 }
 
 /*
@@ -107,7 +112,7 @@ type ServiceClient interface {
 }
 
 type ServiceRestClient struct {
-	url string
+	endpoint registry.Endpoint
 }
 
 // Device Profile client for interacting with the device profile section of metadata
@@ -116,7 +121,38 @@ type DeviceProfileClient interface {
 }
 
 type DeviceProfileRestClient struct {
-	url string
+	endpoint registry.Endpoint
+}
+
+/*
+Base URLs of the sections of metadata, used when the service isn't registered.
+ServiceName is the name metadata registers under.
+*/
+type Endpoints struct {
+	ServiceName      string
+	AddressableURL   string
+	DeviceURL        string
+	CommandURL       string
+	DeviceServiceURL string
+	DeviceProfileURL string
+}
+
+var endpoints Endpoints
+
+/*
+Set the endpoints of the clients, called by the service before getting any client
+*/
+func SetEndpoints(e Endpoints) {
+	endpoints = e
+	addressableClient = nil
+	deviceClient = nil
+	commandClient = nil
+	serviceClient = nil
+	deviceProfileClient = nil
+}
+
+func endpoint(url string) registry.Endpoint {
+	return registry.Endpoint{ServiceName: endpoints.ServiceName, URL: url}
 }
 
 /*
@@ -125,7 +161,7 @@ Return an instance of AddressableClient
 var addressableClient *AddressableRestClient
 func GetAddressableClient() AddressableClient {
 	if addressableClient == nil {
-		addressableClient = &AddressableRestClient{url: config.Configuration.MetaAddressableURL}
+		addressableClient = &AddressableRestClient{endpoint: endpoint(endpoints.AddressableURL)}
 	}
 	return addressableClient
 }
@@ -136,7 +172,7 @@ Return an instance of DeviceClient
 var deviceClient *DeviceRestClient //This would need to be refactored should we adopt another RPC implementation
 func GetDeviceClient() DeviceClient {
 	if deviceClient == nil {
-		deviceClient = &DeviceRestClient{url: config.Configuration.MetaDeviceURL}
+		deviceClient = &DeviceRestClient{endpoint: endpoint(endpoints.DeviceURL)}
 	}
 	return deviceClient
 }
@@ -147,7 +183,7 @@ Return an instance of CommandClient
 var commandClient *CommandRestClient
 func GetCommandClient() CommandClient {
 	if commandClient == nil {
-		commandClient = &CommandRestClient{url: config.Configuration.MetaCommandURL}
+		commandClient = &CommandRestClient{endpoint: endpoint(endpoints.CommandURL)}
 	}
 	return commandClient
 }
@@ -158,7 +194,7 @@ Return an instance of ServiceClient
 var serviceClient *ServiceRestClient
 func GetServiceClient() ServiceClient {
 	if serviceClient == nil {
-		serviceClient = &ServiceRestClient{url: config.Configuration.MetaDeviceServiceURL}
+		serviceClient = &ServiceRestClient{endpoint: endpoint(endpoints.DeviceServiceURL)}
 	}
 	return serviceClient
 }
@@ -167,14 +203,14 @@ func GetServiceClient() ServiceClient {
 var deviceProfileClient *DeviceProfileRestClient
 func GetDeviceProfileClient() DeviceProfileClient {
 	if deviceProfileClient == nil {
-		deviceProfileClient = &DeviceProfileRestClient{url: config.Configuration.MetaDeviceProfileURL}
+		deviceProfileClient = &DeviceProfileRestClient{endpoint: endpoint(endpoints.DeviceProfileURL)}
 	}
 	return deviceProfileClient
 }
 
 // Helper method to make the request and return the response
 func makeRequest(req *http.Request) (*http.Response, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: tracing.Transport(auth.Transport(nil))}
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Println(err)
@@ -205,8 +241,8 @@ func (a *AddressableRestClient) Add(addr *models.Addressable) (string, error) {
 		return "", err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Post(a.url, "application/json", bytes.NewReader(jsonStr))
+	client := &http.Client{Transport: auth.Transport(nil)}
+	resp, err := client.Post(a.endpoint.Resolve(), "application/json", bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
@@ -250,7 +286,7 @@ func (d *AddressableRestClient) decodeAddressable(resp *http.Response) (models.A
 
 // Get the addressable by name
 func (a *AddressableRestClient) AddressableForName(name string) (models.Addressable, error) {
-	req, err := http.NewRequest(http.MethodGet, a.url+"/name/"+url.QueryEscape(name), nil)
+	req, err := http.NewRequest(http.MethodGet, a.endpoint.Resolve()+"/name/"+url.QueryEscape(name), nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.Addressable{}, err
@@ -286,6 +322,20 @@ func (a *AddressableRestClient) AddressableForName(name string) (models.Addressa
 }
 
 // ***************** DEVICE CLIENT METHODS ***********************
+
+// Client passing the trace of ctx on to metadata
+func (d *DeviceRestClient) WithContext(ctx context.Context) *DeviceRestClient {
+	return &DeviceRestClient{endpoint: d.endpoint, ctx: ctx}
+}
+
+// Request made within the context of the client
+func (d *DeviceRestClient) newRequest(method, rawurl string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, rawurl, body)
+	if err != nil || d.ctx == nil {
+		return req, err
+	}
+	return req.WithContext(d.ctx), nil
+}
 // Help method to decode a device slice
 func (d *DeviceRestClient) decodeDeviceSlice(resp *http.Response) ([]models.Device, error) {
 	dec := json.NewDecoder(resp.Body)
@@ -314,7 +364,7 @@ func (d *DeviceRestClient) decodeDevice(resp *http.Response) (models.Device, err
 
 // Get the device by id
 func (d *DeviceRestClient) Device(id string) (models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/"+id, nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.Device{}, err
@@ -349,7 +399,7 @@ func (d *DeviceRestClient) Device(id string) (models.Device, error) {
 
 // Get a list of all devices
 func (d *DeviceRestClient) Devices() ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url, nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve(), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -383,7 +433,7 @@ func (d *DeviceRestClient) Devices() ([]models.Device, error) {
 
 // Get the device by name
 func (d *DeviceRestClient) DeviceForName(name string) (models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/name/"+url.QueryEscape(name), nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name), nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.Device{}, err
@@ -417,7 +467,7 @@ func (d *DeviceRestClient) DeviceForName(name string) (models.Device, error) {
 
 // Get the device by label
 func (d *DeviceRestClient) DevicesByLabel(label string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/label/"+url.QueryEscape(label), nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/label/"+url.QueryEscape(label), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -451,7 +501,7 @@ func (d *DeviceRestClient) DevicesByLabel(label string) ([]models.Device, error)
 
 // Get the devices that are on a service
 func (d *DeviceRestClient) DevicesForService(serviceId string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/service/"+serviceId, nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/service/"+serviceId, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -485,7 +535,7 @@ func (d *DeviceRestClient) DevicesForService(serviceId string) ([]models.Device,
 
 // Get the devices that are on a service(by name)
 func (d *DeviceRestClient) DevicesForServiceByName(serviceName string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/servicename/"+url.QueryEscape(serviceName), nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/servicename/"+url.QueryEscape(serviceName), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -519,7 +569,7 @@ func (d *DeviceRestClient) DevicesForServiceByName(serviceName string) ([]models
 
 // Get the devices for a profile
 func (d *DeviceRestClient) DevicesForProfile(profileId string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/profile/"+profileId, nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/profile/"+profileId, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -553,7 +603,7 @@ func (d *DeviceRestClient) DevicesForProfile(profileId string) ([]models.Device,
 
 // Get the devices for a profile (by name)
 func (d *DeviceRestClient) DevicesForProfileByName(profileName string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/profilename/"+url.QueryEscape(profileName), nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/profilename/"+url.QueryEscape(profileName), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -587,7 +637,7 @@ func (d *DeviceRestClient) DevicesForProfileByName(profileName string) ([]models
 
 // Get the devices for an addressable
 func (d *DeviceRestClient) DevicesForAddressable(addressableId string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/addressable/"+addressableId, nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/addressable/"+addressableId, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -622,7 +672,7 @@ func (d *DeviceRestClient) DevicesForAddressable(addressableId string) ([]models
 
 // Get the devices for an addressable (by name)
 func (d *DeviceRestClient) DevicesForAddressableByName(addressableName string) ([]models.Device, error) {
-	req, err := http.NewRequest(http.MethodGet, d.url+"/addressablename/"+url.QueryEscape(addressableName), nil)
+	req, err := d.newRequest(http.MethodGet, d.endpoint.Resolve()+"/addressablename/"+url.QueryEscape(addressableName), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Device{}, err
@@ -663,7 +713,7 @@ func (d *DeviceRestClient) Add(dev *models.Device) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(jsonStr))
+	req, err := d.newRequest(http.MethodPost, d.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
@@ -704,7 +754,7 @@ func (d *DeviceRestClient) Update(dev models.Device) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, d.url, bytes.NewReader(jsonStr))
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -738,7 +788,7 @@ func (d *DeviceRestClient) Update(dev models.Device) error {
 
 // Update the lastConnected value for a device (specified by id)
 func (d *DeviceRestClient) UpdateLastConnected(id string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/"+id+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/"+id+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -772,7 +822,7 @@ func (d *DeviceRestClient) UpdateLastConnected(id string, time int64) error {
 
 // Update the lastConnected value for a device (specified by name)
 func (d *DeviceRestClient) UpdateLastConnectedByName(name string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/name/"+url.QueryEscape(name)+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name)+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -806,7 +856,7 @@ func (d *DeviceRestClient) UpdateLastConnectedByName(name string, time int64) er
 
 // Update the lastReported value for a device (specified by id)
 func (d *DeviceRestClient) UpdateLastReported(id string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/"+id+"/lastreported/"+strconv.FormatInt(time, 10), nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/"+id+"/lastreported/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -840,7 +890,7 @@ func (d *DeviceRestClient) UpdateLastReported(id string, time int64) error {
 
 // Update the lastReported value for a device (specified by name)
 func (d *DeviceRestClient) UpdateLastReportedByName(name string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/name/"+url.QueryEscape(name)+"/lastreported/"+strconv.FormatInt(time, 10), nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name)+"/lastreported/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -874,7 +924,7 @@ func (d *DeviceRestClient) UpdateLastReportedByName(name string, time int64) err
 
 // Update the opState value for a device (specified by id)
 func (d *DeviceRestClient) UpdateOpState(id string, opState string) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/"+id+"/opstate/"+opState, nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/"+id+"/opstate/"+opState, nil)
 	if err != nil {
 		fmt.Println(err.Error())
 		return err
@@ -908,7 +958,7 @@ func (d *DeviceRestClient) UpdateOpState(id string, opState string) error {
 
 // Update the opState value for a device (specified by name)
 func (d *DeviceRestClient) UpdateOpStateByName(name string, opState string) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/name/"+url.QueryEscape(name)+"/opstate/"+opState, nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name)+"/opstate/"+opState, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -942,7 +992,7 @@ func (d *DeviceRestClient) UpdateOpStateByName(name string, opState string) erro
 
 // Update the adminState value for a device (specified by id)
 func (d *DeviceRestClient) UpdateAdminState(id string, adminState string) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/"+id+"/adminstate/"+adminState, nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/"+id+"/adminstate/"+adminState, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -977,7 +1027,7 @@ func (d *DeviceRestClient) UpdateAdminState(id string, adminState string) error
 
 // Update the adminState value for a device (specified by name)
 func (d *DeviceRestClient) UpdateAdminStateByName(name string, adminState string) error {
-	req, err := http.NewRequest(http.MethodPut, d.url+"/name/"+url.QueryEscape(name)+"/adminstate/"+adminState, nil)
+	req, err := d.newRequest(http.MethodPut, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name)+"/adminstate/"+adminState, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1011,7 +1061,7 @@ func (d *DeviceRestClient) UpdateAdminStateByName(name string, adminState string
 
 // Delete a device (specified by id)
 func (d *DeviceRestClient) Delete(id string) error {
-	req, err := http.NewRequest(http.MethodDelete, d.url+"/id/"+id, nil)
+	req, err := d.newRequest(http.MethodDelete, d.endpoint.Resolve()+"/id/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1045,7 +1095,7 @@ func (d *DeviceRestClient) Delete(id string) error {
 
 // Delete a device (specified by name)
 func (d *DeviceRestClient) DeleteByName(name string) error {
-	req, err := http.NewRequest(http.MethodDelete, d.url+"/name/"+url.QueryEscape(name), nil)
+	req, err := d.newRequest(http.MethodDelete, d.endpoint.Resolve()+"/name/"+url.QueryEscape(name), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1105,7 +1155,7 @@ func (c *CommandRestClient) decodeCommandSlice(resp *http.Response) ([]models.Co
 
 // Get a command by id
 func (c *CommandRestClient) Command(id string) (models.Command, error) {
-	req, err := http.NewRequest(http.MethodGet, c.url+"/"+id, nil)
+	req, err := http.NewRequest(http.MethodGet, c.endpoint.Resolve()+"/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return models.Command{}, err
@@ -1139,7 +1189,7 @@ func (c *CommandRestClient) Command(id string) (models.Command, error) {
 
 // Get a list of all the commands
 func (c *CommandRestClient) Commands() ([]models.Command, error) {
-	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	req, err := http.NewRequest(http.MethodGet, c.endpoint.Resolve(), nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Command{}, err
@@ -1172,7 +1222,7 @@ func (c *CommandRestClient) Commands() ([]models.Command, error) {
 
 // Get a list of commands for a certain name
 func (c *CommandRestClient) CommandsForName(name string) ([]models.Command, error) {
-	req, err := http.NewRequest(http.MethodGet, c.url+"/name/"+name, nil)
+	req, err := http.NewRequest(http.MethodGet, c.endpoint.Resolve()+"/name/"+name, nil)
 	if err != nil {
 		fmt.Println(err)
 		return []models.Command{}, err
@@ -1212,7 +1262,7 @@ func (c *CommandRestClient) Add(com *models.Command) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(jsonStr))
+	req, err := http.NewRequest(http.MethodPost, c.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
@@ -1252,7 +1302,7 @@ func (c *CommandRestClient) Update(com models.Command) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, c.url, bytes.NewReader(jsonStr))
+	req, err := http.NewRequest(http.MethodPut, c.endpoint.Resolve(), bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1286,7 +1336,7 @@ func (c *CommandRestClient) Update(com models.Command) error {
 
 // Delete a command
 func (c *CommandRestClient) Delete(id string) error {
-	req, err := http.NewRequest(http.MethodDelete, c.url+"/id/"+id, nil)
+	req, err := http.NewRequest(http.MethodDelete, c.endpoint.Resolve()+"/id/"+id, nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1333,7 +1383,7 @@ func (s *ServiceRestClient) decodeDeviceService(resp *http.Response) (models.Dev
 
 // Update the last connected time for the device service
 func (s *ServiceRestClient) UpdateLastConnected(id string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, s.url+"/"+id+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
+	req, err := http.NewRequest(http.MethodPut, s.endpoint.Resolve()+"/"+id+"/lastconnected/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1367,7 +1417,7 @@ func (s *ServiceRestClient) UpdateLastConnected(id string, time int64) error {
 
 // Update the last reported time for the device service
 func (s *ServiceRestClient) UpdateLastReported(id string, time int64) error {
-	req, err := http.NewRequest(http.MethodPut, s.url+"/"+id+"/lastreported/"+strconv.FormatInt(time, 10), nil)
+	req, err := http.NewRequest(http.MethodPut, s.endpoint.Resolve()+"/"+id+"/lastreported/"+strconv.FormatInt(time, 10), nil)
 	if err != nil {
 		fmt.Println(err)
 		return err
@@ -1407,8 +1457,8 @@ func (s *ServiceRestClient) Add(ds *models.DeviceService) (string, error) {
 		return "", err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Post(s.url, "application/json", bytes.NewReader(jsonStr))
+	client := &http.Client{Transport: auth.Transport(nil)}
+	resp, err := client.Post(s.endpoint.Resolve(), "application/json", bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
@@ -1437,7 +1487,7 @@ func (s *ServiceRestClient) Add(ds *models.DeviceService) (string, error) {
 
 // Request deviceservice for specified name
 func (s *ServiceRestClient) DeviceServiceForName(name string) (models.DeviceService, error) {
-	req, err := http.NewRequest(http.MethodGet, s.url+"/name/"+name, nil)
+	req, err := http.NewRequest(http.MethodGet, s.endpoint.Resolve()+"/name/"+name, nil)
 	if err != nil {
 		fmt.Printf("DeviceServiceForName NewRequest failed: %v\n", err)
 		return models.DeviceService{}, err
@@ -1479,8 +1529,8 @@ func (dpc *DeviceProfileRestClient) Add(dp *models.DeviceProfile) (string, error
 		return "", err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Post(dpc.url, "application/json", bytes.NewReader(jsonStr))
+	client := &http.Client{Transport: auth.Transport(nil)}
+	resp, err := client.Post(dpc.endpoint.Resolve(), "application/json", bytes.NewReader(jsonStr))
 	if err != nil {
 		fmt.Println(err)
 		return "", err
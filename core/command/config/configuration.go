@@ -17,28 +17,41 @@
  *******************************************************************************/
 package config
 
-// ConfigurationStruct : Struct used to pase the JSON configuration file
+// ConfigurationStruct : Struct used to pase the JSON configuration file.
+// Fields tagged validate are checked when the configuration is loaded, see pkg/config.Validate
 type ConfigurationStruct struct {
-	ApplicationName           string
+	ApplicationName           string `validate:"required"`
 	ConsulProfilesActive      string
 	ReadMaxLimit              int
-	ServicePort               int
+	ServicePort               int    `validate:"min=1,max=65535"`
 	HeartBeatTime             int
-	ConsulPort                int
-	ServiceTimeout            int
+	ConsulPort                int    `validate:"min=1,max=65535"`
+	ServiceTimeout            int    `validate:"min=0"`
 	CheckInterval             string
 	ServiceAddress            string
 	ServiceName               string
 	DeviceServiceProtocol     string
 	HeartBeatMsg              string
+	HeartBeatSink             string // log, support-logging, metrics or bus, see pkg/heartbeat
+	HeartBeatURL              string
 	AppOpenMsg                string
 	URLProtocol               string
 	URLDevicePath             string
 	ConsulHost                string
 	ConsulCheckAddress        string
+	RegistryType              string // consul or file, see support/registry
+	RegistryDirectory         string
+	AuthEnabled               bool
+	AuthAPIKeys               string
+	AuthJWTSecret             string
+	AuthJWTPublicKeyFile      string
+	AuthScopes                string
+	AuthClientAPIKey          string
+	AuthClientToken           string
 	EnableRemoteLogging       bool
 	LogFile                   string
 	LoggingRemoteURL          string
+	MetaServiceName           string
 	MetaAddressableURL        string
 	MetaDeviceServiceURL      string
 	MetaDeviceProfileURL      string
@@ -48,6 +61,12 @@ type ConfigurationStruct struct {
 	MetaEventURL              string
 	MetaScheduleURL           string
 	MetaProvisionWatcherURL   string
+	MetaPingURL               string
+	DataServiceName           string
+	DataReadingURL            string
+	SupportNotificationsNotificationURL string
+	TwinReconcileInterval     int
+	TwinReconcileTimeout      int
 }
 
 // Configuration data for the metadata service
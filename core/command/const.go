@@ -64,6 +64,9 @@ const (
 	COMMAND                  string = "command"
 	COMMANDID                string = "commandid"
 	DEVICE                   string = "device"
+	TWIN                     string = "twin"
+	DESIRED                  string = "desired"
+	DRIFT                    string = "drift"
 	OPERATINGSTATE           string = "operatingState"
 	PROVISIONWATCHER         string = "provisionwatcher"
 	IDENTIFIER               string = "identifier"
@@ -74,6 +77,9 @@ const (
 	DEVICEADDRESSABLES              = "deviceaddressables"
 	DEVICEADDRESSABLESBYNAME        = "deviceaddressablesbyname"
 	PINGENDPOINT                    = "/ping"
+	METRICSENDPOINT                 = "/metrics"
+	HEALTHLIVEENDPOINT              = "/health/live"
+	HEALTHREADYENDPOINT             = "/health/ready"
 	PINGRESPONSE                    = "pong"
 	CONTENTTYPE                     = "Content-Type"
 	TEXTPLAIN                       = "text/plain"
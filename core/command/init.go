@@ -20,40 +20,119 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/edgexfoundry/edgex-go/core/clients/coredataclients"
+	"github.com/edgexfoundry/edgex-go/core/clients/metadataclients"
 	"github.com/edgexfoundry/edgex-go/core/command/config"
-	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
 	logger "github.com/edgexfoundry/edgex-go/support/logging-client"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 var loggingClient logger.LoggingClient
+var authenticator auth.Authenticator
+var policy = authPolicy()
+var twins *twinReconciler
 
-func ConnectToConsul(conf config.ConfigurationStruct) error {
-
-	// Initialize service on Consul
-	err := consulclient.ConsulInit(consulclient.ConsulConfig{
-		ServiceName:    conf.ServiceName,
-		ServicePort:    conf.ServicePort,
-		ServiceAddress: conf.ServiceAddress,
-		CheckAddress:   conf.ConsulCheckAddress,
-		CheckInterval:  conf.CheckInterval,
-		ConsulAddress:  conf.ConsulHost,
-		ConsulPort:     conf.ConsulPort,
+// Connect to the configured registry, register the service and update the configuration
+// from its key/value pairs
+func ConnectToRegistry(conf *config.ConfigurationStruct) error {
+	r, err := registry.Connect(registry.Config{
+		Type:       conf.RegistryType,
+		ConsulHost: conf.ConsulHost,
+		ConsulPort: conf.ConsulPort,
+		Directory:  conf.RegistryDirectory,
 	})
+	if err != nil {
+		return fmt.Errorf("connection to the registry could not be made: %v", err.Error())
+	}
 
+	// Initialize service on the registry
+	err = r.Register(registry.Service{
+		Name:          conf.ServiceName,
+		Address:       conf.ServiceAddress,
+		Port:          conf.ServicePort,
+		CheckAddress:  conf.ConsulCheckAddress,
+		CheckInterval: conf.CheckInterval,
+	})
 	if err != nil {
-		return fmt.Errorf("connection to Consul could not be made: %v", err.Error())
-	} else {
-		// Update configuration data from Consul
-		if err := consulclient.CheckKeyValuePairs(&conf, conf.ApplicationName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
-			return fmt.Errorf("error getting key/values from Consul: %v", err.Error())
-		}
+		return fmt.Errorf("registration of the service could not be made: %v", err.Error())
+	}
+
+	// Update configuration data from the registry
+	if err := r.CheckKeyValuePairs(conf, conf.ApplicationName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
+		return fmt.Errorf("error getting key/values from the registry: %v", err.Error())
 	}
 	return nil
 }
 
-func Init(conf *config.ConfigurationStruct, l logger.LoggingClient) {
+func Init(conf *config.ConfigurationStruct, l logger.LoggingClient) error {
 	loggingClient = l
 	//TODO: The above is set due to global scope throughout the package. How can this be eliminated / refactored?
 	config.Configuration = conf
+
+	// Metadata and core data are resolved through the registry when it is used, the configured URLs otherwise
+	metadataclients.SetEndpoints(metadataclients.Endpoints{
+		ServiceName:      conf.MetaServiceName,
+		AddressableURL:   conf.MetaAddressableURL,
+		DeviceURL:        conf.MetaDeviceURL,
+		CommandURL:       conf.MetaCommandURL,
+		DeviceServiceURL: conf.MetaDeviceServiceURL,
+		DeviceProfileURL: conf.MetaDeviceProfileURL,
+	})
+	health.Register("core-metadata", health.ResolvedURLCheck(
+		registry.Endpoint{ServiceName: conf.MetaServiceName, URL: conf.MetaPingURL}.Resolve))
+
+	var err error
+	authenticator, err = auth.Setup(auth.Config{
+		Enabled:          conf.AuthEnabled,
+		APIKeys:          conf.AuthAPIKeys,
+		JWTSecret:        conf.AuthJWTSecret,
+		JWTPublicKeyFile: conf.AuthJWTPublicKeyFile,
+		Scopes:           conf.AuthScopes,
+	}, policy)
+	if err != nil {
+		return fmt.Errorf("could not initialize authentication: %v", err.Error())
+	}
+
+	twins = newTwinReconciler(conf)
+	return nil
+}
+
+// Reconcile the device twins in the background until shutdown
+func RegisterLifecycle(svc *lifecycle.Service) {
+	svc.Go(twins.run)
+}
+
+func newTwinReconciler(conf *config.ConfigurationStruct) *twinReconciler {
+	readingClient := coredataclients.NewReadingClient(registry.Endpoint{ServiceName: conf.DataServiceName, URL: conf.DataReadingURL})
+	notificationsClient := notifications.NotificationsClient{
+		RemoteUrl:     conf.SupportNotificationsNotificationURL,
+		OwningService: COMMANDSERVICENAME,
+	}
+	return &twinReconciler{
+		store:    newTwinStore(),
+		interval: time.Millisecond * time.Duration(conf.TwinReconcileInterval),
+		timeout:  time.Millisecond * time.Duration(conf.TwinReconcileTimeout),
+		reported: func(deviceName string) (map[string]string, error) {
+			readings, err := readingClient.LatestReadingsForDevice(deviceName)
+			if err != nil {
+				return nil, err
+			}
+			reported := make(map[string]string, len(readings))
+			for _, r := range readings {
+				reported[r.Name] = r.Value
+			}
+			return reported, nil
+		},
+		put: func(deviceID string, commandID string, body string) (string, int) {
+			return commandByDeviceID(deviceID, commandID, body, true)
+		},
+		notify: notificationsClient.RecieveNotification,
+		now:    time.Now,
+	}
 }
@@ -20,18 +20,39 @@ package command
 import (
 	"net/http"
 
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
 	mux "github.com/gorilla/mux"
 )
 
 func LoadRestRoutes() http.Handler {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware(metrics.GorillaRoute))
+	r.Use(auth.Middleware(authenticator, policy))
 	b := r.PathPrefix("/api/v1").Subrouter()
 	b.HandleFunc(PINGENDPOINT, ping)
+	b.Handle(METRICSENDPOINT, metrics.Handler()).Methods(http.MethodGet)
+	b.Handle(HEALTHLIVEENDPOINT, health.LiveHandler()).Methods(http.MethodGet)
+	b.Handle(HEALTHREADYENDPOINT, health.ReadyHandler()).Methods(http.MethodGet)
 
 	loadDeviceRoutes(b)
+	loadTwinRoutes(b)
 	return r
 }
 
+// Scopes required by the command routes: reads need read, commands need write
+// and admin state changes need admin. Changing the desired state of a twin issues commands.
+// The configuration can add to or override them (AuthScopes).
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().
+		Public("/api/v1"+PINGENDPOINT, "/api/v1"+HEALTHLIVEENDPOINT, "/api/v1"+HEALTHREADYENDPOINT).
+		Route(http.MethodPut, "/api/v1/"+TWIN+"/{"+ID+"}/"+DESIRED, auth.ScopeWrite).
+		Route(http.MethodDelete, "/api/v1/"+TWIN+"/{"+ID+"}", auth.ScopeWrite).
+		Route(http.MethodPut, "/api/v1/"+DEVICE+"/{"+ID+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/"+DEVICE+"/"+NAME+"/{"+NAME+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin)
+}
+
 func loadDeviceRoutes(b *mux.Router) {
 
 	b.HandleFunc("/device", restGetAllCommands).Methods(http.MethodGet)
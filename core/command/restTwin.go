@@ -0,0 +1,114 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-command-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/core/clients/metadataclients"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	mux "github.com/gorilla/mux"
+)
+
+func loadTwinRoutes(b *mux.Router) {
+	t := b.PathPrefix("/" + TWIN).Subrouter()
+
+	// /api/<version>/twin
+	t.HandleFunc("/{"+ID+"}", restGetTwin).Methods(http.MethodGet)
+	t.HandleFunc("/{"+ID+"}", restDeleteTwin).Methods(http.MethodDelete)
+	t.HandleFunc("/{"+ID+"}/"+DESIRED, restPutTwinDesired).Methods(http.MethodPut)
+	t.HandleFunc("/{"+ID+"}/"+DRIFT, restGetTwinDrift).Methods(http.MethodGet)
+}
+
+// Device of the twin, a 404 is written when it doesn't exist
+func twinDevice(w http.ResponseWriter, r *http.Request) (models.Device, bool) {
+	d, err := metadataclients.GetDeviceClient().Device(mux.Vars(r)[ID])
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, "Device not found", http.StatusNotFound)
+		return models.Device{}, false
+	}
+	return d, true
+}
+
+// Twin of the device with its current reported state, a 503 is written when core data
+// can't be reached
+func currentTwin(w http.ResponseWriter, r *http.Request) (Twin, bool) {
+	d, ok := twinDevice(w, r)
+	if !ok {
+		return Twin{}, false
+	}
+	t, err := twins.twin(d)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return Twin{}, false
+	}
+	return t, true
+}
+
+func restGetTwin(w http.ResponseWriter, r *http.Request) {
+	t, ok := currentTwin(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&t)
+}
+
+func restGetTwinDrift(w http.ResponseWriter, r *http.Request) {
+	t, ok := currentTwin(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t.Drift())
+}
+
+// Set the desired state of device resources, the body maps resources to their desired value
+func restPutTwinDesired(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var values map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d, ok := twinDevice(w, r)
+	if !ok {
+		return
+	}
+	if err := twins.setDesired(d, values); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("true"))
+}
+
+// Forget the desired state of the device, reconciliation stops
+func restDeleteTwin(w http.ResponseWriter, r *http.Request) {
+	if !twins.store.delete(mux.Vars(r)[ID]) {
+		http.Error(w, "Twin not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("true"))
+}
@@ -0,0 +1,322 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-command-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+)
+
+// Reconciliation status of a desired value
+const (
+	TWINPENDING = "PENDING"
+	TWINSYNCED  = "SYNCED"
+	TWINFAILED  = "FAILED"
+)
+
+// Desired value of a device resource and the progress of its reconciliation
+type TwinProperty struct {
+	Desired     string `json:"desired"`
+	CommandID   string `json:"commandId"` // Put command setting the resource
+	Status      string `json:"status"`
+	Attempts    int    `json:"attempts"`
+	Requested   int64  `json:"requested"` // When the desired value was set
+	LastAttempt int64  `json:"lastAttempt,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Reported and desired state of a device. The reported state is the latest reading of
+// every resource of the device in core data.
+type Twin struct {
+	DeviceID   string                  `json:"deviceId"`
+	DeviceName string                  `json:"deviceName"`
+	Reported   map[string]string       `json:"reported"`
+	Desired    map[string]TwinProperty `json:"desired"`
+}
+
+// Resource whose reported value differs from the desired one
+type TwinDrift struct {
+	Resource string `json:"resource"`
+	Desired  string `json:"desired"`
+	Reported string `json:"reported,omitempty"`
+	Status   string `json:"status"`
+}
+
+// Drift of the twin sorted by resource
+func (t Twin) Drift() []TwinDrift {
+	drift := []TwinDrift{}
+	for resource, p := range t.Desired {
+		if reported, ok := t.Reported[resource]; !ok || reported != p.Desired {
+			drift = append(drift, TwinDrift{Resource: resource, Desired: p.Desired, Reported: reported, Status: p.Status})
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Resource < drift[j].Resource })
+	return drift
+}
+
+// Desired state of the devices, kept in memory only: a restart of core command drops it,
+// pending values included, and the clients have to set them again
+type twinStore struct {
+	mutex sync.Mutex
+	twins map[string]*Twin
+}
+
+func newTwinStore() *twinStore {
+	return &twinStore{twins: make(map[string]*Twin)}
+}
+
+// Copy of the desired state of the device, false if none was set
+func (s *twinStore) get(deviceID string) (Twin, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t, ok := s.twins[deviceID]
+	if !ok {
+		return Twin{}, false
+	}
+	return copyTwin(t), true
+}
+
+// Copies of the twins with pending desired values
+func (s *twinStore) pending() []Twin {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var twins []Twin
+	for _, t := range s.twins {
+		for _, p := range t.Desired {
+			if p.Status == TWINPENDING {
+				twins = append(twins, copyTwin(t))
+				break
+			}
+		}
+	}
+	return twins
+}
+
+// Set desired values of the device, they replace the previous ones of the same resources
+func (s *twinStore) setDesired(device models.Device, desired map[string]TwinProperty) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t, ok := s.twins[device.Id.Hex()]
+	if !ok {
+		t = &Twin{DeviceID: device.Id.Hex(), Desired: make(map[string]TwinProperty)}
+		s.twins[device.Id.Hex()] = t
+	}
+	t.DeviceName = device.Name
+	for resource, p := range desired {
+		t.Desired[resource] = p
+	}
+}
+
+// Apply the outcome of a reconciliation to a desired value, unless it was replaced meanwhile
+func (s *twinStore) update(deviceID string, resource string, requested int64, fn func(p *TwinProperty)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t, ok := s.twins[deviceID]
+	if !ok {
+		return
+	}
+	p, ok := t.Desired[resource]
+	if !ok || p.Requested != requested {
+		return
+	}
+	fn(&p)
+	t.Desired[resource] = p
+}
+
+func (s *twinStore) delete(deviceID string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.twins[deviceID]
+	delete(s.twins, deviceID)
+	return ok
+}
+
+func copyTwin(t *Twin) Twin {
+	c := Twin{DeviceID: t.DeviceID, DeviceName: t.DeviceName, Desired: make(map[string]TwinProperty, len(t.Desired))}
+	for resource, p := range t.Desired {
+		c.Desired[resource] = p
+	}
+	return c
+}
+
+// Put command of the device profile setting the resource, false if none sets it
+func putCommandFor(device models.Device, resource string) (models.Command, bool) {
+	for _, c := range device.Profile.Commands {
+		if c.Put == nil {
+			continue
+		}
+		for _, name := range c.Put.ParameterNames {
+			if name == resource {
+				return c, true
+			}
+		}
+	}
+	return models.Command{}, false
+}
+
+// Issues Put commands until the reported state of the devices matches their desired state,
+// or the reconciliation of a desired value times out
+type twinReconciler struct {
+	store    *twinStore
+	interval time.Duration
+	timeout  time.Duration
+	// Latest value of every resource of the device
+	reported func(deviceName string) (map[string]string, error)
+	// Issue a Put command, as commandByDeviceID
+	put    func(deviceID string, commandID string, body string) (string, int)
+	notify func(n notifications.Notification) error
+	now    func() time.Time
+}
+
+func (r *twinReconciler) millis() int64 {
+	return r.now().UnixNano() / int64(time.Millisecond)
+}
+
+// Reconcile the pending desired values every interval until stop is closed
+func (r *twinReconciler) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reconcile()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Make one reconciliation pass over the devices with pending desired values
+func (r *twinReconciler) reconcile() {
+	for _, t := range r.store.pending() {
+		r.reconcileTwin(t)
+	}
+}
+
+func (r *twinReconciler) reconcileTwin(t Twin) {
+	reported, err := r.reported(t.DeviceName)
+	if err != nil {
+		// Without reported state nothing is known to be synced yet, the commands are retried
+		loggingClient.Error(fmt.Sprintf("twin of %s: error getting the reported state: %v", t.DeviceName, err))
+		reported = map[string]string{}
+	}
+
+	now := r.millis()
+	// Desired values still to be set, grouped by command
+	commands := make(map[string]map[string]string)
+	for resource, p := range t.Desired {
+		if p.Status != TWINPENDING {
+			continue
+		}
+		switch {
+		case reported[resource] == p.Desired:
+			r.store.update(t.DeviceID, resource, p.Requested, func(p *TwinProperty) {
+				p.Status = TWINSYNCED
+				p.Error = ""
+			})
+		case now-p.Requested > int64(r.timeout/time.Millisecond):
+			r.store.update(t.DeviceID, resource, p.Requested, func(p *TwinProperty) {
+				p.Status = TWINFAILED
+			})
+			r.notifyFailure(t, resource, p)
+		default:
+			if commands[p.CommandID] == nil {
+				commands[p.CommandID] = make(map[string]string)
+			}
+			commands[p.CommandID][resource] = p.Desired
+		}
+	}
+
+	for commandID, values := range commands {
+		body, _ := json.Marshal(values)
+		_, status := r.put(t.DeviceID, commandID, string(body))
+		for resource := range values {
+			r.store.update(t.DeviceID, resource, t.Desired[resource].Requested, func(p *TwinProperty) {
+				p.Attempts++
+				p.LastAttempt = now
+				p.Error = ""
+				if status != http.StatusOK {
+					p.Error = "put command failed with status " + strconv.Itoa(status)
+				}
+			})
+		}
+	}
+}
+
+func (r *twinReconciler) notifyFailure(t Twin, resource string, p TwinProperty) {
+	msg := fmt.Sprintf("%s of device %s did not reach the desired value %q after %d attempts", resource, t.DeviceName, p.Desired, p.Attempts)
+	if p.Error != "" {
+		msg += ": " + p.Error
+	}
+	loggingClient.Warn("twin reconciliation failed: " + msg)
+
+	err := r.notify(notifications.Notification{
+		Slug:        "twin-reconciliation-" + t.DeviceName + "-" + strconv.FormatInt(r.millis(), 10),
+		Sender:      COMMANDSERVICENAME,
+		Category:    notifications.HW_HEALTH,
+		Severity:    notifications.CRITICAL,
+		Content:     msg,
+		Description: "Device twin reconciliation failure",
+		Labels:      []string{TWIN, t.DeviceName},
+	})
+	if err != nil {
+		loggingClient.Error("error sending the twin reconciliation notification: " + err.Error())
+	}
+}
+
+// Twin of the device with its current reported state
+func (r *twinReconciler) twin(d models.Device) (Twin, error) {
+	t, ok := r.store.get(d.Id.Hex())
+	if !ok {
+		t = Twin{DeviceID: d.Id.Hex(), DeviceName: d.Name, Desired: make(map[string]TwinProperty)}
+	}
+	reported, err := r.reported(d.Name)
+	if err != nil {
+		return Twin{}, err
+	}
+	t.Reported = reported
+	return t, nil
+}
+
+// Set the desired values of the device resources, the values are reconciled from the next pass.
+// Every resource must be a parameter of a Put command of the device profile.
+func (r *twinReconciler) setDesired(d models.Device, values map[string]string) error {
+	now := r.millis()
+	desired := make(map[string]TwinProperty, len(values))
+	for resource, value := range values {
+		c, ok := putCommandFor(d, resource)
+		if !ok {
+			return fmt.Errorf("no put command of device %s sets %s", d.Name, resource)
+		}
+		desired[resource] = TwinProperty{Desired: value, CommandID: c.Id.Hex(), Status: TWINPENDING, Requested: now}
+	}
+	r.store.setDesired(d, desired)
+	return nil
+}
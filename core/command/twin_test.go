@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-command-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package command
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	logger "github.com/edgexfoundry/edgex-go/support/logging-client"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Device whose profile sets Speed and Direction through one Put command
+func twinTestDevice() models.Device {
+	return models.Device{
+		Id:   bson.NewObjectId(),
+		Name: "fan",
+		Profile: models.DeviceProfile{Commands: []models.Command{
+			{Id: bson.NewObjectId(), Name: "status", Get: &models.Get{}},
+			{Id: bson.NewObjectId(), Name: "motor", Put: &models.Put{ParameterNames: []string{"Speed", "Direction"}}},
+		}},
+	}
+}
+
+type twinFakes struct {
+	reported      map[string]string
+	status        int
+	puts          []string
+	notifications []notifications.Notification
+	now           time.Time
+}
+
+func newTestReconciler(f *twinFakes) *twinReconciler {
+	loggingClient = logger.NewMockClient()
+	return &twinReconciler{
+		store:    newTwinStore(),
+		interval: time.Second,
+		timeout:  time.Minute,
+		reported: func(string) (map[string]string, error) { return f.reported, nil },
+		put: func(_ string, _ string, body string) (string, int) {
+			f.puts = append(f.puts, body)
+			return "", f.status
+		},
+		notify: func(n notifications.Notification) error {
+			f.notifications = append(f.notifications, n)
+			return nil
+		},
+		now: func() time.Time { return f.now },
+	}
+}
+
+func TestTwinReconcile(t *testing.T) {
+	f := &twinFakes{reported: map[string]string{"Speed": "1", "Direction": "left"}, status: http.StatusOK, now: time.Now()}
+	r := newTestReconciler(f)
+	d := twinTestDevice()
+
+	if err := r.setDesired(d, map[string]string{"Speed": "3", "Direction": "left"}); err != nil {
+		t.Fatal(err)
+	}
+	r.reconcile()
+	if len(f.puts) != 1 || f.puts[0] != `{"Speed":"3"}` {
+		t.Fatalf("unexpected put commands %v", f.puts)
+	}
+	twin, _ := r.twin(d)
+	if twin.Desired["Direction"].Status != TWINSYNCED || twin.Desired["Speed"].Status != TWINPENDING {
+		t.Errorf("unexpected desired state %v", twin.Desired)
+	}
+	if drift := twin.Drift(); len(drift) != 1 || drift[0].Resource != "Speed" || drift[0].Reported != "1" {
+		t.Errorf("unexpected drift %v", drift)
+	}
+
+	// The device reports the desired value, no more command is issued
+	f.reported["Speed"] = "3"
+	r.reconcile()
+	twin, _ = r.twin(d)
+	if len(f.puts) != 1 || twin.Desired["Speed"].Status != TWINSYNCED || twin.Desired["Speed"].Attempts != 1 {
+		t.Errorf("unexpected state %v after puts %v", twin.Desired, f.puts)
+	}
+	if len(twin.Drift()) != 0 || len(f.notifications) != 0 {
+		t.Errorf("unexpected drift %v or notifications %v", twin.Drift(), f.notifications)
+	}
+}
+
+func TestTwinReconcileTimeout(t *testing.T) {
+	f := &twinFakes{reported: map[string]string{"Speed": "1"}, status: http.StatusBadGateway, now: time.Now()}
+	r := newTestReconciler(f)
+	d := twinTestDevice()
+
+	r.setDesired(d, map[string]string{"Speed": "3"})
+	r.reconcile()
+	twin, _ := r.twin(d)
+	if p := twin.Desired["Speed"]; p.Status != TWINPENDING || p.Attempts != 1 || p.Error == "" {
+		t.Errorf("unexpected desired state %v", p)
+	}
+
+	f.now = f.now.Add(2 * time.Minute)
+	r.reconcile()
+	twin, _ = r.twin(d)
+	if p := twin.Desired["Speed"]; p.Status != TWINFAILED {
+		t.Errorf("unexpected desired state %v", p)
+	}
+	if len(f.puts) != 1 || len(f.notifications) != 1 || f.notifications[0].Severity != notifications.CRITICAL {
+		t.Errorf("unexpected puts %v or notifications %v", f.puts, f.notifications)
+	}
+
+	// A failed value is not retried
+	r.reconcile()
+	if len(f.puts) != 1 || len(f.notifications) != 1 {
+		t.Errorf("unexpected puts %v or notifications %v", f.puts, f.notifications)
+	}
+}
+
+func TestTwinSetDesiredUnknownResource(t *testing.T) {
+	r := newTestReconciler(&twinFakes{now: time.Now()})
+	d := twinTestDevice()
+
+	if err := r.setDesired(d, map[string]string{"Speed": "3", "Color": "red"}); err == nil {
+		t.Error("expected an error for a resource no put command sets")
+	}
+	if _, ok := r.store.get(d.Id.Hex()); ok {
+		t.Error("no desired value should be set")
+	}
+}
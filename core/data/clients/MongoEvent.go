@@ -38,15 +38,17 @@ func (me MongoEvent) GetBSON() (interface{}, error) {
 	}
 
 	return struct {
-		ID       bson.ObjectId `bson:"_id,omitempty"`
-		Pushed   int64         `bson:"pushed"`
-		Device   string        `bson:"device"` // Device identifier (name or id)
-		Created  int64         `bson:"created"`
-		Modified int64         `bson:"modified"`
-		Origin   int64         `bson:"origin"`
-		Schedule string        `bson:"schedule,omitempty"` // Schedule identifier
-		Event    string        `bson:"event"`              // Schedule event identifier
-		Readings []mgo.DBRef   `bson:"readings"`           // List of readings
+		ID       bson.ObjectId    `bson:"_id,omitempty"`
+		Pushed   int64            `bson:"pushed"`
+		Device   string           `bson:"device"` // Device identifier (name or id)
+		Created  int64            `bson:"created"`
+		Modified int64            `bson:"modified"`
+		Origin   int64            `bson:"origin"`
+		Schedule string           `bson:"schedule,omitempty"` // Schedule identifier
+		Event    string           `bson:"event"`              // Schedule event identifier
+		Readings []mgo.DBRef      `bson:"readings"`           // List of readings
+		Tags     models.Tags      `bson:"tags,omitempty"`
+		Location *models.GeoPoint `bson:"location,omitempty"`
 	}{
 		ID:       me.ID,
 		Pushed:   me.Pushed,
@@ -57,21 +59,25 @@ func (me MongoEvent) GetBSON() (interface{}, error) {
 		Schedule: me.Schedule,
 		Event:    me.Event.Event,
 		Readings: readings,
+		Tags:     me.Tags,
+		Location: me.Location,
 	}, nil
 }
 
 // Custom unmarshaling out of mongo
 func (me *MongoEvent) SetBSON(raw bson.Raw) error {
 	decoded := new(struct {
-		ID       bson.ObjectId `bson:"_id,omitempty"`
-		Pushed   int64         `bson:"pushed"`
-		Device   string        `bson:"device"` // Device identifier (name or id)
-		Created  int64         `bson:"created"`
-		Modified int64         `bson:"modified"`
-		Origin   int64         `bson:"origin"`
-		Schedule string        `bson:"schedule,omitempty"` // Schedule identifier
-		Event    string        `bson:"event"`              // Schedule event identifier
-		Readings []mgo.DBRef   `bson:"readings"`           // List of readings
+		ID       bson.ObjectId    `bson:"_id,omitempty"`
+		Pushed   int64            `bson:"pushed"`
+		Device   string           `bson:"device"` // Device identifier (name or id)
+		Created  int64            `bson:"created"`
+		Modified int64            `bson:"modified"`
+		Origin   int64            `bson:"origin"`
+		Schedule string           `bson:"schedule,omitempty"` // Schedule identifier
+		Event    string           `bson:"event"`              // Schedule event identifier
+		Readings []mgo.DBRef      `bson:"readings"`           // List of readings
+		Tags     models.Tags      `bson:"tags,omitempty"`
+		Location *models.GeoPoint `bson:"location,omitempty"`
 	})
 
 	bsonErr := raw.Unmarshal(decoded)
@@ -88,6 +94,8 @@ func (me *MongoEvent) SetBSON(raw bson.Raw) error {
 	me.Origin = decoded.Origin
 	me.Schedule = decoded.Schedule
 	me.Event.Event = decoded.Event
+	me.Tags = decoded.Tags
+	me.Location = decoded.Location
 
 	// De-reference the DBRef fields
 	mc, err := getCurrentMongoClient()
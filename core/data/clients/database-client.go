@@ -33,6 +33,14 @@ const (
 )
 
 type DBClient interface {
+	// Check that the database answers
+	// Used by the readiness endpoint
+	Ping() error
+
+	// Close the connection to the database
+	// Used on shutdown
+	CloseSession()
+
 	// ********************** EVENT FUNCTIONS *******************************
 	// Return all the events
 	// UnexpectedError - failed to retrieve events from the database
@@ -77,6 +85,28 @@ type DBClient interface {
 	// Delete all of the events by the device id (and the readings)
 	//DeleteEventsByDeviceId(id string) error
 
+	// Get the events carrying the tag key with the value
+	EventsByTag(key, value string) ([]models.Event, error)
+
+	// Return the events collected by the schedule whose creation time is between startTime
+	// and endTime (inclusive). Limit the number of results by limit
+	EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error)
+
+	// Return the events collected by the schedule event whose creation time is between startTime
+	// and endTime (inclusive). Limit the number of results by limit
+	EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error)
+
+	// Return the events located within radius meters of the point, nearest first
+	// Limit the number of results by limit
+	EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error)
+
+	// Return the events located within the polygon
+	// Limit the number of results by limit
+	EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error)
+
+	// Return the latest position of every device that sent located events, sorted by device
+	LatestDevicePositions() ([]models.DevicePosition, error)
+
 	// Return a list of events whos creation time is between startTime and endTime
 	// Limit the number of results by limit
 	EventsByCreationTime(startTime, endTime int64, limit int) ([]models.Event, error)
@@ -149,6 +179,9 @@ type DBClient interface {
 	// Return a list of readings whos created time is between the start and end times
 	ReadingsByCreationTime(start, end int64, limit int) ([]models.Reading, error)
 
+	// Return the most recently created reading of every value descriptor of every device
+	LatestReadings() ([]models.Reading, error)
+
 	// ************************** VALUE DESCRIPTOR FUNCTIONS ***************************
 	// Add a value descriptor
 	// 409 - Formatting is bad or it is not unique
@@ -189,6 +222,30 @@ type DBClient interface {
 
 	// Return a list of value descriptors based on their type
 	ValueDescriptorsByType(t string) ([]models.ValueDescriptor, error)
+
+	// ******************************* ALERT RULE FUNCTIONS ****************************
+	// Add an alert rule
+	// ErrNotUnique if the name is already used
+	AddAlertRule(r models.AlertRule) (bson.ObjectId, error)
+
+	// Return all the alert rules, an empty list if there is none
+	AlertRules() ([]models.AlertRule, error)
+
+	// Return an alert rule based on the id
+	// ErrNotFound if there is no alert rule for the id
+	AlertRuleById(id string) (models.AlertRule, error)
+
+	// Return an alert rule based on the name
+	// ErrNotFound if there is no alert rule for the name
+	AlertRuleByName(name string) (models.AlertRule, error)
+
+	// Update an alert rule identified by its id
+	// ErrNotUnique if the name is used by another alert rule
+	// ErrNotFound if there is no alert rule for the id
+	UpdateAlertRule(r models.AlertRule) error
+
+	// Delete an alert rule based on the id
+	DeleteAlertRuleById(id string) error
 }
 
 type DBConfiguration struct {
@@ -214,8 +271,8 @@ func NewDBClient(config DBConfiguration) (DBClient, error) {
 			fmt.Println("Error creating the mongo client: " + err.Error())
 			return nil, err
 		}
-		CurrentClient = mc
-		return mc, nil
+		CurrentClient = newTracingClient(newMetricsClient(mc))
+		return CurrentClient, nil
 	case INFLUX:
 		// Create the influx client
 		ic, err := newInfluxClient(config)
@@ -223,13 +280,13 @@ func NewDBClient(config DBConfiguration) (DBClient, error) {
 			fmt.Println("Error creating the influx client: " + err.Error())
 			return nil, err
 		}
-		CurrentClient = ic
-		return ic, nil
+		CurrentClient = newTracingClient(newMetricsClient(ic))
+		return CurrentClient, nil
 	case MOCK:
 		//Create the mock client
 		mock := &MockDb{}
-		CurrentClient = mock
-		return mock, nil
+		CurrentClient = newTracingClient(newMetricsClient(mock))
+		return CurrentClient, nil
 	default:
 		return nil, ErrUnsupportedDatabase
 	}
@@ -26,10 +26,15 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"gopkg.in/mgo.v2/bson"
 	"github.com/influxdata/influxdb/client/v2"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Prefix of the influx tags holding the tags of events and readings
+const influxTagPrefix = "tag_"
+
 var currentInfluxClient *InfluxClient // Singleton used so that InfluxEvent can use it to de-reference readings
 
 /*
@@ -72,6 +77,17 @@ func getCurrentInfluxClient() (*InfluxClient, error) {
 	return currentInfluxClient, nil
 }
 
+// Check that the influx server answers
+func (ic *InfluxClient) Ping() error {
+	_, _, err := ic.Client.Ping(0)
+	return err
+}
+
+// Close the influx client
+func (ic *InfluxClient) CloseSession() {
+	ic.Client.Close()
+}
+
 // ******************************* EVENTS **********************************
 
 // Return all the events
@@ -156,6 +172,99 @@ func (ic *InfluxClient) EventsForDevice(id string) ([]models.Event, error) {
 	return ic.getEvents(query)
 }
 
+// Get the events carrying the tag
+func (ic *InfluxClient) EventsByTag(key, value string) ([]models.Event, error) {
+	query := fmt.Sprintf("WHERE %s = '%s'", influxTagColumn(key), strings.Replace(value, "'", "\\'", -1))
+	return ic.getEvents(query)
+}
+
+// Get the events collected by the schedule between startTime and endTime
+func (ic *InfluxClient) EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	query := fmt.Sprintf("WHERE schedule = '%s' AND created >= %d AND created <= %d LIMIT %d",
+		strings.Replace(schedule, "'", "\\'", -1), startTime, endTime, limit)
+	return ic.getEvents(query)
+}
+
+// Get the events collected by the schedule event between startTime and endTime
+func (ic *InfluxClient) EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	query := fmt.Sprintf("WHERE event = '%s' AND created >= %d AND created <= %d LIMIT %d",
+		strings.Replace(scheduleEvent, "'", "\\'", -1), startTime, endTime, limit)
+	return ic.getEvents(query)
+}
+
+// Get the events located within radius meters of the point, nearest first
+// Influx has no geospatial index, the located events are filtered here
+func (ic *InfluxClient) EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	events, err := ic.getEvents("")
+	if err != nil {
+		return nil, err
+	}
+
+	near := []models.Event{}
+	for _, e := range events {
+		if e.Location != nil && models.Distance(point, *e.Location) <= radius {
+			near = append(near, e)
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool {
+		return models.Distance(point, *near[i].Location) < models.Distance(point, *near[j].Location)
+	})
+	if len(near) > limit {
+		near = near[:limit]
+	}
+	return near, nil
+}
+
+// Get the events located within the polygon
+func (ic *InfluxClient) EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	events, err := ic.getEvents("")
+	if err != nil {
+		return nil, err
+	}
+
+	within := []models.Event{}
+	for _, e := range events {
+		if len(within) == limit {
+			break
+		}
+		if e.Location != nil && polygon.Contains(*e.Location) {
+			within = append(within, e)
+		}
+	}
+	return within, nil
+}
+
+// Get the location of the most recent located event of every device
+func (ic *InfluxClient) LatestDevicePositions() ([]models.DevicePosition, error) {
+	events, err := ic.getEvents("")
+	if err != nil {
+		return nil, err
+	}
+	return latestDevicePositions(events), nil
+}
+
+// Location of the most recent located event of every device, sorted by device
+func latestDevicePositions(events []models.Event) []models.DevicePosition {
+	latest := make(map[string]models.DevicePosition)
+	for _, e := range events {
+		if e.Location == nil {
+			continue
+		}
+		if p, ok := latest[e.Device]; !ok || e.Created > p.Created {
+			latest[e.Device] = models.DevicePosition{Device: e.Device, Location: *e.Location, Created: e.Created}
+		}
+	}
+
+	positions := []models.DevicePosition{}
+	for _, p := range latest {
+		positions = append(positions, p)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Device < positions[j].Device
+	})
+	return positions
+}
+
 // Return a list of events whos creation time is between startTime and endTime
 // Limit the number of results by limit
 func (ic *InfluxClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]models.Event, error) {
@@ -279,6 +388,13 @@ func (ic *InfluxClient) addEventToDB(db string, collection string, e *models.Eve
 		"device":   e.Device,
 		"event":    e.Event,
 	}
+	for k, v := range e.Tags {
+		tags[influxTagPrefix+k] = v
+	}
+	if e.Location != nil {
+		fields["longitude"] = e.Location.Longitude()
+		fields["latitude"] = e.Location.Latitude()
+	}
 
 	pt, err := client.NewPoint(
 		collection,
@@ -329,11 +445,61 @@ func parseEvent(res client.Result) (models.Event, error){
 			event.Event = res.Series[0].Values[0][i].(string)
 		case "schedule":
 			event.Schedule = res.Series[0].Values[0][i].(string)
+		case "longitude", "latitude":
+			loc, err := parseInfluxCoordinate(event.Location, col, res.Series[0].Values[0][i])
+			if err != nil {
+				return event, err
+			}
+			event.Location = loc
+		default:
+			event.Tags = parseInfluxTag(event.Tags, col, res.Series[0].Values[0][i])
 		}
 	}
 	return event, nil
 }
 
+// Set the coordinate held by the column on the location, influx returns null for the
+// fields of the events without location
+func parseInfluxCoordinate(loc *models.GeoPoint, col string, value interface{}) (*models.GeoPoint, error) {
+	v, ok := value.(json.Number)
+	if !ok {
+		return loc, nil
+	}
+	f, err := v.Float64()
+	if err != nil {
+		return loc, err
+	}
+	if loc == nil {
+		p := models.NewGeoPoint(0, 0)
+		loc = &p
+	}
+	if col == "longitude" {
+		loc.Coordinates[0] = f
+	} else {
+		loc.Coordinates[1] = f
+	}
+	return loc, nil
+}
+
+// Quoted column of the influx tag holding the tag key
+func influxTagColumn(key string) string {
+	return `"` + influxTagPrefix + strings.Replace(key, `"`, `\"`, -1) + `"`
+}
+
+// Add the value of the column to the tags if it holds one, influx returns null for the tags
+// a point doesn't have
+func parseInfluxTag(tags models.Tags, col string, value interface{}) models.Tags {
+	v, ok := value.(string)
+	if !ok || !strings.HasPrefix(col, influxTagPrefix) {
+		return tags
+	}
+	if tags == nil {
+		tags = make(models.Tags)
+	}
+	tags[strings.TrimPrefix(col, influxTagPrefix)] = v
+	return tags
+}
+
 // ************************ READINGS ************************************
 
 // Return a list of readings sorted by reading id
@@ -425,6 +591,32 @@ func (ic *InfluxClient) ReadingsByCreationTime(start, end int64, limit int) ([]m
 	return ic.getReadings(query)
 }
 
+// Return the most recently created reading of every value descriptor of every device
+func (ic *InfluxClient) LatestReadings() ([]models.Reading, error) {
+	// MAX is a selector, the other columns of each series come from the point it selects
+	query := fmt.Sprintf("SELECT MAX(created), * FROM %s GROUP BY device, name", READINGS_COLLECTION)
+	res, err := queryDB(ic.Client, query, ic.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := []models.Reading{}
+	for _, r := range res {
+		for _, series := range r.Series {
+			if len(series.Values) == 0 {
+				continue
+			}
+			reading, err := parseReadingRow(series.Columns, series.Values[0])
+			if err != nil {
+				return nil, err
+			}
+			reading.Device, reading.Name = series.Tags["device"], series.Tags["name"]
+			readings = append(readings, reading)
+		}
+	}
+	return readings, nil
+}
+
 // Return a list of readings for a device filtered by the value descriptor and limited by the limit
 // The readings are linked to the device through an event
 func (ic *InfluxClient) ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]models.Reading, error) {
@@ -486,6 +678,9 @@ func (ic *InfluxClient) addReadingToDB(db string, collection string, r *models.R
 		"name":     r.Name,
 		"value":    r.Value,
 	}
+	for k, v := range r.Tags {
+		tags[influxTagPrefix+k] = v
+	}
 
 	pt, err := client.NewPoint(
 		collection,
@@ -501,41 +696,48 @@ func (ic *InfluxClient) addReadingToDB(db string, collection string, r *models.R
 }
 
 func parseReading(res client.Result) (models.Reading, error){
+	return parseReadingRow(res.Series[0].Columns, res.Series[0].Values[0])
+}
+
+// Reading held by a row of a query result
+func parseReadingRow(columns []string, row []interface{}) (models.Reading, error) {
 	var reading models.Reading
-	for i, col := range res.Series[0].Columns {
+	for i, col := range columns {
 		switch col {
 		case "id":
-			reading.Id = bson.ObjectIdHex(res.Series[0].Values[0][i].(string))
+			reading.Id = bson.ObjectIdHex(row[i].(string))
 		case "pushed":
-			n, err := res.Series[0].Values[0][i].(json.Number).Int64()
+			n, err := row[i].(json.Number).Int64()
 			if err != nil {
 				return reading, err
 			}
 			reading.Pushed = n
 		case "created":
-			n, err := res.Series[0].Values[0][i].(json.Number).Int64()
+			n, err := row[i].(json.Number).Int64()
 			if err != nil {
 				return reading, err
 			}
 			reading.Created = n
 		case "origin":
-			n, err := res.Series[0].Values[0][i].(json.Number).Int64()
+			n, err := row[i].(json.Number).Int64()
 			if err != nil {
 				return reading, err
 			}
 			reading.Origin = n
 		case "modified":
-			n, err := res.Series[0].Values[0][i].(json.Number).Int64()
+			n, err := row[i].(json.Number).Int64()
 			if err != nil {
 				return reading, err
 			}
 			reading.Modified = n
 		case "device":
-			reading.Device = res.Series[0].Values[0][i].(string)
+			reading.Device = row[i].(string)
 		case "name":
-			reading.Name = res.Series[0].Values[0][i].(string)
+			reading.Name = row[i].(string)
 		case "value":
-			reading.Value = res.Series[0].Values[0][i].(string)
+			reading.Value = row[i].(string)
+		default:
+			reading.Tags = parseInfluxTag(reading.Tags, col, row[i])
 		}
 	}
 	return reading, nil
@@ -817,3 +1019,142 @@ func parseValueDescriptor(res client.Result) (models.ValueDescriptor, error){
 	}
 	return valuedescriptors, nil
 }
+
+// ******************************* ALERT RULES **********************************
+
+// The alert rules are stored one point per rule, the rule as JSON in the "rule" field
+
+// Add an alert rule
+// ErrNotUnique if the name is already used
+func (ic *InfluxClient) AddAlertRule(r models.AlertRule) (bson.ObjectId, error) {
+	_, err := ic.AlertRuleByName(r.Name)
+	if err == nil {
+		return r.Id, errs.ErrNotUnique
+	}
+	if err != errs.ErrNotFound {
+		return r.Id, err
+	}
+
+	r.Id = bson.NewObjectId()
+	r.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	return r.Id, ic.addAlertRuleToDB(r)
+}
+
+// Return all the alert rules
+func (ic *InfluxClient) AlertRules() ([]models.AlertRule, error) {
+	rules, err := ic.getAlertRules("")
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, err
+}
+
+// Return an alert rule based on the id
+func (ic *InfluxClient) AlertRuleById(id string) (models.AlertRule, error) {
+	if !bson.IsObjectIdHex(id) {
+		return models.AlertRule{}, ErrInvalidObjectId
+	}
+	return ic.getAlertRule(fmt.Sprintf("WHERE id = '%s'", id))
+}
+
+// Return an alert rule based on the name
+func (ic *InfluxClient) AlertRuleByName(name string) (models.AlertRule, error) {
+	return ic.getAlertRule(fmt.Sprintf("WHERE name = '%s'", strings.Replace(name, "'", "\\'", -1)))
+}
+
+// Update an alert rule identified by its id
+// ErrNotUnique if the name is used by another alert rule
+func (ic *InfluxClient) UpdateAlertRule(r models.AlertRule) error {
+	if _, err := ic.AlertRuleById(r.Id.Hex()); err != nil {
+		return err
+	}
+	other, err := ic.AlertRuleByName(r.Name)
+	if err == nil && other.Id != r.Id {
+		return errs.ErrNotUnique
+	}
+	if err != nil && err != errs.ErrNotFound {
+		return err
+	}
+
+	if err := ic.deleteById(ALERT_RULE_COLLECTION, r.Id.Hex()); err != nil {
+		return err
+	}
+	r.Modified = time.Now().UnixNano() / int64(time.Millisecond)
+	return ic.addAlertRuleToDB(r)
+}
+
+// Delete an alert rule based on the id
+func (ic *InfluxClient) DeleteAlertRuleById(id string) error {
+	if _, err := ic.AlertRuleById(id); err != nil {
+		return err
+	}
+	return ic.deleteById(ALERT_RULE_COLLECTION, id)
+}
+
+func (ic *InfluxClient) getAlertRule(q string) (models.AlertRule, error) {
+	rules, err := ic.getAlertRules(q)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if len(rules) < 1 {
+		return models.AlertRule{}, errs.ErrNotFound
+	}
+	return rules[0], nil
+}
+
+func (ic *InfluxClient) getAlertRules(q string) ([]models.AlertRule, error) {
+	rules := []models.AlertRule{}
+	query := fmt.Sprintf("SELECT * FROM %s %s", ALERT_RULE_COLLECTION, q)
+	res, err := queryDB(ic.Client, query, ic.Database)
+	if err != nil {
+		return rules, err
+	}
+	for _, result := range res {
+		for _, series := range result.Series {
+			column := -1
+			for i, col := range series.Columns {
+				if col == "rule" {
+					column = i
+				}
+			}
+			if column < 0 {
+				continue
+			}
+			for _, values := range series.Values {
+				var r models.AlertRule
+				if err := json.Unmarshal([]byte(values[column].(string)), &r); err != nil {
+					return rules, err
+				}
+				rules = append(rules, r)
+			}
+		}
+	}
+	return rules, nil
+}
+
+func (ic *InfluxClient) addAlertRuleToDB(r models.AlertRule) error {
+	bp, err := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:  ic.Database,
+		Precision: "us",
+	})
+	if err != nil {
+		return err
+	}
+	rule, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	tags := map[string]string{
+		"id":   r.Id.Hex(),
+		"name": r.Name,
+	}
+	fields := map[string]interface{}{
+		"rule": string(rule),
+	}
+
+	pt, err := client.NewPoint(ALERT_RULE_COLLECTION, tags, fields, time.Now())
+	if err != nil {
+		return err
+	}
+	bp.AddPoint(pt)
+	return ic.Client.Write(bp)
+}
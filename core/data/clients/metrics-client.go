@@ -0,0 +1,281 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package clients
+
+import (
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var dbLatency = metrics.NewHistogram("edgex_db_operation_duration_seconds",
+	"Latency of database client operations, by operation.", nil, "operation")
+
+// Decorates a DBClient to record the latency of every operation
+type metricsClient struct {
+	DBClient
+}
+
+func newMetricsClient(c DBClient) DBClient {
+	return &metricsClient{DBClient: c}
+}
+
+func (c *metricsClient) Ping() error {
+	defer dbLatency.ObserveSince(time.Now(), "Ping")
+	return c.DBClient.Ping()
+}
+
+func (c *metricsClient) CloseSession() {
+	c.DBClient.CloseSession()
+}
+
+func (c *metricsClient) Events() ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "Events")
+	return c.DBClient.Events()
+}
+
+func (c *metricsClient) AddEvent(e *models.Event) (bson.ObjectId, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AddEvent")
+	return c.DBClient.AddEvent(e)
+}
+
+func (c *metricsClient) UpdateEvent(e models.Event) error {
+	defer dbLatency.ObserveSince(time.Now(), "UpdateEvent")
+	return c.DBClient.UpdateEvent(e)
+}
+
+func (c *metricsClient) EventById(id string) (models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventById")
+	return c.DBClient.EventById(id)
+}
+
+func (c *metricsClient) EventCount() (int, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventCount")
+	return c.DBClient.EventCount()
+}
+
+func (c *metricsClient) EventCountByDeviceId(id string) (int, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventCountByDeviceId")
+	return c.DBClient.EventCountByDeviceId(id)
+}
+
+func (c *metricsClient) DeleteEventById(id string) error {
+	defer dbLatency.ObserveSince(time.Now(), "DeleteEventById")
+	return c.DBClient.DeleteEventById(id)
+}
+
+func (c *metricsClient) EventsForDeviceLimit(id string, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsForDeviceLimit")
+	return c.DBClient.EventsForDeviceLimit(id, limit)
+}
+
+func (c *metricsClient) EventsForDevice(id string) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsForDevice")
+	return c.DBClient.EventsForDevice(id)
+}
+
+func (c *metricsClient) EventsByTag(key, value string) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsByTag")
+	return c.DBClient.EventsByTag(key, value)
+}
+
+func (c *metricsClient) EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsBySchedule")
+	return c.DBClient.EventsBySchedule(schedule, startTime, endTime, limit)
+}
+
+func (c *metricsClient) EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsByScheduleEvent")
+	return c.DBClient.EventsByScheduleEvent(scheduleEvent, startTime, endTime, limit)
+}
+
+func (c *metricsClient) EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsNear")
+	return c.DBClient.EventsNear(point, radius, limit)
+}
+
+func (c *metricsClient) EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsWithin")
+	return c.DBClient.EventsWithin(polygon, limit)
+}
+
+func (c *metricsClient) LatestDevicePositions() ([]models.DevicePosition, error) {
+	defer dbLatency.ObserveSince(time.Now(), "LatestDevicePositions")
+	return c.DBClient.LatestDevicePositions()
+}
+
+func (c *metricsClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsByCreationTime")
+	return c.DBClient.EventsByCreationTime(startTime, endTime, limit)
+}
+
+func (c *metricsClient) ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingsByDeviceAndValueDescriptor")
+	return c.DBClient.ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor, limit)
+}
+
+func (c *metricsClient) EventsOlderThanAge(age int64) ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsOlderThanAge")
+	return c.DBClient.EventsOlderThanAge(age)
+}
+
+func (c *metricsClient) EventsPushed() ([]models.Event, error) {
+	defer dbLatency.ObserveSince(time.Now(), "EventsPushed")
+	return c.DBClient.EventsPushed()
+}
+
+func (c *metricsClient) ScrubAllEvents() error {
+	defer dbLatency.ObserveSince(time.Now(), "ScrubAllEvents")
+	return c.DBClient.ScrubAllEvents()
+}
+
+func (c *metricsClient) Readings() ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "Readings")
+	return c.DBClient.Readings()
+}
+
+func (c *metricsClient) AddReading(r models.Reading) (bson.ObjectId, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AddReading")
+	return c.DBClient.AddReading(r)
+}
+
+func (c *metricsClient) UpdateReading(r models.Reading) error {
+	defer dbLatency.ObserveSince(time.Now(), "UpdateReading")
+	return c.DBClient.UpdateReading(r)
+}
+
+func (c *metricsClient) ReadingById(id string) (models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingById")
+	return c.DBClient.ReadingById(id)
+}
+
+func (c *metricsClient) ReadingCount() (int, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingCount")
+	return c.DBClient.ReadingCount()
+}
+
+func (c *metricsClient) DeleteReadingById(id string) error {
+	defer dbLatency.ObserveSince(time.Now(), "DeleteReadingById")
+	return c.DBClient.DeleteReadingById(id)
+}
+
+func (c *metricsClient) ReadingsByDevice(id string, limit int) ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingsByDevice")
+	return c.DBClient.ReadingsByDevice(id, limit)
+}
+
+func (c *metricsClient) ReadingsByValueDescriptor(name string, limit int) ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingsByValueDescriptor")
+	return c.DBClient.ReadingsByValueDescriptor(name, limit)
+}
+
+func (c *metricsClient) ReadingsByValueDescriptorNames(names []string, limit int) ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingsByValueDescriptorNames")
+	return c.DBClient.ReadingsByValueDescriptorNames(names, limit)
+}
+
+func (c *metricsClient) LatestReadings() ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "LatestReadings")
+	return c.DBClient.LatestReadings()
+}
+
+func (c *metricsClient) ReadingsByCreationTime(start, end int64, limit int) ([]models.Reading, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ReadingsByCreationTime")
+	return c.DBClient.ReadingsByCreationTime(start, end, limit)
+}
+
+func (c *metricsClient) AddValueDescriptor(v models.ValueDescriptor) (bson.ObjectId, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AddValueDescriptor")
+	return c.DBClient.AddValueDescriptor(v)
+}
+
+func (c *metricsClient) ValueDescriptors() ([]models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptors")
+	return c.DBClient.ValueDescriptors()
+}
+
+func (c *metricsClient) UpdateValueDescriptor(v models.ValueDescriptor) error {
+	defer dbLatency.ObserveSince(time.Now(), "UpdateValueDescriptor")
+	return c.DBClient.UpdateValueDescriptor(v)
+}
+
+func (c *metricsClient) DeleteValueDescriptorById(id string) error {
+	defer dbLatency.ObserveSince(time.Now(), "DeleteValueDescriptorById")
+	return c.DBClient.DeleteValueDescriptorById(id)
+}
+
+func (c *metricsClient) ValueDescriptorByName(name string) (models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorByName")
+	return c.DBClient.ValueDescriptorByName(name)
+}
+
+func (c *metricsClient) ValueDescriptorsByName(names []string) ([]models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorsByName")
+	return c.DBClient.ValueDescriptorsByName(names)
+}
+
+func (c *metricsClient) ValueDescriptorById(id string) (models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorById")
+	return c.DBClient.ValueDescriptorById(id)
+}
+
+func (c *metricsClient) ValueDescriptorsByUomLabel(uomLabel string) ([]models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorsByUomLabel")
+	return c.DBClient.ValueDescriptorsByUomLabel(uomLabel)
+}
+
+func (c *metricsClient) ValueDescriptorsByLabel(label string) ([]models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorsByLabel")
+	return c.DBClient.ValueDescriptorsByLabel(label)
+}
+
+func (c *metricsClient) ValueDescriptorsByType(t string) ([]models.ValueDescriptor, error) {
+	defer dbLatency.ObserveSince(time.Now(), "ValueDescriptorsByType")
+	return c.DBClient.ValueDescriptorsByType(t)
+}
+
+func (c *metricsClient) AddAlertRule(r models.AlertRule) (bson.ObjectId, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AddAlertRule")
+	return c.DBClient.AddAlertRule(r)
+}
+
+func (c *metricsClient) AlertRules() ([]models.AlertRule, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AlertRules")
+	return c.DBClient.AlertRules()
+}
+
+func (c *metricsClient) AlertRuleById(id string) (models.AlertRule, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AlertRuleById")
+	return c.DBClient.AlertRuleById(id)
+}
+
+func (c *metricsClient) AlertRuleByName(name string) (models.AlertRule, error) {
+	defer dbLatency.ObserveSince(time.Now(), "AlertRuleByName")
+	return c.DBClient.AlertRuleByName(name)
+}
+
+func (c *metricsClient) UpdateAlertRule(r models.AlertRule) error {
+	defer dbLatency.ObserveSince(time.Now(), "UpdateAlertRule")
+	return c.DBClient.UpdateAlertRule(r)
+}
+
+func (c *metricsClient) DeleteAlertRuleById(id string) error {
+	defer dbLatency.ObserveSince(time.Now(), "DeleteAlertRuleById")
+	return c.DBClient.DeleteAlertRuleById(id)
+}
@@ -19,6 +19,7 @@ package clients
 import (
 	"time"
 
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -34,6 +35,13 @@ type MockParams struct {
 	ReadingName string
 	ValueDescriptorName string
 	NoReadingsKey string
+	TagKey string
+	TagValue string
+	ScheduleName string
+	ScheduleEventName string
+	Location models.GeoPoint
+	AlertRuleId bson.ObjectId
+	AlertRuleName string
 }
 
 var mockParams *MockParams
@@ -53,7 +61,14 @@ func init() {
 		Origin:123456789,
 	    ReadingName:"Temperature",
 		ValueDescriptorName:"Temperature",
-		NoReadingsKey:"no_readings"}
+		NoReadingsKey:"no_readings",
+		TagKey:"site",
+		TagValue:"plant-1",
+		ScheduleName:"TestScheduleA",
+		ScheduleEventName:"SampleEvent",
+		Location:models.NewGeoPoint(-97.7431, 30.2672),
+		AlertRuleId:bson.NewObjectId(),
+		AlertRuleName:"HighTemperature"}
 }
 
 type MockDb struct {
@@ -64,6 +79,13 @@ func (mc *MockDb) AddReading(r models.Reading) (bson.ObjectId, error) {
 	return bson.NewObjectId(), nil
 }
 
+func (mc *MockDb) Ping() error {
+	return nil
+}
+
+func (mc *MockDb) CloseSession() {
+}
+
 //DatabaseClient interface methods
 func (mc *MockDb) Events() ([]models.Event, error) {
 	ticks := time.Now().Unix()
@@ -173,6 +195,84 @@ func (mc *MockDb) EventsOlderThanAge(age int64) ([]models.Event, error) {
 	return events, nil
 }
 
+func (mc *MockDb) EventsByTag(key, value string) ([]models.Event, error) {
+	events := []models.Event{}
+	ticks := time.Now().Unix()
+
+	if key == mockParams.TagKey && value == mockParams.TagValue {
+		evt := models.Event{ID:mockParams.EventId, Pushed:1, Device:mockParams.DeviceName, Created:ticks, Modified:ticks,
+			Origin:mockParams.Origin, Readings:buildListOfMockReadings(), Tags:map[string]string{key:value}}
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
+func (mc *MockDb) EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	if schedule != mockParams.ScheduleName {
+		return []models.Event{}, nil
+	}
+	return mockScheduledEvents(startTime, endTime, limit), nil
+}
+
+func (mc *MockDb) EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	if scheduleEvent != mockParams.ScheduleEventName {
+		return []models.Event{}, nil
+	}
+	return mockScheduledEvents(startTime, endTime, limit), nil
+}
+
+// Two events of the mock schedule created at EventAgeInTicks, filtered by the time range and the limit
+func mockScheduledEvents(startTime, endTime int64, limit int) []models.Event {
+	events := []models.Event{}
+	created := mockParams.EventAgeInTicks
+
+	if created < startTime || created > endTime {
+		return events
+	}
+	for len(events) < 2 && len(events) < limit {
+		evt := models.Event{ID:bson.NewObjectId(), Pushed:1, Device:mockParams.DeviceName, Created:created, Modified:created,
+			Origin:mockParams.Origin, Schedule:mockParams.ScheduleName, Event:mockParams.ScheduleEventName, Readings:buildListOfMockReadings()}
+		events = append(events, evt)
+	}
+	return events
+}
+
+func (mc *MockDb) EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	events := []models.Event{}
+	for _, e := range mockLocatedEvents() {
+		if len(events) < limit && models.Distance(point, *e.Location) <= radius {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (mc *MockDb) EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	events := []models.Event{}
+	for _, e := range mockLocatedEvents() {
+		if len(events) < limit && polygon.Contains(*e.Location) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (mc *MockDb) LatestDevicePositions() ([]models.DevicePosition, error) {
+	return latestDevicePositions(mockLocatedEvents()), nil
+}
+
+// An older event of the mock device at 0,0 and a recent one at the mock location
+func mockLocatedEvents() []models.Event {
+	origin := models.NewGeoPoint(0, 0)
+	location := models.NewGeoPoint(mockParams.Location.Longitude(), mockParams.Location.Latitude())
+	return []models.Event{
+		{ID:bson.NewObjectId(), Device:mockParams.DeviceName, Created:mockParams.EventAgeInTicks, Origin:mockParams.Origin,
+			Readings:buildListOfMockReadings(), Location:&origin},
+		{ID:mockParams.EventId, Device:mockParams.DeviceName, Created:mockParams.EventAgeInTicks+1, Origin:mockParams.Origin,
+			Readings:buildListOfMockReadings(), Location:&location},
+	}
+}
+
 func (mc *MockDb) EventsPushed() ([]models.Event, error) {
 	events := []models.Event{}
 	ticks := time.Now().Unix()
@@ -236,6 +336,10 @@ func (mc *MockDb) ReadingsByCreationTime(start, end int64, limit int) ([]models.
 	return buildListOfMockReadingsWithLimit(limit), nil
 }
 
+func (mc *MockDb) LatestReadings() ([]models.Reading, error) {
+	return buildListOfMockReadings(), nil
+}
+
 func (mc *MockDb) AddValueDescriptor(v models.ValueDescriptor) (bson.ObjectId, error) {
 	return v.Id, nil
 }
@@ -282,6 +386,54 @@ func (mc *MockDb) ValueDescriptorsByType(t string) ([]models.ValueDescriptor, er
 	return buildListofMockValueDescritors(), nil
 }
 
+// The name of the mock alert rule is already used
+func (mc *MockDb) AddAlertRule(r models.AlertRule) (bson.ObjectId, error) {
+	if r.Name == mockParams.AlertRuleName {
+		return r.Id, errs.ErrNotUnique
+	}
+	return bson.NewObjectId(), nil
+}
+
+func (mc *MockDb) AlertRules() ([]models.AlertRule, error) {
+	return []models.AlertRule{buildMockAlertRule()}, nil
+}
+
+func (mc *MockDb) AlertRuleById(id string) (models.AlertRule, error) {
+	if id != mockParams.AlertRuleId.Hex() {
+		return models.AlertRule{}, errs.ErrNotFound
+	}
+	return buildMockAlertRule(), nil
+}
+
+func (mc *MockDb) AlertRuleByName(name string) (models.AlertRule, error) {
+	if name != mockParams.AlertRuleName {
+		return models.AlertRule{}, errs.ErrNotFound
+	}
+	return buildMockAlertRule(), nil
+}
+
+func (mc *MockDb) UpdateAlertRule(r models.AlertRule) error {
+	return nil
+}
+
+func (mc *MockDb) DeleteAlertRuleById(id string) error {
+	return nil
+}
+
+// Threshold rule raised when the temperature of the mock device exceeds 100
+func buildMockAlertRule() models.AlertRule {
+	max := 100.0
+	return models.AlertRule{
+		Id:              mockParams.AlertRuleId,
+		Name:            mockParams.AlertRuleName,
+		Kind:            models.AlertThreshold,
+		Device:          mockParams.DeviceName,
+		ValueDescriptor: mockParams.ValueDescriptorName,
+		Max:             &max,
+		Hysteresis:      5,
+	}
+}
+
 func buildListOfMockReadings() []models.Reading {
 	ticks := time.Now().Unix()
 	r1 := models.Reading{Id:bson.NewObjectId(),
@@ -32,6 +32,7 @@ const (
 	EVENTS_COLLECTION           = "event"
 	READINGS_COLLECTION         = "reading"
 	VALUE_DESCRIPTOR_COLLECTION = "valueDescriptor"
+	ALERT_RULE_COLLECTION       = "alertRule"
 )
 
 var currentMongoClient *MongoClient // Singleton used so that MongoEvent can use it to de-reference readings
@@ -71,6 +72,13 @@ func newMongoClient(config DBConfiguration) (*MongoClient, error) {
 		return nil, err
 	}
 
+	// Geospatial queries on the events need a spherical index of their location
+	err = session.DB(config.DatabaseName).C(EVENTS_COLLECTION).EnsureIndex(mgo.Index{Key: []string{"$2dsphere:location"}})
+	if err != nil {
+		fmt.Println("Error indexing the location of the events: " + err.Error())
+		return nil, err
+	}
+
 	mongoClient := &MongoClient{Session: session, Database: session.DB(config.DatabaseName)}
 	currentMongoClient = mongoClient // Set the singleton
 	return mongoClient, nil
@@ -90,6 +98,18 @@ func (mc *MongoClient) GetSessionCopy() *mgo.Session {
 	return mc.Session.Copy()
 }
 
+// Check that the mongo server answers
+func (mc *MongoClient) Ping() error {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+	return s.Ping()
+}
+
+// Close the mongo session, copies in use are closed by their owners
+func (mc *MongoClient) CloseSession() {
+	mc.Session.Close()
+}
+
 // ******************************* EVENTS **********************************
 
 // Return all the events
@@ -183,6 +203,76 @@ func (mc *MongoClient) EventsForDevice(id string) ([]models.Event, error) {
 	return mc.getEvents(bson.M{"device": id})
 }
 
+// Get the events carrying the tag
+func (mc *MongoClient) EventsByTag(key, value string) ([]models.Event, error) {
+	return mc.getEvents(bson.M{"tags." + key: value})
+}
+
+// Get the events collected by the schedule between startTime and endTime
+func (mc *MongoClient) EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	query := bson.M{"schedule": schedule, "created": bson.M{
+		"$gte": startTime,
+		"$lte": endTime,
+	}}
+	return mc.getEventsLimit(query, limit)
+}
+
+// Get the events collected by the schedule event between startTime and endTime
+func (mc *MongoClient) EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	query := bson.M{"event": scheduleEvent, "created": bson.M{
+		"$gte": startTime,
+		"$lte": endTime,
+	}}
+	return mc.getEventsLimit(query, limit)
+}
+
+// Get the events located within radius meters of the point, nearest first
+func (mc *MongoClient) EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	query := bson.M{"location": bson.M{"$nearSphere": bson.M{
+		"$geometry":    point,
+		"$maxDistance": radius,
+	}}}
+	return mc.getEventsLimit(query, limit)
+}
+
+// Get the events located within the polygon
+func (mc *MongoClient) EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	query := bson.M{"location": bson.M{"$geoWithin": bson.M{"$geometry": polygon}}}
+	return mc.getEventsLimit(query, limit)
+}
+
+// Get the location of the most recent located event of every device
+func (mc *MongoClient) LatestDevicePositions() ([]models.DevicePosition, error) {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"location": bson.M{"$exists": true}}},
+		{"$sort": bson.M{"created": -1}},
+		{"$group": bson.M{
+			"_id":      "$device",
+			"location": bson.M{"$first": "$location"},
+			"created":  bson.M{"$first": "$created"},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+	var res []struct {
+		Device   string          `bson:"_id"`
+		Location models.GeoPoint `bson:"location"`
+		Created  int64           `bson:"created"`
+	}
+	err := s.DB(mc.Database.Name).C(EVENTS_COLLECTION).Pipe(pipeline).All(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := []models.DevicePosition{}
+	for _, p := range res {
+		positions = append(positions, models.DevicePosition{Device: p.Device, Location: p.Location, Created: p.Created})
+	}
+	return positions, nil
+}
+
 // Return a list of events whos creation time is between startTime and endTime
 // Limit the number of results by limit
 func (mc *MongoClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]models.Event, error) {
@@ -369,6 +459,33 @@ func (mc *MongoClient) DeleteReadingById(id string) error {
 	return mc.deleteById(id, READINGS_COLLECTION)
 }
 
+// Return the most recently created reading of every value descriptor of every device
+func (mc *MongoClient) LatestReadings() ([]models.Reading, error) {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	pipeline := []bson.M{
+		{"$sort": bson.M{"created": -1}},
+		{"$group": bson.M{
+			"_id":     bson.M{"device": "$device", "name": "$name"},
+			"reading": bson.M{"$first": "$$ROOT"},
+		}},
+	}
+	var res []struct {
+		Reading models.Reading `bson:"reading"`
+	}
+	err := s.DB(mc.Database.Name).C(READINGS_COLLECTION).Pipe(pipeline).AllowDiskUse().All(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := []models.Reading{}
+	for _, r := range res {
+		readings = append(readings, r.Reading)
+	}
+	return readings, nil
+}
+
 // Return a list of readings for the given device (id or name)
 // Sort the list of readings on creation date
 func (mc *MongoClient) ReadingsByDevice(id string, limit int) ([]models.Reading, error) {
@@ -644,3 +761,87 @@ func (mc *MongoClient) deleteById(id string, col string) error {
 	}
 	return err
 }
+
+// ******************************* ALERT RULES **********************************
+
+// Add an alert rule
+// ErrNotUnique if the name is already used
+func (mc *MongoClient) AddAlertRule(r models.AlertRule) (bson.ObjectId, error) {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	_, err := mc.getAlertRule(bson.M{"name": r.Name})
+	if err == nil {
+		return r.Id, errs.ErrNotUnique
+	}
+	if err != errs.ErrNotFound {
+		return r.Id, err
+	}
+
+	r.Id = bson.NewObjectId()
+	r.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	err = s.DB(mc.Database.Name).C(ALERT_RULE_COLLECTION).Insert(r)
+	return r.Id, err
+}
+
+// Return all the alert rules
+func (mc *MongoClient) AlertRules() ([]models.AlertRule, error) {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	rules := []models.AlertRule{}
+	err := s.DB(mc.Database.Name).C(ALERT_RULE_COLLECTION).Find(nil).Sort("name").All(&rules)
+	return rules, err
+}
+
+// Return an alert rule based on the id
+func (mc *MongoClient) AlertRuleById(id string) (models.AlertRule, error) {
+	if !bson.IsObjectIdHex(id) {
+		return models.AlertRule{}, ErrInvalidObjectId
+	}
+	return mc.getAlertRule(bson.M{"_id": bson.ObjectIdHex(id)})
+}
+
+// Return an alert rule based on the name
+func (mc *MongoClient) AlertRuleByName(name string) (models.AlertRule, error) {
+	return mc.getAlertRule(bson.M{"name": name})
+}
+
+// Update an alert rule identified by its id
+// ErrNotUnique if the name is used by another alert rule
+func (mc *MongoClient) UpdateAlertRule(r models.AlertRule) error {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	other, err := mc.getAlertRule(bson.M{"name": r.Name})
+	if err == nil && other.Id != r.Id {
+		return errs.ErrNotUnique
+	}
+	if err != nil && err != errs.ErrNotFound {
+		return err
+	}
+
+	r.Modified = time.Now().UnixNano() / int64(time.Millisecond)
+	err = s.DB(mc.Database.Name).C(ALERT_RULE_COLLECTION).UpdateId(r.Id, r)
+	if err == mgo.ErrNotFound {
+		return errs.ErrNotFound
+	}
+	return err
+}
+
+// Delete an alert rule based on the id
+func (mc *MongoClient) DeleteAlertRuleById(id string) error {
+	return mc.deleteById(id, ALERT_RULE_COLLECTION)
+}
+
+func (mc *MongoClient) getAlertRule(q bson.M) (models.AlertRule, error) {
+	s := mc.GetSessionCopy()
+	defer s.Close()
+
+	var r models.AlertRule
+	err := s.DB(mc.Database.Name).C(ALERT_RULE_COLLECTION).Find(q).One(&r)
+	if err == mgo.ErrNotFound {
+		return r, errs.ErrNotFound
+	}
+	return r, err
+}
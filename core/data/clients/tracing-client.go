@@ -0,0 +1,295 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package clients
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Decorates a DBClient to record a span for every operation made within a trace.
+// The trace is taken from the context the client was bound to with WithContext.
+type tracingClient struct {
+	DBClient
+	ctx context.Context
+}
+
+func newTracingClient(c DBClient) DBClient {
+	return &tracingClient{DBClient: c, ctx: context.Background()}
+}
+
+// Client recording its operations in the trace of ctx, c itself if it doesn't trace operations
+func WithContext(ctx context.Context, c DBClient) DBClient {
+	if t, ok := c.(*tracingClient); ok {
+		return &tracingClient{DBClient: t.DBClient, ctx: ctx}
+	}
+	return c
+}
+
+func (c *tracingClient) span(operation string) opentracing.Span {
+	span, _ := tracing.StartChildSpan(c.ctx, "db "+operation, ext.SpanKindRPCClient)
+	return span
+}
+
+func (c *tracingClient) Ping() error {
+	defer c.span("Ping").Finish()
+	return c.DBClient.Ping()
+}
+
+func (c *tracingClient) CloseSession() {
+	c.DBClient.CloseSession()
+}
+
+func (c *tracingClient) Events() ([]models.Event, error) {
+	defer c.span("Events").Finish()
+	return c.DBClient.Events()
+}
+
+func (c *tracingClient) AddEvent(e *models.Event) (bson.ObjectId, error) {
+	defer c.span("AddEvent").Finish()
+	return c.DBClient.AddEvent(e)
+}
+
+func (c *tracingClient) UpdateEvent(e models.Event) error {
+	defer c.span("UpdateEvent").Finish()
+	return c.DBClient.UpdateEvent(e)
+}
+
+func (c *tracingClient) EventById(id string) (models.Event, error) {
+	defer c.span("EventById").Finish()
+	return c.DBClient.EventById(id)
+}
+
+func (c *tracingClient) EventCount() (int, error) {
+	defer c.span("EventCount").Finish()
+	return c.DBClient.EventCount()
+}
+
+func (c *tracingClient) EventCountByDeviceId(id string) (int, error) {
+	defer c.span("EventCountByDeviceId").Finish()
+	return c.DBClient.EventCountByDeviceId(id)
+}
+
+func (c *tracingClient) DeleteEventById(id string) error {
+	defer c.span("DeleteEventById").Finish()
+	return c.DBClient.DeleteEventById(id)
+}
+
+func (c *tracingClient) EventsForDeviceLimit(id string, limit int) ([]models.Event, error) {
+	defer c.span("EventsForDeviceLimit").Finish()
+	return c.DBClient.EventsForDeviceLimit(id, limit)
+}
+
+func (c *tracingClient) EventsForDevice(id string) ([]models.Event, error) {
+	defer c.span("EventsForDevice").Finish()
+	return c.DBClient.EventsForDevice(id)
+}
+
+func (c *tracingClient) EventsByTag(key, value string) ([]models.Event, error) {
+	defer c.span("EventsByTag").Finish()
+	return c.DBClient.EventsByTag(key, value)
+}
+
+func (c *tracingClient) EventsBySchedule(schedule string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer c.span("EventsBySchedule").Finish()
+	return c.DBClient.EventsBySchedule(schedule, startTime, endTime, limit)
+}
+
+func (c *tracingClient) EventsByScheduleEvent(scheduleEvent string, startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer c.span("EventsByScheduleEvent").Finish()
+	return c.DBClient.EventsByScheduleEvent(scheduleEvent, startTime, endTime, limit)
+}
+
+func (c *tracingClient) EventsNear(point models.GeoPoint, radius float64, limit int) ([]models.Event, error) {
+	defer c.span("EventsNear").Finish()
+	return c.DBClient.EventsNear(point, radius, limit)
+}
+
+func (c *tracingClient) EventsWithin(polygon models.GeoPolygon, limit int) ([]models.Event, error) {
+	defer c.span("EventsWithin").Finish()
+	return c.DBClient.EventsWithin(polygon, limit)
+}
+
+func (c *tracingClient) LatestDevicePositions() ([]models.DevicePosition, error) {
+	defer c.span("LatestDevicePositions").Finish()
+	return c.DBClient.LatestDevicePositions()
+}
+
+func (c *tracingClient) EventsByCreationTime(startTime, endTime int64, limit int) ([]models.Event, error) {
+	defer c.span("EventsByCreationTime").Finish()
+	return c.DBClient.EventsByCreationTime(startTime, endTime, limit)
+}
+
+func (c *tracingClient) ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor string, limit int) ([]models.Reading, error) {
+	defer c.span("ReadingsByDeviceAndValueDescriptor").Finish()
+	return c.DBClient.ReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor, limit)
+}
+
+func (c *tracingClient) EventsOlderThanAge(age int64) ([]models.Event, error) {
+	defer c.span("EventsOlderThanAge").Finish()
+	return c.DBClient.EventsOlderThanAge(age)
+}
+
+func (c *tracingClient) EventsPushed() ([]models.Event, error) {
+	defer c.span("EventsPushed").Finish()
+	return c.DBClient.EventsPushed()
+}
+
+func (c *tracingClient) ScrubAllEvents() error {
+	defer c.span("ScrubAllEvents").Finish()
+	return c.DBClient.ScrubAllEvents()
+}
+
+func (c *tracingClient) Readings() ([]models.Reading, error) {
+	defer c.span("Readings").Finish()
+	return c.DBClient.Readings()
+}
+
+func (c *tracingClient) AddReading(r models.Reading) (bson.ObjectId, error) {
+	defer c.span("AddReading").Finish()
+	return c.DBClient.AddReading(r)
+}
+
+func (c *tracingClient) UpdateReading(r models.Reading) error {
+	defer c.span("UpdateReading").Finish()
+	return c.DBClient.UpdateReading(r)
+}
+
+func (c *tracingClient) ReadingById(id string) (models.Reading, error) {
+	defer c.span("ReadingById").Finish()
+	return c.DBClient.ReadingById(id)
+}
+
+func (c *tracingClient) ReadingCount() (int, error) {
+	defer c.span("ReadingCount").Finish()
+	return c.DBClient.ReadingCount()
+}
+
+func (c *tracingClient) DeleteReadingById(id string) error {
+	defer c.span("DeleteReadingById").Finish()
+	return c.DBClient.DeleteReadingById(id)
+}
+
+func (c *tracingClient) ReadingsByDevice(id string, limit int) ([]models.Reading, error) {
+	defer c.span("ReadingsByDevice").Finish()
+	return c.DBClient.ReadingsByDevice(id, limit)
+}
+
+func (c *tracingClient) ReadingsByValueDescriptor(name string, limit int) ([]models.Reading, error) {
+	defer c.span("ReadingsByValueDescriptor").Finish()
+	return c.DBClient.ReadingsByValueDescriptor(name, limit)
+}
+
+func (c *tracingClient) ReadingsByValueDescriptorNames(names []string, limit int) ([]models.Reading, error) {
+	defer c.span("ReadingsByValueDescriptorNames").Finish()
+	return c.DBClient.ReadingsByValueDescriptorNames(names, limit)
+}
+
+func (c *tracingClient) LatestReadings() ([]models.Reading, error) {
+	defer c.span("LatestReadings").Finish()
+	return c.DBClient.LatestReadings()
+}
+
+func (c *tracingClient) ReadingsByCreationTime(start, end int64, limit int) ([]models.Reading, error) {
+	defer c.span("ReadingsByCreationTime").Finish()
+	return c.DBClient.ReadingsByCreationTime(start, end, limit)
+}
+
+func (c *tracingClient) AddValueDescriptor(v models.ValueDescriptor) (bson.ObjectId, error) {
+	defer c.span("AddValueDescriptor").Finish()
+	return c.DBClient.AddValueDescriptor(v)
+}
+
+func (c *tracingClient) ValueDescriptors() ([]models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptors").Finish()
+	return c.DBClient.ValueDescriptors()
+}
+
+func (c *tracingClient) UpdateValueDescriptor(v models.ValueDescriptor) error {
+	defer c.span("UpdateValueDescriptor").Finish()
+	return c.DBClient.UpdateValueDescriptor(v)
+}
+
+func (c *tracingClient) DeleteValueDescriptorById(id string) error {
+	defer c.span("DeleteValueDescriptorById").Finish()
+	return c.DBClient.DeleteValueDescriptorById(id)
+}
+
+func (c *tracingClient) ValueDescriptorByName(name string) (models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorByName").Finish()
+	return c.DBClient.ValueDescriptorByName(name)
+}
+
+func (c *tracingClient) ValueDescriptorsByName(names []string) ([]models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorsByName").Finish()
+	return c.DBClient.ValueDescriptorsByName(names)
+}
+
+func (c *tracingClient) ValueDescriptorById(id string) (models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorById").Finish()
+	return c.DBClient.ValueDescriptorById(id)
+}
+
+func (c *tracingClient) ValueDescriptorsByUomLabel(uomLabel string) ([]models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorsByUomLabel").Finish()
+	return c.DBClient.ValueDescriptorsByUomLabel(uomLabel)
+}
+
+func (c *tracingClient) ValueDescriptorsByLabel(label string) ([]models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorsByLabel").Finish()
+	return c.DBClient.ValueDescriptorsByLabel(label)
+}
+
+func (c *tracingClient) ValueDescriptorsByType(t string) ([]models.ValueDescriptor, error) {
+	defer c.span("ValueDescriptorsByType").Finish()
+	return c.DBClient.ValueDescriptorsByType(t)
+}
+
+func (c *tracingClient) AddAlertRule(r models.AlertRule) (bson.ObjectId, error) {
+	defer c.span("AddAlertRule").Finish()
+	return c.DBClient.AddAlertRule(r)
+}
+
+func (c *tracingClient) AlertRules() ([]models.AlertRule, error) {
+	defer c.span("AlertRules").Finish()
+	return c.DBClient.AlertRules()
+}
+
+func (c *tracingClient) AlertRuleById(id string) (models.AlertRule, error) {
+	defer c.span("AlertRuleById").Finish()
+	return c.DBClient.AlertRuleById(id)
+}
+
+func (c *tracingClient) AlertRuleByName(name string) (models.AlertRule, error) {
+	defer c.span("AlertRuleByName").Finish()
+	return c.DBClient.AlertRuleByName(name)
+}
+
+func (c *tracingClient) UpdateAlertRule(r models.AlertRule) error {
+	defer c.span("UpdateAlertRule").Finish()
+	return c.DBClient.UpdateAlertRule(r)
+}
+
+func (c *tracingClient) DeleteAlertRuleById(id string) error {
+	defer c.span("DeleteAlertRuleById").Finish()
+	return c.DBClient.DeleteAlertRuleById(id)
+}
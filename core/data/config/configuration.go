@@ -17,40 +17,60 @@
  *******************************************************************************/
 package config
 
+// Fields tagged reload can change while the service runs, see pkg/config.Reloader.
+// Fields tagged validate are checked when the configuration is loaded, see pkg/config.Validate
 type ConfigurationStruct struct {
-	ApplicationName            string
+	ApplicationName            string `validate:"required"`
 	ConsulProfilesActive       string
-	ReadMaxLimit               int
-	MetaDataCheck              bool
-	ValidateCheck              bool
+	ReadMaxLimit               int    `reload:"true" validate:"min=1"`
+	MetaDataCheck              bool   `reload:"true"`
+	ValidateCheck              bool   `reload:"true"`
 	AddToEventQueue            bool
-	PersistData                bool
+	PersistData                bool   `reload:"true"`
 	HeartBeatTime              int
 	HeartBeatMsg               string
+	HeartBeatSink              string // log, support-logging, metrics or bus, see pkg/heartbeat
+	HeartBeatURL               string
+	HeartBeatTopic             string
+	HeartBeatBusPort           int
 	AppOpenMsg                 string
 	FormatSpecifier            string
 	MsgPubType                 string
-	ServicePort                int
-	ServiceTimeout             int
+	MsgEncoding                string
+	ServicePort                int    `validate:"min=1,max=65535"`
+	ServiceTimeout             int    `validate:"min=0"`
 	ServiceAddress             string
 	ServiceName                string
-	DeviceUpdateLastConnected  bool
-	ServiceUpdateLastConnected bool
+	DeviceUpdateLastConnected  bool   `reload:"true"`
+	ServiceUpdateLastConnected bool   `reload:"true"`
 	MongoDBUserName            string
 	MongoDBPassword            string
-	MongoDatabaseName          string
-	MongoDBHost                string
-	MongoDBPort                int
+	MongoDatabaseName          string `validate:"required"`
+	MongoDBHost                string `validate:"required"`
+	MongoDBPort                int    `validate:"min=1,max=65535"`
 	MongoDBConnectTimeout      int
 	MongoDBMaxWaitTime         int
 	MongoDBKeepAlive           bool
 	ConsulHost                 string
 	ConsulCheckAddress         string
-	ConsulPort                 int
+	RegistryType               string // consul or file, see support/registry
+	RegistryDirectory          string
+	AuthEnabled                bool
+	AuthAPIKeys                string
+	AuthJWTSecret              string
+	AuthJWTPublicKeyFile       string
+	AuthScopes                 string
+	AuthClientAPIKey           string
+	AuthClientToken            string
+	TracingEnabled             bool
+	TracingExporter            string
+	TracingDestination         string
+	ConsulPort                 int    `validate:"min=1,max=65535"`
 	CheckInterval              string
-	EnableRemoteLogging        bool
-	LoggingFile                string
-	LoggingRemoteURL           string
+	EnableRemoteLogging        bool   `reload:"true"`
+	LoggingFile                string `reload:"true"`
+	LoggingRemoteURL           string `reload:"true"`
+	MetaServiceName            string
 	MetaAddressableURL         string
 	MetaDeviceServiceURL       string
 	MetaDeviceProfileURL       string
@@ -61,6 +81,8 @@ type ConfigurationStruct struct {
 	MetaScheduleURL            string
 	MetaProvisionWatcherURL    string
 	MetaPingURL                string
+	SupportNotificationsNotificationURL string
+	AlertCheckInterval         int
 	ActiveMQBroker             string
 	ZeroMQAddressPort          string
 	AmqBroker                  string
@@ -0,0 +1,32 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package config
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Configuration in use, safe to call while it is reloaded
+func Current() *ConfigurationStruct {
+	return (*ConfigurationStruct)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&Configuration))))
+}
+
+// Replace the configuration in use, readers get either the previous or the new one
+// but never a configuration half updated
+func Swap(c *ConfigurationStruct) {
+	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&Configuration)), unsafe.Pointer(c))
+}
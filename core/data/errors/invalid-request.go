@@ -0,0 +1,27 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package errors
+
+// The request is malformed, Field names the offending parameter or property when known
+type InvalidRequest struct {
+	Field   string
+	Message string
+}
+
+func (e InvalidRequest) Error() string {
+	return e.Message
+}
@@ -0,0 +1,59 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package errors
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
+)
+
+// Describe err to the clients of the REST API. Its type decides the status code and the code
+// of the response, errors of unknown types are unanticipated issues of the service.
+func NewServiceError(err error) *errs.ServiceError {
+	switch e := err.(type) {
+	case *errs.ServiceError:
+		c := *e
+		return &c
+	case InvalidRequest:
+		return newServiceError(http.StatusBadRequest, errs.CodeInvalidRequest, err, e.Field)
+	case NotFound:
+		return newServiceError(http.StatusNotFound, errs.CodeNotFound, err, "")
+	case DeviceNotFound:
+		return newServiceError(http.StatusNotFound, errs.CodeDeviceNotFound, err, "device")
+	case NoValueDescriptor:
+		return newServiceError(http.StatusConflict, errs.CodeValueDescriptorNotFound, err, "name")
+	case BadValueDescriptor:
+		return newServiceError(http.StatusConflict, errs.CodeInvalidValueDescriptor, err, "")
+	case ValueDescriptorStillInUse:
+		return newServiceError(http.StatusConflict, errs.CodeValueDescriptorInUse, err, "")
+	case LimitExceeded:
+		return newServiceError(http.StatusRequestEntityTooLarge, errs.CodeLimitExceeded, err, "")
+	}
+
+	switch err {
+	case errs.ErrNotFound:
+		return newServiceError(http.StatusNotFound, errs.CodeNotFound, err, "")
+	case errs.ErrNotUnique:
+		return newServiceError(http.StatusConflict, errs.CodeDuplicate, err, "")
+	}
+	return newServiceError(http.StatusServiceUnavailable, errs.CodeServiceError, err, "")
+}
+
+func newServiceError(status int, code string, err error, field string) *errs.ServiceError {
+	return &errs.ServiceError{StatusCode: status, Code: code, Message: err.Error(), Field: field}
+}
@@ -18,37 +18,52 @@
 package data
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/edgexfoundry/edgex-go/core/aggregates/devices"
+	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
+	"github.com/edgexfoundry/edgex-go/core/clients/metadataclients"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
 	"github.com/edgexfoundry/edgex-go/core/data/log"
 	"github.com/edgexfoundry/edgex-go/core/data/messaging"
-	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/core/domain/codec"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
 	"github.com/edgexfoundry/edgex-go/support/logging-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
-func ConnectToConsul(conf config.ConfigurationStruct) error {
-
-	// Initialize service on Consul
-	err := consulclient.ConsulInit(consulclient.ConsulConfig{
-		ServiceName:    conf.ServiceName,
-		ServicePort:    conf.ServicePort,
-		ServiceAddress: conf.ServiceAddress,
-		CheckAddress:   conf.ConsulCheckAddress,
-		CheckInterval:  conf.CheckInterval,
-		ConsulAddress:  conf.ConsulHost,
-		ConsulPort:     conf.ConsulPort,
+// Connect to the configured registry, register the service and update the configuration
+// from its key/value pairs
+func ConnectToRegistry(conf *config.ConfigurationStruct) error {
+	r, err := registry.Connect(registry.Config{
+		Type:       conf.RegistryType,
+		ConsulHost: conf.ConsulHost,
+		ConsulPort: conf.ConsulPort,
+		Directory:  conf.RegistryDirectory,
 	})
+	if err != nil {
+		return fmt.Errorf("connection to the registry could not be made: %v", err.Error())
+	}
 
+	// Initialize service on the registry
+	err = r.Register(registry.Service{
+		Name:          conf.ServiceName,
+		Address:       conf.ServiceAddress,
+		Port:          conf.ServicePort,
+		CheckAddress:  conf.ConsulCheckAddress,
+		CheckInterval: conf.CheckInterval,
+	})
 	if err != nil {
-		return fmt.Errorf("connection to Consul could not be made: %v", err.Error())
-	} else {
-		// Update configuration data from Consul
-		if err := consulclient.CheckKeyValuePairs(&conf, conf.ServiceName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
-			return fmt.Errorf("error getting key/values from Consul: %v", err.Error())
-		}
+		return fmt.Errorf("registration of the service could not be made: %v", err.Error())
+	}
+
+	// Update configuration data from the registry
+	if err := r.CheckKeyValuePairs(conf, conf.ServiceName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
+		return fmt.Errorf("error getting key/values from the registry: %v", err.Error())
 	}
 	return nil
 }
@@ -56,6 +71,7 @@ func ConnectToConsul(conf config.ConfigurationStruct) error {
 func Init(conf *config.ConfigurationStruct, l logger.LoggingClient) error {
 	log.Logger = l
 	config.Configuration = conf
+	metadataclients.SetEndpoints(metadataEndpoints(conf))
 	
 	// Create a database client
 	_, err := clients.NewDBClient(clients.DBConfiguration{
@@ -71,8 +87,65 @@ func Init(conf *config.ConfigurationStruct, l logger.LoggingClient) error {
 		return fmt.Errorf("couldn't connect to database: %v", err.Error())
 	}
 
+	// Rebuild the current values of the devices
+	if err = events.LoadLatestReadings(); err != nil {
+		return fmt.Errorf("couldn't load the latest readings: %v", err.Error())
+	}
+	if err = events.LoadAlertRules(); err != nil {
+		return fmt.Errorf("couldn't load the alert rules: %v", err.Error())
+	}
+
 	// Create the event publisher
-	_ = messaging.NewMQPublisher(conf.ZeroMQAddressPort, messaging.ZEROMQ)
+	encoding, err := codec.ForName(conf.MsgEncoding)
+	if err != nil {
+		return fmt.Errorf("couldn't create event publisher: %v", err.Error())
+	}
+	_, err = messaging.NewMQPublisher(conf.ZeroMQAddressPort, messaging.ZEROMQ, encoding)
+	if err != nil {
+		return fmt.Errorf("couldn't create event publisher: %v", err.Error())
+	}
 
+	registerHealthChecks(conf)
 	return nil
 }
+
+// Run the background workers of core data and release its resources on shutdown.
+// The publisher is flushed before the database is closed.
+func RegisterLifecycle(svc *lifecycle.Service) {
+	svc.Go(devices.Worker)
+	svc.Go(events.AlertWorker)
+	svc.OnShutdown("event publisher", func() error {
+		return messaging.CurrentPublisher.Close()
+	})
+	svc.OnShutdown("database client", func() error {
+		clients.CurrentClient.CloseSession()
+		return nil
+	})
+}
+
+// Dependencies reported by the readiness endpoint
+func registerHealthChecks(conf *config.ConfigurationStruct) {
+	health.Register("database", func() error {
+		return clients.CurrentClient.Ping()
+	})
+	health.Register("messagebus", func() error {
+		if messaging.CurrentPublisher == nil {
+			return errors.New("event publisher not initialized")
+		}
+		return messaging.CurrentPublisher.Status()
+	})
+	health.Register("core-metadata", health.ResolvedURLCheck(
+		registry.Endpoint{ServiceName: conf.MetaServiceName, URL: conf.MetaPingURL}.Resolve))
+}
+
+// Metadata resolved through the registry when it is used, the configured URLs otherwise
+func metadataEndpoints(conf *config.ConfigurationStruct) metadataclients.Endpoints {
+	return metadataclients.Endpoints{
+		ServiceName:      conf.MetaServiceName,
+		AddressableURL:   conf.MetaAddressableURL,
+		DeviceURL:        conf.MetaDeviceURL,
+		CommandURL:       conf.MetaCommandURL,
+		DeviceServiceURL: conf.MetaDeviceServiceURL,
+		DeviceProfileURL: conf.MetaDeviceProfileURL,
+	}
+}
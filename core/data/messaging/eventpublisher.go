@@ -18,7 +18,10 @@
 package messaging
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/edgex-go/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/core/domain/codec"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/edgexfoundry/edgex-go/core/data/messaging/mocks"
 )
@@ -32,7 +35,10 @@ const (
 )
 
 type EventPublisher interface {
-	SendEventMessage(e models.Event) error
+	// The message carries the trace of ctx, if any, in its headers
+	SendEventMessage(ctx context.Context, e models.Event) error
+	Close() error
+	Status() error
 }
 
 // Publisher to send events to northbound services
@@ -41,27 +47,43 @@ type EdgeXEventPublisher struct {
 	zmq      MQClient
 }
 
-func NewMQPublisher(addrPort string, pubType MQPublisherType) EventPublisher {
+// The encoding selects the wire format of published events, nil meaning JSON
+// Fails if the socket can't be created or bound
+func NewMQPublisher(addrPort string, pubType MQPublisherType, encoding codec.Codec) (EventPublisher, error) {
 	var p EventPublisher
 	if pubType == MOCK {
 		p = &mocks.MockEventPublisher{}
 	} else {
-		conf := zeroMQConfiguration{AddressPort: addrPort}
-		p = &EdgeXEventPublisher{protocol: pubType, zmq: newZeroMQEventPublisher(conf)}
+		conf := zeroMQConfiguration{AddressPort: addrPort, Encoding: encoding}
+		zmq, err := newZeroMQEventPublisher(conf)
+		if err != nil {
+			return nil, err
+		}
+		p = &EdgeXEventPublisher{protocol: pubType, zmq: zmq}
 	}
 	CurrentPublisher = p
-	return p
+	return p, nil
 }
 
 // Send the event
-func (ep *EdgeXEventPublisher) SendEventMessage(e models.Event) error {
+func (ep *EdgeXEventPublisher) SendEventMessage(ctx context.Context, e models.Event) error {
 	// Switch based on the protocol you're using
 	switch ep.protocol {
 	case ZEROMQ:
-		return ep.zmq.SendEventMessage(e)
+		return ep.zmq.SendEventMessage(ctx, e)
 	default:
 		return errors.UnsupportedPublisher{}
 	}
 }
 
+// Flush and close the publisher
+func (ep *EdgeXEventPublisher) Close() error {
+	return ep.zmq.Close()
+}
+
+// Error of the last publication or of a closed publisher
+func (ep *EdgeXEventPublisher) Status() error {
+	return ep.zmq.Status()
+}
+
 var CurrentPublisher MQClient
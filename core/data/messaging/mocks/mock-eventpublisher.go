@@ -18,6 +18,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 )
 
@@ -25,6 +27,14 @@ type MockEventPublisher struct {
 
 }
 
-func (ep *MockEventPublisher) SendEventMessage(e models.Event) error {
+func (ep *MockEventPublisher) SendEventMessage(ctx context.Context, e models.Event) error {
+	return nil
+}
+
+func (ep *MockEventPublisher) Close() error {
+	return nil
+}
+
+func (ep *MockEventPublisher) Status() error {
 	return nil
-}
\ No newline at end of file
+}
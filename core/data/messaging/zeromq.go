@@ -18,48 +18,162 @@
 package messaging
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"sync"
+	"time"
 
+	"github.com/edgexfoundry/edgex-go/core/domain/codec"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
 	zmq "github.com/pebbe/zmq4"
 )
 
 type MQClient interface {
-	SendEventMessage(e models.Event) error
+	SendEventMessage(ctx context.Context, e models.Event) error
+	// Flush pending messages and release the connection
+	Close() error
+	// Error of the last publication, nil once a message went out again
+	Status() error
 }
 
+var errClosed = errors.New("publisher closed")
+
+// Time given to pending messages to leave the socket when it is closed
+const zeroMQFlushTimeout = 2 * time.Second
+
 // Configuration struct for ZeroMQ
 type zeroMQConfiguration struct {
 	AddressPort string
+	Encoding    codec.Codec
 }
 
 // ZeroMQ implementation of the event publisher
 type zeroMQClient struct {
-	socket	*zmq.Socket
-	mux     sync.Mutex
+	socket   *zmq.Socket
+	encoding codec.Codec
+	lastErr  error
+	mux      sync.Mutex
 }
 
-func newZeroMQEventPublisher(config zeroMQConfiguration) MQClient {
-	newSocket, _ := zmq.NewSocket(zmq.PUB)
-	newSocket.Bind(config.AddressPort)
+func newZeroMQEventPublisher(config zeroMQConfiguration) (MQClient, error) {
+	newSocket, err := zmq.NewSocket(zmq.PUB)
+	if err != nil {
+		return nil, err
+	}
+	if err = newSocket.Bind(config.AddressPort); err != nil {
+		newSocket.Close()
+		return nil, err
+	}
 
-	return &zeroMQClient{
-		socket: newSocket,
+	encoding := config.Encoding
+	if encoding == nil {
+		encoding, _ = codec.ForName("")
 	}
+
+	return &zeroMQClient{
+		socket:   newSocket,
+		encoding: encoding,
+	}, nil
 }
 
-func (zep *zeroMQClient) SendEventMessage(e models.Event) error {
-	s, err := json.Marshal(&e)
+func (zep *zeroMQClient) SendEventMessage(ctx context.Context, e models.Event) error {
+	span, ctx := tracing.StartChildSpan(ctx, "publish event", ext.SpanKindProducer)
+	defer span.Finish()
+
+	s, err := zep.encoding.EncodeEvent(e)
 	if err != nil {
 		return err
 	}
+	// First frame is the content type so receivers can pick the matching codec,
+	// an optional third frame holds the JSON encoded headers of the trace
+	frames := []interface{}{zep.encoding.ContentType(), s}
+	if headers := tracing.MessageHeaders(ctx); headers != nil {
+		h, err := json.Marshal(headers)
+		if err != nil {
+			return err
+		}
+		frames = append(frames, h)
+	}
+
 	zep.mux.Lock()
 	defer zep.mux.Unlock()
-	_, err = zep.socket.SendBytes(s, 0)
+	if zep.socket == nil {
+		return errClosed
+	}
+	_, err = zep.socket.SendMessage(frames...)
+	zep.lastErr = err
 	if err != nil {
+		ext.Error.Set(span, true)
 		return err
 	}
 
 	return nil
 }
+
+// The publisher is down once it's closed or when the last send failed
+func (zep *zeroMQClient) Status() error {
+	zep.mux.Lock()
+	defer zep.mux.Unlock()
+	if zep.socket == nil {
+		return errClosed
+	}
+	return zep.lastErr
+}
+
+// Wait for a send in progress, then close the socket giving queued messages time to go out
+func (zep *zeroMQClient) Close() error {
+	zep.mux.Lock()
+	defer zep.mux.Unlock()
+	if zep.socket == nil {
+		return nil
+	}
+	zep.socket.SetLinger(zeroMQFlushTimeout)
+	err := zep.socket.Close()
+	zep.socket = nil
+	return err
+}
+
+// ZeroMQ publisher of raw payloads, the first frame of every message is its topic so
+// subscribers can filter on it
+type TopicPublisher struct {
+	socket *zmq.Socket
+	mux    sync.Mutex
+}
+
+func NewTopicPublisher(addrPort string) (*TopicPublisher, error) {
+	newSocket, err := zmq.NewSocket(zmq.PUB)
+	if err != nil {
+		return nil, err
+	}
+	if err = newSocket.Bind(addrPort); err != nil {
+		newSocket.Close()
+		return nil, err
+	}
+	return &TopicPublisher{socket: newSocket}, nil
+}
+
+func (tp *TopicPublisher) Publish(topic string, payload []byte) error {
+	tp.mux.Lock()
+	defer tp.mux.Unlock()
+	if tp.socket == nil {
+		return errClosed
+	}
+	_, err := tp.socket.SendMessage(topic, payload)
+	return err
+}
+
+// Close the socket giving queued messages time to go out
+func (tp *TopicPublisher) Close() error {
+	tp.mux.Lock()
+	defer tp.mux.Unlock()
+	if tp.socket == nil {
+		return nil
+	}
+	tp.socket.SetLinger(zeroMQFlushTimeout)
+	err := tp.socket.Close()
+	tp.socket = nil
+	return err
+}
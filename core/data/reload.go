@@ -0,0 +1,74 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/data/config"
+	pkgconfig "github.com/edgexfoundry/edgex-go/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
+)
+
+// Target of the logs, the file or the remote logging service
+func LoggingTarget(conf *config.ConfigurationStruct) string {
+	if !conf.EnableRemoteLogging {
+		return conf.LoggingFile
+	}
+	return conf.LoggingRemoteURL
+}
+
+// Watch the configuration file of the profile and, when the registry is used, the key/value pairs
+// of the service, applying the changes of the reloadable fields while the service runs.
+// The logs go to lc, which follows the changes of the logging settings.
+func WatchConfiguration(svc *lifecycle.Service, profile string, useRegistry bool, lc *logger.ReloadableClient) {
+	reloader := pkgconfig.NewReloader(config.Current(), func(next interface{}) {
+		config.Swap(next.(*config.ConfigurationStruct))
+	}, lc)
+	reloader.Subscribe(func(changes []pkgconfig.Change) {
+		for _, c := range changes {
+			switch c.Field {
+			case "EnableRemoteLogging", "LoggingFile", "LoggingRemoteURL":
+				conf := config.Current()
+				lc.Set(logger.NewClient(conf.ApplicationName, conf.EnableRemoteLogging, LoggingTarget(conf)))
+				return
+			}
+		}
+	})
+
+	svc.Go(reloader.WatchFile(profile))
+	r := registry.Current()
+	if !useRegistry || r == nil {
+		return
+	}
+	svc.Go(func(stop <-chan struct{}) {
+		conf := config.Current()
+		err := r.WatchKeyValuePairs(conf, conf.ServiceName, strings.Split(conf.ConsulProfilesActive, ";"), stop,
+			func(previous, next interface{}) {
+				reloader.Apply("registry", previous, next)
+			},
+			func(err error) {
+				lc.Error(fmt.Sprintf("error watching the key/values of the registry: %v", err.Error()))
+			})
+		if err != nil {
+			lc.Error(fmt.Sprintf("configuration in the registry not watched: %v", err.Error()))
+			<-stop
+		}
+	})
+}
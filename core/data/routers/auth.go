@@ -0,0 +1,35 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package routers
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+)
+
+// Scopes required by the core data routes: reads need read, changes need write
+// and the bulk removal of events needs admin. The configuration can add to or override them (AuthScopes).
+func AuthPolicy() *auth.Policy {
+	return auth.NewPolicy().
+		Public("/api/v1/ping", health.ApiLiveRoute, health.ApiReadyRoute).
+		Route(http.MethodDelete, "/api/v1/event/scrub", auth.ScopeAdmin).
+		Route(http.MethodDelete, "/api/v1/event/scruball", auth.ScopeAdmin).
+		Route(http.MethodDelete, "/api/v1/event/removeold/age/{age}", auth.ScopeAdmin)
+}
@@ -43,12 +43,11 @@ func (t routerType) Type() routerType {
 func NewRouter(t RouterTyper) (routing.RestRouter, error) {
 	switch t {
 	case Gorilla:
-		g := &gorillaRouter{mux.NewRouter() }
+		g := &gorillaRouter{router: mux.NewRouter()}
 		return g, nil
-		/*	break;
-		case Opentrace:
-			return nil, fmt.Errorf("opentrace not yet supported")
-			break;*/
+	case Opentrace:
+		o := &opentraceRouter{gorillaRouter{router: mux.NewRouter()}}
+		return o, nil
 	}
 	//effectively default case
 	return nil, fmt.Errorf("unrecognized router type")
@@ -22,14 +22,28 @@ import (
 	"net/http"
 
 	"github.com/edgexfoundry/edgex-go/core/data/routers/internal"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"github.com/edgexfoundry/edgex-go/routing"
 	"github.com/gorilla/mux"
 	)
 
 type gorillaRouter struct {
-	router *mux.Router
+	router      *mux.Router
+	middlewares []routing.Middleware
+}
+
+func (g *gorillaRouter) Use(mw ...routing.Middleware) {
+	g.middlewares = append(g.middlewares, mw...)
 }
 
 func (g *gorillaRouter) LoadRoutes() http.Handler {
+	// Metrics come first so they also count the requests rejected by other middlewares
+	g.router.Use(metrics.Middleware(metrics.GorillaRoute))
+	g.router.Use(mux.MiddlewareFunc(routing.RequestID))
+	for _, mw := range g.middlewares {
+		g.router.Use(mux.MiddlewareFunc(mw))
+	}
 	b := g.router.PathPrefix("/api/v1").Subrouter()
 
 	// EVENTS
@@ -44,6 +58,14 @@ func (g *gorillaRouter) LoadRoutes() http.Handler {
 	e.HandleFunc("/id/{id}", internal.EventIdHandler).Methods(http.MethodDelete, http.MethodPut)
 	e.HandleFunc("/device/{deviceId}/{limit:[0-9]+}", internal.GetEventByDeviceHandler).Methods(http.MethodGet)
 	e.HandleFunc("/device/{deviceId}", internal.DeleteByDeviceIdHandler).Methods(http.MethodDelete)
+	e.HandleFunc("/tag/{key}/{value}", internal.EventByTagHandler).Methods(http.MethodGet)
+	e.HandleFunc("/location/near/{longitude}/{latitude}/{radius}/{limit:[0-9]+}", internal.EventsNearHandler).Methods(http.MethodGet)
+	e.HandleFunc("/location/within/{limit:[0-9]+}", internal.EventsWithinHandler).Methods(http.MethodPost)
+	e.HandleFunc("/location/latest", internal.DevicePositionsHandler).Methods(http.MethodGet)
+	e.HandleFunc("/schedule/event/{event}/{limit:[0-9]+}", internal.EventByScheduleEventHandler).Methods(http.MethodGet)
+	e.HandleFunc("/schedule/event/{event}/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", internal.EventByScheduleEventHandler).Methods(http.MethodGet)
+	e.HandleFunc("/schedule/{schedule}/{limit:[0-9]+}", internal.EventByScheduleHandler).Methods(http.MethodGet)
+	e.HandleFunc("/schedule/{schedule}/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", internal.EventByScheduleHandler).Methods(http.MethodGet)
 	e.HandleFunc("/removeold/age/{age:[0-9]+}", internal.EventByAgeHandler).Methods(http.MethodDelete)
 	e.HandleFunc("/{start:[0-9]+}/{end:[0-9]+}/{limit:[0-9]+}", internal.EventByCreationTimeHandler).Methods(http.MethodGet)
 	e.HandleFunc("/device/{deviceId}/valuedescriptor/{valueDescriptor}/{limit:[0-9]+}", internal.ReadingByDeviceFilteredValueDescriptor).Methods(http.MethodGet)
@@ -56,6 +78,8 @@ func (g *gorillaRouter) LoadRoutes() http.Handler {
 	rd.HandleFunc("/id/{id}", internal.DeleteReadingByIdHandler).Methods(http.MethodDelete)
 	rd.HandleFunc("/{id}", internal.GetReadingByIdHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/device/{deviceId}/{limit:[0-9]+}", internal.ReadingByDeviceHandler).Methods(http.MethodGet)
+	rd.HandleFunc("/latest/device/{name}", internal.LatestReadingsByDeviceHandler).Methods(http.MethodGet)
+	rd.HandleFunc("/latest/name/{name}", internal.LatestReadingsByValueDescriptorHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/name/{name}/{limit:[0-9]+}", internal.ReadingByValueDescriptorHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/uomlabel/{uomLabel}/{limit:[0-9]+}", internal.ReadingByUomLabelHandler).Methods(http.MethodGet)
 	rd.HandleFunc("/label/{label}/{limit:[0-9]+}", internal.ReadingByLabelHandler).Methods(http.MethodGet)
@@ -75,9 +99,25 @@ func (g *gorillaRouter) LoadRoutes() http.Handler {
 	vd.HandleFunc("/devicename/{device}", internal.ValueDescriptorByDeviceHandler).Methods(http.MethodGet)
 	vd.HandleFunc("/deviceid/{id}", internal.ValueDescriptorByDeviceIdHandler).Methods(http.MethodGet)
 
+	// ALERT RULES
+	// /api/v1/alertrule
+	b.HandleFunc("/alertrule", internal.AlertRuleHandler).Methods(http.MethodGet, http.MethodPut, http.MethodPost)
+	ar := b.PathPrefix("/alertrule").Subrouter()
+	ar.HandleFunc("/name/{name}", internal.AlertRuleByNameHandler).Methods(http.MethodGet)
+	ar.HandleFunc("/{id}", internal.AlertRuleByIdHandler).Methods(http.MethodGet, http.MethodDelete)
+
 	// Ping Resource
 	// /api/v1/ping
 	b.HandleFunc("/ping", internal.PingHandler)
 
+	// Metrics Resource
+	// /api/v1/metrics
+	b.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	// Health Resource
+	// /api/v1/health
+	b.Handle("/health/live", health.LiveHandler()).Methods(http.MethodGet)
+	b.Handle("/health/ready", health.ReadyHandler()).Methods(http.MethodGet)
+
 	return g.router
 }
\ No newline at end of file
@@ -21,14 +21,22 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
 	"github.com/edgexfoundry/edgex-go/core/data/clients"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
 	"github.com/edgexfoundry/edgex-go/core/data/log"
+	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	"github.com/edgexfoundry/edgex-go/routing"
 	"github.com/edgexfoundry/edgex-go/support/logging-client"
 )
 
@@ -103,6 +111,280 @@ func TestGetEventByIdHandler(t *testing.T) {
 	testEventWithoutReadings(event, t)
 }
 
+func TestGetEventsByScheduleHandlers(t *testing.T) {
+	config.Configuration.ReadMaxLimit = 10
+	ticks := strconv.FormatInt(globalMockParams.EventAgeInTicks, 10)
+	tests := []struct {
+		path  string
+		count int
+	}{
+		{"/api/v1/event/schedule/" + globalMockParams.ScheduleName + "/10", 2},
+		{"/api/v1/event/schedule/" + globalMockParams.ScheduleName + "/1", 1},
+		{"/api/v1/event/schedule/" + globalMockParams.ScheduleName + "/0/" + ticks + "/10", 2},
+		{"/api/v1/event/schedule/" + globalMockParams.ScheduleName + "/0/1/10", 0},
+		{"/api/v1/event/schedule/unknown/10", 0},
+		{"/api/v1/event/schedule/event/" + globalMockParams.ScheduleEventName + "/10", 2},
+		{"/api/v1/event/schedule/event/" + globalMockParams.ScheduleEventName + "/" + ticks + "/" + ticks + "/10", 2},
+		{"/api/v1/event/schedule/event/" + globalMockParams.ScheduleName + "/10", 0},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status code %d", tt.path, w.Code)
+			continue
+		}
+		var events []models.Event
+		if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if len(events) != tt.count {
+			t.Errorf("%s: expected %d events, got %d", tt.path, tt.count, len(events))
+		}
+	}
+}
+
+func TestLocationHandlers(t *testing.T) {
+	config.Configuration.ReadMaxLimit = 10
+	near := "/api/v1/event/location/near/" + strconv.FormatFloat(globalMockParams.Location.Longitude(), 'f', -1, 64) +
+		"/" + strconv.FormatFloat(globalMockParams.Location.Latitude(), 'f', -1, 64) + "/500/10"
+	polygon := `{"type":"Polygon","coordinates":[[[-1,-1],[1,-1],[1,1],[-1,1],[-1,-1]]]}`
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, near, "", http.StatusOK},
+		{http.MethodGet, "/api/v1/event/location/near/north/0/500/10", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/event/location/near/0/95/500/10", "", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/event/location/within/10", polygon, http.StatusOK},
+		{http.MethodPost, "/api/v1/event/location/within/10", `{"type":"Point","coordinates":[0,0]}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/event/location/latest", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s: status code %d, expected %d", tt.method, tt.path, w.Code, tt.status)
+		}
+	}
+}
+
+func TestLatestReadingsHandlers(t *testing.T) {
+	if err := events.LoadLatestReadings(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path  string
+		count int
+	}{
+		{"/api/v1/reading/latest/device/" + url.PathEscape(globalMockParams.DeviceName), 2},
+		{"/api/v1/reading/latest/name/" + globalMockParams.ReadingName, 1},
+		{"/api/v1/reading/latest/device/unknown", 0},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		var readings []models.Reading
+		if err := json.Unmarshal(w.Body.Bytes(), &readings); w.Code != http.StatusOK || err != nil {
+			t.Errorf("%s: status code %d, %v", tt.path, w.Code, err)
+			continue
+		}
+		if len(readings) != tt.count {
+			t.Errorf("%s: expected %d readings, got %d", tt.path, tt.count, len(readings))
+		}
+	}
+}
+
+func TestAlertRuleHandlers(t *testing.T) {
+	config.Configuration.ReadMaxLimit = 10
+	rule := `{"name":"Cold","kind":"THRESHOLD","valueDescriptor":"Temperature","min":0,"hysteresis":2}`
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/alertrule", "", http.StatusOK},
+		{http.MethodPost, "/api/v1/alertrule", rule, http.StatusOK},
+		{http.MethodPost, "/api/v1/alertrule", `{"name":"Cold","kind":"THRESHOLD","valueDescriptor":"Temperature"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/alertrule", `{"name":"` + globalMockParams.AlertRuleName + `","kind":"STALE","device":"dev","timeout":1000}`, http.StatusConflict},
+		{http.MethodPut, "/api/v1/alertrule", `{"name":"` + globalMockParams.AlertRuleName + `","kind":"STALE","device":"dev","timeout":1000}`, http.StatusOK},
+		{http.MethodPut, "/api/v1/alertrule", rule, http.StatusNotFound},
+		{http.MethodGet, "/api/v1/alertrule/" + globalMockParams.AlertRuleId.Hex(), "", http.StatusOK},
+		{http.MethodGet, "/api/v1/alertrule/name/" + globalMockParams.AlertRuleName, "", http.StatusOK},
+		{http.MethodGet, "/api/v1/alertrule/name/unknown", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/alertrule/" + globalMockParams.AlertRuleId.Hex(), "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s %s: status code %d, expected %d", tt.method, tt.path, tt.body, w.Code, tt.status)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/event/"+globalMockParams.EventId.Hex(), nil)
+	testRoutes.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+	w := httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Error("value expected, status code " + strconv.Itoa(w.Code) + " " + req.Method + " " + req.URL.Path)
+		return
+	}
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		`edgex_http_requests_total{route="/api/v1/event/{id}",method="GET",code="200"}`,
+		`edgex_db_operation_duration_seconds_count{operation="EventById"}`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Error("metric missing from response: " + expected)
+		}
+	}
+}
+
+func TestHealthHandlers(t *testing.T) {
+	defer health.Reset()
+	health.Register("database", clients.CurrentClient.Ping)
+
+	for _, path := range []string{"/api/v1/health/live", "/api/v1/health/ready"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		if w.Code != 200 {
+			t.Error("ready expected, status code " + strconv.Itoa(w.Code) + " " + req.Method + " " + req.URL.Path)
+			continue
+		}
+		var report health.Report
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil || report.Status != health.StatusUp {
+			t.Error("unexpected report " + w.Body.String())
+		}
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	a, _ := auth.NewAuthenticator(auth.Config{Enabled: true, APIKeys: "reader:read,admin:admin"})
+	r, _ := NewRouter(Gorilla)
+	r.Use(auth.Middleware(a, AuthPolicy()))
+	routes := r.LoadRoutes()
+
+	tests := []struct {
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{http.MethodGet, "/api/v1/ping", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/event/count", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/event/count", "reader", http.StatusOK},
+		{http.MethodDelete, "/api/v1/event/scruball", "reader", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/event/scruball", "admin", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set(auth.APIKeyHeader, tt.key)
+		}
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, req)
+
+		if w.Code != tt.want {
+			t.Error("status code " + strconv.Itoa(w.Code) + ", expected " + strconv.Itoa(tt.want) + " " + req.Method + " " + req.URL.Path)
+		}
+	}
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	config.Configuration.ReadMaxLimit = 0
+	tests := []struct {
+		method    string
+		path      string
+		body      string
+		requestID string
+		status    int
+		code      string
+	}{
+		{http.MethodGet, "/api/v1/event", "", "", http.StatusRequestEntityTooLarge, errs.CodeLimitExceeded},
+		{http.MethodPost, "/api/v1/event", "{", "abc123", http.StatusBadRequest, errs.CodeInvalidRequest},
+		{http.MethodPut, "/api/v1/event/id/not-an-id", "", "", http.StatusBadRequest, errs.CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.requestID != "" {
+			req.Header.Set(routing.RequestIDHeader, tt.requestID)
+		}
+		w := httptest.NewRecorder()
+		testRoutes.ServeHTTP(w, req)
+
+		var e errs.ServiceError
+		if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+			t.Error("error envelope expected, received " + w.Body.String() + " " + req.Method + " " + req.URL.Path)
+			continue
+		}
+		if w.Code != tt.status || e.StatusCode != tt.status || e.Code != tt.code || e.Message == "" {
+			t.Error("unexpected error " + strconv.Itoa(w.Code) + " " + w.Body.String() + " " + req.Method + " " + req.URL.Path)
+		}
+		if e.RequestID == "" || e.RequestID != w.Header().Get(routing.RequestIDHeader) {
+			t.Error("request ID missing from the response " + w.Body.String() + " " + req.Method + " " + req.URL.Path)
+		}
+		if tt.requestID != "" && e.RequestID != tt.requestID {
+			t.Error("request ID of the caller not kept " + e.RequestID)
+		}
+	}
+}
+
+type spanRecorder struct {
+	spans []tracing.Span
+}
+
+func (r *spanRecorder) Export(s tracing.Span) {
+	r.spans = append(r.spans, s)
+}
+
+func (r *spanRecorder) Close() error {
+	return nil
+}
+
+func TestOpentraceRouter(t *testing.T) {
+	recorder := &spanRecorder{}
+	tracing.RegisterExporter("test", func(string) (tracing.Exporter, error) { return recorder, nil })
+	tracer, _ := tracing.Init("core-data", tracing.Config{Enabled: true, Exporter: "test"})
+	defer tracer.Close()
+
+	r, err := NewRouter(Opentrace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/event/count/"+globalMockParams.DeviceId.Hex(), nil)
+	w := httptest.NewRecorder()
+	r.LoadRoutes().ServeHTTP(w, req)
+
+	if len(recorder.spans) != 1 || recorder.spans[0].Operation != "GET /api/v1/event/count/{deviceId}" {
+		t.Errorf("unexpected spans %v", recorder.spans)
+	}
+}
+
 func testEventWithoutReadings(event models.Event, t *testing.T) {
 	if event.ID.Hex() != globalMockParams.EventId.Hex() {
 		t.Error("eventId mismatch. expected " + globalMockParams.EventId.Hex() + " received " + event.ID.Hex())
@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/gorilla/mux"
+)
+
+// GET, POST, and PUT for alert rules
+// HTTP 400 if the rule is invalid, HTTP 409 if its name is already used
+// api/v1/alertrule
+func AlertRuleHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := events.GetAllAlertRules()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// Check the limit
+		if len(rules) > getConfiguration().ReadMaxLimit {
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
+			return
+		}
+
+		encode(rules, w)
+	case http.MethodPost:
+		rule := models.AlertRule{}
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			writeError(w, r, invalidBody(err))
+			return
+		}
+
+		id, err := events.AddAlertRule(rule)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(id))
+	case http.MethodPut:
+		rule := models.AlertRule{}
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			writeError(w, r, invalidBody(err))
+			return
+		}
+
+		if err := events.UpdateAlertRule(rule); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("true"))
+	}
+}
+
+// Get or delete an alert rule based on the ID
+// HTTP 404 not found if the ID isn't in the database
+// api/v1/alertrule/{id}
+func AlertRuleByIdHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id := mux.Vars(r)["id"]
+
+	switch r.Method {
+	case http.MethodGet:
+		rule, err := events.GetAlertRuleById(id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(rule, w)
+	case http.MethodDelete:
+		if err := events.DeleteAlertRuleById(id); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("true"))
+	}
+}
+
+// Get an alert rule based on the name
+// api/v1/alertrule/name/{name}
+func AlertRuleByNameHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	name, err := url.QueryUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, r, invalidParameter("name", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		rule, err := events.GetAlertRuleByName(name)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(rule, w)
+	}
+}
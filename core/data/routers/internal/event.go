@@ -19,15 +19,15 @@ package internal
 
 import (
 	"encoding/json"
-	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
 	"github.com/edgexfoundry/edgex-go/core/data/config"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/data/log"
-	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/edgexfoundry/edgex-go/support/logging-client"
 	"github.com/gorilla/mux"
@@ -38,7 +38,7 @@ func getLogger() logger.LoggingClient {
 }
 
 func getConfiguration() *config.ConfigurationStruct {
-	return config.Configuration
+	return config.Current()
 }
 
 // Undocumented feature to remove all readings and events from the database
@@ -52,7 +52,7 @@ func ScrubAllHandler(w http.ResponseWriter, r *http.Request) {
 
 		err := events.Purge()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			writeError(w, r, err)
 			return
 		}
 
@@ -62,6 +62,7 @@ func ScrubAllHandler(w http.ResponseWriter, r *http.Request) {
 
 /*
 Handler for the event API
+Status code 400 - malformed event
 Status code 404 - event not found
 Status code 413 - number of events exceeds limit
 Status code 503 - unanticipated issues
@@ -77,15 +78,13 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		events, err := events.GetAllEvents()
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			writeError(w, r, err)
 			return
 		}
 
 		// Check max limit
 		if len(events) > getConfiguration().ReadMaxLimit {
-			getLogger().Error(maxExceededString)
-			http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
 			return
 		}
 
@@ -99,17 +98,15 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Problem Decoding Event
 		if err != nil {
-			getLogger().Error("Error decoding event: " + err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		getLogger().Info("Posting Event: " + e.String())
 
-		id, err := events.AddNewEvent(e)
+		id, err := events.AddNewEvent(r.Context(), e)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -120,8 +117,7 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			err = encode(id, w)
 			if err != nil {
-				getLogger().Error(err.Error())
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeError(w, r, err)
 				return
 			}
 		}
@@ -135,19 +131,13 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Problem decoding event
 		if err != nil {
-			getLogger().Error("Error decoding the event: " + err.Error())
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		err = events.UpdateEvent(from)
-
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, fmt.Errorf("event id %s: %v", from.ID.Hex(), err.Error()).Error(), http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusServiceUnavailable)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -175,11 +165,7 @@ func GetEventByIdHandler(w http.ResponseWriter, r *http.Request) {
 		// Get the event
 		e, err := events.GetEventById(id)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, fmt.Errorf("event id %s: %v", id, err.Error()).Error(), http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusServiceUnavailable)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -199,7 +185,7 @@ func EventCountHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		count, err := events.CountEvents()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			writeError(w, r, err)
 			return
 		}
 
@@ -223,8 +209,7 @@ func EventCountByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := url.QueryUnescape(vars["deviceId"])
 	if err != nil {
-		getLogger().Error("Problem unescaping URL: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("deviceId", err))
 		return
 	}
 
@@ -233,7 +218,7 @@ func EventCountByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 
 		count, err := events.CountByDevice(id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -261,11 +246,7 @@ func EventIdHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPut:
 		err := events.Touch(id)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, fmt.Errorf("event id %s: %v", id, err.Error()).Error(), http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -277,12 +258,8 @@ func EventIdHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodDelete:
 		// Check if the event exists
 		err := events.DeleteEventById(id)
-		if err != nil {
-			if err == errs.ErrNotFound { //One could make the argument that this shouldn't throw an error
-				http.Error(w, fmt.Errorf("event id %s: %v", id, err.Error()).Error(), http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+		if err != nil { //One could make the argument that not found shouldn't throw an error
+			writeError(w, r, err)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -305,8 +282,7 @@ func GetEventByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Problems unescaping URL
 	if err != nil {
-		getLogger().Error("Error unescaping URL: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("deviceId", err))
 		return
 	}
 
@@ -315,15 +291,13 @@ func GetEventByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 		// Convert limit to int
 		limitNum, err := strconv.Atoi(limit)
 		if err != nil {
-			getLogger().Error("Error converting to integer: " + err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, invalidParameter("limit", err))
 			return
 		}
 
 		eventList, err := events.GetEventsByDevice(deviceId, limitNum)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -342,8 +316,7 @@ func DeleteByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 	deviceId, err := url.QueryUnescape(vars["deviceId"])
 	// Problems unescaping URL
 	if err != nil {
-		getLogger().Error("Error unescaping the URL: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("deviceId", err))
 		return
 	}
 
@@ -351,8 +324,7 @@ func DeleteByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodDelete:
 		count, err := events.DeleteByDevice(deviceId)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -362,6 +334,230 @@ func DeleteByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Get the events carrying a tag
+// {key} - tag key, {value} - tag value
+// 413 - number of events exceeds limit
+// 503 - service unavailable
+// api/v1/event/tag/{key}/{value}
+func EventByTagHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+	key, err := url.QueryUnescape(vars["key"])
+	if err != nil {
+		writeError(w, r, invalidParameter("key", err))
+		return
+	}
+	value, err := url.QueryUnescape(vars["value"])
+	if err != nil {
+		writeError(w, r, invalidParameter("value", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		e, err := events.GetEventsByTag(key, value)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// Check max limit
+		if len(e) > getConfiguration().ReadMaxLimit {
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
+			return
+		}
+
+		encode(e, w)
+	}
+}
+
+// Get the events collected by a schedule, optionally created in a time range
+// {schedule} - schedule name, {start} - start time, {end} - end time, {limit} - max number of results
+// 413 - number of results exceeds limit
+// 503 - service unavailable
+// api/v1/event/schedule/{schedule}/{limit}
+// api/v1/event/schedule/{schedule}/{start}/{end}/{limit}
+func EventByScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+	schedule, err := url.QueryUnescape(vars["schedule"])
+	if err != nil {
+		writeError(w, r, invalidParameter("schedule", err))
+		return
+	}
+
+	start, end, limit, err := scheduleQueryVars(vars)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		e, err := events.GetEventsBySchedule(schedule, start, end, limit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(e, w)
+	}
+}
+
+// Get the events collected by a schedule event, optionally created in a time range
+// {event} - schedule event name, {start} - start time, {end} - end time, {limit} - max number of results
+// 413 - number of results exceeds limit
+// 503 - service unavailable
+// api/v1/event/schedule/event/{event}/{limit}
+// api/v1/event/schedule/event/{event}/{start}/{end}/{limit}
+func EventByScheduleEventHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+	scheduleEvent, err := url.QueryUnescape(vars["event"])
+	if err != nil {
+		writeError(w, r, invalidParameter("event", err))
+		return
+	}
+
+	start, end, limit, err := scheduleQueryVars(vars)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		e, err := events.GetEventsByScheduleEvent(scheduleEvent, start, end, limit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(e, w)
+	}
+}
+
+// Parse the time range and the limit of the schedule queries
+// Without {start} and {end} the events of any creation time are returned
+func scheduleQueryVars(vars map[string]string) (start int64, end int64, limit int, err error) {
+	start, end = 0, math.MaxInt64
+	if s, ok := vars["start"]; ok {
+		if start, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return 0, 0, 0, invalidParameter("start", err)
+		}
+		if end, err = strconv.ParseInt(vars["end"], 10, 64); err != nil {
+			return 0, 0, 0, invalidParameter("end", err)
+		}
+	}
+
+	if limit, err = strconv.Atoi(vars["limit"]); err != nil {
+		return 0, 0, 0, invalidParameter("limit", err)
+	}
+	return start, end, limit, nil
+}
+
+// Get the events located within a radius of a point, nearest first
+// {longitude}, {latitude} - center in degrees, {radius} - radius in meters, {limit} - max number of results
+// 400 - malformed point or radius
+// 503 - service unavailable
+// api/v1/event/location/near/{longitude}/{latitude}/{radius}/{limit}
+func EventsNearHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	vars := mux.Vars(r)
+	longitude, err := strconv.ParseFloat(vars["longitude"], 64)
+	if err != nil {
+		writeError(w, r, invalidParameter("longitude", err))
+		return
+	}
+	latitude, err := strconv.ParseFloat(vars["latitude"], 64)
+	if err != nil {
+		writeError(w, r, invalidParameter("latitude", err))
+		return
+	}
+	radius, err := strconv.ParseFloat(vars["radius"], 64)
+	if err != nil {
+		writeError(w, r, invalidParameter("radius", err))
+		return
+	}
+	limit, err := strconv.Atoi(vars["limit"])
+	if err != nil {
+		writeError(w, r, invalidParameter("limit", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		e, err := events.GetEventsNear(models.NewGeoPoint(longitude, latitude), radius, limit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(e, w)
+	}
+}
+
+// Get the events located within the GeoJSON polygon of the body
+// {limit} - max number of results
+// 400 - malformed polygon
+// 503 - service unavailable
+// api/v1/event/location/within/{limit}
+func EventsWithinHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	limit, err := strconv.Atoi(mux.Vars(r)["limit"])
+	if err != nil {
+		writeError(w, r, invalidParameter("limit", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var polygon models.GeoPolygon
+		if err := json.NewDecoder(r.Body).Decode(&polygon); err != nil {
+			writeError(w, r, invalidBody(err))
+			return
+		}
+
+		e, err := events.GetEventsWithin(polygon, limit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		encode(e, w)
+	}
+}
+
+// Get the latest position of every device sending located events
+// 413 - number of devices exceeds limit
+// 503 - service unavailable
+// api/v1/event/location/latest
+func DevicePositionsHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err := events.GetLatestDevicePositions()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// Check max limit
+		if len(p) > getConfiguration().ReadMaxLimit {
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
+			return
+		}
+
+		encode(p, w)
+	}
+}
+
 // Get events by creation time
 // {start} - start time, {end} - end time, {limit} - max number of results
 // Sort the events by creation date
@@ -375,24 +571,21 @@ func EventByCreationTimeHandler(w http.ResponseWriter, r *http.Request) {
 	start, err := strconv.ParseInt(vars["start"], 10, 64)
 	// Problems converting start time
 	if err != nil {
-		getLogger().Error("Problem converting start time: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("start", err))
 		return
 	}
 
 	end, err := strconv.ParseInt(vars["end"], 10, 64)
 	// Problems converting end time
 	if err != nil {
-		getLogger().Error("Problem converting end time: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("end", err))
 		return
 	}
 
 	limit, err := strconv.Atoi(vars["limit"])
 	// Problems converting limit
 	if err != nil {
-		getLogger().Error("Problem converting limit: " + strconv.Itoa(limit))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
@@ -400,8 +593,7 @@ func EventByCreationTimeHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		e, err := events.GetEventsByCreateTime(start, end, limit)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -422,32 +614,28 @@ func ReadingByDeviceFilteredValueDescriptor(w http.ResponseWriter, r *http.Reque
 	valueDescriptor, err := url.QueryUnescape(vars["valueDescriptor"])
 	// Problems unescaping URL
 	if err != nil {
-		getLogger().Error("Problem unescaping value descriptor: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("valueDescriptor", err))
 		return
 	}
 
 	deviceId, err := url.QueryUnescape(vars["deviceId"])
 	// Problems unescaping URL
 	if err != nil {
-		getLogger().Error("Problem unescaping device ID: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("deviceId", err))
 		return
 	}
 
 	limitNum, err := strconv.Atoi(limit)
 	// Problem converting the limit
 	if err != nil {
-		getLogger().Error("Problem converting limit to integer: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 	switch r.Method {
 	case http.MethodGet:
 		readings, err := events.GetReadingsByDeviceAndValueDescriptor(deviceId, valueDescriptor, limitNum)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 		encode(readings, w)
@@ -464,8 +652,7 @@ func EventByAgeHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Problem converting age
 	if err != nil {
-		getLogger().Error("Error converting the age to an integer")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("age", err))
 		return
 	}
 
@@ -475,8 +662,7 @@ func EventByAgeHandler(w http.ResponseWriter, r *http.Request) {
 
 		count, err := events.DeleteByAge(age)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -499,8 +685,7 @@ func ScrubHandler(w http.ResponseWriter, r *http.Request) {
 
 		count, err := events.PurgeIfPublished()
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -508,4 +693,4 @@ func ScrubHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(strconv.Itoa(count)))
 	}
-}
+}
\ No newline at end of file
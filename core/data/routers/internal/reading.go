@@ -24,9 +24,9 @@ import (
 	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"github.com/edgexfoundry/edgex-go/core/domain/errs"
 )
 
 
@@ -37,20 +37,19 @@ func ReadingHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
-		r, err := events.GetAllReadings()
+		readings, err := events.GetAllReadings()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		// Check max limit
-		if len(r) > getConfiguration().ReadMaxLimit {
-			http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
-			getLogger().Error(maxExceededString)
+		if len(readings) > getConfiguration().ReadMaxLimit {
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
 			return
 		}
 
-		encode(r, w)
+		encode(readings, w)
 	case http.MethodPost:
 		reading := models.Reading{}
 		dec := json.NewDecoder(r.Body)
@@ -58,19 +57,13 @@ func ReadingHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Problem decoding
 		if err != nil {
-			getLogger().Error("Error decoding the reading: " + err.Error())
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		id, err := events.AddNewReading(reading)
 		if err != nil {
-			getLogger().Error(err.Error())
-			if err == errs.ErrNotFound {
-				http.Error(w, "Value descriptor not found for reading", http.StatusConflict)
-			} else {
-				http.Error(w, err.Error(), http.StatusServiceUnavailable)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -81,8 +74,7 @@ func ReadingHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			err = encode(id, w)
 			if err != nil {
-				getLogger().Error(err.Error())
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeError(w, r, err)
 				return
 			}
 		}
@@ -93,18 +85,13 @@ func ReadingHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Problem decoding
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error("Error decoding the reading: " + err.Error())
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		err = events.UpdateReading(from)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, "Reading not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -127,11 +114,7 @@ func GetReadingByIdHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		reading, err := events.GetReadingById(id)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, "Reading not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusServiceUnavailable)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -148,7 +131,7 @@ func ReadingCountHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		count, err := events.CountReadings()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -173,7 +156,7 @@ func DeleteReadingByIdHandler(w http.ResponseWriter, r *http.Request) {
 		// Check if the reading exists
 		err := events.DeleteReadingById(id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -193,15 +176,13 @@ func ReadingByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	limit, err := strconv.Atoi(vars["limit"])
 	// Problems converting limit to int
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 	deviceId, err := url.QueryUnescape(vars["deviceId"])
 	// Problems unescaping URL
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error unescaping the device ID: " + err.Error())
+		writeError(w, r, invalidParameter("deviceId", err))
 		return
 	}
 
@@ -209,11 +190,7 @@ func ReadingByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		readings, err := events.GetReadingsByDevice(deviceId, limit)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, err.Error(), http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -221,6 +198,42 @@ func ReadingByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Get the latest reading of every value descriptor of the device, sorted by value descriptor
+// The readings are served from memory, an unknown device has no readings
+// api/v1/reading/latest/device/{name}
+func LatestReadingsByDeviceHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	name, err := url.QueryUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, r, invalidParameter("name", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		encode(events.GetLatestReadingsByDevice(name), w)
+	}
+}
+
+// Get the latest reading of the value descriptor for every device, sorted by device
+// The readings are served from memory, an unknown value descriptor has no readings
+// api/v1/reading/latest/name/{name}
+func LatestReadingsByValueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	name, err := url.QueryUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		writeError(w, r, invalidParameter("name", err))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		encode(events.GetLatestReadingsByValueDescriptor(name), w)
+	}
+}
+
 // Return a list of all readings associated with a value descriptor, limited by limit
 // HTTP 413 (limit exceeded) if the limit is greater than max limit
 // api/v1/reading/name/{name}/{limit}
@@ -231,26 +244,20 @@ func ReadingByValueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
 	name, err := url.QueryUnescape(vars["name"])
 	// Problems with unescaping URL
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping value descriptor name: " + err.Error())
+		writeError(w, r, invalidParameter("name", err))
 		return
 	}
 	limit, err := strconv.Atoi(vars["limit"])
 	// Problems converting limit to int
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
 	// Check for value descriptor
 	readings, err := events.GetReadingsByValueDescriptor(name, limit)
 	if err != nil {
-		if err == errs.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+		writeError(w, r, err)
 		return
 	}
 
@@ -267,22 +274,20 @@ func ReadingByUomLabelHandler(w http.ResponseWriter, r *http.Request) {
 	uomLabel, err := url.QueryUnescape(vars["uomLabel"])
 	// Problems unescaping URL
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping the UOM Label: " + err.Error())
+		writeError(w, r, invalidParameter("uomLabel", err))
 		return
 	}
 
 	limit, err := strconv.Atoi(vars["limit"])
 	// Problems converting limit to int
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
 	readings, err := events.GetReadingsByUomLabel(uomLabel, limit)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -299,22 +304,20 @@ func ReadingByLabelHandler(w http.ResponseWriter, r *http.Request) {
 	label, err := url.QueryUnescape(vars["label"])
 	// Problem unescaping
 	if err != nil {
-		getLogger().Error("Error unescaping the label of the value descriptor: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("label", err))
 		return
 	}
 	limit, err := strconv.Atoi(vars["limit"])
 	// Problems converting to int
 	if err != nil {
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
 	readings, err := events.GetReadingsByValueDescriptorLabel(label, limit)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -331,23 +334,21 @@ func ReadingByTypeHandler(w http.ResponseWriter, r *http.Request) {
 
 	t, err := url.QueryUnescape(vars["type"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error escaping the type: " + err.Error())
+		writeError(w, r, invalidParameter("type", err))
 		return
 	}
 
 	l, err := strconv.Atoi(vars["limit"])
 	// Problem converting to int
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
 	readings, err := events.GetReadingsByType(t, l)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -362,20 +363,17 @@ func ReadingByCreationTimeHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	s, err := strconv.ParseInt((vars["start"]), 10, 64)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the start time to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("start", err))
 		return
 	}
 	e, err := strconv.ParseInt((vars["end"]), 10, 64)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the end time to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("end", err))
 		return
 	}
 	l, err := strconv.Atoi(vars["limit"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error converting the limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
@@ -384,7 +382,7 @@ func ReadingByCreationTimeHandler(w http.ResponseWriter, r *http.Request) {
 
 		readings, err := events.GetReadingsByCreateTime(s, e, l)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
@@ -403,22 +401,19 @@ func ReadingByValueDescriptorAndDeviceHandler(w http.ResponseWriter, r *http.Req
 	// Get the variables from the URL
 	name, err := url.QueryUnescape(vars["name"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error unescaping the value descriptor name: " + err.Error())
+		writeError(w, r, invalidParameter("name", err))
 		return
 	}
 
 	device, err := url.QueryUnescape(vars["device"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error unescaping the device: " + err.Error())
+		writeError(w, r, invalidParameter("device", err))
 		return
 	}
 
 	limit, err := strconv.Atoi(vars["limit"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error converting limit to an integer: " + err.Error())
+		writeError(w, r, invalidParameter("limit", err))
 		return
 	}
 
@@ -426,10 +421,9 @@ func ReadingByValueDescriptorAndDeviceHandler(w http.ResponseWriter, r *http.Req
 	case http.MethodGet:
 		readings, err := events.GetReadingsByDeviceAndValueDescriptor(device, name, limit)
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 		encode(readings, w)
 	}
-}
+}
\ No newline at end of file
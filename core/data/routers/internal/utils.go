@@ -21,6 +21,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
+	"github.com/edgexfoundry/edgex-go/routing"
 )
 
 
@@ -37,6 +40,28 @@ func encode(i interface{}, w http.ResponseWriter) error {
 	return nil
 }
 
+// Helper function for returning errors from REST calls as the JSON error envelope.
+// The type of err decides the status code, see errors.NewServiceError.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	e := errors.NewServiceError(err)
+	e.RequestID = r.Header.Get(routing.RequestIDHeader)
+	getLogger().Error(fmt.Sprintf("%s %s failed with %d %s: %s (request %s)", r.Method, r.URL.Path, e.StatusCode, e.Code, e.Message, e.RequestID))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.StatusCode)
+	json.NewEncoder(w).Encode(e)
+}
+
+// The body of the request can't be decoded
+func invalidBody(err error) error {
+	return errors.InvalidRequest{Message: "invalid request body: " + err.Error()}
+}
+
+// The URL parameter name has a malformed value
+func invalidParameter(name string, err error) error {
+	return errors.InvalidRequest{Field: name, Message: "invalid " + name + ": " + err.Error()}
+}
+
 // Test if the service is working
 func PingHandler(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
@@ -19,20 +19,15 @@ package internal
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/url"
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"github.com/edgexfoundry/edgex-go/core/domain/errs"
+	"github.com/edgexfoundry/edgex-go/core/data/errors"
 	"github.com/edgexfoundry/edgex-go/core/aggregates/events"
 )
 
-const (
-	maxExceededString string = "Error, exceeded the max limit as defined in config"
-)
-
 
 // GET, POST, and PUT for value descriptors
 // api/v1/valuedescriptor
@@ -43,15 +38,13 @@ func ValueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		vList, err := events.GetAllValueDescriptors()
 		if err != nil {
-			getLogger().Error(err.Error())
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		// Check the limit
 		if len(vList) > getConfiguration().ReadMaxLimit {
-			http.Error(w, maxExceededString, http.StatusRequestEntityTooLarge)
-			getLogger().Error(maxExceededString)
+			writeError(w, r, errors.LimitExceeded{Limit: getConfiguration().ReadMaxLimit})
 			return
 		}
 
@@ -62,32 +55,24 @@ func ValueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
 		err := dec.Decode(&v)
 		// Problems decoding
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error("Error decoding the value descriptor: " + err.Error())
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		// Check the formatting
 		match, err := events.ValidateFormatString(v)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error("Error checking for format string for POSTed value descriptor")
+			writeError(w, r, err)
 			return
 		}
 		if !match {
-			err := errors.New("error posting value descriptor. Format is not a valid printf format")
-			http.Error(w, err.Error(), http.StatusConflict)
-			getLogger().Error(err.Error())
+			writeError(w, r, errors.BadValueDescriptor{VName: v.Name})
 			return
 		}
 
 		id, err := events.AddValueDescriptor(v)
 		if err != nil {
-			if err == errs.ErrNotUnique {
-				http.Error(w, "Value Descriptor already exists", http.StatusConflict)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			writeError(w, r, err)
 			return
 		}
 
@@ -98,19 +83,13 @@ func ValueDescriptorHandler(w http.ResponseWriter, r *http.Request) {
 		from := models.ValueDescriptor{}
 		err := dec.Decode(&from)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error("Error decoding the value descriptor: " + err.Error())
+			writeError(w, r, invalidBody(err))
 			return
 		}
 
 		err = events.UpdateValueDescriptor(from)
 		if err != nil {
-			if err == errs.ErrNotUnique {
-				http.Error(w, "Value descriptor name is not unique", http.StatusConflict)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
-			getLogger().Error(err.Error())
+			writeError(w, r, err)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -130,13 +109,8 @@ func DeleteValueDescriptorByIdHandler(w http.ResponseWriter, r *http.Request) {
 	id := vars["id"]
 
 	err := events.DeleteValueDescriptorById(id)
-	if err != nil {
-		getLogger().Error(err.Error())
-		if err == errs.ErrNotFound { //One could make the case that this should not throw an error
-			http.Error(w, "Value descriptor not found", http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
+	if err != nil { //One could make the case that not found should not throw an error
+		writeError(w, r, err)
 		return
 	}
 
@@ -155,8 +129,7 @@ func ValueDescriptorByNameHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Problems unescaping
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping the value descriptor name: " + err.Error())
+		writeError(w, r, invalidParameter("name", err))
 		return
 	}
 
@@ -164,12 +137,7 @@ func ValueDescriptorByNameHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		v, err := events.GetValueDescriptorByName(name)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, "Value Descriptor not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
-			getLogger().Error(err.Error())
+			writeError(w, r, err)
 			return
 		}
 
@@ -177,17 +145,13 @@ func ValueDescriptorByNameHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodDelete:
 		// Check if the value descriptor exists
 		vd, err := events.GetValueDescriptorByName(name)
-		if err != nil {
-			if err == errs.ErrNotFound { //One could make the argument that this should not throw an error
-				http.Error(w, "Value Descriptor not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
-			getLogger().Error(err.Error())
+		if err != nil { //One could make the argument that not found should not throw an error
+			writeError(w, r, err)
 			return
 		}
 
 		if err = events.DeleteValueDescriptorById(vd.Id.Hex()); err != nil {
+			writeError(w, r, err)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -209,12 +173,7 @@ func ValueDescriptorByIdHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		v, err := events.GetValueDescriptorById(id)
 		if err != nil {
-			if err == errs.ErrNotFound {
-				http.Error(w, "Value descriptor not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusServiceUnavailable)
-			}
-			getLogger().Error(err.Error())
+			writeError(w, r, err)
 			return
 		}
 
@@ -232,8 +191,7 @@ func ValueDescriptorByUomLabelHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Problem unescaping
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping the UOM Label of the value descriptor: " + err.Error())
+		writeError(w, r, invalidParameter("uomLabel", err))
 		return
 	}
 
@@ -241,8 +199,7 @@ func ValueDescriptorByUomLabelHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		v, err := events.GetValueDescriptorsByUomLabel(uomLabel)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error(err.Error())
+			writeError(w, r, err)
 			return
 		}
 
@@ -260,8 +217,7 @@ func ValueDescriptorByLabelHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Problem unescaping
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping label for the value descriptor: " + err.Error())
+		writeError(w, r, invalidParameter("label", err))
 		return
 	}
 
@@ -269,8 +225,7 @@ func ValueDescriptorByLabelHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		v, err := events.GetValueDescriptorsByLabel(label)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			getLogger().Error(err.Error())
+			writeError(w, r, err)
 			return
 		}
 
@@ -288,19 +243,13 @@ func ValueDescriptorByDeviceHandler(w http.ResponseWriter, r *http.Request) {
 
 	device, err := url.QueryUnescape(vars["device"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		getLogger().Error("Error unescaping the device: " + err.Error())
+		writeError(w, r, invalidParameter("device", err))
 		return
 	}
 
 	vdList, err := events.GetValueDescriptorsByDeviceName(device)
 	if err != nil {
-		if err == errs.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-		getLogger().Error(err.Error())
+		writeError(w, r, err)
 		return
 	}
 
@@ -317,21 +266,15 @@ func ValueDescriptorByDeviceIdHandler(w http.ResponseWriter, r *http.Request) {
 
 	deviceId, err := url.QueryUnescape(vars["id"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		getLogger().Error("Error unescaping the device ID: " + err.Error())
+		writeError(w, r, invalidParameter("id", err))
 		return
 	}
 
 	vdList, err := events.GetValueDescriptorsByDeviceId(deviceId)
 	if err != nil {
-		if err == errs.ErrNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
-		} else {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-		getLogger().Error(err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	encode(vdList, w)
-}
+}
\ No newline at end of file
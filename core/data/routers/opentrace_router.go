@@ -0,0 +1,37 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-data-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package routers
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+)
+
+// Gorilla routes recording a span for every request, named after the matched route.
+// Handlers find the span in the context of the request to pass the trace on.
+type opentraceRouter struct {
+	gorillaRouter
+}
+
+func (o *opentraceRouter) LoadRoutes() http.Handler {
+	// Tracing comes first so the span covers the work of the other middlewares
+	o.router.Use(tracing.Middleware(metrics.GorillaRoute))
+	return o.gorillaRouter.LoadRoutes()
+}
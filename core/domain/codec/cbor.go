@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package codec
+
+import (
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	ugorji "github.com/ugorji/go/codec"
+)
+
+// CBOR (RFC 7049) encoding of the flat wire form
+type cborCodec struct{}
+
+var cborHandle = &ugorji.CborHandle{}
+
+func (cborCodec) ContentType() string {
+	return ContentTypeCBOR
+}
+
+func (cborCodec) EncodeEvent(e models.Event) ([]byte, error) {
+	return cborEncode(toWireEvent(e))
+}
+
+func (cborCodec) DecodeEvent(data []byte) (models.Event, error) {
+	var w wireEvent
+	if err := ugorji.NewDecoderBytes(data, cborHandle).Decode(&w); err != nil {
+		return models.Event{}, err
+	}
+	return fromWireEvent(w)
+}
+
+func (cborCodec) EncodeReading(r models.Reading) ([]byte, error) {
+	return cborEncode(toWireReading(r))
+}
+
+func (cborCodec) DecodeReading(data []byte) (models.Reading, error) {
+	var w wireReading
+	if err := ugorji.NewDecoderBytes(data, cborHandle).Decode(&w); err != nil {
+		return models.Reading{}, err
+	}
+	return fromWireReading(w)
+}
+
+func cborEncode(v interface{}) ([]byte, error) {
+	var b []byte
+	err := ugorji.NewEncoderBytes(&b, cborHandle).Encode(v)
+	return b, err
+}
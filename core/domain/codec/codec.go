@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package codec provides the wire encodings used to exchange events and readings
+// between services. Every codec round-trips a model to exactly what decoding its
+// JSON form would produce.
+package codec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Content types carried in the header frame of published messages
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeCBOR     = "application/cbor"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Codec encodes and decodes events and readings in a single wire format
+type Codec interface {
+	// Content type identifying the encoding on the message bus
+	ContentType() string
+
+	EncodeEvent(e models.Event) ([]byte, error)
+	DecodeEvent(data []byte) (models.Event, error)
+
+	EncodeReading(r models.Reading) ([]byte, error)
+	DecodeReading(data []byte) (models.Reading, error)
+}
+
+var codecs = map[string]Codec{
+	ContentTypeJSON:     jsonCodec{},
+	ContentTypeCBOR:     cborCodec{},
+	ContentTypeProtobuf: protobufCodec{},
+}
+
+var names = map[string]string{
+	"json":     ContentTypeJSON,
+	"cbor":     ContentTypeCBOR,
+	"protobuf": ContentTypeProtobuf,
+}
+
+// Return the codec for a content type, as found in a message header
+func ForContentType(contentType string) (Codec, error) {
+	c, ok := codecs[strings.ToLower(strings.TrimSpace(contentType))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+	return c, nil
+}
+
+// Return the codec for a configured encoding name (json, cbor or protobuf)
+// An empty name selects JSON so that existing configurations keep working
+func ForName(name string) (Codec, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return codecs[ContentTypeJSON], nil
+	}
+	contentType, ok := names[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported message encoding: %s", name)
+	}
+	return codecs[contentType], nil
+}
+
+// Object IDs travel as hex strings, like in the JSON form
+func parseObjectId(id string) (bson.ObjectId, error) {
+	if id == "" {
+		return "", nil
+	}
+	if !bson.IsObjectIdHex(id) {
+		return "", fmt.Errorf("invalid object id: %s", id)
+	}
+	return bson.ObjectIdHex(id), nil
+}
@@ -0,0 +1,165 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package codec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var testReading = models.Reading{Id: bson.NewObjectId(), Pushed: 1, Created: 2, Origin: 3, Modified: 4,
+	Device: "device1", Name: "temperature", Value: "-12.5", Tags: models.Tags{"firmware": "1.2", "batch": "b-17"}}
+
+var testLocation = models.NewGeoPoint(6.1296, 49.6116)
+
+var testEvent = models.Event{ID: bson.NewObjectId(), Pushed: 5, Device: "device1", Created: 6, Modified: 7,
+	Origin: -8, Schedule: "schedule1", Event: "scheduleEvent1",
+	Readings: []models.Reading{testReading, {Name: "humidity", Value: "40"}}, Tags: models.Tags{"site": "plant-1"},
+	Location: &testLocation}
+
+func allCodecs() []Codec {
+	return []Codec{jsonCodec{}, cborCodec{}, protobufCodec{}}
+}
+
+// Decode the JSON form, which is what every codec has to reproduce
+func viaJSON(t *testing.T, in interface{}, out interface{}) {
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json marshal: %v", err)
+	}
+	if err = json.Unmarshal(b, out); err != nil {
+		t.Fatalf("json unmarshal: %v", err)
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		event models.Event
+	}{
+		{"full event", testEvent},
+		{"empty event", models.Event{}},
+		{"empty readings", models.Event{Device: "device1", Readings: []models.Reading{}}},
+		{"empty tags", models.Event{Device: "device1", Tags: models.Tags{}}},
+		{"location at 0,0", models.Event{Device: "device1", Location: &models.GeoPoint{Type: models.GeoJSONPoint, Coordinates: []float64{0, 0}}}},
+	}
+	for _, c := range allCodecs() {
+		for _, tt := range tests {
+			t.Run(c.ContentType()+"/"+tt.name, func(t *testing.T) {
+				var want models.Event
+				viaJSON(t, tt.event, &want)
+
+				b, err := c.EncodeEvent(tt.event)
+				if err != nil {
+					t.Fatalf("EncodeEvent() error = %v", err)
+				}
+				got, err := c.DecodeEvent(b)
+				if err != nil {
+					t.Fatalf("DecodeEvent() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("DecodeEvent() = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestReadingRoundTrip(t *testing.T) {
+	for _, c := range allCodecs() {
+		for _, r := range []models.Reading{testReading, {}} {
+			t.Run(c.ContentType(), func(t *testing.T) {
+				var want models.Reading
+				viaJSON(t, r, &want)
+
+				b, err := c.EncodeReading(r)
+				if err != nil {
+					t.Fatalf("EncodeReading() error = %v", err)
+				}
+				got, err := c.DecodeReading(b)
+				if err != nil {
+					t.Fatalf("DecodeReading() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("DecodeReading() = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestBinaryIsSmallerThanJSON(t *testing.T) {
+	j, _ := jsonCodec{}.EncodeEvent(testEvent)
+	for _, c := range []Codec{cborCodec{}, protobufCodec{}} {
+		b, _ := c.EncodeEvent(testEvent)
+		if len(b) >= len(j) {
+			t.Errorf("%s encoding is %d bytes, JSON is %d", c.ContentType(), len(b), len(j))
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, c := range allCodecs() {
+		if _, err := c.DecodeEvent([]byte{0xff, 0xff, 0xff}); err == nil {
+			t.Errorf("%s: expected an error decoding garbage", c.ContentType())
+		}
+	}
+}
+
+func TestForName(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantErr     bool
+	}{
+		{"", ContentTypeJSON, false},
+		{"json", ContentTypeJSON, false},
+		{"CBOR", ContentTypeCBOR, false},
+		{"protobuf", ContentTypeProtobuf, false},
+		{"xml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ForName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.ContentType() != tt.contentType {
+				t.Errorf("ForName() = %s, want %s", c.ContentType(), tt.contentType)
+			}
+		})
+	}
+}
+
+func TestForContentType(t *testing.T) {
+	for _, ct := range []string{ContentTypeJSON, ContentTypeCBOR, ContentTypeProtobuf} {
+		c, err := ForContentType(ct)
+		if err != nil {
+			t.Fatalf("ForContentType(%s) error = %v", ct, err)
+		}
+		if c.ContentType() != ct {
+			t.Errorf("ForContentType(%s) = %s", ct, c.ContentType())
+		}
+	}
+	if _, err := ForContentType("text/plain"); err == nil {
+		t.Error("expected an error for an unsupported content type")
+	}
+}
@@ -0,0 +1,50 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+// JSON encoding, the reference form for the other codecs
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (jsonCodec) EncodeEvent(e models.Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (jsonCodec) DecodeEvent(data []byte) (models.Event, error) {
+	var e models.Event
+	err := json.Unmarshal(data, &e)
+	return e, err
+}
+
+func (jsonCodec) EncodeReading(r models.Reading) ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (jsonCodec) DecodeReading(data []byte) (models.Reading, error) {
+	var r models.Reading
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
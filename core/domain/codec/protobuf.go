@@ -0,0 +1,348 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package codec
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Protocol buffers encoding of the flat wire form, as described by event.proto
+type protobufCodec struct{}
+
+// Field numbers from event.proto
+const (
+	readingId       protowire.Number = 1
+	readingPushed   protowire.Number = 2
+	readingCreated  protowire.Number = 3
+	readingOrigin   protowire.Number = 4
+	readingModified protowire.Number = 5
+	readingDevice   protowire.Number = 6
+	readingName     protowire.Number = 7
+	readingValue    protowire.Number = 8
+	readingTags     protowire.Number = 9
+
+	eventId       protowire.Number = 1
+	eventPushed   protowire.Number = 2
+	eventDevice   protowire.Number = 3
+	eventCreated  protowire.Number = 4
+	eventModified protowire.Number = 5
+	eventOrigin   protowire.Number = 6
+	eventSchedule protowire.Number = 7
+	eventEvent    protowire.Number = 8
+	eventReadings protowire.Number = 9
+	eventTags     protowire.Number = 10
+	eventLocation protowire.Number = 11
+
+	locationLongitude protowire.Number = 1
+	locationLatitude  protowire.Number = 2
+
+	// Maps are repeated entries of a key and a value
+	mapKey   protowire.Number = 1
+	mapValue protowire.Number = 2
+)
+
+func (protobufCodec) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+func (protobufCodec) EncodeEvent(e models.Event) ([]byte, error) {
+	return appendEvent(nil, toWireEvent(e)), nil
+}
+
+func (protobufCodec) DecodeEvent(data []byte) (models.Event, error) {
+	w, err := consumeEvent(data)
+	if err != nil {
+		return models.Event{}, err
+	}
+	return fromWireEvent(w)
+}
+
+func (protobufCodec) EncodeReading(r models.Reading) ([]byte, error) {
+	return appendReading(nil, toWireReading(r)), nil
+}
+
+func (protobufCodec) DecodeReading(data []byte) (models.Reading, error) {
+	w, err := consumeReading(data)
+	if err != nil {
+		return models.Reading{}, err
+	}
+	return fromWireReading(w)
+}
+
+// Proto3 semantics: fields holding the zero value are not written
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendReading(b []byte, r wireReading) []byte {
+	b = appendString(b, readingId, r.Id)
+	b = appendInt64(b, readingPushed, r.Pushed)
+	b = appendInt64(b, readingCreated, r.Created)
+	b = appendInt64(b, readingOrigin, r.Origin)
+	b = appendInt64(b, readingModified, r.Modified)
+	b = appendString(b, readingDevice, r.Device)
+	b = appendString(b, readingName, r.Name)
+	b = appendString(b, readingValue, r.Value)
+	b = appendTags(b, readingTags, r.Tags)
+	return b
+}
+
+func appendEvent(b []byte, e wireEvent) []byte {
+	b = appendString(b, eventId, e.ID)
+	b = appendInt64(b, eventPushed, e.Pushed)
+	b = appendString(b, eventDevice, e.Device)
+	b = appendInt64(b, eventCreated, e.Created)
+	b = appendInt64(b, eventModified, e.Modified)
+	b = appendInt64(b, eventOrigin, e.Origin)
+	b = appendString(b, eventSchedule, e.Schedule)
+	b = appendString(b, eventEvent, e.Event)
+	for _, r := range e.Readings {
+		b = protowire.AppendTag(b, eventReadings, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendReading(nil, r))
+	}
+	b = appendTags(b, eventTags, e.Tags)
+	if e.Location != nil {
+		// Written even at 0,0, a message field is present or not
+		b = protowire.AppendTag(b, eventLocation, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendLocation(nil, *e.Location))
+	}
+	return b
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendLocation(b []byte, l wireLocation) []byte {
+	b = appendDouble(b, locationLongitude, l.Longitude)
+	b = appendDouble(b, locationLatitude, l.Latitude)
+	return b
+}
+
+// Entries are sorted by key so that equal tags always encode the same way
+func appendTags(b []byte, num protowire.Number, tags map[string]string) []byte {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		entry := appendString(nil, mapKey, k)
+		entry = appendString(entry, mapValue, tags[k])
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+// Walk the fields of a message, handing each known field to fn and skipping unknown ones
+func consumeFields(data []byte, fn func(num protowire.Number, typ protowire.Type, data []byte) (int, error)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		n, err := fn(num, typ, data)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func consumeString(typ protowire.Type, data []byte, v *string) (int, error) {
+	if typ != protowire.BytesType {
+		return 0, fmt.Errorf("unexpected wire type %d for string field", typ)
+	}
+	s, n := protowire.ConsumeString(data)
+	*v = s
+	return n, nil
+}
+
+func consumeInt64(typ protowire.Type, data []byte, v *int64) (int, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d for int64 field", typ)
+	}
+	u, n := protowire.ConsumeVarint(data)
+	*v = int64(u)
+	return n, nil
+}
+
+func consumeDouble(typ protowire.Type, data []byte, v *float64) (int, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, fmt.Errorf("unexpected wire type %d for double field", typ)
+	}
+	u, n := protowire.ConsumeFixed64(data)
+	*v = math.Float64frombits(u)
+	return n, nil
+}
+
+func consumeLocation(data []byte) (wireLocation, error) {
+	var l wireLocation
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch num {
+		case locationLongitude:
+			return consumeDouble(typ, data, &l.Longitude)
+		case locationLatitude:
+			return consumeDouble(typ, data, &l.Latitude)
+		}
+		return 0, nil
+	})
+	return l, err
+}
+
+func consumeTag(typ protowire.Type, data []byte, tags *map[string]string) (int, error) {
+	if typ != protowire.BytesType {
+		return 0, fmt.Errorf("unexpected wire type %d for tags field", typ)
+	}
+	b, n := protowire.ConsumeBytes(data)
+	if n < 0 {
+		return n, nil
+	}
+	var k, v string
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch num {
+		case mapKey:
+			return consumeString(typ, data, &k)
+		case mapValue:
+			return consumeString(typ, data, &v)
+		}
+		return 0, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if *tags == nil {
+		*tags = map[string]string{}
+	}
+	(*tags)[k] = v
+	return n, nil
+}
+
+func consumeReading(data []byte) (wireReading, error) {
+	var r wireReading
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch num {
+		case readingId:
+			return consumeString(typ, data, &r.Id)
+		case readingPushed:
+			return consumeInt64(typ, data, &r.Pushed)
+		case readingCreated:
+			return consumeInt64(typ, data, &r.Created)
+		case readingOrigin:
+			return consumeInt64(typ, data, &r.Origin)
+		case readingModified:
+			return consumeInt64(typ, data, &r.Modified)
+		case readingDevice:
+			return consumeString(typ, data, &r.Device)
+		case readingName:
+			return consumeString(typ, data, &r.Name)
+		case readingValue:
+			return consumeString(typ, data, &r.Value)
+		case readingTags:
+			return consumeTag(typ, data, &r.Tags)
+		}
+		return 0, nil
+	})
+	return r, err
+}
+
+func consumeEvent(data []byte) (wireEvent, error) {
+	var e wireEvent
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) (int, error) {
+		switch num {
+		case eventId:
+			return consumeString(typ, data, &e.ID)
+		case eventPushed:
+			return consumeInt64(typ, data, &e.Pushed)
+		case eventDevice:
+			return consumeString(typ, data, &e.Device)
+		case eventCreated:
+			return consumeInt64(typ, data, &e.Created)
+		case eventModified:
+			return consumeInt64(typ, data, &e.Modified)
+		case eventOrigin:
+			return consumeInt64(typ, data, &e.Origin)
+		case eventSchedule:
+			return consumeString(typ, data, &e.Schedule)
+		case eventEvent:
+			return consumeString(typ, data, &e.Event)
+		case eventReadings:
+			if typ != protowire.BytesType {
+				return 0, fmt.Errorf("unexpected wire type %d for readings field", typ)
+			}
+			b, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+			r, err := consumeReading(b)
+			if err != nil {
+				return 0, err
+			}
+			e.Readings = append(e.Readings, r)
+			return n, nil
+		case eventTags:
+			return consumeTag(typ, data, &e.Tags)
+		case eventLocation:
+			if typ != protowire.BytesType {
+				return 0, fmt.Errorf("unexpected wire type %d for location field", typ)
+			}
+			b, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return n, nil
+			}
+			l, err := consumeLocation(b)
+			if err != nil {
+				return 0, err
+			}
+			e.Location = &l
+			return n, nil
+		}
+		return 0, nil
+	})
+	return e, err
+}
@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+package codec
+
+import (
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+// Flat form of a reading shared by the binary codecs, mirroring its JSON fields
+type wireReading struct {
+	Id       string            `codec:"id"`
+	Pushed   int64             `codec:"pushed"`
+	Created  int64             `codec:"created"`
+	Origin   int64             `codec:"origin"`
+	Modified int64             `codec:"modified"`
+	Device   string            `codec:"device"`
+	Name     string            `codec:"name"`
+	Value    string            `codec:"value"`
+	Tags     map[string]string `codec:"tags,omitempty"`
+}
+
+// Flat form of an event shared by the binary codecs, mirroring its JSON fields
+type wireEvent struct {
+	ID       string            `codec:"id"`
+	Pushed   int64             `codec:"pushed"`
+	Device   string            `codec:"device"`
+	Created  int64             `codec:"created"`
+	Modified int64             `codec:"modified"`
+	Origin   int64             `codec:"origin"`
+	Schedule string            `codec:"schedule"`
+	Event    string            `codec:"event"`
+	Readings []wireReading     `codec:"readings"`
+	Tags     map[string]string `codec:"tags,omitempty"`
+	Location *wireLocation     `codec:"location,omitempty"`
+}
+
+// GeoJSON point of an event, in degrees
+type wireLocation struct {
+	Longitude float64 `codec:"lon"`
+	Latitude  float64 `codec:"lat"`
+}
+
+func toWireReading(r models.Reading) wireReading {
+	return wireReading{
+		Id:       r.Id.Hex(),
+		Pushed:   r.Pushed,
+		Created:  r.Created,
+		Origin:   r.Origin,
+		Modified: r.Modified,
+		Device:   r.Device,
+		Name:     r.Name,
+		Value:    r.Value,
+		Tags:     r.Tags,
+	}
+}
+
+func fromWireReading(w wireReading) (models.Reading, error) {
+	id, err := parseObjectId(w.Id)
+	if err != nil {
+		return models.Reading{}, err
+	}
+	return models.Reading{
+		Id:       id,
+		Pushed:   w.Pushed,
+		Created:  w.Created,
+		Origin:   w.Origin,
+		Modified: w.Modified,
+		Device:   w.Device,
+		Name:     w.Name,
+		Value:    w.Value,
+		Tags:     fromWireTags(w.Tags),
+	}, nil
+}
+
+func toWireEvent(e models.Event) wireEvent {
+	w := wireEvent{
+		ID:       e.ID.Hex(),
+		Pushed:   e.Pushed,
+		Device:   e.Device,
+		Created:  e.Created,
+		Modified: e.Modified,
+		Origin:   e.Origin,
+		Schedule: e.Schedule,
+		Event:    e.Event,
+		Tags:     e.Tags,
+	}
+	// Events are validated on their way in, their locations are points
+	if e.Location != nil && len(e.Location.Coordinates) == 2 {
+		w.Location = &wireLocation{Longitude: e.Location.Longitude(), Latitude: e.Location.Latitude()}
+	}
+	for _, r := range e.Readings {
+		w.Readings = append(w.Readings, toWireReading(r))
+	}
+	return w
+}
+
+func fromWireEvent(w wireEvent) (models.Event, error) {
+	id, err := parseObjectId(w.ID)
+	if err != nil {
+		return models.Event{}, err
+	}
+	e := models.Event{
+		ID:       id,
+		Pushed:   w.Pushed,
+		Device:   w.Device,
+		Created:  w.Created,
+		Modified: w.Modified,
+		Origin:   w.Origin,
+		Schedule: w.Schedule,
+		Event:    w.Event,
+		Tags:     fromWireTags(w.Tags),
+	}
+	if w.Location != nil {
+		p := models.NewGeoPoint(w.Location.Longitude, w.Location.Latitude)
+		e.Location = &p
+	}
+	// Like JSON, where empty arrays are null, no readings decode to a nil slice
+	for _, wr := range w.Readings {
+		r, err := fromWireReading(wr)
+		if err != nil {
+			return models.Event{}, err
+		}
+		e.Readings = append(e.Readings, r)
+	}
+	return e, nil
+}
+
+// Like JSON, where empty tags are left out, no tags decode to nil
+func fromWireTags(tags map[string]string) models.Tags {
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
@@ -27,19 +27,23 @@ const (
 	INVALID DATABASE = iota
 	MONGODB
 	MYSQL
+	SQLITE
+	MEMORY
 )
 
 const (
 	invalidStr = "invalid"
 	mongoStr   = "mongodb"
 	mysqlStr   = "mysql"
+	sqliteStr  = "sqlite"
+	memoryStr  = "memory"
 )
 
 // DATABASEArr : Add in order declared in Struct for string value
-var databaseArr = [...]string{invalidStr, mongoStr, mysqlStr}
+var databaseArr = [...]string{invalidStr, mongoStr, mysqlStr, sqliteStr, memoryStr}
 
 func (db DATABASE) String() string {
-	if db >= INVALID && db <= MYSQL {
+	if db >= INVALID && db <= MEMORY {
 		return databaseArr[db]
 	}
 	return invalidStr
@@ -51,6 +55,10 @@ func GetDatabaseType(db string) (DATABASE, error) {
 		return MONGODB, nil
 	} else if mysqlStr == db {
 		return MYSQL, nil
+	} else if sqliteStr == db {
+		return SQLITE, nil
+	} else if memoryStr == db {
+		return MEMORY, nil
 	} else {
 		return INVALID, errors.New("Undefined Database Type")
 	}
@@ -30,6 +30,8 @@ func TestGetDatabaseType(t *testing.T) {
 	}{
 		{"type is mongo", "mongodb", MONGODB, false},
 		{"type is mysql", "mysql", MYSQL, false},
+		{"type is sqlite", "sqlite", SQLITE, false},
+		{"type is memory", "memory", MEMORY, false},
 		{"type is unknown", "foo", INVALID, true},
 	}
 	for _, tt := range tests {
@@ -53,9 +55,11 @@ func TestStringDatabaseType(t *testing.T) {
 	}{
 		{"mongo", MONGODB},
 		{"mysql", MYSQL},
+		{"sqlite", SQLITE},
+		{"memory", MEMORY},
 		{"unknown", INVALID},
 		{"invalid1", INVALID - 1},
-		{"invalid2", MYSQL + 1},
+		{"invalid2", MEMORY + 1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,46 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: edgex domain library
+ * @version: 0.5.0
+ *******************************************************************************/
+package errs
+
+// Machine readable codes of the errors returned by the REST APIs
+const (
+	CodeInvalidRequest          = "INVALID_REQUEST"
+	CodeNotFound                = "NOT_FOUND"
+	CodeDeviceNotFound          = "DEVICE_NOT_FOUND"
+	CodeDuplicate               = "DUPLICATE"
+	CodeValueDescriptorNotFound = "VALUE_DESCRIPTOR_NOT_FOUND"
+	CodeInvalidValueDescriptor  = "INVALID_VALUE_DESCRIPTOR"
+	CodeValueDescriptorInUse    = "VALUE_DESCRIPTOR_IN_USE"
+	CodeLimitExceeded           = "LIMIT_EXCEEDED"
+	CodeServiceError            = "SERVICE_ERROR"
+)
+
+// Body of the error responses of the REST APIs.
+// Services write it as JSON along with the status code, clients decode it back into an error.
+type ServiceError struct {
+	StatusCode int    `json:"status"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	// Request parameter or body property at fault, if any
+	Field string `json:"field,omitempty"`
+	// Identifier of the failed request, to look it up in the logs of the service
+	RequestID string `json:"requestId,omitempty"`
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,117 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Kinds of alert rules
+const (
+	AlertThreshold    = "THRESHOLD" // The value leaves the [Min, Max] range
+	AlertRateOfChange = "RATE"      // The value changes faster than MaxRate per second
+	AlertStale        = "STALE"     // No reading for Timeout milliseconds
+)
+
+// Severities of the notification raising an alert
+const (
+	AlertCritical = "CRITICAL"
+	AlertNormal   = "NORMAL"
+)
+
+/*
+ * Alert rule evaluated on the readings ingested by core data. The rule applies to the readings
+ * of a device, of a value descriptor or of both; an alert is kept per device and value descriptor.
+ * An alert raised by a threshold or rate rule clears once the value is back within the limit by
+ * Hysteresis, a stale alert clears on the next reading.
+ */
+type AlertRule struct {
+	Id              bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	Created         int64         `bson:"created" json:"created"`
+	Modified        int64         `bson:"modified" json:"modified"`
+	Origin          int64         `bson:"origin" json:"origin"`
+	Name            string        `bson:"name" json:"name"`
+	Description     string        `bson:"description,omitempty" json:"description,omitempty"`
+	Kind            string        `bson:"kind" json:"kind"`
+	Device          string        `bson:"device,omitempty" json:"device,omitempty"`                   // Device name, any device when empty
+	ValueDescriptor string        `bson:"valueDescriptor,omitempty" json:"valueDescriptor,omitempty"` // Any value descriptor when empty
+	Min             *float64      `bson:"min,omitempty" json:"min,omitempty"`
+	Max             *float64      `bson:"max,omitempty" json:"max,omitempty"`
+	MaxRate         float64       `bson:"maxRate,omitempty" json:"maxRate,omitempty"`
+	Timeout         int64         `bson:"timeout,omitempty" json:"timeout,omitempty"`
+	Hysteresis      float64       `bson:"hysteresis,omitempty" json:"hysteresis,omitempty"`
+	Severity        string        `bson:"severity,omitempty" json:"severity,omitempty"` // CRITICAL when empty
+}
+
+func (r AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert rule without name")
+	}
+	if r.Device == "" && r.ValueDescriptor == "" {
+		return errors.New("alert rule without device nor value descriptor")
+	}
+	if r.Hysteresis < 0 {
+		return errors.New("negative alert rule hysteresis")
+	}
+	switch r.Severity {
+	case "", AlertCritical, AlertNormal:
+	default:
+		return fmt.Errorf("unknown alert rule severity %q", r.Severity)
+	}
+
+	switch r.Kind {
+	case AlertThreshold:
+		if r.Min == nil && r.Max == nil {
+			return errors.New("threshold alert rule without min nor max")
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return errors.New("threshold alert rule min greater than max")
+		}
+	case AlertRateOfChange:
+		if r.MaxRate <= 0 {
+			return errors.New("rate alert rule without positive max rate")
+		}
+	case AlertStale:
+		if r.Timeout <= 0 {
+			return errors.New("stale alert rule without positive timeout")
+		}
+	default:
+		return fmt.Errorf("unknown alert rule kind %q", r.Kind)
+	}
+	return nil
+}
+
+// Whether the rule applies to the readings of the device and value descriptor
+func (r AlertRule) Matches(device, valueDescriptor string) bool {
+	return (r.Device == "" || r.Device == device) && (r.ValueDescriptor == "" || r.ValueDescriptor == valueDescriptor)
+}
+
+/*
+ * To String function for AlertRule
+ */
+func (r AlertRule) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import "testing"
+
+func TestAlertRule_Validate(t *testing.T) {
+	low, high := 10.0, 20.0
+	tests := []struct {
+		name    string
+		rule    AlertRule
+		wantErr bool
+	}{
+		{"threshold", AlertRule{Name: "hot", Kind: AlertThreshold, ValueDescriptor: "Temperature", Max: &high}, false},
+		{"threshold range", AlertRule{Name: "range", Kind: AlertThreshold, Device: "dev", Min: &low, Max: &high, Hysteresis: 1}, false},
+		{"rate", AlertRule{Name: "jump", Kind: AlertRateOfChange, Device: "dev", MaxRate: 2}, false},
+		{"stale", AlertRule{Name: "silent", Kind: AlertStale, Device: "dev", Timeout: 60000, Severity: AlertNormal}, false},
+		{"no name", AlertRule{Kind: AlertThreshold, Device: "dev", Max: &high}, true},
+		{"no scope", AlertRule{Name: "hot", Kind: AlertThreshold, Max: &high}, true},
+		{"unknown kind", AlertRule{Name: "hot", Kind: "OTHER", Device: "dev"}, true},
+		{"threshold without limit", AlertRule{Name: "hot", Kind: AlertThreshold, Device: "dev"}, true},
+		{"inverted range", AlertRule{Name: "range", Kind: AlertThreshold, Device: "dev", Min: &high, Max: &low}, true},
+		{"rate without max rate", AlertRule{Name: "jump", Kind: AlertRateOfChange, Device: "dev"}, true},
+		{"stale without timeout", AlertRule{Name: "silent", Kind: AlertStale, Device: "dev"}, true},
+		{"negative hysteresis", AlertRule{Name: "hot", Kind: AlertThreshold, Device: "dev", Max: &high, Hysteresis: -1}, true},
+		{"unknown severity", AlertRule{Name: "hot", Kind: AlertThreshold, Device: "dev", Max: &high, Severity: "HIGH"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AlertRule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertRule_Matches(t *testing.T) {
+	r := AlertRule{Device: "dev1", ValueDescriptor: "Temperature"}
+	if !r.Matches("dev1", "Temperature") || r.Matches("dev2", "Temperature") || r.Matches("dev1", "Pressure") {
+		t.Error("rule on a device and value descriptor matches only both")
+	}
+	r = AlertRule{ValueDescriptor: "Temperature"}
+	if !r.Matches("dev1", "Temperature") || !r.Matches("dev2", "Temperature") {
+		t.Error("rule on a value descriptor matches every device")
+	}
+}
@@ -33,33 +33,35 @@ import (
 type Device struct {
 	DescribedObject `bson:",inline"`
 	Id              bson.ObjectId  `bson:"_id,omitempty" json:"id"`
-	Name            string         `bson:"name" json:"name"`                     // Unique name for identifying a device
-	AdminState      AdminState     `bson:"adminState" json:"adminState"`         // Admin state (locked/unlocked)
-	OperatingState  OperatingState `bson:"operatingState" json:"operatingState"` // Operating state (enabled/disabled)
-	Addressable     Addressable    `bson:"addressable" json:"addressable"`       // Addressable for the device - stores information about it's address
-	LastConnected   int64          `bson:"lastConnected" json:"lastConnected"`   // Time (milliseconds) that the device last provided any feedback or responded to any request
-	LastReported    int64          `bson:"lastReported" json:"lastReported"`     // Time (milliseconds) that the device reported data to the core microservice
-	Labels          []string       `bson:"labels" json:"labels"`                 // Other labels applied to the device to help with searching
-	Location        interface{}    `bson:"location" json:"location"`             // Device service specific location (interface{} is an empty interface so it can be anything)
-	Service         DeviceService  `bson:"service" json:"service"`               // Associated Device Service - One per device
-	Profile         DeviceProfile  `bson:"profile" json:"profile"`               // Associated Device Profile - Describes the device
+	Name            string         `bson:"name" json:"name"`                       // Unique name for identifying a device
+	AdminState      AdminState     `bson:"adminState" json:"adminState"`           // Admin state (locked/unlocked)
+	OperatingState  OperatingState `bson:"operatingState" json:"operatingState"`   // Operating state (enabled/disabled)
+	Addressable     Addressable    `bson:"addressable" json:"addressable"`         // Addressable for the device - stores information about it's address
+	LastConnected   int64          `bson:"lastConnected" json:"lastConnected"`     // Time (milliseconds) that the device last provided any feedback or responded to any request
+	LastReported    int64          `bson:"lastReported" json:"lastReported"`       // Time (milliseconds) that the device reported data to the core microservice
+	Labels          []string       `bson:"labels" json:"labels"`                   // Other labels applied to the device to help with searching
+	Location        interface{}    `bson:"location" json:"location"`               // Device service specific location (interface{} is an empty interface so it can be anything)
+	Service         DeviceService  `bson:"service" json:"service"`                 // Associated Device Service - One per device
+	Profile         DeviceProfile  `bson:"profile" json:"profile"`                 // Associated Device Profile - Describes the device
+	ProfileRevision int            `bson:"profileRevision" json:"profileRevision"` // Revision of the profile the device is pinned to, 0 follows the latest revision
 }
 
 // Custom marshaling to make empty strings null
 func (d Device) MarshalJSON() ([]byte, error) {
 	test := struct {
 		DescribedObject
-		Id             *bson.ObjectId `json:"id"`
-		Name           *string        `json:"name"`           // Unique name for identifying a device
-		AdminState     AdminState     `json:"adminState"`     // Admin state (locked/unlocked)
-		OperatingState OperatingState `json:"operatingState"` // Operating state (enabled/disabled)
-		Addressable    Addressable    `json:"addressable"`    // Addressable for the device - stores information about it's address
-		LastConnected  int64          `json:"lastConnected"`  // Time (milliseconds) that the device last provided any feedback or responded to any request
-		LastReported   int64          `json:"lastReported"`   // Time (milliseconds) that the device reported data to the core microservice
-		Labels         []string       `json:"labels"`         // Other labels applied to the device to help with searching
-		Location       interface{}    `json:"location"`       // Device service specific location (interface{} is an empty interface so it can be anything)
-		Service        DeviceService  `json:"service"`        // Associated Device Service - One per device
-		Profile        DeviceProfile  `json:"profile"`        // Associated Device Profile - Describes the device
+		Id              *bson.ObjectId `json:"id"`
+		Name            *string        `json:"name"`                      // Unique name for identifying a device
+		AdminState      AdminState     `json:"adminState"`                // Admin state (locked/unlocked)
+		OperatingState  OperatingState `json:"operatingState"`            // Operating state (enabled/disabled)
+		Addressable     Addressable    `json:"addressable"`               // Addressable for the device - stores information about it's address
+		LastConnected   int64          `json:"lastConnected"`             // Time (milliseconds) that the device last provided any feedback or responded to any request
+		LastReported    int64          `json:"lastReported"`              // Time (milliseconds) that the device reported data to the core microservice
+		Labels          []string       `json:"labels"`                    // Other labels applied to the device to help with searching
+		Location        interface{}    `json:"location"`                  // Device service specific location (interface{} is an empty interface so it can be anything)
+		Service         DeviceService  `json:"service"`                   // Associated Device Service - One per device
+		Profile         DeviceProfile  `json:"profile"`                   // Associated Device Profile - Describes the device
+		ProfileRevision int            `json:"profileRevision,omitempty"` // Revision of the profile the device is pinned to
 	}{
 		DescribedObject: d.DescribedObject,
 		AdminState:      d.AdminState,
@@ -71,6 +73,7 @@ func (d Device) MarshalJSON() ([]byte, error) {
 		Location:        d.Location,
 		Service:         d.Service,
 		Profile:         d.Profile,
+		ProfileRevision: d.ProfileRevision,
 	}
 
 	if d.Id != "" {
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/json"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+/*
+ * Immutable revision of a device profile, one is recorded every time the profile changes.
+ * The revision holds a copy of the whole profile, commands included.
+ */
+type DeviceProfileRevision struct {
+	BaseObject `bson:",inline"`
+	Id         bson.ObjectId   `bson:"_id,omitempty" json:"id"`
+	ProfileId  bson.ObjectId   `bson:"profileId" json:"profileId"` // Device profile of the revision
+	Revision   int             `bson:"revision" json:"revision"`   // Number of the revision, starting at 1 for each profile
+	Action     string          `bson:"action" json:"action"`       // Change which made the revision (create, update or rollback)
+	Author     string          `bson:"author" json:"author"`       // Caller who made the change
+	Changes    []ProfileChange `bson:"changes" json:"changes"`     // Differences with the previous revision
+	Profile    DeviceProfile   `bson:"profile" json:"profile"`     // Device profile as of the revision
+}
+
+// Value of the profile which changed in a revision, the path is the JSON path of the value
+// (i.e. commands[0].get.path), before is nil for added values and after for removed ones
+type ProfileChange struct {
+	Path   string      `bson:"path" json:"path"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
+
+/*
+ * String function for DeviceProfileRevision
+ */
+func (r DeviceProfileRevision) String() string {
+	out, err := json.Marshal(r)
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
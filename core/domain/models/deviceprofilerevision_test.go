@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"strconv"
+	"testing"
+)
+
+var TestProfileRevision = DeviceProfileRevision{BaseObject: TestBaseObject, Revision: 2, Action: "update", Author: "admin",
+	Changes: []ProfileChange{{Path: "model", Before: "A", After: TestModel}}, Profile: DeviceProfile{Name: TestProfileName}}
+
+func TestDeviceProfileRevision_String(t *testing.T) {
+	tests := []struct {
+		name string
+		r    DeviceProfileRevision
+		want string
+	}{
+		{"device profile revision to string", TestProfileRevision,
+			"{\"created\":" + strconv.FormatInt(TestBaseObject.Created, 10) +
+				",\"modified\":" + strconv.FormatInt(TestBaseObject.Modified, 10) +
+				",\"origin\":" + strconv.FormatInt(TestBaseObject.Origin, 10) +
+				",\"id\":\"\",\"profileId\":\"\",\"revision\":2,\"action\":\"update\",\"author\":\"admin\"" +
+				",\"changes\":[{\"path\":\"model\",\"before\":\"A\",\"after\":\"" + TestModel + "\"}]" +
+				",\"profile\":" + TestProfileRevision.Profile.String() +
+				"}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.String(); got != tt.want {
+				t.Errorf("DeviceProfileRevision.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -40,6 +40,10 @@ type Event struct {
 	Schedule string        `bson:"schedule,omitempty" json:"schedule"` // Schedule identifier
 	Event    string        `bson:"event,omitempty"`                    // Schedule event identifier
 	Readings []Reading     `bson:"readings" json:"readings"`           // List of readings
+	// Arbitrary metadata, e.g. a batch identifier or a site code
+	Tags Tags `bson:"tags,omitempty" json:"tags,omitempty"`
+	// Where the event was taken, defaults to the location of the device
+	Location *GeoPoint `bson:"location,omitempty" json:"location,omitempty"`
 }
 
 // Custom marshaling to make empty strings null
@@ -54,12 +58,16 @@ func (e Event) MarshalJSON() ([]byte, error) {
 		Schedule *string       `json:"schedule"` // Schedule identifier
 		Event    *string       `json:"event"`    // Schedule event identifier
 		Readings []Reading     `json:"readings"` // List of readings
+		Tags     Tags          `json:"tags,omitempty"`
+		Location *GeoPoint     `json:"location,omitempty"`
 	}{
 		ID:       e.ID,
 		Pushed:   e.Pushed,
 		Created:  e.Created,
 		Modified: e.Modified,
 		Origin:   e.Origin,
+		Tags:     e.Tags,
+		Location: e.Location,
 	}
 
 	// Empty strings are null
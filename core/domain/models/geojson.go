@@ -0,0 +1,215 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// GeoJSON geometry types
+const (
+	GeoJSONPoint   = "Point"
+	GeoJSONPolygon = "Polygon"
+)
+
+// Mean radius of the earth in meters, as used by MongoDB for spherical queries
+const earthRadius = 6378100.0
+
+/*
+ * GeoJSON point, the coordinates are the longitude and the latitude in degrees
+ */
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+func NewGeoPoint(longitude, latitude float64) GeoPoint {
+	return GeoPoint{Type: GeoJSONPoint, Coordinates: []float64{longitude, latitude}}
+}
+
+func (p GeoPoint) Longitude() float64 {
+	return p.Coordinates[0]
+}
+
+func (p GeoPoint) Latitude() float64 {
+	return p.Coordinates[1]
+}
+
+func (p GeoPoint) Validate() error {
+	if p.Type != GeoJSONPoint {
+		return fmt.Errorf("GeoJSON type %q is not %s", p.Type, GeoJSONPoint)
+	}
+	return validatePosition(p.Coordinates)
+}
+
+/*
+ * GeoJSON polygon made of linear rings, the first ring is the exterior and the others are holes.
+ * Every ring is closed: its last position repeats its first.
+ */
+type GeoPolygon struct {
+	Type        string        `bson:"type" json:"type"`
+	Coordinates [][][]float64 `bson:"coordinates" json:"coordinates"`
+}
+
+func (p GeoPolygon) Validate() error {
+	if p.Type != GeoJSONPolygon {
+		return fmt.Errorf("GeoJSON type %q is not %s", p.Type, GeoJSONPolygon)
+	}
+	if len(p.Coordinates) == 0 {
+		return errors.New("GeoJSON polygon without ring")
+	}
+	for _, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return errors.New("GeoJSON polygon ring with less than 4 positions")
+		}
+		for _, position := range ring {
+			if err := validatePosition(position); err != nil {
+				return err
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return errors.New("GeoJSON polygon ring is not closed")
+		}
+	}
+	return nil
+}
+
+// Whether the point lies in the exterior ring of the polygon and outside of its holes.
+// The edges are straight lines between the coordinates, which is close enough to the
+// geodesics of MongoDB for areas a few kilometers wide.
+func (p GeoPolygon) Contains(point GeoPoint) bool {
+	if len(p.Coordinates) == 0 || !ringContains(p.Coordinates[0], point) {
+		return false
+	}
+	for _, hole := range p.Coordinates[1:] {
+		if ringContains(hole, point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ray casting from the point towards increasing longitudes
+func ringContains(ring [][]float64, point GeoPoint) bool {
+	x, y := point.Longitude(), point.Latitude()
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi, xj, yj := ring[i][0], ring[i][1], ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Great-circle distance in meters between two points
+func Distance(a, b GeoPoint) float64 {
+	lat1, lat2 := a.Latitude()*math.Pi/180, b.Latitude()*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude() - a.Longitude()) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func validatePosition(position []float64) error {
+	if len(position) != 2 {
+		return errors.New("GeoJSON position must hold a longitude and a latitude")
+	}
+	if !inRange(position[0], 180) {
+		return fmt.Errorf("longitude %v out of range", position[0])
+	}
+	if !inRange(position[1], 90) {
+		return fmt.Errorf("latitude %v out of range", position[1])
+	}
+	return nil
+}
+
+// NaN compares false with every bound so it's checked on its own
+func inRange(v float64, bound float64) bool {
+	return !math.IsNaN(v) && v >= -bound && v <= bound
+}
+
+// Location held as GeoJSON, e.g. the Location of a Device decoded from JSON or BSON
+type geoJSONLocation struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Decode a location of any shape as GeoJSON. A location is GeoJSON when it is an object
+// with a type and coordinates, any other location is device service specific.
+func decodeLocation(location interface{}) (geoJSONLocation, bool) {
+	var l geoJSONLocation
+	if location == nil {
+		return l, false
+	}
+	b, err := json.Marshal(location)
+	if err != nil || json.Unmarshal(b, &l) != nil {
+		return l, false
+	}
+	return l, l.Type != "" && len(l.Coordinates) > 0
+}
+
+// GeoJSON point held by a location of any shape, e.g. the Location of a Device
+func GeoPointFromLocation(location interface{}) (GeoPoint, bool) {
+	l, ok := decodeLocation(location)
+	if !ok || l.Type != GeoJSONPoint {
+		return GeoPoint{}, false
+	}
+	p := GeoPoint{Type: l.Type}
+	if json.Unmarshal(l.Coordinates, &p.Coordinates) != nil || p.Validate() != nil {
+		return GeoPoint{}, false
+	}
+	return p, true
+}
+
+// Validate a location of any shape. GeoJSON locations must be valid points or polygons,
+// other locations are device service specific and always valid.
+func ValidateLocation(location interface{}) error {
+	l, ok := decodeLocation(location)
+	if !ok {
+		return nil
+	}
+	switch l.Type {
+	case GeoJSONPoint:
+		p := GeoPoint{Type: l.Type}
+		if err := json.Unmarshal(l.Coordinates, &p.Coordinates); err != nil {
+			return fmt.Errorf("invalid GeoJSON point coordinates: %v", err)
+		}
+		return p.Validate()
+	case GeoJSONPolygon:
+		p := GeoPolygon{Type: l.Type}
+		if err := json.Unmarshal(l.Coordinates, &p.Coordinates); err != nil {
+			return fmt.Errorf("invalid GeoJSON polygon coordinates: %v", err)
+		}
+		return p.Validate()
+	}
+	return fmt.Errorf("unsupported GeoJSON type %q", l.Type)
+}
+
+/*
+ * Latest position reported by a device, taken from its most recent located event
+ */
+type DevicePosition struct {
+	Device   string   `bson:"device" json:"device"`
+	Location GeoPoint `bson:"location" json:"location"`
+	Created  int64    `bson:"created" json:"created"` // Creation time of the event
+}
@@ -0,0 +1,128 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func decodeJSON(s string) interface{} {
+	var v interface{}
+	json.Unmarshal([]byte(s), &v)
+	return v
+}
+
+func TestValidateLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		location interface{}
+		wantErr  bool
+	}{
+		{"nil", nil, false},
+		{"device service specific", "building 4, floor 2", false},
+		{"object without coordinates", decodeJSON(`{"type":"room","name":"lab"}`), false},
+		{"point", decodeJSON(`{"type":"Point","coordinates":[-97.7431,30.2672]}`), false},
+		{"bson point", bson.M{"type": "Point", "coordinates": []interface{}{-97.7431, 30.2672}}, false},
+		{"point out of range", decodeJSON(`{"type":"Point","coordinates":[-197.7431,30.2672]}`), true},
+		{"point without latitude", decodeJSON(`{"type":"Point","coordinates":[-97.7431]}`), true},
+		{"polygon", decodeJSON(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`), false},
+		{"open polygon", decodeJSON(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`), true},
+		{"unsupported type", decodeJSON(`{"type":"LineString","coordinates":[[0,0],[1,1]]}`), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateLocation(tt.location); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeoPointValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		coordinates []float64
+		wantErr     bool
+	}{
+		{"point", []float64{-97.7431, 30.2672}, false},
+		{"bounds", []float64{180, -90}, false},
+		{"longitude out of range", []float64{-180.5, 30.2672}, true},
+		{"NaN longitude", []float64{math.NaN(), 30.2672}, true},
+		{"NaN latitude", []float64{-97.7431, math.NaN()}, true},
+		{"infinite latitude", []float64{-97.7431, math.Inf(1)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := GeoPoint{Type: GeoJSONPoint, Coordinates: tt.coordinates}
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeoPointFromLocation(t *testing.T) {
+	p, ok := GeoPointFromLocation(decodeJSON(`{"type":"Point","coordinates":[-97.7431,30.2672]}`))
+	if !ok || p.Longitude() != -97.7431 || p.Latitude() != 30.2672 {
+		t.Errorf("unexpected point %v", p)
+	}
+	if _, ok := GeoPointFromLocation(decodeJSON(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`)); ok {
+		t.Error("a polygon is not a point")
+	}
+	if _, ok := GeoPointFromLocation("building 4"); ok {
+		t.Error("a device service specific location is not a point")
+	}
+}
+
+func TestGeoPolygon_Contains(t *testing.T) {
+	square := GeoPolygon{Type: GeoJSONPolygon, Coordinates: [][][]float64{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}},
+	}}
+	tests := []struct {
+		name  string
+		point GeoPoint
+		want  bool
+	}{
+		{"inside", NewGeoPoint(2, 2), true},
+		{"outside", NewGeoPoint(12, 2), false},
+		{"in the hole", NewGeoPoint(5, 5), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := square.Contains(tt.point); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// One degree of latitude is about 111.3 km
+	d := Distance(NewGeoPoint(0, 0), NewGeoPoint(0, 1))
+	if math.Abs(d-111317) > 100 {
+		t.Errorf("unexpected distance %v", d)
+	}
+	if d := Distance(NewGeoPoint(-97.7431, 30.2672), NewGeoPoint(-97.7431, 30.2672)); d != 0 {
+		t.Errorf("distance to self is %v", d)
+	}
+}
@@ -39,6 +39,8 @@ type Reading struct {
 	Device   string        `bson:"device" json:"device"`
 	Name     string        `bson:"name" json:"name"`
 	Value    string        `bson:"value" json:"value"` // Device sensor data value
+	// Arbitrary metadata, refining the tags of the event
+	Tags Tags `bson:"tags,omitempty" json:"tags,omitempty"`
 }
 
 // Custom marshaling to make empty strings null
@@ -52,12 +54,14 @@ func (r Reading) MarshalJSON() ([]byte, error) {
 		Device   *string       `json:"device"`
 		Name     *string       `json:"name"`
 		Value    *string       `json:"value"` // Device sensor data value
+		Tags     Tags          `json:"tags,omitempty"`
 	}{
 		Id:       r.Id,
 		Pushed:   r.Pushed,
 		Created:  r.Created,
 		Origin:   r.Origin,
 		Modified: r.Modified,
+		Tags:     r.Tags,
 	}
 
 	// Empty strings are null
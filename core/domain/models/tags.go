@@ -0,0 +1,54 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-domain-go library
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+/*
+ * Arbitrary metadata attached to events and readings, e.g. a batch identifier,
+ * a firmware version or a site code
+ */
+type Tags map[string]string
+
+// Custom marshaling as <Tags><Tag key="site">plant-1</Tag></Tags>, encoding/xml doesn't handle maps.
+// Empty tags are left out.
+func (t Tags) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(t) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		tag := xml.StartElement{Name: xml.Name{Local: "Tag"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: k}}}
+		if err := e.EncodeElement(t[k], tag); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
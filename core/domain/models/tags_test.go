@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *******************************************************************************/
+
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestTags_MarshalXML(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Event
+		want string
+	}{
+		{"no tags", Event{Device: "dev1"}, "<Event><ID></ID><Pushed>0</Pushed><Device>dev1</Device><Created>0</Created><Modified>0</Modified><Origin>0</Origin><Schedule></Schedule><Event></Event></Event>"},
+		{"tags", Event{Device: "dev1", Tags: Tags{"site": "plant-1", "batch": "42"}}, "<Event><ID></ID><Pushed>0</Pushed><Device>dev1</Device><Created>0</Created><Modified>0</Modified><Origin>0</Origin><Schedule></Schedule><Event></Event><Tags><Tag key=\"batch\">42</Tag><Tag key=\"site\">plant-1</Tag></Tags></Event>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.Marshal(tt.e)
+			if err != nil {
+				t.Errorf("xml.Marshal() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("xml.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,30 +23,44 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/domain/enums"
 )
 
-// Struct used to pase the JSON configuration file
+// Struct used to pase the JSON configuration file.
+// Fields tagged validate are checked when the configuration is loaded, see pkg/config.Validate
 type ConfigurationStruct struct {
-	ApplicationName                     string
-	DBType                              string
-	MongoDatabaseName                   string
+	ApplicationName                     string `validate:"required"`
+	DBType                              string // mongodb, mysql, sqlite (cgo builds only) or memory, see repository.go
+	MongoDatabaseName                   string `validate:"required"`
 	MongoDBUserName                     string
 	MongoDBPassword                     string
-	MongoDBHost                         string
-	MongoDBPort                         int
+	MongoDBHost                         string `validate:"required"`
+	MongoDBPort                         int    `validate:"min=1,max=65535"`
 	MongoDBConnectTimeout               int
-	ReadMaxLimit                        int
+	SQLDataSource                       string // Data source name of the mysql or sqlite database
+	ReadMaxLimit                        int    `validate:"min=1"`
+	StrictProfileValidation             bool   // Reject device profiles with lint errors, see lint_deviceprofile.go
 	Protocol                            string
 	ServiceName                         string
 	ServiceAddress                      string
-	ServicePort                          int
-	ServiceTimeout                       int
+	ServicePort                         int    `validate:"min=1,max=65535"`
+	ServiceTimeout                      int    `validate:"min=0"`
 	HeartBeatTime                       int
 	HeartBeatMsg                        string
+	HeartBeatSink                       string // log, support-logging, metrics or bus, see pkg/heartbeat
+	HeartBeatURL                        string
 	AppOpenMsg                          string
 	CheckInterval                       string
 	ConsulProfilesActive                string
 	ConsulHost                          string
 	ConsulCheckAddress                  string
-	ConsulPort                          int
+	RegistryType                        string // consul or file, see support/registry
+	RegistryDirectory                   string
+	AuthEnabled                         bool
+	AuthAPIKeys                         string
+	AuthJWTSecret                       string
+	AuthJWTPublicKeyFile                string
+	AuthScopes                          string
+	AuthClientAPIKey                    string
+	AuthClientToken                     string
+	ConsulPort                          int    `validate:"min=1,max=65535"`
 	EnableRemoteLogging                 bool
 	LoggingFile                         string
 	LoggingRemoteURL                    string
@@ -91,6 +105,7 @@ var (
 	SECOL      = "scheduleEvent"
 	SCOL       = "schedule"
 	PWCOL      = "provisionWatcher"
+	DPRCOL     = "deviceProfileRevision"
 	TIMELAYOUT = "20060102T150405"
 
 	/* ---------------- URL PARAM NAMES -----------------------*/
@@ -120,6 +135,12 @@ var (
 	DEVICEPROFILE            = "deviceprofile"
 	UPLOADFILE               = "uploadfile"
 	UPLOAD                   = "upload"
+	VALIDATE                 = "validate"
+	REVISION                 = "revision"
+	ROLLBACK                 = "rollback"
+	PROFILEREVISION          = "profilerevision"
+	BUNDLE                   = "bundle"
+	DRYRUN                   = "dryrun"
 	MODEL                    = "model"
 	MANUFACTURER             = "manufacturer"
 	YAML                     = "yaml"
@@ -149,6 +170,7 @@ var (
 	ErrDuplicateName             = errors.New("Duplicate name for the resource")
 	ErrDuplicateCommandInProfile = errors.New("Duplicate name for command in device profile")
 	ErrCommandStillInUse         = errors.New("Command is still in use by device profiles")
+	ErrStillInUse                = errors.New("Resource is still in use by other resources")
 	/* TODO ENUM */
 	LOCKED   = "LOCKED"
 	UNLOCKED = "UNLOCKED"
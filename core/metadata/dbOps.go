@@ -18,269 +18,202 @@
 package metadata
 
 import (
-	"time"
+	"errors"
 
-	enums "github.com/edgexfoundry/edgex-go/core/domain/enums"
 	models "github.com/edgexfoundry/edgex-go/core/domain/models"
-	mgo "gopkg.in/mgo.v2"
 	bson "gopkg.in/mgo.v2/bson"
 )
 
-type DataStore struct {
-	s *mgo.Session
-}
+// Storage of the metadata, set by dbConnect
+var repository Repository
 
-func (ds DataStore) dataStore() *DataStore {
-	return &DataStore{ds.s.Copy()}
+// Connect to the database of the configured type
+func dbConnect() error {
+	r, err := newRepository(DATABASE)
+	if err != nil {
+		return err
+	}
+	if err := r.Connect(); err != nil {
+		return err
+	}
+	repository = r
+	return nil
 }
 
-// Connect to the database
-func dbConnect() bool {
-	if DATABASE == enums.MONGODB {
-
-		mongoDBDialInfo := &mgo.DialInfo{
-			Addrs:    []string{DOCKERMONGO},
-			Timeout:  time.Duration(configuration.MongoDBConnectTimeout) * time.Millisecond,
-			Database: MONGODATABASE,
-			Username: DBUSER,
-			Password: DBPASS,
-		}
-		s, err := mgo.DialWithInfo(mongoDBDialInfo)
-		if err != nil {
-			return false
-		}
+// Check that the database answers
+func dbPing() error {
+	if repository == nil {
+		return errors.New("not connected to the database")
+	}
+	return repository.Ping()
+}
 
-		// Set timeout based on configuration
-		s.SetSocketTimeout(time.Duration(configuration.MongoDBConnectTimeout) * time.Millisecond)
-		DS.s = s
-		return true
+// Close the database connection
+func dbClose() error {
+	if repository != nil {
+		return repository.Close()
 	}
-	return false
+	return nil
 }
 
 /* ----------------------- Schedule Event ------------------------------*/
 func getAllScheduleEvents(se *[]models.ScheduleEvent) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllScheduleEvents(se)
-	}
-	return nil
+	return repository.GetAllScheduleEvents(se)
 }
 func addScheduleEvent(se *models.ScheduleEvent) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddScheduleEvent(se)
-	}
-	return nil
+	return repository.AddScheduleEvent(se)
 }
 func getScheduleEventByName(se *models.ScheduleEvent, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleEventByName(se, n)
-	}
-	return nil
+	return repository.GetScheduleEventByName(se, n)
 }
 func updateScheduleEvent(se models.ScheduleEvent) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateScheduleEvent(se)
-	}
-	return nil
+	return repository.UpdateScheduleEvent(se)
 }
 func getScheduleEventById(se *models.ScheduleEvent, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleEventById(se, id)
-	}
-	return nil
+	return repository.GetScheduleEventById(se, id)
 }
 func getScheduleEventsByScheduleName(se *[]models.ScheduleEvent, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleEventsByScheduleName(se, n)
-	}
-	return nil
+	return repository.GetScheduleEventsByScheduleName(se, n)
 }
 func getScheduleEventsByAddressableId(se *[]models.ScheduleEvent, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleEventsByAddressableId(se, id)
-	}
-	return nil
+	return repository.GetScheduleEventsByAddressableId(se, id)
 }
 func getScheduleEventsByServiceName(se *[]models.ScheduleEvent, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleEventsByServiceName(se, n)
-	}
-	return nil
+	return repository.GetScheduleEventsByServiceName(se, n)
 }
 
 /* -------------------------- Schedule ---------------------------------*/
 func getAllSchedules(s *[]models.Schedule) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllSchedules(s)
-	}
-	return nil
+	return repository.GetAllSchedules(s)
 }
 func addSchedule(s *models.Schedule) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddSchedule(s)
-	}
-	return nil
+	return repository.AddSchedule(s)
 }
 func getScheduleByName(s *models.Schedule, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleByName(s, n)
-	}
-	return nil
+	return repository.GetScheduleByName(s, n)
 }
 func updateSchedule(s models.Schedule) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateSchedule(s)
-	}
-	return nil
+	return repository.UpdateSchedule(s)
 }
 func getScheduleById(s *models.Schedule, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetScheduleById(s, id)
-	}
-	return nil
+	return repository.GetScheduleById(s, id)
 }
 
 /* ------------------------Device Report -------------------------------*/
 func getAllDeviceReports(dr *[]models.DeviceReport) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllDeviceReports(dr)
-	}
-	return nil
+	return repository.GetAllDeviceReports(dr)
 }
 func getDeviceReportByDeviceName(dr *[]models.DeviceReport, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceReportByDeviceName(dr, n)
-	}
-	return nil
+	return repository.GetDeviceReportByDeviceName(dr, n)
 }
 func getDeviceReportByName(dr *models.DeviceReport, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceReportByName(dr, n)
-	}
-	return nil
+	return repository.GetDeviceReportByName(dr, n)
 }
 func getDeviceReportById(dr *models.DeviceReport, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceReportById(dr, id)
-	}
-	return nil
+	return repository.GetDeviceReportById(dr, id)
 }
 func addDeviceReport(dr *models.DeviceReport) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddDeviceReport(dr)
-	}
-	return nil
+	return repository.AddDeviceReport(dr)
 }
 func updateDeviceReport(dr *models.DeviceReport) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateDeviceReport(dr)
-	}
-	return nil
+	return repository.UpdateDeviceReport(dr)
 }
 func getDeviceReportsByScheduleEventName(dr *[]models.DeviceReport, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceReportsByScheduleEventName(dr, n)
-	}
-	return nil
+	return repository.GetDeviceReportsByScheduleEventName(dr, n)
 }
 
 // ------------------------------------- DEVICE --------------------------------------------
 
 func UpdateDevice(d models.Device) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateDevice(d)
-	}
-	return nil
+	return repository.UpdateDevice(d)
 }
 func getDeviceById(d *models.Device, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceById(d, id)
+	if err := repository.GetDeviceById(d, id); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfile(d)
 }
 func getDeviceByName(d *models.Device, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceByName(d, n)
+	if err := repository.GetDeviceByName(d, n); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfile(d)
 }
 func getAllDevices(d *[]models.Device) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllDevices(d)
+	n := len(*d)
+	if err := repository.GetAllDevices(d); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByProfileId(d *[]models.Device, pid string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByProfileId(d, pid)
+	n := len(*d)
+	if err := repository.GetDevicesByProfileId(d, pid); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByProfileName(d *[]models.Device, pn string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByProfileName(d, pn)
+	n := len(*d)
+	if err := repository.GetDevicesByProfileName(d, pn); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByServiceId(d *[]models.Device, sid string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByServiceId(d, sid)
+	n := len(*d)
+	if err := repository.GetDevicesByServiceId(d, sid); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByServiceName(d *[]models.Device, sn string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByServiceName(d, sn)
+	n := len(*d)
+	if err := repository.GetDevicesByServiceName(d, sn); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByAddressableId(d *[]models.Device, aid string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByAddressableId(d, aid)
+	// Check if the addressable exists
+	var a models.Addressable
+	if err := getAddressableById(&a, aid); err == ErrNotFound {
+		return err
 	}
-	return nil
+	n := len(*d)
+	if err := repository.GetDevicesByAddressableId(d, aid); err != nil {
+		return err
+	}
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesByAddressableName(d *[]models.Device, an string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesByAddressableName(d, an)
+	n := len(*d)
+	if err := repository.GetDevicesByAddressableName(d, an); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func getDevicesWithLabel(d *[]models.Device, l []string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDevicesWithLabel(d, l)
+	n := len(*d)
+	if err := repository.GetDevicesWithLabel(d, l); err != nil {
+		return err
 	}
-	return nil
+	return pinDeviceProfiles((*d)[n:])
 }
 func addDevice(d *models.Device) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddNewDevice(d)
-	}
-	return nil
+	return repository.AddDevice(d)
 }
 func updateDeviceProfile(dp *models.DeviceProfile) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateDeviceProfile(dp)
-	}
-	return nil
+	return repository.UpdateDeviceProfile(dp)
 }
 func addDeviceProfile(d *models.DeviceProfile) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddNewDeviceProfile(d)
-	}
-	return nil
+	return repository.AddDeviceProfile(d)
 }
 func getAllDeviceProfiles(d *[]models.DeviceProfile) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfiles(d, bson.M{})
-	}
-	return nil
+	return repository.GetAllDeviceProfiles(d)
 }
 func getDeviceProfileById(d *models.DeviceProfile, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfileById(d, id)
-	}
-	return nil
+	return repository.GetDeviceProfileById(d, id)
 }
 func deleteDeviceProfileById(dpid string) error {
 	if err := deleteById(DPCOL, dpid); err != nil {
@@ -291,328 +224,269 @@ func deleteDeviceProfileById(dpid string) error {
 func deleteDeviceProfileByName(n string) error {
 	var dp models.DeviceProfile
 	getDeviceProfileByName(&dp, n)
-	// Delete all of the commands for the device profile
+	// Delete the device profile
+	if err := deleteByName(DPCOL, n); err != nil {
+		return err
+	}
+	// Delete all of the commands for the device profile, no longer referenced by it
 	for i := 0; i < len(dp.Commands); i++ {
 		// TODO Figure out how to store MONGO ID
 		if err := deleteById(COMCOL, dp.Commands[i].Id.Hex()); err != nil {
 			return err
 		}
 	}
-	// Delete the device profile
-	if err := deleteByName(DPCOL, n); err != nil {
-		return err
-	}
 	return nil
 }
 
-//func getDeviceProfilesByCommandName(d *[]models.DeviceProfile, cn string) error {
-//	if DATABASE == enums.MONGODB {
-//		return mgoGetDeviceProfilesByCommandName(d, cn)
-//	}
-//	return nil
-//}
 func getDeviceProfilesByModel(dp *[]models.DeviceProfile, m string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfilesByModel(dp, m)
-	}
-	return nil
+	return repository.GetDeviceProfilesByModel(dp, m)
 }
 func getDeviceProfilesWithLabel(dp *[]models.DeviceProfile, l []string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfilesWithLabel(dp, l)
-	}
-	return nil
+	return repository.GetDeviceProfilesWithLabel(dp, l)
 }
 func getDeviceProfilesByManufacturerModel(dp *[]models.DeviceProfile, man string, mod string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfilesByManufacturerModel(dp, man, mod)
-	}
-	return nil
+	return repository.GetDeviceProfilesByManufacturerModel(dp, man, mod)
 }
 func getDeviceProfilesByManufacturer(dp *[]models.DeviceProfile, man string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfilesByManufacturer(dp, man)
-	}
-	return nil
+	return repository.GetDeviceProfilesByManufacturer(dp, man)
 }
 func getDeviceProfileByName(dp *models.DeviceProfile, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfileByName(dp, n)
-	}
-	return nil
+	return repository.GetDeviceProfileByName(dp, n)
 }
 func updateAddressable(ra *models.Addressable, r *models.Addressable) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateAddressable(ra, r)
+	if ra == nil {
+		return nil
 	}
-	return nil
+	if ra.Name != "" {
+		r.Name = ra.Name
+	}
+	if ra.Protocol != "" {
+		r.Protocol = ra.Protocol
+	}
+	if ra.Address != "" {
+		r.Address = ra.Address
+	}
+	if ra.Port != int(0) {
+		r.Port = ra.Port
+	}
+	if ra.Path != "" {
+		r.Path = ra.Path
+	}
+	if ra.Publisher != "" {
+		r.Publisher = ra.Publisher
+	}
+	if ra.User != "" {
+		r.User = ra.User
+	}
+	if ra.Password != "" {
+		r.Password = ra.Password
+	}
+	if ra.Topic != "" {
+		r.Topic = ra.Topic
+	}
+	return repository.UpdateAddressable(r)
+}
+
+// Store every field of the addressable
+func replaceAddressable(a *models.Addressable) error {
+	return repository.UpdateAddressable(a)
 }
 func addAddressable(a *models.Addressable) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddNewAddressable(a)
-	}
-	return nil
+	return repository.AddAddressable(a)
 }
 func getAddressableById(a *models.Addressable, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressableById(a, id)
-	}
-	return nil
+	return repository.GetAddressableById(a, id)
 }
 func getAddressableByName(a *models.Addressable, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressableByName(a, n)
-	}
-	return nil
+	return repository.GetAddressableByName(a, n)
 }
 func getAddressablesByTopic(a *[]models.Addressable, t string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressablesByTopic(a, t)
-	}
-	return nil
+	return repository.GetAddressablesByTopic(a, t)
 }
 func getAddressablesByPort(a *[]models.Addressable, p int) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressablesByPort(a, p)
-	}
-	return nil
+	return repository.GetAddressablesByPort(a, p)
 }
 func getAddressablesByPublisher(a *[]models.Addressable, p string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressablesByPublisher(a, p)
-	}
-	return nil
+	return repository.GetAddressablesByPublisher(a, p)
 }
 func getAddressablesByAddress(a *[]models.Addressable, add string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressablesByAddress(a, add)
-	}
-	return nil
-}
-func getAddressable(d *models.Addressable, q bson.M) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressable(d, q)
-	}
-	return nil
+	return repository.GetAddressablesByAddress(a, add)
 }
-func getAddressables(d *[]models.Addressable, q bson.M) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAddressables(d, q)
-	}
-	return nil
+func getAllAddressables(d *[]models.Addressable) error {
+	return repository.GetAllAddressables(d)
 }
 func isAddressableAssociatedToDevice(a models.Addressable) (bool, error) {
-	if DATABASE == enums.MONGODB {
-		return mgoIsAddressableAssociatedToDevice(a)
-	}
-	return false, nil
+	return repository.IsAddressableAssociatedToDevice(a)
 }
 func isAddressableAssociatedToDeviceService(a models.Addressable) (bool, error) {
-	if DATABASE == enums.MONGODB {
-		return mgoIsAddressableAssociatedToDeviceService(a)
-	}
-	return false, nil
+	return repository.IsAddressableAssociatedToDeviceService(a)
 }
 
 // ------------------------ DEVICE SERVICE -----------------------
 
 func updateDeviceService(ds models.DeviceService) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateDeviceService(ds)
-	}
-	return nil
+	return repository.UpdateDeviceService(ds)
 }
 func getDeviceServicesByAddressableName(d *[]models.DeviceService, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceServicesByAddressableName(d, n)
-	}
-	return nil
+	return repository.GetDeviceServicesByAddressableName(d, n)
 }
 func getDeviceServicesByAddressableId(d *[]models.DeviceService, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceServicesByAddressableId(d, id)
-	}
-	return nil
+	return repository.GetDeviceServicesByAddressableId(d, id)
 }
 func getDeviceServicesWithLabel(d *[]models.DeviceService, l []string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceServicesWithLabel(d, l)
-	}
-	return nil
+	return repository.GetDeviceServicesWithLabel(d, l)
 }
 func getDeviceServiceById(d *models.DeviceService, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceServiceById(d, id)
-	}
-	return nil
+	return repository.GetDeviceServiceById(d, id)
 }
 func getDeviceServiceByName(d *models.DeviceService, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceServiceByName(d, n)
-	}
-	return nil
+	return repository.GetDeviceServiceByName(d, n)
 }
 func getAllDeviceServices(d *[]models.DeviceService) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllDeviceServices(d)
-	}
-	return nil
+	return repository.GetAllDeviceServices(d)
 }
 func addDeviceService(ds *models.DeviceService) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddNewDeviceService(ds)
-	}
-	return nil
+	return repository.AddDeviceService(ds)
 }
 
 /* -----------------------Provision Watcher ----------------------*/
 func getProvisionWatcherById(pw *models.ProvisionWatcher, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatcherById(pw, id)
-	}
-	return nil
+	return repository.GetProvisionWatcherById(pw, id)
 }
 func getAllProvisionWatchers(pw *[]models.ProvisionWatcher) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllProvisionWatchers(pw)
-	}
-	return nil
+	return repository.GetAllProvisionWatchers(pw)
 }
 func getProvisionWatcherByName(pw *models.ProvisionWatcher, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatcherByName(pw, n)
-	}
-	return nil
+	return repository.GetProvisionWatcherByName(pw, n)
 }
 func getProvisionWatcherByProfileId(pw *[]models.ProvisionWatcher, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatcherByProfileId(pw, id)
-	}
-	return nil
+	return repository.GetProvisionWatchersByProfileId(pw, id)
 }
 func getProvisionWatchersByProfileName(pw *[]models.ProvisionWatcher, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatchersByProfileName(pw, n)
-	}
-	return nil
+	return repository.GetProvisionWatchersByProfileName(pw, n)
 }
 func getProvisionWatchersByServiceId(pw *[]models.ProvisionWatcher, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatchersByServiceId(pw, id)
-	}
-	return nil
+	return repository.GetProvisionWatchersByServiceId(pw, id)
 }
 func getProvisionWatchersByServiceName(pw *[]models.ProvisionWatcher, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatchersByServiceName(pw, n)
-	}
-	return nil
+	return repository.GetProvisionWatchersByServiceName(pw, n)
 }
 func getProvisionWatchersByIdentifier(pw *[]models.ProvisionWatcher, k string, v string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetProvisionWatchersByIdentifier(pw, k, v)
-	}
-	return nil
+	return repository.GetProvisionWatchersByIdentifier(pw, k, v)
 }
 func addProvisionWatcher(pw *models.ProvisionWatcher) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddProvisionWatcher(pw)
-	}
-	return nil
+	// get Device Service
+	var dev models.DeviceService
+	if pw.Service.Service.Id.Hex() != "" {
+		getDeviceServiceById(&dev, pw.Service.Service.Id.Hex())
+	} else if pw.Service.Service.Name != "" {
+		getDeviceServiceByName(&dev, pw.Service.Service.Name)
+	} else {
+		return errors.New("Device Service ID or Name is required")
+	}
+	pw.Service = dev
+
+	// get Device Profile
+	var dp models.DeviceProfile
+	if pw.Profile.Id.Hex() != "" {
+		getDeviceProfileById(&dp, pw.Profile.Id.Hex())
+	} else if pw.Profile.Name != "" {
+		getDeviceProfileByName(&dp, pw.Profile.Name)
+	} else {
+		return errors.New("Device Profile ID or Name is required")
+	}
+	pw.Profile = dp
+
+	return repository.AddProvisionWatcher(pw)
 }
 func updateProvisionWatcher(pw models.ProvisionWatcher) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateProvisionWatcher(pw)
-	}
-	return nil
+	return repository.UpdateProvisionWatcher(pw)
 }
 
 /* -----------------------COMMAND ----------------------*/
 func getCommandById(c *models.Command, id string) error {
-	if DATABASE == enums.MONGODB {
-		err := mgoGetCommandById(c, id)
-		if err == mgo.ErrNotFound {
-			return ErrNotFound
-		} else {
-			return err
-		}
-	}
-	return nil
+	return repository.GetCommandById(c, id)
 }
 func getCommandByName(d *[]models.Command, id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetCommandByName(d, id)
-	}
-	return nil
+	return repository.GetCommandByName(d, id)
 }
 func addCommand(c *models.Command) error {
-	if DATABASE == enums.MONGODB {
-		return mgoAddCommand(c)
-	}
-	return nil
+	return repository.AddCommand(c)
 }
 func getAllCommands(d *[]models.Command) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetAllCommands(d)
-	}
-	return nil
+	return repository.GetAllCommands(d)
 }
+
+// Update command uses the ID of the command for identification
 func updateCommand(c *models.Command, r *models.Command) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateCommand(c, r)
+	if c == nil {
+		return nil
 	}
-	return nil
+
+	// Check if the command has a valid ID
+	if len(c.Id.Hex()) == 0 || !bson.IsObjectIdHex(c.Id.Hex()) {
+		err := errors.New("ID required for updating a command")
+		return err
+	}
+
+	// Update the fields
+	if c.Name != "" {
+		r.Name = c.Name
+	}
+	// TODO check for Get and Put Equality
+
+	if (c.Get.String() != models.Get{}.String()) {
+		r.Get = c.Get
+	}
+	if (c.Put.String() != models.Put{}.String()) {
+		r.Put = c.Put
+	}
+	if c.Origin != 0 {
+		r.Origin = c.Origin
+	}
+
+	return repository.UpdateCommand(r)
 }
 func deleteByName(c string, n string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoDeleteByName(c, n)
-	}
-	return nil
+	return repository.DeleteByName(c, n)
 }
 func deleteCommandById(id string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoDeleteCommandById(id)
-	}
-
-	return nil
+	return repository.DeleteCommandById(id)
 }
 
 // Get the device profiles that are using the command
 func getDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Command) error {
-	if DATABASE == enums.MONGODB {
-		return mgoGetDeviceProfilesUsingCommand(dp, c)
-	}
-
-	return nil
+	return repository.GetDeviceProfilesUsingCommand(dp, c)
 }
 func deleteById(c string, did string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoDeleteById(c, did)
-	}
-	return nil
-
+	return repository.DeleteById(c, did)
 }
 func setByName(c string, n string, pv2 string, p2 string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateByName(c, n, pv2, p2)
-	}
-	return nil
+	return repository.SetByName(c, n, pv2, p2)
 }
 func setByNameInt(c string, n string, pv2 string, p2 int64) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateByNameInt(c, n, pv2, p2)
-	}
-	return nil
+	return repository.SetByNameInt(c, n, pv2, p2)
 }
 func setById(c string, did string, pv2 string, p2 string) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateById(c, did, pv2, p2)
-	}
-	return nil
+	return repository.SetById(c, did, pv2, p2)
 }
 func setByIdInt(c string, did string, pv2 string, p2 int64) error {
-	if DATABASE == enums.MONGODB {
-		return mgoUpdateByIdInt(c, did, pv2, p2)
-	}
-	return nil
+	return repository.SetByIdInt(c, did, pv2, p2)
+}
+
+/* ------------------------Device Profile Revision -------------------------*/
+func addDeviceProfileRevision(dpr *models.DeviceProfileRevision) error {
+	return repository.AddDeviceProfileRevision(dpr)
+}
+func getDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error {
+	return repository.GetDeviceProfileRevisions(dpr, pid)
+}
+func getDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error {
+	return repository.GetDeviceProfileRevision(dpr, pid, revision)
+}
+func deleteDeviceProfileRevisions(pid string) error {
+	return repository.DeleteDeviceProfileRevisions(pid)
+}
+func deleteDeviceProfileRevisionsFrom(pid string, revision int) error {
+	return repository.DeleteDeviceProfileRevisionsFrom(pid, revision)
 }
@@ -23,34 +23,49 @@ import (
 	"strings"
 
 	enums "github.com/edgexfoundry/edgex-go/core/domain/enums"
-	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/lifecycle"
 	logger "github.com/edgexfoundry/edgex-go/support/logging-client"
 	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 )
 
 // DS : DataStore to retrieve data from database.
 var DS DataStore
 var loggingClient logger.LoggingClient
+var authenticator auth.Authenticator
+var policy = authPolicy()
 var notificationsClient = notifications.NotificationsClient{}
 
-func ConnectToConsul(conf ConfigurationStruct) error {
-	// Initialize service on Consul
-	err := consulclient.ConsulInit(consulclient.ConsulConfig{
-		ServiceName:    conf.ServiceName,
-		ServicePort:    conf.ServicePort,
-		ServiceAddress: conf.ServiceAddress,
-		CheckAddress:   conf.ConsulCheckAddress,
-		CheckInterval:  conf.CheckInterval,
-		ConsulAddress:  conf.ConsulHost,
-		ConsulPort:     conf.ConsulPort,
+// Connect to the configured registry, register the service and update the configuration
+// from its key/value pairs
+func ConnectToRegistry(conf *ConfigurationStruct) error {
+	r, err := registry.Connect(registry.Config{
+		Type:       conf.RegistryType,
+		ConsulHost: conf.ConsulHost,
+		ConsulPort: conf.ConsulPort,
+		Directory:  conf.RegistryDirectory,
 	})
 	if err != nil {
-		return fmt.Errorf("connection to Consul could not be made: %v", err.Error())
-	} else {
-		// Update configuration data from Consul
-		if err := consulclient.CheckKeyValuePairs(&conf, conf.ApplicationName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
-			return fmt.Errorf("error getting key/values from Consul: %v", err.Error())
-		}
+		return fmt.Errorf("connection to the registry could not be made: %v", err.Error())
+	}
+
+	// Initialize service on the registry
+	err = r.Register(registry.Service{
+		Name:          conf.ServiceName,
+		Address:       conf.ServiceAddress,
+		Port:          conf.ServicePort,
+		CheckAddress:  conf.ConsulCheckAddress,
+		CheckInterval: conf.CheckInterval,
+	})
+	if err != nil {
+		return fmt.Errorf("registration of the service could not be made: %v", err.Error())
+	}
+
+	// Update configuration data from the registry
+	if err := r.CheckKeyValuePairs(conf, conf.ApplicationName, strings.Split(conf.ConsulProfilesActive, ";")); err != nil {
+		return fmt.Errorf("error getting key/values from the registry: %v", err.Error())
 	}
 	return nil
 }
@@ -78,9 +93,26 @@ func Init(conf ConfigurationStruct, l logger.LoggingClient) error {
 	if err != nil {
 		return err
 	}
-	if !dbConnect() {
-		return err
+	if err = dbConnect(); err != nil {
+		return fmt.Errorf("connection to the %s database could not be made: %v", DBTYPE, err.Error())
 	}
 
+	health.Register("database", dbPing)
+
+	authenticator, err = auth.Setup(auth.Config{
+		Enabled:          configuration.AuthEnabled,
+		APIKeys:          configuration.AuthAPIKeys,
+		JWTSecret:        configuration.AuthJWTSecret,
+		JWTPublicKeyFile: configuration.AuthJWTPublicKeyFile,
+		Scopes:           configuration.AuthScopes,
+	}, policy)
+	if err != nil {
+		return fmt.Errorf("could not initialize authentication: %v", err.Error())
+	}
 	return nil
 }
+
+// Release the resources of metadata on shutdown
+func RegisterLifecycle(svc *lifecycle.Service) {
+	svc.OnShutdown("database session", dbClose)
+}
@@ -0,0 +1,233 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/yaml.v2"
+)
+
+// Problem found in a device profile, the field is the path to the offending value
+// (i.e. resources[0].get[1].object)
+type profileIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Result of linting a device profile
+// Errors are broken references which device services would only find at runtime,
+// warnings are suspicious but usable parts of the profile
+type profileLint struct {
+	Valid    bool           `json:"valid"`
+	Errors   []profileIssue `json:"errors"`
+	Warnings []profileIssue `json:"warnings"`
+}
+
+func (l *profileLint) error(field string, format string, args ...interface{}) {
+	l.Errors = append(l.Errors, profileIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *profileLint) warning(field string, format string, args ...interface{}) {
+	l.Warnings = append(l.Warnings, profileIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check the names and the cross references of the device profile
+// The references to device resources are only checked when the profile has device resources,
+// older profiles only have commands
+func lintDeviceProfile(dp models.DeviceProfile) profileLint {
+	l := profileLint{Errors: []profileIssue{}, Warnings: []profileIssue{}}
+
+	if dp.Name == "" {
+		l.error("name", "Device profile name is required")
+	}
+
+	deviceResources := map[string]bool{}
+	for i, o := range dp.DeviceResources {
+		field := fmt.Sprintf("deviceResources[%d]", i)
+		switch {
+		case o.Name == "":
+			l.error(field+".name", "Device resource name is required")
+		case deviceResources[o.Name]:
+			l.error(field+".name", "Duplicate device resource name %s", o.Name)
+		}
+		deviceResources[o.Name] = true
+
+		if o.Properties.Value.Type == "" {
+			l.warning(field+".properties.value.type", "Device resource %s has no value type", o.Name)
+		}
+		switch o.Properties.Value.ReadWrite {
+		case "", "R", "W", "RW":
+		default:
+			l.warning(field+".properties.value.readWrite", "Unknown read/write permission %s, must be R, W or RW", o.Properties.Value.ReadWrite)
+		}
+	}
+	checkRefs := len(dp.DeviceResources) > 0
+	if !checkRefs {
+		l.warning("deviceResources", "No device resources, the references of the resources and commands aren't checked")
+	}
+
+	resources := map[string]bool{}
+	for i, r := range dp.Resources {
+		field := fmt.Sprintf("resources[%d]", i)
+		switch {
+		case r.Name == "":
+			l.error(field+".name", "Resource name is required")
+		case resources[r.Name]:
+			l.error(field+".name", "Duplicate resource name %s", r.Name)
+		}
+		resources[r.Name] = true
+	}
+
+	used := map[string]bool{}
+	for i, r := range dp.Resources {
+		for _, ops := range []struct {
+			name       string
+			operations []models.ResourceOperation
+		}{{"get", r.Get}, {"set", r.Set}} {
+			for j, ro := range ops.operations {
+				field := fmt.Sprintf("resources[%d].%s[%d]", i, ops.name, j)
+				used[ro.Object] = true
+				for _, s := range ro.Secondary {
+					used[s] = true
+				}
+
+				if ro.Object == "" && ro.Resource == "" {
+					l.error(field, "Resource operation needs an object or a resource")
+				}
+				if ro.Resource != "" && !resources[ro.Resource] {
+					l.error(field+".resource", "Unknown resource %s", ro.Resource)
+				}
+				if !checkRefs {
+					continue
+				}
+				if ro.Object != "" && !deviceResources[ro.Object] {
+					l.error(field+".object", "Unknown device resource %s", ro.Object)
+				}
+				for k, s := range ro.Secondary {
+					if !deviceResources[s] {
+						l.error(fmt.Sprintf("%s.secondary[%d]", field, k), "Unknown device resource %s", s)
+					}
+				}
+			}
+		}
+	}
+	if len(dp.Resources) > 0 {
+		for i, o := range dp.DeviceResources {
+			if o.Name != "" && !used[o.Name] {
+				l.warning(fmt.Sprintf("deviceResources[%d]", i), "Device resource %s isn't used by any resource", o.Name)
+			}
+		}
+	}
+
+	commands := map[string]bool{}
+	for i, c := range dp.Commands {
+		field := fmt.Sprintf("commands[%d]", i)
+		switch {
+		case c.Name == "":
+			l.error(field+".name", "Command name is required")
+		case commands[c.Name]:
+			l.error(field+".name", "Duplicate command name %s", c.Name)
+		}
+		commands[c.Name] = true
+
+		if len(dp.Resources) > 0 && c.Name != "" && !resources[c.Name] {
+			l.warning(field+".name", "No resource for command %s", c.Name)
+		}
+		if !checkRefs {
+			continue
+		}
+		if c.Get != nil {
+			lintResponses(&l, field+".get", c.Get.Responses, deviceResources)
+		}
+		if c.Put != nil {
+			lintResponses(&l, field+".put", c.Put.Responses, deviceResources)
+			for j, p := range c.Put.ParameterNames {
+				if !deviceResources[p] {
+					l.warning(fmt.Sprintf("%s.put.parameterNames[%d]", field, j), "Unknown device resource %s", p)
+				}
+			}
+		}
+	}
+
+	l.Valid = len(l.Errors) == 0
+	return l
+}
+
+// Check the expected values of the command responses name device resources
+func lintResponses(l *profileLint, field string, responses []models.Response, deviceResources map[string]bool) {
+	for i, r := range responses {
+		for j, v := range r.ExpectedValues {
+			if !deviceResources[v] {
+				l.error(fmt.Sprintf("%s.responses[%d].expectedValues[%d]", field, i, j), "Unknown device resource %s", v)
+			}
+		}
+	}
+}
+
+// Lint the device profile before it's added or updated
+// The issues are logged, in strict mode a profile with errors is rejected with the lint result
+func checkProfileLint(dp models.DeviceProfile, w http.ResponseWriter) error {
+	l := lintDeviceProfile(dp)
+	for _, i := range l.Errors {
+		loggingClient.Warn("Device profile " + dp.Name + ": " + i.Field + ": " + i.Message)
+	}
+	for _, i := range l.Warnings {
+		loggingClient.Debug("Device profile " + dp.Name + ": " + i.Field + ": " + i.Message)
+	}
+
+	if l.Valid || !configuration.StrictProfileValidation {
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(l)
+	return errors.New("Device profile " + dp.Name + " has lint errors")
+}
+
+// Lint a device profile without adding it
+// The profile is YAML when the content type says so, JSON otherwise
+func restValidateDeviceProfile(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	var dp models.DeviceProfile
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		err = yaml.Unmarshal(body, &dp)
+	} else {
+		err = json.Unmarshal(body, &dp)
+	}
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lintDeviceProfile(dp))
+}
@@ -0,0 +1,108 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+// A profile without lint issues, the test cases break one part of it
+func lintTestProfile() models.DeviceProfile {
+	value := models.PropertyValue{Type: "Float", ReadWrite: "RW"}
+	return models.DeviceProfile{
+		Name: "thermostat",
+		DeviceResources: []models.DeviceObject{
+			{Name: "temperature", Properties: models.ProfileProperty{Value: value}},
+			{Name: "setpoint", Properties: models.ProfileProperty{Value: value}},
+		},
+		Resources: []models.ProfileResource{
+			{Name: "temperature", Get: []models.ResourceOperation{{Operation: "get", Object: "temperature"}}},
+			{Name: "setpoint", Set: []models.ResourceOperation{{Operation: "set", Object: "setpoint", Secondary: []string{"temperature"}}}},
+		},
+		Commands: []models.Command{
+			{Name: "temperature", Get: &models.Get{Action: models.Action{Responses: []models.Response{{Code: "200", ExpectedValues: []string{"temperature"}}}}}},
+			{Name: "setpoint", Put: &models.Put{ParameterNames: []string{"setpoint"}}},
+		},
+	}
+}
+
+func fields(issues []profileIssue) []string {
+	res := []string{}
+	for _, i := range issues {
+		res = append(res, i.Field)
+	}
+	return res
+}
+
+func TestLintDeviceProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(dp *models.DeviceProfile)
+		errors   []string
+		warnings []string
+	}{
+		{"valid", func(dp *models.DeviceProfile) {}, []string{}, []string{}},
+		{"no name", func(dp *models.DeviceProfile) { dp.Name = "" }, []string{"name"}, []string{}},
+		{"unknown object", func(dp *models.DeviceProfile) { dp.Resources[0].Get[0].Object = "humidity" },
+			[]string{"resources[0].get[0].object"}, []string{}},
+		{"unknown secondary", func(dp *models.DeviceProfile) { dp.Resources[1].Set[0].Secondary = []string{"humidity"} },
+			[]string{"resources[1].set[0].secondary[0]"}, []string{}},
+		{"unknown expected value", func(dp *models.DeviceProfile) {
+			dp.Commands[0].Get.Responses[0].ExpectedValues = []string{"humidity"}
+		}, []string{"commands[0].get.responses[0].expectedValues[0]"}, []string{}},
+		{"unknown parameter", func(dp *models.DeviceProfile) { dp.Commands[1].Put.ParameterNames = []string{"humidity"} },
+			[]string{}, []string{"commands[1].put.parameterNames[0]"}},
+		{"duplicate names", func(dp *models.DeviceProfile) {
+			dp.DeviceResources[1].Name = "temperature"
+			dp.Commands[1].Name = "temperature"
+		}, []string{"deviceResources[1].name", "resources[1].set[0].object", "commands[1].name"}, []string{"commands[1].put.parameterNames[0]"}},
+		{"command without resource", func(dp *models.DeviceProfile) { dp.Commands[1].Name = "mode" },
+			[]string{}, []string{"commands[1].name"}},
+		{"unused device resource", func(dp *models.DeviceProfile) {
+			dp.Resources[1].Set[0].Secondary = nil
+			dp.Resources[0].Get[0] = models.ResourceOperation{Resource: "setpoint"}
+		}, []string{}, []string{"deviceResources[0]"}},
+		{"bad property", func(dp *models.DeviceProfile) {
+			dp.DeviceResources[0].Properties.Value = models.PropertyValue{ReadWrite: "X"}
+		},
+			[]string{}, []string{"deviceResources[0].properties.value.type", "deviceResources[0].properties.value.readWrite"}},
+		{"commands only", func(dp *models.DeviceProfile) {
+			dp.DeviceResources = nil
+			dp.Resources = nil
+		}, []string{}, []string{"deviceResources"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := lintTestProfile()
+			tt.change(&dp)
+			l := lintDeviceProfile(dp)
+			if !reflect.DeepEqual(fields(l.Errors), tt.errors) {
+				t.Errorf("Errors: expected %v, got %v", tt.errors, l.Errors)
+			}
+			if !reflect.DeepEqual(fields(l.Warnings), tt.warnings) {
+				t.Errorf("Warnings: expected %v, got %v", tt.warnings, l.Warnings)
+			}
+			if l.Valid != (len(tt.errors) == 0) {
+				t.Errorf("Expected valid to be %v", !l.Valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,1647 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+/*
+ * Repository kept in memory, for the tests and the single node demos: the metadata is lost when
+ * the service stops.
+ *
+ * The resources are stored as copies with their references reduced to IDs, resolved on reads.
+ * The rules of the SQL repository apply: the names are unique, a resource can't be added with
+ * a reference to a missing resource nor deleted while it is still referenced, and the names
+ * referenced by schedule events and device reports follow the renames.
+ */
+type memoryRepository struct {
+	mutex             sync.RWMutex
+	order             []bson.ObjectId                     // IDs of the resources in the order they were added
+	names             map[string]map[string]bson.ObjectId // Collection to the IDs by name, commands aren't unique
+	addressables      map[bson.ObjectId]models.Addressable
+	deviceServices    map[bson.ObjectId]models.DeviceService
+	commands          map[bson.ObjectId]models.Command
+	deviceProfiles    map[bson.ObjectId]models.DeviceProfile
+	profileRevisions  map[bson.ObjectId][]models.DeviceProfileRevision // Profile ID to its revisions ordered by number
+	devices           map[bson.ObjectId]models.Device
+	schedules         map[bson.ObjectId]models.Schedule
+	scheduleEvents    map[bson.ObjectId]models.ScheduleEvent
+	deviceReports     map[bson.ObjectId]models.DeviceReport
+	provisionWatchers map[bson.ObjectId]models.ProvisionWatcher
+}
+
+var errMissingReference = errors.New("Reference to a resource which doesn't exist")
+
+// Empty repository
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		names: map[string]map[string]bson.ObjectId{
+			ADDCOL: {}, DSCOL: {}, DPCOL: {}, DEVICECOL: {}, SCOL: {}, SECOL: {}, DRCOL: {}, PWCOL: {},
+		},
+		addressables:      map[bson.ObjectId]models.Addressable{},
+		deviceServices:    map[bson.ObjectId]models.DeviceService{},
+		commands:          map[bson.ObjectId]models.Command{},
+		deviceProfiles:    map[bson.ObjectId]models.DeviceProfile{},
+		profileRevisions:  map[bson.ObjectId][]models.DeviceProfileRevision{},
+		devices:           map[bson.ObjectId]models.Device{},
+		schedules:         map[bson.ObjectId]models.Schedule{},
+		scheduleEvents:    map[bson.ObjectId]models.ScheduleEvent{},
+		deviceReports:     map[bson.ObjectId]models.DeviceReport{},
+		provisionWatchers: map[bson.ObjectId]models.ProvisionWatcher{},
+	}
+}
+
+func (r *memoryRepository) Connect() error {
+	return nil
+}
+func (r *memoryRepository) Ping() error {
+	return nil
+}
+func (r *memoryRepository) Close() error {
+	return nil
+}
+
+// Deep copy through BSON, the values read back are the ones mongo would return
+func memoryCopy(from interface{}, to interface{}) error {
+	data, err := bson.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, to)
+}
+
+// ErrDuplicateName when the name is used by another resource of the collection
+func (r *memoryRepository) checkName(collection string, n string, id bson.ObjectId) error {
+	if other, ok := r.names[collection][n]; ok && other != id {
+		return ErrDuplicateName
+	}
+	return nil
+}
+
+// Record the resource under its name, previous is its former name
+func (r *memoryRepository) setName(collection string, id bson.ObjectId, previous string, n string) {
+	if r.names[collection][previous] == id {
+		delete(r.names[collection], previous)
+	}
+	r.names[collection][n] = id
+}
+
+// Remove the ID from the order of the resources
+func (r *memoryRepository) forget(id bson.ObjectId) {
+	for i := range r.order {
+		if r.order[i] == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func hasLabel(labels []string, l []string) bool {
+	for _, label := range labels {
+		for _, wanted := range l {
+			if label == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/* ----------------------------- References ----------------------------------*/
+// An empty reference is valid, as a NULL foreign key
+func (r *memoryRepository) checkRefs(addressable bson.ObjectId, service bson.ObjectId, profile bson.ObjectId) error {
+	if _, ok := r.addressables[addressable]; addressable != "" && !ok {
+		return errMissingReference
+	}
+	if _, ok := r.deviceServices[service]; service != "" && !ok {
+		return errMissingReference
+	}
+	if _, ok := r.deviceProfiles[profile]; profile != "" && !ok {
+		return errMissingReference
+	}
+	return nil
+}
+func (r *memoryRepository) checkNameRef(collection string, n string) error {
+	if _, ok := r.names[collection][n]; n != "" && !ok {
+		return errMissingReference
+	}
+	return nil
+}
+
+// Resource referenced, empty when there is no reference
+func (r *memoryRepository) addressableRef(id bson.ObjectId) (models.Addressable, error) {
+	var a models.Addressable
+	stored, ok := r.addressables[id]
+	if !ok {
+		return a, nil
+	}
+	err := memoryCopy(stored, &a)
+	return a, err
+}
+func (r *memoryRepository) deviceServiceRef(id bson.ObjectId) (models.DeviceService, error) {
+	stored, ok := r.deviceServices[id]
+	if !ok {
+		return models.DeviceService{}, nil
+	}
+	return r.readDeviceService(stored)
+}
+func (r *memoryRepository) deviceProfileRef(id bson.ObjectId) (models.DeviceProfile, error) {
+	stored, ok := r.deviceProfiles[id]
+	if !ok {
+		return models.DeviceProfile{}, nil
+	}
+	return r.readDeviceProfile(stored)
+}
+
+/* -----------------------------------Addressable --------------------------*/
+func (r *memoryRepository) findAddressables(match func(a models.Addressable) bool) ([]models.Addressable, error) {
+	var as []models.Addressable
+	for _, id := range r.order {
+		stored, ok := r.addressables[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		a, err := r.addressableRef(id)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
+func (r *memoryRepository) addressable(a *models.Addressable, id bson.ObjectId) error {
+	if _, ok := r.addressables[id]; !ok {
+		return ErrNotFound
+	}
+	var err error
+	*a, err = r.addressableRef(id)
+	return err
+}
+func (r *memoryRepository) UpdateAddressable(a *models.Addressable) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.addressables[a.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(ADDCOL, a.Name, a.Id); err != nil {
+		return err
+	}
+	var stored models.Addressable
+	if err := memoryCopy(a, &stored); err != nil {
+		return err
+	}
+	r.addressables[a.Id] = stored
+	r.setName(ADDCOL, a.Id, previous.Name, a.Name)
+	return nil
+}
+func (r *memoryRepository) AddAddressable(a *models.Addressable) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(ADDCOL, a.Name, ""); err != nil {
+		return err
+	}
+	a.Created = makeTimestamp()
+	a.Id = bson.NewObjectId()
+	var stored models.Addressable
+	if err := memoryCopy(a, &stored); err != nil {
+		return err
+	}
+	r.addressables[a.Id] = stored
+	r.setName(ADDCOL, a.Id, "", a.Name)
+	r.order = append(r.order, a.Id)
+	return nil
+}
+func (r *memoryRepository) GetAddressableById(a *models.Addressable, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.addressable(a, objectId(id))
+}
+func (r *memoryRepository) GetAddressableByName(a *models.Addressable, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.addressable(a, r.names[ADDCOL][n])
+}
+func (r *memoryRepository) GetAllAddressables(a *[]models.Addressable) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	as, err := r.findAddressables(func(models.Addressable) bool { return true })
+	*a = append(*a, as...)
+	return err
+}
+func (r *memoryRepository) GetAddressablesByTopic(a *[]models.Addressable, t string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	as, err := r.findAddressables(func(s models.Addressable) bool { return s.Topic == t })
+	*a = append(*a, as...)
+	return err
+}
+func (r *memoryRepository) GetAddressablesByPort(a *[]models.Addressable, p int) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	as, err := r.findAddressables(func(s models.Addressable) bool { return s.Port == p })
+	*a = append(*a, as...)
+	return err
+}
+func (r *memoryRepository) GetAddressablesByPublisher(a *[]models.Addressable, p string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	as, err := r.findAddressables(func(s models.Addressable) bool { return s.Publisher == p })
+	*a = append(*a, as...)
+	return err
+}
+func (r *memoryRepository) GetAddressablesByAddress(a *[]models.Addressable, add string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	as, err := r.findAddressables(func(s models.Addressable) bool { return s.Address == add })
+	*a = append(*a, as...)
+	return err
+}
+func (r *memoryRepository) IsAddressableAssociatedToDevice(a models.Addressable) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, d := range r.devices {
+		if d.Addressable.Id == a.Id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (r *memoryRepository) IsAddressableAssociatedToDeviceService(a models.Addressable) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, ds := range r.deviceServices {
+		if ds.Service.Addressable.Id == a.Id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+/* ----------------------------- Device Service ----------------------------------*/
+func (r *memoryRepository) readDeviceService(stored models.DeviceService) (models.DeviceService, error) {
+	var ds models.DeviceService
+	if err := memoryCopy(stored, &ds); err != nil {
+		return ds, err
+	}
+	var err error
+	ds.Service.Addressable, err = r.addressableRef(stored.Service.Addressable.Id)
+	return ds, err
+}
+func (r *memoryRepository) findDeviceServices(match func(ds models.DeviceService) bool) ([]models.DeviceService, error) {
+	var dss []models.DeviceService
+	for _, id := range r.order {
+		stored, ok := r.deviceServices[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		ds, err := r.readDeviceService(stored)
+		if err != nil {
+			return nil, err
+		}
+		dss = append(dss, ds)
+	}
+	return dss, nil
+}
+func (r *memoryRepository) deviceService(ds *models.DeviceService, id bson.ObjectId) error {
+	stored, ok := r.deviceServices[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var err error
+	*ds, err = r.readDeviceService(stored)
+	return err
+}
+
+// Copy of the service to store, with the reference to its addressable
+func (r *memoryRepository) storeDeviceService(ds models.DeviceService) error {
+	if err := r.checkRefs(ds.Service.Addressable.Id, "", ""); err != nil {
+		return err
+	}
+	ds.Service.Addressable = models.Addressable{Id: ds.Service.Addressable.Id}
+	var stored models.DeviceService
+	if err := memoryCopy(ds, &stored); err != nil {
+		return err
+	}
+	r.deviceServices[ds.Service.Id] = stored
+	return nil
+}
+func (r *memoryRepository) UpdateDeviceService(ds models.DeviceService) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.deviceServices[ds.Service.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(DSCOL, ds.Service.Name, ds.Service.Id); err != nil {
+		return err
+	}
+	ds.Service.Modified = makeTimestamp()
+	if err := r.storeDeviceService(ds); err != nil {
+		return err
+	}
+	r.setName(DSCOL, ds.Service.Id, previous.Service.Name, ds.Service.Name)
+	return nil
+}
+func (r *memoryRepository) GetDeviceServicesByAddressableName(d *[]models.DeviceService, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	aid, ok := r.names[ADDCOL][n]
+	dss, err := r.findDeviceServices(func(ds models.DeviceService) bool { return ok && ds.Service.Addressable.Id == aid })
+	*d = append(*d, dss...)
+	return err
+}
+func (r *memoryRepository) GetDeviceServicesByAddressableId(d *[]models.DeviceService, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	aid := objectId(id)
+	dss, err := r.findDeviceServices(func(ds models.DeviceService) bool { return aid != "" && ds.Service.Addressable.Id == aid })
+	*d = append(*d, dss...)
+	return err
+}
+func (r *memoryRepository) GetDeviceServicesWithLabel(d *[]models.DeviceService, l []string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dss, err := r.findDeviceServices(func(ds models.DeviceService) bool { return hasLabel(ds.Service.Labels, l) })
+	*d = append(*d, dss...)
+	return err
+}
+func (r *memoryRepository) GetDeviceServiceById(d *models.DeviceService, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceService(d, objectId(id))
+}
+func (r *memoryRepository) GetDeviceServiceByName(d *models.DeviceService, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceService(d, r.names[DSCOL][n])
+}
+func (r *memoryRepository) GetAllDeviceServices(d *[]models.DeviceService) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dss, err := r.findDeviceServices(func(models.DeviceService) bool { return true })
+	*d = append(*d, dss...)
+	return err
+}
+func (r *memoryRepository) AddDeviceService(ds *models.DeviceService) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(DSCOL, ds.Service.Name, ""); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	ds.Service.Created = ts
+	ds.Service.Modified = ts
+	ds.Service.Id = bson.NewObjectId()
+	if err := r.storeDeviceService(*ds); err != nil {
+		return err
+	}
+	r.setName(DSCOL, ds.Service.Id, "", ds.Service.Name)
+	r.order = append(r.order, ds.Service.Id)
+	return nil
+}
+
+/* ------------------------Command -------------------------------------*/
+func (r *memoryRepository) findCommands(match func(c models.Command) bool) ([]models.Command, error) {
+	var cs []models.Command
+	for _, id := range r.order {
+		stored, ok := r.commands[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		var c models.Command
+		if err := memoryCopy(stored, &c); err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+func (r *memoryRepository) insertCommand(c *models.Command) error {
+	c.Created = makeTimestamp()
+	c.Id = bson.NewObjectId()
+	var stored models.Command
+	if err := memoryCopy(c, &stored); err != nil {
+		return err
+	}
+	r.commands[c.Id] = stored
+	r.order = append(r.order, c.Id)
+	return nil
+}
+
+// Whether a device profile uses the command
+func (r *memoryRepository) commandInUse(id bson.ObjectId) bool {
+	for _, dp := range r.deviceProfiles {
+		for _, c := range dp.Commands {
+			if c.Id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+func (r *memoryRepository) GetCommandById(c *models.Command, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	stored, ok := r.commands[objectId(id)]
+	if !ok {
+		return ErrNotFound
+	}
+	var command models.Command
+	err := memoryCopy(stored, &command)
+	*c = command
+	return err
+}
+func (r *memoryRepository) GetCommandByName(c *[]models.Command, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	cs, err := r.findCommands(func(s models.Command) bool { return s.Name == n })
+	*c = append(*c, cs...)
+	return err
+}
+func (r *memoryRepository) AddCommand(c *models.Command) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.insertCommand(c)
+}
+func (r *memoryRepository) GetAllCommands(c *[]models.Command) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	cs, err := r.findCommands(func(models.Command) bool { return true })
+	*c = append(*c, cs...)
+	return err
+}
+func (r *memoryRepository) UpdateCommand(c *models.Command) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.commands[c.Id]; !ok {
+		return ErrNotFound
+	}
+	var stored models.Command
+	if err := memoryCopy(c, &stored); err != nil {
+		return err
+	}
+	r.commands[c.Id] = stored
+	return nil
+}
+func (r *memoryRepository) DeleteCommandById(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.commandInUse(objectId(id)) {
+		return ErrCommandStillInUse
+	}
+	return r.remove(COMCOL, objectId(id))
+}
+
+/* -----------------------------Device Profile -----------------------------*/
+func (r *memoryRepository) readDeviceProfile(stored models.DeviceProfile) (models.DeviceProfile, error) {
+	var dp models.DeviceProfile
+	if err := memoryCopy(stored, &dp); err != nil {
+		return dp, err
+	}
+	for i, c := range stored.Commands {
+		var command models.Command
+		if err := memoryCopy(r.commands[c.Id], &command); err != nil {
+			return dp, err
+		}
+		dp.Commands[i] = command
+	}
+	return dp, nil
+}
+func (r *memoryRepository) findDeviceProfiles(match func(dp models.DeviceProfile) bool) ([]models.DeviceProfile, error) {
+	var dps []models.DeviceProfile
+	for _, id := range r.order {
+		stored, ok := r.deviceProfiles[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		dp, err := r.readDeviceProfile(stored)
+		if err != nil {
+			return nil, err
+		}
+		dps = append(dps, dp)
+	}
+	return dps, nil
+}
+func (r *memoryRepository) deviceProfile(dp *models.DeviceProfile, id bson.ObjectId) error {
+	stored, ok := r.deviceProfiles[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var err error
+	*dp, err = r.readDeviceProfile(stored)
+	return err
+}
+
+// Copy of the profile to store, with the references to its commands
+func (r *memoryRepository) storeDeviceProfile(dp models.DeviceProfile) error {
+	commands := make([]models.Command, len(dp.Commands))
+	names := map[string]bool{}
+	for i, c := range dp.Commands {
+		if _, ok := r.commands[c.Id]; !ok {
+			return errMissingReference
+		}
+		if names[c.Name] {
+			return ErrDuplicateCommandInProfile
+		}
+		names[c.Name] = true
+		commands[i] = models.Command{Id: c.Id}
+	}
+	dp.Commands = commands
+	var stored models.DeviceProfile
+	if err := memoryCopy(dp, &stored); err != nil {
+		return err
+	}
+	r.deviceProfiles[dp.Id] = stored
+	return nil
+}
+func (r *memoryRepository) UpdateDeviceProfile(dp *models.DeviceProfile) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.deviceProfiles[dp.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(DPCOL, dp.Name, dp.Id); err != nil {
+		return err
+	}
+	dp.Modified = makeTimestamp()
+	if err := r.storeDeviceProfile(*dp); err != nil {
+		return err
+	}
+	r.setName(DPCOL, dp.Id, previous.Name, dp.Name)
+	return nil
+}
+func (r *memoryRepository) AddDeviceProfile(dp *models.DeviceProfile) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(DPCOL, dp.Name, ""); err != nil {
+		return err
+	}
+	for i := range dp.Commands {
+		for j := 0; j < i; j++ {
+			if dp.Commands[j].Name == dp.Commands[i].Name {
+				return ErrDuplicateCommandInProfile
+			}
+		}
+	}
+	for i := 0; i < len(dp.Commands); i++ {
+		if err := r.insertCommand(&dp.Commands[i]); err != nil {
+			return err
+		}
+	}
+	ts := makeTimestamp()
+	dp.Created = ts
+	dp.Modified = ts
+	dp.Id = bson.NewObjectId()
+	if err := r.storeDeviceProfile(*dp); err != nil {
+		return err
+	}
+	r.setName(DPCOL, dp.Id, "", dp.Name)
+	r.order = append(r.order, dp.Id)
+	return nil
+}
+func (r *memoryRepository) GetAllDeviceProfiles(dp *[]models.DeviceProfile) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(models.DeviceProfile) bool { return true })
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *memoryRepository) GetDeviceProfileById(dp *models.DeviceProfile, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceProfile(dp, objectId(id))
+}
+func (r *memoryRepository) GetDeviceProfilesByModel(dp *[]models.DeviceProfile, m string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(s models.DeviceProfile) bool { return s.Model == m })
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *memoryRepository) GetDeviceProfilesWithLabel(dp *[]models.DeviceProfile, l []string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(s models.DeviceProfile) bool { return hasLabel(s.Labels, l) })
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *memoryRepository) GetDeviceProfilesByManufacturerModel(dp *[]models.DeviceProfile, man string, mod string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(s models.DeviceProfile) bool { return s.Manufacturer == man && s.Model == mod })
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *memoryRepository) GetDeviceProfilesByManufacturer(dp *[]models.DeviceProfile, man string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(s models.DeviceProfile) bool { return s.Manufacturer == man })
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *memoryRepository) GetDeviceProfileByName(dp *models.DeviceProfile, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceProfile(dp, r.names[DPCOL][n])
+}
+func (r *memoryRepository) GetDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Command) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	dps, err := r.findDeviceProfiles(func(s models.DeviceProfile) bool {
+		for _, sc := range s.Commands {
+			if sc.Id == c.Id {
+				return true
+			}
+		}
+		return false
+	})
+	*dp = append(*dp, dps...)
+	return err
+}
+
+/* ------------------------Device Profile Revision -------------------------*/
+func (r *memoryRepository) AddDeviceProfileRevision(dpr *models.DeviceProfileRevision) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.deviceProfiles[dpr.ProfileId]; !ok {
+		return errMissingReference
+	}
+	revisions := r.profileRevisions[dpr.ProfileId]
+	i := sort.Search(len(revisions), func(i int) bool { return revisions[i].Revision >= dpr.Revision })
+	if i < len(revisions) && revisions[i].Revision == dpr.Revision {
+		return ErrDuplicateName
+	}
+	ts := makeTimestamp()
+	dpr.Created = ts
+	dpr.Modified = ts
+	dpr.Id = bson.NewObjectId()
+	var stored models.DeviceProfileRevision
+	if err := memoryCopy(dpr, &stored); err != nil {
+		return err
+	}
+	revisions = append(revisions, models.DeviceProfileRevision{})
+	copy(revisions[i+1:], revisions[i:])
+	revisions[i] = stored
+	r.profileRevisions[dpr.ProfileId] = revisions
+	return nil
+}
+func (r *memoryRepository) GetDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, stored := range r.profileRevisions[objectId(pid)] {
+		var revision models.DeviceProfileRevision
+		if err := memoryCopy(stored, &revision); err != nil {
+			return err
+		}
+		*dpr = append(*dpr, revision)
+	}
+	return nil
+}
+func (r *memoryRepository) GetDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, stored := range r.profileRevisions[objectId(pid)] {
+		if stored.Revision == revision {
+			var res models.DeviceProfileRevision
+			if err := memoryCopy(stored, &res); err != nil {
+				return err
+			}
+			*dpr = res
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+func (r *memoryRepository) DeleteDeviceProfileRevisions(pid string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.profileRevisions, objectId(pid))
+	return nil
+}
+func (r *memoryRepository) DeleteDeviceProfileRevisionsFrom(pid string, revision int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	revisions := r.profileRevisions[objectId(pid)]
+	i := sort.Search(len(revisions), func(i int) bool { return revisions[i].Revision >= revision })
+	if i == 0 {
+		delete(r.profileRevisions, objectId(pid))
+	} else {
+		r.profileRevisions[objectId(pid)] = revisions[:i]
+	}
+	return nil
+}
+
+/* ----------------------------- Device ---------------------------------- */
+func (r *memoryRepository) readDevice(stored models.Device) (models.Device, error) {
+	var d models.Device
+	if err := memoryCopy(stored, &d); err != nil {
+		return d, err
+	}
+	var err error
+	if d.Addressable, err = r.addressableRef(stored.Addressable.Id); err != nil {
+		return d, err
+	}
+	if d.Service, err = r.deviceServiceRef(stored.Service.Service.Id); err != nil {
+		return d, err
+	}
+	d.Profile, err = r.deviceProfileRef(stored.Profile.Id)
+	return d, err
+}
+func (r *memoryRepository) findDevices(match func(d models.Device) bool) ([]models.Device, error) {
+	var ds []models.Device
+	for _, id := range r.order {
+		stored, ok := r.devices[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		d, err := r.readDevice(stored)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+func (r *memoryRepository) device(d *models.Device, id bson.ObjectId) error {
+	stored, ok := r.devices[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var err error
+	*d, err = r.readDevice(stored)
+	return err
+}
+
+// Copy of the device to store, with the references to its addressable, service and profile
+func (r *memoryRepository) storeDevice(d models.Device) error {
+	if err := r.checkRefs(d.Addressable.Id, d.Service.Service.Id, d.Profile.Id); err != nil {
+		return err
+	}
+	d.Addressable = models.Addressable{Id: d.Addressable.Id}
+	var service models.DeviceService
+	service.Service.Id = d.Service.Service.Id
+	d.Service = service
+	d.Profile = models.DeviceProfile{Id: d.Profile.Id}
+	var stored models.Device
+	if err := memoryCopy(d, &stored); err != nil {
+		return err
+	}
+	r.devices[d.Id] = stored
+	return nil
+}
+func (r *memoryRepository) UpdateDevice(d models.Device) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.devices[d.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(DEVICECOL, d.Name, d.Id); err != nil {
+		return err
+	}
+	if err := r.storeDevice(d); err != nil {
+		return err
+	}
+	r.setName(DEVICECOL, d.Id, previous.Name, d.Name)
+
+	// The reports follow the device
+	for id, dr := range r.deviceReports {
+		if dr.Device == previous.Name {
+			dr.Device = d.Name
+			r.deviceReports[id] = dr
+		}
+	}
+	return nil
+}
+func (r *memoryRepository) GetDeviceById(d *models.Device, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.device(d, objectId(id))
+}
+func (r *memoryRepository) GetDeviceByName(d *models.Device, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.device(d, r.names[DEVICECOL][n])
+}
+func (r *memoryRepository) GetAllDevices(d *[]models.Device) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ds, err := r.findDevices(func(models.Device) bool { return true })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByProfileId(d *[]models.Device, pid string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id := objectId(pid)
+	ds, err := r.findDevices(func(s models.Device) bool { return id != "" && s.Profile.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByProfileName(d *[]models.Device, pn string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id, ok := r.names[DPCOL][pn]
+	ds, err := r.findDevices(func(s models.Device) bool { return ok && s.Profile.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByServiceId(d *[]models.Device, sid string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id := objectId(sid)
+	ds, err := r.findDevices(func(s models.Device) bool { return id != "" && s.Service.Service.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByServiceName(d *[]models.Device, sn string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id, ok := r.names[DSCOL][sn]
+	ds, err := r.findDevices(func(s models.Device) bool { return ok && s.Service.Service.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByAddressableId(d *[]models.Device, aid string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id := objectId(aid)
+	ds, err := r.findDevices(func(s models.Device) bool { return id != "" && s.Addressable.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesByAddressableName(d *[]models.Device, an string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	id, ok := r.names[ADDCOL][an]
+	ds, err := r.findDevices(func(s models.Device) bool { return ok && s.Addressable.Id == id })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) GetDevicesWithLabel(d *[]models.Device, l []string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ds, err := r.findDevices(func(s models.Device) bool { return hasLabel(s.Labels, l) })
+	*d = append(*d, ds...)
+	return err
+}
+func (r *memoryRepository) AddDevice(d *models.Device) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(DEVICECOL, d.Name, ""); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	d.Created = ts
+	d.Modified = ts
+	d.Id = bson.NewObjectId()
+	if err := r.storeDevice(*d); err != nil {
+		return err
+	}
+	r.setName(DEVICECOL, d.Id, "", d.Name)
+	r.order = append(r.order, d.Id)
+	return nil
+}
+
+/* -------------------------- Schedule ---------------------------------*/
+func (r *memoryRepository) findSchedules(match func(s models.Schedule) bool) ([]models.Schedule, error) {
+	var ss []models.Schedule
+	for _, id := range r.order {
+		stored, ok := r.schedules[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		var s models.Schedule
+		if err := memoryCopy(stored, &s); err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+func (r *memoryRepository) schedule(s *models.Schedule, id bson.ObjectId) error {
+	stored, ok := r.schedules[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var schedule models.Schedule
+	err := memoryCopy(stored, &schedule)
+	*s = schedule
+	return err
+}
+func (r *memoryRepository) GetAllSchedules(s *[]models.Schedule) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ss, err := r.findSchedules(func(models.Schedule) bool { return true })
+	*s = append(*s, ss...)
+	return err
+}
+func (r *memoryRepository) AddSchedule(s *models.Schedule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(SCOL, s.Name, ""); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	s.Created = ts
+	s.Modified = ts
+	s.Id = bson.NewObjectId()
+	var stored models.Schedule
+	if err := memoryCopy(s, &stored); err != nil {
+		return err
+	}
+	r.schedules[s.Id] = stored
+	r.setName(SCOL, s.Id, "", s.Name)
+	r.order = append(r.order, s.Id)
+	return nil
+}
+func (r *memoryRepository) GetScheduleByName(s *models.Schedule, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.schedule(s, r.names[SCOL][n])
+}
+func (r *memoryRepository) UpdateSchedule(s models.Schedule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.schedules[s.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(SCOL, s.Name, s.Id); err != nil {
+		return err
+	}
+	s.Modified = makeTimestamp()
+	var stored models.Schedule
+	if err := memoryCopy(s, &stored); err != nil {
+		return err
+	}
+	r.schedules[s.Id] = stored
+	r.setName(SCOL, s.Id, previous.Name, s.Name)
+
+	// The events follow the schedule
+	for id, se := range r.scheduleEvents {
+		if se.Schedule == previous.Name {
+			se.Schedule = s.Name
+			r.scheduleEvents[id] = se
+		}
+	}
+	return nil
+}
+func (r *memoryRepository) GetScheduleById(s *models.Schedule, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.schedule(s, objectId(id))
+}
+
+/* ----------------------- Schedule Event ------------------------------*/
+func (r *memoryRepository) readScheduleEvent(stored models.ScheduleEvent) (models.ScheduleEvent, error) {
+	var se models.ScheduleEvent
+	if err := memoryCopy(stored, &se); err != nil {
+		return se, err
+	}
+	var err error
+	se.Addressable, err = r.addressableRef(stored.Addressable.Id)
+	return se, err
+}
+func (r *memoryRepository) findScheduleEvents(match func(se models.ScheduleEvent) bool) ([]models.ScheduleEvent, error) {
+	var ses []models.ScheduleEvent
+	for _, id := range r.order {
+		stored, ok := r.scheduleEvents[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		se, err := r.readScheduleEvent(stored)
+		if err != nil {
+			return nil, err
+		}
+		ses = append(ses, se)
+	}
+	return ses, nil
+}
+func (r *memoryRepository) scheduleEvent(se *models.ScheduleEvent, id bson.ObjectId) error {
+	stored, ok := r.scheduleEvents[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var err error
+	*se, err = r.readScheduleEvent(stored)
+	return err
+}
+
+// Copy of the event to store, with the references to its schedule and addressable
+func (r *memoryRepository) storeScheduleEvent(se models.ScheduleEvent) error {
+	if err := r.checkNameRef(SCOL, se.Schedule); err != nil {
+		return err
+	}
+	if err := r.checkRefs(se.Addressable.Id, "", ""); err != nil {
+		return err
+	}
+	se.Addressable = models.Addressable{Id: se.Addressable.Id}
+	var stored models.ScheduleEvent
+	if err := memoryCopy(se, &stored); err != nil {
+		return err
+	}
+	r.scheduleEvents[se.Id] = stored
+	return nil
+}
+func (r *memoryRepository) GetAllScheduleEvents(se *[]models.ScheduleEvent) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ses, err := r.findScheduleEvents(func(models.ScheduleEvent) bool { return true })
+	*se = append(*se, ses...)
+	return err
+}
+func (r *memoryRepository) AddScheduleEvent(se *models.ScheduleEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(SECOL, se.Name, ""); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	se.Created = ts
+	se.Modified = ts
+	se.Id = bson.NewObjectId()
+	if err := r.storeScheduleEvent(*se); err != nil {
+		return err
+	}
+	r.setName(SECOL, se.Id, "", se.Name)
+	r.order = append(r.order, se.Id)
+	return nil
+}
+func (r *memoryRepository) GetScheduleEventByName(se *models.ScheduleEvent, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.scheduleEvent(se, r.names[SECOL][n])
+}
+func (r *memoryRepository) UpdateScheduleEvent(se models.ScheduleEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.scheduleEvents[se.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(SECOL, se.Name, se.Id); err != nil {
+		return err
+	}
+	se.Modified = makeTimestamp()
+	if err := r.storeScheduleEvent(se); err != nil {
+		return err
+	}
+	r.setName(SECOL, se.Id, previous.Name, se.Name)
+
+	// The reports follow the event
+	for id, dr := range r.deviceReports {
+		if dr.Event == previous.Name {
+			dr.Event = se.Name
+			r.deviceReports[id] = dr
+		}
+	}
+	return nil
+}
+func (r *memoryRepository) GetScheduleEventById(se *models.ScheduleEvent, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.scheduleEvent(se, objectId(id))
+}
+func (r *memoryRepository) GetScheduleEventsByScheduleName(se *[]models.ScheduleEvent, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ses, err := r.findScheduleEvents(func(s models.ScheduleEvent) bool { return s.Schedule == n })
+	*se = append(*se, ses...)
+	return err
+}
+func (r *memoryRepository) GetScheduleEventsByAddressableId(se *[]models.ScheduleEvent, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	aid := objectId(id)
+	ses, err := r.findScheduleEvents(func(s models.ScheduleEvent) bool { return aid != "" && s.Addressable.Id == aid })
+	*se = append(*se, ses...)
+	return err
+}
+func (r *memoryRepository) GetScheduleEventsByServiceName(se *[]models.ScheduleEvent, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	ses, err := r.findScheduleEvents(func(s models.ScheduleEvent) bool { return s.Service == n })
+	*se = append(*se, ses...)
+	return err
+}
+
+/* ------------------------Device Report -------------------------------*/
+func (r *memoryRepository) findDeviceReports(match func(dr models.DeviceReport) bool) ([]models.DeviceReport, error) {
+	var drs []models.DeviceReport
+	for _, id := range r.order {
+		stored, ok := r.deviceReports[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		var dr models.DeviceReport
+		if err := memoryCopy(stored, &dr); err != nil {
+			return nil, err
+		}
+		drs = append(drs, dr)
+	}
+	return drs, nil
+}
+func (r *memoryRepository) deviceReport(dr *models.DeviceReport, id bson.ObjectId) error {
+	stored, ok := r.deviceReports[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var report models.DeviceReport
+	err := memoryCopy(stored, &report)
+	*dr = report
+	return err
+}
+
+// Copy of the report to store, with the references to its device and event
+func (r *memoryRepository) storeDeviceReport(dr models.DeviceReport) error {
+	if err := r.checkNameRef(DEVICECOL, dr.Device); err != nil {
+		return err
+	}
+	if err := r.checkNameRef(SECOL, dr.Event); err != nil {
+		return err
+	}
+	var stored models.DeviceReport
+	if err := memoryCopy(dr, &stored); err != nil {
+		return err
+	}
+	r.deviceReports[dr.Id] = stored
+	return nil
+}
+func (r *memoryRepository) GetAllDeviceReports(dr *[]models.DeviceReport) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	drs, err := r.findDeviceReports(func(models.DeviceReport) bool { return true })
+	*dr = append(*dr, drs...)
+	return err
+}
+func (r *memoryRepository) GetDeviceReportByDeviceName(dr *[]models.DeviceReport, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	drs, err := r.findDeviceReports(func(s models.DeviceReport) bool { return s.Device == n })
+	*dr = append(*dr, drs...)
+	return err
+}
+func (r *memoryRepository) GetDeviceReportByName(dr *models.DeviceReport, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceReport(dr, r.names[DRCOL][n])
+}
+func (r *memoryRepository) GetDeviceReportById(dr *models.DeviceReport, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.deviceReport(dr, objectId(id))
+}
+func (r *memoryRepository) AddDeviceReport(dr *models.DeviceReport) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(DRCOL, dr.Name, ""); err != nil {
+		return err
+	}
+	dr.Created = makeTimestamp()
+	dr.Id = bson.NewObjectId()
+	if err := r.storeDeviceReport(*dr); err != nil {
+		return err
+	}
+	r.setName(DRCOL, dr.Id, "", dr.Name)
+	r.order = append(r.order, dr.Id)
+	return nil
+}
+func (r *memoryRepository) UpdateDeviceReport(dr *models.DeviceReport) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.deviceReports[dr.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(DRCOL, dr.Name, dr.Id); err != nil {
+		return err
+	}
+	if err := r.storeDeviceReport(*dr); err != nil {
+		return err
+	}
+	r.setName(DRCOL, dr.Id, previous.Name, dr.Name)
+	return nil
+}
+func (r *memoryRepository) GetDeviceReportsByScheduleEventName(dr *[]models.DeviceReport, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	drs, err := r.findDeviceReports(func(s models.DeviceReport) bool { return s.Event == n })
+	*dr = append(*dr, drs...)
+	return err
+}
+
+/* ----------------------Provision Watcher -----------------------------*/
+func (r *memoryRepository) readProvisionWatcher(stored models.ProvisionWatcher) (models.ProvisionWatcher, error) {
+	var pw models.ProvisionWatcher
+	if err := memoryCopy(stored, &pw); err != nil {
+		return pw, err
+	}
+	var err error
+	if pw.Profile, err = r.deviceProfileRef(stored.Profile.Id); err != nil {
+		return pw, err
+	}
+	pw.Service, err = r.deviceServiceRef(stored.Service.Service.Id)
+	return pw, err
+}
+func (r *memoryRepository) findProvisionWatchers(match func(pw models.ProvisionWatcher) bool) ([]models.ProvisionWatcher, error) {
+	var pws []models.ProvisionWatcher
+	for _, id := range r.order {
+		stored, ok := r.provisionWatchers[id]
+		if !ok || !match(stored) {
+			continue
+		}
+		pw, err := r.readProvisionWatcher(stored)
+		if err != nil {
+			return nil, err
+		}
+		pws = append(pws, pw)
+	}
+	return pws, nil
+}
+func (r *memoryRepository) provisionWatcher(pw *models.ProvisionWatcher, id bson.ObjectId) error {
+	stored, ok := r.provisionWatchers[id]
+	if !ok {
+		return ErrNotFound
+	}
+	var err error
+	*pw, err = r.readProvisionWatcher(stored)
+	return err
+}
+
+// Copy of the watcher to store, with the references to its profile and service
+func (r *memoryRepository) storeProvisionWatcher(pw models.ProvisionWatcher) error {
+	if err := r.checkRefs("", pw.Service.Service.Id, pw.Profile.Id); err != nil {
+		return err
+	}
+	var service models.DeviceService
+	service.Service.Id = pw.Service.Service.Id
+	pw.Service = service
+	pw.Profile = models.DeviceProfile{Id: pw.Profile.Id}
+	var stored models.ProvisionWatcher
+	if err := memoryCopy(pw, &stored); err != nil {
+		return err
+	}
+	r.provisionWatchers[pw.Id] = stored
+	return nil
+}
+func (r *memoryRepository) GetProvisionWatcherById(pw *models.ProvisionWatcher, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.provisionWatcher(pw, objectId(id))
+}
+func (r *memoryRepository) GetAllProvisionWatchers(pw *[]models.ProvisionWatcher) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	pws, err := r.findProvisionWatchers(func(models.ProvisionWatcher) bool { return true })
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) GetProvisionWatcherByName(pw *models.ProvisionWatcher, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.provisionWatcher(pw, r.names[PWCOL][n])
+}
+func (r *memoryRepository) GetProvisionWatchersByProfileId(pw *[]models.ProvisionWatcher, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	pid := objectId(id)
+	pws, err := r.findProvisionWatchers(func(s models.ProvisionWatcher) bool { return pid != "" && s.Profile.Id == pid })
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) GetProvisionWatchersByProfileName(pw *[]models.ProvisionWatcher, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	pid, ok := r.names[DPCOL][n]
+	pws, err := r.findProvisionWatchers(func(s models.ProvisionWatcher) bool { return ok && s.Profile.Id == pid })
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) GetProvisionWatchersByServiceId(pw *[]models.ProvisionWatcher, id string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	sid := objectId(id)
+	pws, err := r.findProvisionWatchers(func(s models.ProvisionWatcher) bool { return sid != "" && s.Service.Service.Id == sid })
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) GetProvisionWatchersByServiceName(pw *[]models.ProvisionWatcher, n string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	sid, ok := r.names[DSCOL][n]
+	pws, err := r.findProvisionWatchers(func(s models.ProvisionWatcher) bool { return ok && s.Service.Service.Id == sid })
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) GetProvisionWatchersByIdentifier(pw *[]models.ProvisionWatcher, k string, v string) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	pws, err := r.findProvisionWatchers(func(s models.ProvisionWatcher) bool {
+		value, ok := s.Identifiers[k]
+		return ok && value == v
+	})
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *memoryRepository) AddProvisionWatcher(pw *models.ProvisionWatcher) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkName(PWCOL, pw.Name, ""); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	pw.Created = ts
+	pw.Modified = ts
+	pw.Id = bson.NewObjectId()
+	if err := r.storeProvisionWatcher(*pw); err != nil {
+		return err
+	}
+	r.setName(PWCOL, pw.Id, "", pw.Name)
+	r.order = append(r.order, pw.Id)
+	return nil
+}
+func (r *memoryRepository) UpdateProvisionWatcher(pw models.ProvisionWatcher) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	previous, ok := r.provisionWatchers[pw.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	if err := r.checkName(PWCOL, pw.Name, pw.Id); err != nil {
+		return err
+	}
+	pw.Modified = makeTimestamp()
+	if err := r.storeProvisionWatcher(pw); err != nil {
+		return err
+	}
+	r.setName(PWCOL, pw.Id, previous.Name, pw.Name)
+	return nil
+}
+
+/* ------------------------ Collection --------------------------------*/
+// Name of the resource of the collection, false when it doesn't exist
+func (r *memoryRepository) resourceName(collection string, id bson.ObjectId) (string, bool, error) {
+	switch collection {
+	case ADDCOL:
+		a, ok := r.addressables[id]
+		return a.Name, ok, nil
+	case DSCOL:
+		ds, ok := r.deviceServices[id]
+		return ds.Service.Name, ok, nil
+	case COMCOL:
+		c, ok := r.commands[id]
+		return c.Name, ok, nil
+	case DPCOL:
+		dp, ok := r.deviceProfiles[id]
+		return dp.Name, ok, nil
+	case DEVICECOL:
+		d, ok := r.devices[id]
+		return d.Name, ok, nil
+	case SCOL:
+		s, ok := r.schedules[id]
+		return s.Name, ok, nil
+	case SECOL:
+		se, ok := r.scheduleEvents[id]
+		return se.Name, ok, nil
+	case DRCOL:
+		dr, ok := r.deviceReports[id]
+		return dr.Name, ok, nil
+	case PWCOL:
+		pw, ok := r.provisionWatchers[id]
+		return pw.Name, ok, nil
+	default:
+		return "", false, errors.New("Unknown collection: " + collection)
+	}
+}
+
+// Whether other resources still reference the resource with the ID and name
+func (r *memoryRepository) inUse(collection string, id bson.ObjectId, n string) bool {
+	switch collection {
+	case ADDCOL:
+		for _, ds := range r.deviceServices {
+			if ds.Service.Addressable.Id == id {
+				return true
+			}
+		}
+		for _, d := range r.devices {
+			if d.Addressable.Id == id {
+				return true
+			}
+		}
+		for _, se := range r.scheduleEvents {
+			if se.Addressable.Id == id {
+				return true
+			}
+		}
+	case DSCOL:
+		for _, d := range r.devices {
+			if d.Service.Service.Id == id {
+				return true
+			}
+		}
+		for _, pw := range r.provisionWatchers {
+			if pw.Service.Service.Id == id {
+				return true
+			}
+		}
+	case COMCOL:
+		return r.commandInUse(id)
+	case DPCOL:
+		for _, d := range r.devices {
+			if d.Profile.Id == id {
+				return true
+			}
+		}
+		for _, pw := range r.provisionWatchers {
+			if pw.Profile.Id == id {
+				return true
+			}
+		}
+	case DEVICECOL:
+		for _, dr := range r.deviceReports {
+			if dr.Device == n {
+				return true
+			}
+		}
+	case SCOL:
+		for _, se := range r.scheduleEvents {
+			if se.Schedule == n {
+				return true
+			}
+		}
+	case SECOL:
+		for _, dr := range r.deviceReports {
+			if dr.Event == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Delete the resource, ErrStillInUse while it is referenced
+func (r *memoryRepository) remove(collection string, id bson.ObjectId) error {
+	n, ok, err := r.resourceName(collection, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	if r.inUse(collection, id, n) {
+		return ErrStillInUse
+	}
+	switch collection {
+	case ADDCOL:
+		delete(r.addressables, id)
+	case DSCOL:
+		delete(r.deviceServices, id)
+	case COMCOL:
+		delete(r.commands, id)
+	case DPCOL:
+		delete(r.deviceProfiles, id)
+		delete(r.profileRevisions, id)
+	case DEVICECOL:
+		delete(r.devices, id)
+	case SCOL:
+		delete(r.schedules, id)
+	case SECOL:
+		delete(r.scheduleEvents, id)
+	case DRCOL:
+		delete(r.deviceReports, id)
+	case PWCOL:
+		delete(r.provisionWatchers, id)
+	}
+	if r.names[collection][n] == id {
+		delete(r.names[collection], n)
+	}
+	r.forget(id)
+	return nil
+}
+
+// IDs of the resources with the name, the command names aren't unique
+func (r *memoryRepository) idsByName(collection string, n string) []bson.ObjectId {
+	if collection != COMCOL {
+		if id, ok := r.names[collection][n]; ok {
+			return []bson.ObjectId{id}
+		}
+		return nil
+	}
+	var ids []bson.ObjectId
+	for id, c := range r.commands {
+		if c.Name == n {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Set one of the state fields of a device or device service and its modified time
+func (r *memoryRepository) set(collection string, id bson.ObjectId, field string, value interface{}) error {
+	switch collection {
+	case DEVICECOL:
+		d, ok := r.devices[id]
+		if !ok {
+			return ErrNotFound
+		}
+		if err := setState(field, value, &d.AdminState, &d.OperatingState, &d.LastConnected, &d.LastReported); err != nil {
+			return err
+		}
+		d.Modified = makeTimestamp()
+		r.devices[id] = d
+	case DSCOL:
+		ds, ok := r.deviceServices[id]
+		if !ok {
+			return ErrNotFound
+		}
+		s := &ds.Service
+		if err := setState(field, value, &ds.AdminState, &s.OperatingState, &s.LastConnected, &s.LastReported); err != nil {
+			return err
+		}
+		s.Modified = makeTimestamp()
+		r.deviceServices[id] = ds
+	default:
+		return errors.New("No state fields in the collection: " + collection)
+	}
+	return nil
+}
+func setState(field string, value interface{}, adminState *models.AdminState, operatingState *models.OperatingState,
+	lastConnected *int64, lastReported *int64) error {
+	s, isString := value.(string)
+	i, isInt := value.(int64)
+	switch {
+	case field == ADMINSTATE && isString:
+		*adminState = models.AdminState(s)
+	case field == OPERATINGSTATE && isString:
+		*operatingState = models.OperatingState(s)
+	case field == LASTCONNECTED && isInt:
+		*lastConnected = i
+	case field == LASTREPORTED && isInt:
+		*lastReported = i
+	default:
+		return errors.New("Unknown field: " + field)
+	}
+	return nil
+}
+func (r *memoryRepository) DeleteById(collection string, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.remove(collection, objectId(id))
+}
+func (r *memoryRepository) DeleteByName(collection string, n string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ids := r.idsByName(collection, n)
+	if len(ids) == 0 {
+		return ErrNotFound
+	}
+	for _, id := range ids {
+		if err := r.remove(collection, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (r *memoryRepository) SetById(collection string, id string, field string, value string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.set(collection, objectId(id), field, value)
+}
+func (r *memoryRepository) SetByIdInt(collection string, id string, field string, value int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.set(collection, objectId(id), field, value)
+}
+func (r *memoryRepository) SetByName(collection string, n string, field string, value string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.set(collection, r.names[collection][n], field, value)
+}
+func (r *memoryRepository) SetByNameInt(collection string, n string, field string, value int64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.set(collection, r.names[collection][n], field, value)
+}
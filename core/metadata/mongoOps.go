@@ -33,6 +33,52 @@ func makeTimestamp() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
 }
 
+type DataStore struct {
+	s *mgo.Session
+}
+
+func (ds DataStore) dataStore() *DataStore {
+	return &DataStore{ds.s.Copy()}
+}
+
+// Connect to the mongo database
+func mgoConnect() error {
+	mongoDBDialInfo := &mgo.DialInfo{
+		Addrs:    []string{DOCKERMONGO},
+		Timeout:  time.Duration(configuration.MongoDBConnectTimeout) * time.Millisecond,
+		Database: MONGODATABASE,
+		Username: DBUSER,
+		Password: DBPASS,
+	}
+	s, err := mgo.DialWithInfo(mongoDBDialInfo)
+	if err != nil {
+		return err
+	}
+
+	// Set timeout based on configuration
+	s.SetSocketTimeout(time.Duration(configuration.MongoDBConnectTimeout) * time.Millisecond)
+	DS.s = s
+	return nil
+}
+
+// Check that the database answers
+func mgoPing() error {
+	if DS.s == nil {
+		return errors.New("not connected to the database")
+	}
+	ds := DS.dataStore()
+	defer ds.s.Close()
+	return ds.s.Ping()
+}
+
+// Close the database session
+func mgoClose() error {
+	if DS.s != nil {
+		DS.s.Close()
+	}
+	return nil
+}
+
 /* -----------------------Schedule Event ------------------------*/
 func mgoUpdateScheduleEvent(se models.ScheduleEvent) error {
 	ds := DS.dataStore()
@@ -434,11 +480,6 @@ func mgoGetDevicesByServiceName(d *[]models.Device, sn string) error {
 }
 func mgoGetDevicesByAddressableId(d *[]models.Device, aid string) error {
 	if bson.IsObjectIdHex(aid) {
-		// Check if the addressable exists
-		var a *models.Addressable
-		if mgoGetAddressableById(a, aid) == mgo.ErrNotFound {
-			return mgo.ErrNotFound
-		}
 		return mgoGetDevices(d, bson.M{ADDRESSABLE + "." + "$id": bson.ObjectIdHex(aid)})
 	} else {
 		err := errors.New("mgoGetDevicesByAddressableId Invalid Object ID " + aid)
@@ -636,43 +677,76 @@ func mgoGetDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Comma
 	return mgoGetDeviceProfiles(dp, query)
 }
 
-/* -----------------------------------Addressable --------------------------*/
-func mgoUpdateAddressable(ra *models.Addressable, r *models.Addressable) error {
+/* ------------------------Device Profile Revision -------------------------*/
+func mgoAddDeviceProfileRevision(dpr *models.DeviceProfileRevision) error {
 	ds := DS.dataStore()
-
 	defer ds.s.Close()
-	c := ds.s.DB(DB).C(ADDCOL)
-	if ra == nil {
-		return nil
-	}
-	if ra.Name != "" {
-		r.Name = ra.Name
-	}
-	if ra.Protocol != "" {
-		r.Protocol = ra.Protocol
-	}
-	if ra.Address != "" {
-		r.Address = ra.Address
-	}
-	if ra.Port != int(0) {
-		r.Port = ra.Port
+	col := ds.s.DB(DB).C(DPRCOL)
+	count, err := col.Find(bson.M{"profileId": dpr.ProfileId, "revision": dpr.Revision}).Count()
+	if err != nil {
+		return err
+	} else if count > 0 {
+		return ErrDuplicateName
 	}
-	if ra.Path != "" {
-		r.Path = ra.Path
+	ts := makeTimestamp()
+	dpr.Created = ts
+	dpr.Modified = ts
+	dpr.Id = bson.NewObjectId()
+
+	return col.Insert(dpr)
+}
+func mgoGetDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error {
+	if !bson.IsObjectIdHex(pid) {
+		return errors.New("mgoGetDeviceProfileRevisions Invalid Object ID " + pid)
 	}
-	if ra.Publisher != "" {
-		r.Publisher = ra.Publisher
+	ds := DS.dataStore()
+	defer ds.s.Close()
+	col := ds.s.DB(DB).C(DPRCOL)
+
+	var revisions []models.DeviceProfileRevision
+	if err := col.Find(bson.M{"profileId": bson.ObjectIdHex(pid)}).Sort("revision").All(&revisions); err != nil {
+		return err
 	}
-	if ra.User != "" {
-		r.User = ra.User
+	*dpr = append(*dpr, revisions...)
+	return nil
+}
+func mgoGetDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error {
+	if !bson.IsObjectIdHex(pid) {
+		return errors.New("mgoGetDeviceProfileRevision Invalid Object ID " + pid)
 	}
-	if ra.Password != "" {
-		r.Password = ra.Password
+	ds := DS.dataStore()
+	defer ds.s.Close()
+	col := ds.s.DB(DB).C(DPRCOL)
+	return col.Find(bson.M{"profileId": bson.ObjectIdHex(pid), "revision": revision}).One(dpr)
+}
+func mgoDeleteDeviceProfileRevisions(pid string) error {
+	if !bson.IsObjectIdHex(pid) {
+		return errors.New("mgoDeleteDeviceProfileRevisions Invalid Object ID " + pid)
 	}
-	if ra.Topic != "" {
-		r.Topic = ra.Topic
+	ds := DS.dataStore()
+	defer ds.s.Close()
+	col := ds.s.DB(DB).C(DPRCOL)
+	_, err := col.RemoveAll(bson.M{"profileId": bson.ObjectIdHex(pid)})
+	return err
+}
+func mgoDeleteDeviceProfileRevisionsFrom(pid string, revision int) error {
+	if !bson.IsObjectIdHex(pid) {
+		return errors.New("mgoDeleteDeviceProfileRevisionsFrom Invalid Object ID " + pid)
 	}
-	if err := c.UpdateId(r.Id, r); err != nil {
+	ds := DS.dataStore()
+	defer ds.s.Close()
+	col := ds.s.DB(DB).C(DPRCOL)
+	_, err := col.RemoveAll(bson.M{"profileId": bson.ObjectIdHex(pid), "revision": bson.M{"$gte": revision}})
+	return err
+}
+
+/* -----------------------------------Addressable --------------------------*/
+func mgoUpdateAddressable(a *models.Addressable) error {
+	ds := DS.dataStore()
+
+	defer ds.s.Close()
+	c := ds.s.DB(DB).C(ADDCOL)
+	if err := c.UpdateId(a.Id, a); err != nil {
 		return err
 	}
 	return nil
@@ -690,7 +764,7 @@ func mgoGetAddressables(d *[]models.Addressable, q bson.M) error {
 }
 func mgoGetAddressableById(a *models.Addressable, id string) error {
 	if bson.IsObjectIdHex(id) {
-		return getAddressable(a, bson.M{_ID: bson.ObjectIdHex(id)})
+		return mgoGetAddressable(a, bson.M{_ID: bson.ObjectIdHex(id)})
 	} else {
 		err := errors.New("mgoGetAddressableById Invalid Object ID " + id)
 		return err
@@ -963,28 +1037,6 @@ func mgoAddProvisionWatcher(pw *models.ProvisionWatcher) error {
 		return ErrDuplicateName
 	}
 
-	// get Device Service
-	var dev models.DeviceService
-	if pw.Service.Service.Id.Hex() != "" {
-		mgoGetDeviceServiceById(&dev, pw.Service.Service.Id.Hex())
-	} else if pw.Service.Service.Name != "" {
-		mgoGetDeviceServiceByName(&dev, pw.Service.Service.Name)
-	} else {
-		return errors.New("Device Service ID or Name is required")
-	}
-	pw.Service = dev
-
-	// get Device Profile
-	var dp models.DeviceProfile
-	if pw.Profile.Id.Hex() != "" {
-		mgoGetDeviceProfileById(&dp, pw.Profile.Id.Hex())
-	} else if pw.Profile.Name != "" {
-		mgoGetDeviceProfileByName(&dp, pw.Profile.Name)
-	} else {
-		return errors.New("Device Profile ID or Name is required")
-	}
-	pw.Profile = dp
-
 	// Set data
 	ts := makeTimestamp()
 	pw.Created = ts
@@ -1069,37 +1121,12 @@ func mgoAddCommand(c *models.Command) error {
 }
 
 // Update command uses the ID of the command for identification
-func mgoUpdateCommand(c *models.Command, r *models.Command) error {
+func mgoUpdateCommand(c *models.Command) error {
 	ds := DS.dataStore()
 	defer ds.s.Close()
 	col := ds.s.DB(DB).C(COMCOL)
-	if c == nil {
-		return nil
-	}
-
-	// Check if the command has a valid ID
-	if len(c.Id.Hex()) == 0 || !bson.IsObjectIdHex(c.Id.Hex()) {
-		err := errors.New("ID required for updating a command")
-		return err
-	}
-
-	// Update the fields
-	if c.Name != "" {
-		r.Name = c.Name
-	}
-	// TODO check for Get and Put Equality
-
-	if (c.Get.String() != models.Get{}.String()) {
-		r.Get = c.Get
-	}
-	if (c.Put.String() != models.Put{}.String()) {
-		r.Put = c.Put
-	}
-	if c.Origin != 0 {
-		r.Origin = c.Origin
-	}
 
-	if err := col.UpdateId(r.Id, r); err != nil {
+	if err := col.UpdateId(c.Id, c); err != nil {
 		return err
 	}
 	return nil
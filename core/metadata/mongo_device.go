@@ -34,16 +34,17 @@ type MongoDevice struct {
 type MongoDeviceBSON struct {
 	models.DescribedObject `bson:",inline"`
 	Id                     bson.ObjectId         `bson:"_id,omitempty"`
-	Name                   string                `bson:"name"`           // Unique name for identifying a device
-	AdminState             models.AdminState     `bson:"adminState"`     // Admin state (locked/unlocked)
-	OperatingState         models.OperatingState `bson:"operatingState"` // Operating state (enabled/disabled)
-	Addressable            mgo.DBRef             `bson:"addressable"`    // Addressable for the device - stores information about it's address
-	LastConnected          int64                 `bson:"lastConnected"`  // Time (milliseconds) that the device last provided any feedback or responded to any request
-	LastReported           int64                 `bson:"lastReported"`   // Time (milliseconds) that the device reported data to the core microservice
-	Labels                 []string              `bson:"labels"`         // Other labels applied to the device to help with searching
-	Location               interface{}           `bson:"location"`       // Device service specific location (interface{} is an empty interface so it can be anything)
-	Service                mgo.DBRef             `bson:"service"`        // Associated Device Service - One per device
-	Profile                mgo.DBRef             `bson:"profile"`        // Associated Device Profile - Describes the device
+	Name                   string                `bson:"name"`            // Unique name for identifying a device
+	AdminState             models.AdminState     `bson:"adminState"`      // Admin state (locked/unlocked)
+	OperatingState         models.OperatingState `bson:"operatingState"`  // Operating state (enabled/disabled)
+	Addressable            mgo.DBRef             `bson:"addressable"`     // Addressable for the device - stores information about it's address
+	LastConnected          int64                 `bson:"lastConnected"`   // Time (milliseconds) that the device last provided any feedback or responded to any request
+	LastReported           int64                 `bson:"lastReported"`    // Time (milliseconds) that the device reported data to the core microservice
+	Labels                 []string              `bson:"labels"`          // Other labels applied to the device to help with searching
+	Location               interface{}           `bson:"location"`        // Device service specific location (interface{} is an empty interface so it can be anything)
+	Service                mgo.DBRef             `bson:"service"`         // Associated Device Service - One per device
+	Profile                mgo.DBRef             `bson:"profile"`         // Associated Device Profile - Describes the device
+	ProfileRevision        int                   `bson:"profileRevision"` // Revision of the profile the device is pinned to
 }
 
 // Custom marshaling into mongo
@@ -61,6 +62,7 @@ func (md MongoDevice) GetBSON() (interface{}, error) {
 		Location:        md.Location,
 		Service:         mgo.DBRef{Collection: DSCOL, Id: md.Service.Service.Id},
 		Profile:         mgo.DBRef{Collection: DPCOL, Id: md.Profile.Id},
+		ProfileRevision: md.ProfileRevision,
 	}, nil
 }
 
@@ -69,16 +71,17 @@ func (md *MongoDevice) SetBSON(raw bson.Raw) error {
 	decoded := new(struct {
 		models.DescribedObject `bson:",inline"`
 		Id                     bson.ObjectId         `bson:"_id,omitempty"`
-		Name                   string                `bson:"name"`           // Unique name for identifying a device
-		AdminState             models.AdminState     `bson:"adminState"`     // Admin state (locked/unlocked)
-		OperatingState         models.OperatingState `bson:"operatingState"` // Operating state (enabled/disabled)
-		Addressable            mgo.DBRef             `bson:"addressable"`    // Addressable for the device - stores information about it's address
-		LastConnected          int64                 `bson:"lastConnected"`  // Time (milliseconds) that the device last provided any feedback or responded to any request
-		LastReported           int64                 `bson:"lastReported"`   // Time (milliseconds) that the device reported data to the core microservice
-		Labels                 []string              `bson:"labels"`         // Other labels applied to the device to help with searching
-		Location               interface{}           `bson:"location"`       // Device service specific location (interface{} is an empty interface so it can be anything)
-		Service                mgo.DBRef             `bson:"service"`        // Associated Device Service - One per device
-		Profile                mgo.DBRef             `bson:"profile"`        // Associated Device Profile - Describes the device
+		Name                   string                `bson:"name"`            // Unique name for identifying a device
+		AdminState             models.AdminState     `bson:"adminState"`      // Admin state (locked/unlocked)
+		OperatingState         models.OperatingState `bson:"operatingState"`  // Operating state (enabled/disabled)
+		Addressable            mgo.DBRef             `bson:"addressable"`     // Addressable for the device - stores information about it's address
+		LastConnected          int64                 `bson:"lastConnected"`   // Time (milliseconds) that the device last provided any feedback or responded to any request
+		LastReported           int64                 `bson:"lastReported"`    // Time (milliseconds) that the device reported data to the core microservice
+		Labels                 []string              `bson:"labels"`          // Other labels applied to the device to help with searching
+		Location               interface{}           `bson:"location"`        // Device service specific location (interface{} is an empty interface so it can be anything)
+		Service                mgo.DBRef             `bson:"service"`         // Associated Device Service - One per device
+		Profile                mgo.DBRef             `bson:"profile"`         // Associated Device Profile - Describes the device
+		ProfileRevision        int                   `bson:"profileRevision"` // Revision of the profile the device is pinned to
 	})
 	bsonErr := raw.Unmarshal(decoded)
 	if bsonErr != nil {
@@ -95,6 +98,7 @@ func (md *MongoDevice) SetBSON(raw bson.Raw) error {
 	md.LastReported = decoded.LastReported
 	md.Labels = decoded.Labels
 	md.Location = decoded.Location
+	md.ProfileRevision = decoded.ProfileRevision
 
 	// De-reference the DBRef fields
 	ds := DS.dataStore()
@@ -0,0 +1,400 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Repository stored in mongo, see mongoOps.go
+type mongoRepository struct{}
+
+// Errors of mgo returned as the errors of the repository
+func mongoError(err error) error {
+	if err == mgo.ErrNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (mongoRepository) Connect() error {
+	return mongoError(mgoConnect())
+}
+
+func (mongoRepository) Ping() error {
+	return mongoError(mgoPing())
+}
+
+func (mongoRepository) Close() error {
+	return mongoError(mgoClose())
+}
+
+func (mongoRepository) GetAllScheduleEvents(se *[]models.ScheduleEvent) error {
+	return mongoError(mgoGetAllScheduleEvents(se))
+}
+
+func (mongoRepository) AddScheduleEvent(se *models.ScheduleEvent) error {
+	return mongoError(mgoAddScheduleEvent(se))
+}
+
+func (mongoRepository) GetScheduleEventByName(se *models.ScheduleEvent, n string) error {
+	return mongoError(mgoGetScheduleEventByName(se, n))
+}
+
+func (mongoRepository) UpdateScheduleEvent(se models.ScheduleEvent) error {
+	return mongoError(mgoUpdateScheduleEvent(se))
+}
+
+func (mongoRepository) GetScheduleEventById(se *models.ScheduleEvent, id string) error {
+	return mongoError(mgoGetScheduleEventById(se, id))
+}
+
+func (mongoRepository) GetScheduleEventsByScheduleName(se *[]models.ScheduleEvent, n string) error {
+	return mongoError(mgoGetScheduleEventsByScheduleName(se, n))
+}
+
+func (mongoRepository) GetScheduleEventsByAddressableId(se *[]models.ScheduleEvent, id string) error {
+	return mongoError(mgoGetScheduleEventsByAddressableId(se, id))
+}
+
+func (mongoRepository) GetScheduleEventsByServiceName(se *[]models.ScheduleEvent, n string) error {
+	return mongoError(mgoGetScheduleEventsByServiceName(se, n))
+}
+
+func (mongoRepository) GetAllSchedules(s *[]models.Schedule) error {
+	return mongoError(mgoGetAllSchedules(s))
+}
+
+func (mongoRepository) AddSchedule(s *models.Schedule) error {
+	return mongoError(mgoAddSchedule(s))
+}
+
+func (mongoRepository) GetScheduleByName(s *models.Schedule, n string) error {
+	return mongoError(mgoGetScheduleByName(s, n))
+}
+
+func (mongoRepository) UpdateSchedule(s models.Schedule) error {
+	return mongoError(mgoUpdateSchedule(s))
+}
+
+func (mongoRepository) GetScheduleById(s *models.Schedule, id string) error {
+	return mongoError(mgoGetScheduleById(s, id))
+}
+
+func (mongoRepository) GetAllDeviceReports(dr *[]models.DeviceReport) error {
+	return mongoError(mgoGetAllDeviceReports(dr))
+}
+
+func (mongoRepository) GetDeviceReportByDeviceName(dr *[]models.DeviceReport, n string) error {
+	return mongoError(mgoGetDeviceReportByDeviceName(dr, n))
+}
+
+func (mongoRepository) GetDeviceReportByName(dr *models.DeviceReport, n string) error {
+	return mongoError(mgoGetDeviceReportByName(dr, n))
+}
+
+func (mongoRepository) GetDeviceReportById(dr *models.DeviceReport, id string) error {
+	return mongoError(mgoGetDeviceReportById(dr, id))
+}
+
+func (mongoRepository) AddDeviceReport(dr *models.DeviceReport) error {
+	return mongoError(mgoAddDeviceReport(dr))
+}
+
+func (mongoRepository) UpdateDeviceReport(dr *models.DeviceReport) error {
+	return mongoError(mgoUpdateDeviceReport(dr))
+}
+
+func (mongoRepository) GetDeviceReportsByScheduleEventName(dr *[]models.DeviceReport, n string) error {
+	return mongoError(mgoGetDeviceReportsByScheduleEventName(dr, n))
+}
+
+func (mongoRepository) UpdateDevice(d models.Device) error {
+	return mongoError(mgoUpdateDevice(d))
+}
+
+func (mongoRepository) GetDeviceById(d *models.Device, id string) error {
+	return mongoError(mgoGetDeviceById(d, id))
+}
+
+func (mongoRepository) GetDeviceByName(d *models.Device, n string) error {
+	return mongoError(mgoGetDeviceByName(d, n))
+}
+
+func (mongoRepository) GetAllDevices(d *[]models.Device) error {
+	return mongoError(mgoGetAllDevices(d))
+}
+
+func (mongoRepository) GetDevicesByProfileId(d *[]models.Device, pid string) error {
+	return mongoError(mgoGetDevicesByProfileId(d, pid))
+}
+
+func (mongoRepository) GetDevicesByProfileName(d *[]models.Device, pn string) error {
+	return mongoError(mgoGetDevicesByProfileName(d, pn))
+}
+
+func (mongoRepository) GetDevicesByServiceId(d *[]models.Device, sid string) error {
+	return mongoError(mgoGetDevicesByServiceId(d, sid))
+}
+
+func (mongoRepository) GetDevicesByServiceName(d *[]models.Device, sn string) error {
+	return mongoError(mgoGetDevicesByServiceName(d, sn))
+}
+
+func (mongoRepository) GetDevicesByAddressableId(d *[]models.Device, aid string) error {
+	return mongoError(mgoGetDevicesByAddressableId(d, aid))
+}
+
+func (mongoRepository) GetDevicesByAddressableName(d *[]models.Device, an string) error {
+	return mongoError(mgoGetDevicesByAddressableName(d, an))
+}
+
+func (mongoRepository) GetDevicesWithLabel(d *[]models.Device, l []string) error {
+	return mongoError(mgoGetDevicesWithLabel(d, l))
+}
+
+func (mongoRepository) AddDevice(d *models.Device) error {
+	return mongoError(mgoAddNewDevice(d))
+}
+
+func (mongoRepository) UpdateDeviceProfile(dp *models.DeviceProfile) error {
+	return mongoError(mgoUpdateDeviceProfile(dp))
+}
+
+func (mongoRepository) AddDeviceProfile(dp *models.DeviceProfile) error {
+	return mongoError(mgoAddNewDeviceProfile(dp))
+}
+
+func (mongoRepository) GetAllDeviceProfiles(dp *[]models.DeviceProfile) error {
+	return mongoError(mgoGetDeviceProfiles(dp, bson.M{}))
+}
+
+func (mongoRepository) GetDeviceProfileById(dp *models.DeviceProfile, id string) error {
+	return mongoError(mgoGetDeviceProfileById(dp, id))
+}
+
+func (mongoRepository) GetDeviceProfilesByModel(dp *[]models.DeviceProfile, m string) error {
+	return mongoError(mgoGetDeviceProfilesByModel(dp, m))
+}
+
+func (mongoRepository) GetDeviceProfilesWithLabel(dp *[]models.DeviceProfile, l []string) error {
+	return mongoError(mgoGetDeviceProfilesWithLabel(dp, l))
+}
+
+func (mongoRepository) GetDeviceProfilesByManufacturerModel(dp *[]models.DeviceProfile, man string, mod string) error {
+	return mongoError(mgoGetDeviceProfilesByManufacturerModel(dp, man, mod))
+}
+
+func (mongoRepository) GetDeviceProfilesByManufacturer(dp *[]models.DeviceProfile, man string) error {
+	return mongoError(mgoGetDeviceProfilesByManufacturer(dp, man))
+}
+
+func (mongoRepository) GetDeviceProfileByName(dp *models.DeviceProfile, n string) error {
+	return mongoError(mgoGetDeviceProfileByName(dp, n))
+}
+
+func (mongoRepository) GetDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Command) error {
+	return mongoError(mgoGetDeviceProfilesUsingCommand(dp, c))
+}
+
+func (mongoRepository) AddDeviceProfileRevision(dpr *models.DeviceProfileRevision) error {
+	return mongoError(mgoAddDeviceProfileRevision(dpr))
+}
+
+func (mongoRepository) GetDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error {
+	return mongoError(mgoGetDeviceProfileRevisions(dpr, pid))
+}
+
+func (mongoRepository) GetDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error {
+	return mongoError(mgoGetDeviceProfileRevision(dpr, pid, revision))
+}
+
+func (mongoRepository) DeleteDeviceProfileRevisions(pid string) error {
+	return mongoError(mgoDeleteDeviceProfileRevisions(pid))
+}
+
+func (mongoRepository) DeleteDeviceProfileRevisionsFrom(pid string, revision int) error {
+	return mongoError(mgoDeleteDeviceProfileRevisionsFrom(pid, revision))
+}
+
+func (mongoRepository) UpdateAddressable(a *models.Addressable) error {
+	return mongoError(mgoUpdateAddressable(a))
+}
+
+func (mongoRepository) AddAddressable(a *models.Addressable) error {
+	return mongoError(mgoAddNewAddressable(a))
+}
+
+func (mongoRepository) GetAddressableById(a *models.Addressable, id string) error {
+	return mongoError(mgoGetAddressableById(a, id))
+}
+
+func (mongoRepository) GetAddressableByName(a *models.Addressable, n string) error {
+	return mongoError(mgoGetAddressableByName(a, n))
+}
+
+func (mongoRepository) GetAllAddressables(a *[]models.Addressable) error {
+	return mongoError(mgoGetAddressables(a, bson.M{}))
+}
+
+func (mongoRepository) GetAddressablesByTopic(a *[]models.Addressable, t string) error {
+	return mongoError(mgoGetAddressablesByTopic(a, t))
+}
+
+func (mongoRepository) GetAddressablesByPort(a *[]models.Addressable, p int) error {
+	return mongoError(mgoGetAddressablesByPort(a, p))
+}
+
+func (mongoRepository) GetAddressablesByPublisher(a *[]models.Addressable, p string) error {
+	return mongoError(mgoGetAddressablesByPublisher(a, p))
+}
+
+func (mongoRepository) GetAddressablesByAddress(a *[]models.Addressable, add string) error {
+	return mongoError(mgoGetAddressablesByAddress(a, add))
+}
+
+func (mongoRepository) IsAddressableAssociatedToDevice(a models.Addressable) (bool, error) {
+	associated, err := mgoIsAddressableAssociatedToDevice(a)
+	return associated, mongoError(err)
+}
+
+func (mongoRepository) IsAddressableAssociatedToDeviceService(a models.Addressable) (bool, error) {
+	associated, err := mgoIsAddressableAssociatedToDeviceService(a)
+	return associated, mongoError(err)
+}
+
+func (mongoRepository) UpdateDeviceService(ds models.DeviceService) error {
+	return mongoError(mgoUpdateDeviceService(ds))
+}
+
+func (mongoRepository) GetDeviceServicesByAddressableName(d *[]models.DeviceService, n string) error {
+	return mongoError(mgoGetDeviceServicesByAddressableName(d, n))
+}
+
+func (mongoRepository) GetDeviceServicesByAddressableId(d *[]models.DeviceService, id string) error {
+	return mongoError(mgoGetDeviceServicesByAddressableId(d, id))
+}
+
+func (mongoRepository) GetDeviceServicesWithLabel(d *[]models.DeviceService, l []string) error {
+	return mongoError(mgoGetDeviceServicesWithLabel(d, l))
+}
+
+func (mongoRepository) GetDeviceServiceById(d *models.DeviceService, id string) error {
+	return mongoError(mgoGetDeviceServiceById(d, id))
+}
+
+func (mongoRepository) GetDeviceServiceByName(d *models.DeviceService, n string) error {
+	return mongoError(mgoGetDeviceServiceByName(d, n))
+}
+
+func (mongoRepository) GetAllDeviceServices(d *[]models.DeviceService) error {
+	return mongoError(mgoGetAllDeviceServices(d))
+}
+
+func (mongoRepository) AddDeviceService(ds *models.DeviceService) error {
+	return mongoError(mgoAddNewDeviceService(ds))
+}
+
+func (mongoRepository) GetProvisionWatcherById(pw *models.ProvisionWatcher, id string) error {
+	return mongoError(mgoGetProvisionWatcherById(pw, id))
+}
+
+func (mongoRepository) GetAllProvisionWatchers(pw *[]models.ProvisionWatcher) error {
+	return mongoError(mgoGetAllProvisionWatchers(pw))
+}
+
+func (mongoRepository) GetProvisionWatcherByName(pw *models.ProvisionWatcher, n string) error {
+	return mongoError(mgoGetProvisionWatcherByName(pw, n))
+}
+
+func (mongoRepository) GetProvisionWatchersByProfileId(pw *[]models.ProvisionWatcher, id string) error {
+	return mongoError(mgoGetProvisionWatcherByProfileId(pw, id))
+}
+
+func (mongoRepository) GetProvisionWatchersByProfileName(pw *[]models.ProvisionWatcher, n string) error {
+	return mongoError(mgoGetProvisionWatchersByProfileName(pw, n))
+}
+
+func (mongoRepository) GetProvisionWatchersByServiceId(pw *[]models.ProvisionWatcher, id string) error {
+	return mongoError(mgoGetProvisionWatchersByServiceId(pw, id))
+}
+
+func (mongoRepository) GetProvisionWatchersByServiceName(pw *[]models.ProvisionWatcher, n string) error {
+	return mongoError(mgoGetProvisionWatchersByServiceName(pw, n))
+}
+
+func (mongoRepository) GetProvisionWatchersByIdentifier(pw *[]models.ProvisionWatcher, k string, v string) error {
+	return mongoError(mgoGetProvisionWatchersByIdentifier(pw, k, v))
+}
+
+func (mongoRepository) AddProvisionWatcher(pw *models.ProvisionWatcher) error {
+	return mongoError(mgoAddProvisionWatcher(pw))
+}
+
+func (mongoRepository) UpdateProvisionWatcher(pw models.ProvisionWatcher) error {
+	return mongoError(mgoUpdateProvisionWatcher(pw))
+}
+
+func (mongoRepository) GetCommandById(c *models.Command, id string) error {
+	return mongoError(mgoGetCommandById(c, id))
+}
+
+func (mongoRepository) GetCommandByName(c *[]models.Command, n string) error {
+	return mongoError(mgoGetCommandByName(c, n))
+}
+
+func (mongoRepository) AddCommand(c *models.Command) error {
+	return mongoError(mgoAddCommand(c))
+}
+
+func (mongoRepository) GetAllCommands(c *[]models.Command) error {
+	return mongoError(mgoGetAllCommands(c))
+}
+
+func (mongoRepository) UpdateCommand(c *models.Command) error {
+	return mongoError(mgoUpdateCommand(c))
+}
+
+func (mongoRepository) DeleteCommandById(id string) error {
+	return mongoError(mgoDeleteCommandById(id))
+}
+
+func (mongoRepository) DeleteById(collection string, id string) error {
+	return mongoError(mgoDeleteById(collection, id))
+}
+
+func (mongoRepository) DeleteByName(collection string, n string) error {
+	return mongoError(mgoDeleteByName(collection, n))
+}
+
+func (mongoRepository) SetById(collection string, id string, field string, value string) error {
+	return mongoError(mgoUpdateById(collection, id, field, value))
+}
+
+func (mongoRepository) SetByIdInt(collection string, id string, field string, value int64) error {
+	return mongoError(mgoUpdateByIdInt(collection, id, field, value))
+}
+
+func (mongoRepository) SetByName(collection string, n string, field string, value string) error {
+	return mongoError(mgoUpdateByName(collection, n, field, value))
+}
+
+func (mongoRepository) SetByNameInt(collection string, n string, field string, value int64) error {
+	return mongoError(mgoUpdateByNameInt(collection, n, field, value))
+}
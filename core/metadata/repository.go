@@ -0,0 +1,177 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"errors"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+/*
+ * Storage of the metadata, see dbOps.go for the operations of the service built on it.
+ * ErrNotFound is returned when the requested resource doesn't exist, ErrDuplicateName when
+ * a resource is added with a name already in use.
+ */
+type Repository interface {
+	// Open the connection to the database
+	Connect() error
+	// Check that the database answers
+	Ping() error
+	// Close the connection
+	Close() error
+
+	/* ----------------------- Schedule Event ------------------------------*/
+	GetAllScheduleEvents(se *[]models.ScheduleEvent) error
+	AddScheduleEvent(se *models.ScheduleEvent) error
+	GetScheduleEventByName(se *models.ScheduleEvent, n string) error
+	UpdateScheduleEvent(se models.ScheduleEvent) error
+	GetScheduleEventById(se *models.ScheduleEvent, id string) error
+	GetScheduleEventsByScheduleName(se *[]models.ScheduleEvent, n string) error
+	GetScheduleEventsByAddressableId(se *[]models.ScheduleEvent, id string) error
+	GetScheduleEventsByServiceName(se *[]models.ScheduleEvent, n string) error
+
+	/* -------------------------- Schedule ---------------------------------*/
+	GetAllSchedules(s *[]models.Schedule) error
+	AddSchedule(s *models.Schedule) error
+	GetScheduleByName(s *models.Schedule, n string) error
+	UpdateSchedule(s models.Schedule) error
+	GetScheduleById(s *models.Schedule, id string) error
+
+	/* ------------------------Device Report -------------------------------*/
+	GetAllDeviceReports(dr *[]models.DeviceReport) error
+	GetDeviceReportByDeviceName(dr *[]models.DeviceReport, n string) error
+	GetDeviceReportByName(dr *models.DeviceReport, n string) error
+	GetDeviceReportById(dr *models.DeviceReport, id string) error
+	AddDeviceReport(dr *models.DeviceReport) error
+	UpdateDeviceReport(dr *models.DeviceReport) error
+	GetDeviceReportsByScheduleEventName(dr *[]models.DeviceReport, n string) error
+
+	/* ----------------------------- Device ---------------------------------- */
+	UpdateDevice(d models.Device) error
+	GetDeviceById(d *models.Device, id string) error
+	GetDeviceByName(d *models.Device, n string) error
+	GetAllDevices(d *[]models.Device) error
+	GetDevicesByProfileId(d *[]models.Device, pid string) error
+	GetDevicesByProfileName(d *[]models.Device, pn string) error
+	GetDevicesByServiceId(d *[]models.Device, sid string) error
+	GetDevicesByServiceName(d *[]models.Device, sn string) error
+	GetDevicesByAddressableId(d *[]models.Device, aid string) error
+	GetDevicesByAddressableName(d *[]models.Device, an string) error
+	GetDevicesWithLabel(d *[]models.Device, l []string) error
+	AddDevice(d *models.Device) error
+
+	/* -----------------------------Device Profile -----------------------------*/
+	UpdateDeviceProfile(dp *models.DeviceProfile) error
+	// Add the profile and its commands
+	AddDeviceProfile(dp *models.DeviceProfile) error
+	GetAllDeviceProfiles(dp *[]models.DeviceProfile) error
+	GetDeviceProfileById(dp *models.DeviceProfile, id string) error
+	GetDeviceProfilesByModel(dp *[]models.DeviceProfile, m string) error
+	GetDeviceProfilesWithLabel(dp *[]models.DeviceProfile, l []string) error
+	GetDeviceProfilesByManufacturerModel(dp *[]models.DeviceProfile, man string, mod string) error
+	GetDeviceProfilesByManufacturer(dp *[]models.DeviceProfile, man string) error
+	GetDeviceProfileByName(dp *models.DeviceProfile, n string) error
+	GetDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Command) error
+
+	/* ------------------------Device Profile Revision -------------------------*/
+	// Add the revision, ErrDuplicateName when the profile already has a revision with its number
+	AddDeviceProfileRevision(dpr *models.DeviceProfileRevision) error
+	// Revisions of the profile ordered by number
+	GetDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error
+	GetDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error
+	// Delete the revisions of a deleted profile
+	DeleteDeviceProfileRevisions(pid string) error
+	// Delete the revisions of the profile from a number on, for the changes that are undone
+	DeleteDeviceProfileRevisionsFrom(pid string, revision int) error
+
+	/* -----------------------------------Addressable --------------------------*/
+	UpdateAddressable(a *models.Addressable) error
+	AddAddressable(a *models.Addressable) error
+	GetAddressableById(a *models.Addressable, id string) error
+	GetAddressableByName(a *models.Addressable, n string) error
+	GetAllAddressables(a *[]models.Addressable) error
+	GetAddressablesByTopic(a *[]models.Addressable, t string) error
+	GetAddressablesByPort(a *[]models.Addressable, p int) error
+	GetAddressablesByPublisher(a *[]models.Addressable, p string) error
+	GetAddressablesByAddress(a *[]models.Addressable, add string) error
+	IsAddressableAssociatedToDevice(a models.Addressable) (bool, error)
+	IsAddressableAssociatedToDeviceService(a models.Addressable) (bool, error)
+
+	/* ----------------------------- Device Service ----------------------------------*/
+	UpdateDeviceService(ds models.DeviceService) error
+	GetDeviceServicesByAddressableName(d *[]models.DeviceService, n string) error
+	GetDeviceServicesByAddressableId(d *[]models.DeviceService, id string) error
+	GetDeviceServicesWithLabel(d *[]models.DeviceService, l []string) error
+	GetDeviceServiceById(d *models.DeviceService, id string) error
+	GetDeviceServiceByName(d *models.DeviceService, n string) error
+	GetAllDeviceServices(d *[]models.DeviceService) error
+	AddDeviceService(ds *models.DeviceService) error
+
+	/* ----------------------Provision Watcher -----------------------------*/
+	GetProvisionWatcherById(pw *models.ProvisionWatcher, id string) error
+	GetAllProvisionWatchers(pw *[]models.ProvisionWatcher) error
+	GetProvisionWatcherByName(pw *models.ProvisionWatcher, n string) error
+	GetProvisionWatchersByProfileId(pw *[]models.ProvisionWatcher, id string) error
+	GetProvisionWatchersByProfileName(pw *[]models.ProvisionWatcher, n string) error
+	GetProvisionWatchersByServiceId(pw *[]models.ProvisionWatcher, id string) error
+	GetProvisionWatchersByServiceName(pw *[]models.ProvisionWatcher, n string) error
+	GetProvisionWatchersByIdentifier(pw *[]models.ProvisionWatcher, k string, v string) error
+	// Add the watcher, its service and profile are already resolved
+	AddProvisionWatcher(pw *models.ProvisionWatcher) error
+	UpdateProvisionWatcher(pw models.ProvisionWatcher) error
+
+	/* ------------------------Command -------------------------------------*/
+	GetCommandById(c *models.Command, id string) error
+	GetCommandByName(c *[]models.Command, n string) error
+	AddCommand(c *models.Command) error
+	GetAllCommands(c *[]models.Command) error
+	UpdateCommand(c *models.Command) error
+	// Delete the command, ErrCommandStillInUse while a device profile uses it
+	DeleteCommandById(id string) error
+
+	/* ------------------------ Collection --------------------------------*/
+	// Delete by ID or name from the collection (DEVICECOL, DPCOL...)
+	DeleteById(collection string, id string) error
+	DeleteByName(collection string, n string) error
+	// Set a field (ADMINSTATE, OPERATINGSTATE, LASTCONNECTED or LASTREPORTED) and the modified time
+	SetById(collection string, id string, field string, value string) error
+	SetByIdInt(collection string, id string, field string, value int64) error
+	SetByName(collection string, n string, field string, value string) error
+	SetByNameInt(collection string, n string, field string, value int64) error
+}
+
+// Repository of the configured database type
+func newRepository(database enums.DATABASE) (Repository, error) {
+	switch database {
+	case enums.MONGODB:
+		return mongoRepository{}, nil
+	case enums.MEMORY:
+		return newMemoryRepository(), nil
+	case enums.SQLITE:
+		// sql_sqlite.go needs cgo, the default build of core-metadata has none
+		if _, ok := sqlDialects[enums.SQLITE]; !ok {
+			return nil, errors.New("Database type sqlite requires a cgo build of core-metadata (CGO_ENABLED=1)")
+		}
+		return newSqlRepository(database, configuration.SQLDataSource)
+	case enums.MYSQL:
+		return newSqlRepository(database, configuration.SQLDataSource)
+	default:
+		return nil, errors.New("Unsupported database type: " + database.String())
+	}
+}
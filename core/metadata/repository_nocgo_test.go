@@ -0,0 +1,35 @@
+//go:build !cgo
+// +build !cgo
+
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+)
+
+// The SQLite tests of sqlOps_test.go need cgo, without it the database type is refused
+func TestSqliteRequiresCgo(t *testing.T) {
+	if _, err := newRepository(enums.SQLITE); err == nil || !strings.Contains(err.Error(), "cgo") {
+		t.Errorf("Expected the sqlite repository to require cgo, got %v", err)
+	}
+}
@@ -26,13 +26,11 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
-	"gopkg.in/mgo.v2/bson"
 )
 
 func restGetAllAddressables(w http.ResponseWriter, _ *http.Request) {
 	results := make([]models.Addressable, 0)
-	err := getAddressables(&results, bson.M{})
+	err := getAllAddressables(&results)
 	if err != nil {
 		loggingClient.Error(err.Error(), "")
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -97,7 +95,7 @@ func restUpdateAddressable(w http.ResponseWriter, r *http.Request) {
 			err = getAddressableByName(&res, ra.Name)
 		}
 		if err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, err.Error(), http.StatusNotFound)
 			} else {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -125,7 +123,7 @@ func restUpdateAddressable(w http.ResponseWriter, r *http.Request) {
 
 	if err := updateAddressable(&ra, &res); err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -144,7 +142,7 @@ func restGetAddressableById(w http.ResponseWriter, r *http.Request) {
 	var id string = vars["id"]
 	var result models.Addressable
 	if err := getAddressableById(&result, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			loggingClient.Error(err.Error(), "")
@@ -184,7 +182,7 @@ func restDeleteAddressableById(w http.ResponseWriter, r *http.Request) {
 
 	err = deleteById(ADDCOL, id)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			loggingClient.Error(err.Error(), "")
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -234,7 +232,7 @@ func restDeleteAddressableByName(w http.ResponseWriter, r *http.Request) {
 
 	if err := deleteByName(ADDCOL, n); err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -284,7 +282,7 @@ func restGetAddressableByName(w http.ResponseWriter, r *http.Request) {
 	var result models.Addressable
 	if err := getAddressableByName(&result, dn); err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -309,7 +307,7 @@ func restGetAddressableByTopic(w http.ResponseWriter, r *http.Request) {
 	err = getAddressablesByTopic(&res, t)
 	if err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -0,0 +1,986 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+)
+
+// Metadata of a site, imported and exported as a whole
+// The resources reference each other by name, whether they are in the bundle or already stored
+type bundle struct {
+	Addressables   []models.Addressable   `json:"addressables,omitempty"`
+	DeviceServices []models.DeviceService `json:"deviceServices,omitempty"`
+	DeviceProfiles []models.DeviceProfile `json:"deviceProfiles,omitempty"`
+	Devices        []models.Device        `json:"devices,omitempty"`
+	Schedules      []models.Schedule      `json:"schedules,omitempty"`
+	ScheduleEvents []models.ScheduleEvent `json:"scheduleEvents,omitempty"`
+}
+
+// What the import did, or would do for a dry run, with a resource of the bundle
+type bundleAction struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// Result of a bundle import, the actions are in the order they were applied
+type bundleResult struct {
+	DryRun     bool           `json:"dryRun"`
+	Actions    []bundleAction `json:"actions"`
+	Error      string         `json:"error,omitempty"`
+	RolledBack bool           `json:"rolledBack,omitempty"`
+}
+
+// Actions of a bundle import
+const (
+	bundleCreate    = "create"
+	bundleUpdate    = "update"
+	bundleUnchanged = "unchanged"
+)
+
+/* ------ Import ------*/
+
+// Apply a bundle, the resources are created or updated by name in dependency order:
+// addressables, device services, device profiles, devices, schedules and schedule events
+// Resources which are already stored as in the bundle are left alone, so a bundle can be applied again
+// With dryrun=true nothing is changed, the result has the actions the import would take
+// When a change fails the changes made so far are undone
+func restImportBundle(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	b, err := decodeBundle(body, r.Header.Get("Content-Type"))
+	if err != nil {
+		loggingClient.Error("Problem decoding bundle: "+err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	issues, err := validateBundle(b)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if len(issues) > 0 {
+		err = errors.New("Invalid bundle: " + strings.Join(issues, "; "))
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bi := bundleImport{
+		dryRun: r.URL.Query().Get(DRYRUN) == "true",
+		author: requestAuthor(r),
+	}
+	bi.result = bundleResult{DryRun: bi.dryRun, Actions: []bundleAction{}}
+	status := http.StatusOK
+	if err := bi.apply(b); err != nil {
+		loggingClient.Error(err.Error(), "")
+		bi.result.Error = err.Error()
+		bi.result.RolledBack = bi.rollback()
+		status = http.StatusServiceUnavailable
+	} else {
+		for _, cleanup := range bi.cleanup {
+			if err := cleanup(); err != nil {
+				loggingClient.Error("Problem deleting replaced resources: "+err.Error(), "")
+			}
+		}
+		for _, notify := range bi.notify {
+			if err := notify(); err != nil {
+				loggingClient.Error("Problem notifying associated device services: "+err.Error(), "")
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(bi.result)
+}
+
+// Decode a bundle, it's YAML when the content type says so and JSON otherwise
+// YAML bundles use the JSON names of the fields
+func decodeBundle(body []byte, contentType string) (bundle, error) {
+	var b bundle
+	if strings.Contains(contentType, "yaml") {
+		var v interface{}
+		if err := yaml.Unmarshal(body, &v); err != nil {
+			return b, err
+		}
+		data, err := json.Marshal(jsonCompatible(v))
+		if err != nil {
+			return b, err
+		}
+		body = data
+	}
+	err := json.Unmarshal(body, &b)
+	return b, err
+}
+
+// YAML maps can have keys of any type, JSON objects only have string keys
+func jsonCompatible(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range t {
+			t[i] = jsonCompatible(e)
+		}
+	}
+	return v
+}
+
+// Problems which would stop the bundle from being applied: missing or duplicate names, references to
+// resources which are neither in the bundle nor stored, and the checks made when the resources
+// are added one by one
+// The error is set when the stored resources can't be read
+func validateBundle(b bundle) ([]string, error) {
+	issues := []string{}
+	issue := func(format string, args ...interface{}) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	var names []string
+	for _, a := range b.Addressables {
+		names = append(names, a.Name)
+	}
+	addressables := bundleNames(ADDRESSABLE, names, issue)
+	names = nil
+	for _, ds := range b.DeviceServices {
+		names = append(names, ds.Service.Name)
+	}
+	services := bundleNames(DEVICESERVICE, names, issue)
+	names = nil
+	for _, dp := range b.DeviceProfiles {
+		names = append(names, dp.Name)
+	}
+	profiles := bundleNames(DEVICEPROFILE, names, issue)
+	names = nil
+	for _, d := range b.Devices {
+		names = append(names, d.Name)
+	}
+	bundleNames(DEVICE, names, issue)
+	names = nil
+	for _, s := range b.Schedules {
+		names = append(names, s.Name)
+	}
+	schedules := bundleNames(SCHEDULE, names, issue)
+	names = nil
+	for _, se := range b.ScheduleEvents {
+		names = append(names, se.Name)
+	}
+	bundleNames(SCHEDULEEVENT, names, issue)
+
+	findAddressable := func(n string) error {
+		var a models.Addressable
+		return getAddressableByName(&a, n)
+	}
+	findService := func(n string) error {
+		var ds models.DeviceService
+		return getDeviceServiceByName(&ds, n)
+	}
+	findProfile := func(n string) error {
+		var dp models.DeviceProfile
+		return getDeviceProfileByName(&dp, n)
+	}
+	findSchedule := func(n string) error {
+		var s models.Schedule
+		return getScheduleByName(&s, n)
+	}
+
+	for _, ds := range b.DeviceServices {
+		from := DEVICESERVICE + " " + ds.Service.Name
+		if err := bundleReference(from, ADDRESSABLE, ds.Service.Addressable.Name, addressables, findAddressable, issue); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dp := range b.DeviceProfiles {
+		from := DEVICEPROFILE + " " + dp.Name
+		if l := lintDeviceProfile(dp); !l.Valid && configuration.StrictProfileValidation {
+			for _, i := range l.Errors {
+				issue("%s: %s: %s", from, i.Field, i.Message)
+			}
+		}
+		commands := map[string]bool{}
+		for _, c := range dp.Commands {
+			if commands[c.Name] {
+				issue("%s: duplicate command name %s", from, c.Name)
+			}
+			commands[c.Name] = true
+		}
+	}
+
+	for _, d := range b.Devices {
+		from := DEVICE + " " + d.Name
+		if err := bundleReference(from, ADDRESSABLE, d.Addressable.Name, addressables, findAddressable, issue); err != nil {
+			return nil, err
+		}
+		if err := bundleReference(from, DEVICESERVICE, d.Service.Service.Name, services, findService, issue); err != nil {
+			return nil, err
+		}
+		if err := bundleReference(from, DEVICEPROFILE, d.Profile.Name, profiles, findProfile, issue); err != nil {
+			return nil, err
+		}
+		if d.OperatingState == models.OperatingState("") || d.AdminState == models.AdminState("") {
+			issue("%s: operating state and admin state are required", from)
+		}
+		if err := models.ValidateLocation(d.Location); err != nil {
+			issue("%s: invalid location: %s", from, err.Error())
+		}
+	}
+
+	for _, s := range b.Schedules {
+		from := SCHEDULE + " " + s.Name
+		if s.Start != "" {
+			if _, err := msToTime(s.Start); err != nil {
+				issue("%s: incorrect start time format: %s", from, err.Error())
+			}
+		}
+		if s.End != "" {
+			if _, err := msToTime(s.End); err != nil {
+				issue("%s: incorrect end time format: %s", from, err.Error())
+			}
+		}
+		if s.Frequency != "" && !isIntervalValid(s.Frequency) {
+			issue("%s: frequency format incorrect: %s", from, s.Frequency)
+		}
+	}
+
+	for _, se := range b.ScheduleEvents {
+		from := SCHEDULEEVENT + " " + se.Name
+		if err := bundleReference(from, SCHEDULE, se.Schedule, schedules, findSchedule, issue); err != nil {
+			return nil, err
+		}
+		if err := bundleReference(from, ADDRESSABLE, se.Addressable.Name, addressables, findAddressable, issue); err != nil {
+			return nil, err
+		}
+	}
+
+	return issues, nil
+}
+
+// The names of the resources of a kind in the bundle, which are required and unique
+func bundleNames(kind string, names []string, issue func(string, ...interface{})) map[string]bool {
+	set := map[string]bool{}
+	for i, n := range names {
+		switch {
+		case n == "":
+			issue("%s %d: name is required", kind, i)
+		case set[n]:
+			issue("%s %s: duplicate name", kind, n)
+		}
+		set[n] = true
+	}
+	return set
+}
+
+// Check a reference by name, to a resource of the bundle or to a stored resource
+func bundleReference(from string, kind string, name string, names map[string]bool, find func(string) error, issue func(string, ...interface{})) error {
+	if name == "" {
+		issue("%s: %s name is required", from, kind)
+		return nil
+	}
+	if names[name] {
+		return nil
+	}
+	switch err := find(name); err {
+	case nil:
+	case ErrNotFound:
+		issue("%s: unknown %s %s", from, kind, name)
+	default:
+		return err
+	}
+	return nil
+}
+
+// State of a bundle import
+// The changes made so far are undone in reverse order when the import fails,
+// the replaced resources are only deleted and the device services notified once the whole bundle is applied
+type bundleImport struct {
+	dryRun  bool
+	author  string
+	result  bundleResult
+	undo    []func() error
+	cleanup []func() error
+	notify  []func() error
+}
+
+func (bi *bundleImport) apply(b bundle) error {
+	for _, a := range b.Addressables {
+		if err := bi.importAddressable(a); err != nil {
+			return bundleError(ADDRESSABLE, a.Name, err)
+		}
+	}
+	for _, ds := range b.DeviceServices {
+		if err := bi.importDeviceService(ds); err != nil {
+			return bundleError(DEVICESERVICE, ds.Service.Name, err)
+		}
+	}
+	for _, dp := range b.DeviceProfiles {
+		if err := bi.importDeviceProfile(dp); err != nil {
+			return bundleError(DEVICEPROFILE, dp.Name, err)
+		}
+	}
+	for _, d := range b.Devices {
+		if err := bi.importDevice(d); err != nil {
+			return bundleError(DEVICE, d.Name, err)
+		}
+	}
+	for _, s := range b.Schedules {
+		if err := bi.importSchedule(s); err != nil {
+			return bundleError(SCHEDULE, s.Name, err)
+		}
+	}
+	for _, se := range b.ScheduleEvents {
+		if err := bi.importScheduleEvent(se); err != nil {
+			return bundleError(SCHEDULEEVENT, se.Name, err)
+		}
+	}
+	return nil
+}
+
+func bundleError(kind string, name string, err error) error {
+	return errors.New("Problem importing " + kind + " " + name + ": " + err.Error())
+}
+
+// Undo the changes, the result tells whether all of them were undone
+func (bi *bundleImport) rollback() bool {
+	rolledBack := true
+	for i := len(bi.undo) - 1; i >= 0; i-- {
+		if err := bi.undo[i](); err != nil {
+			loggingClient.Error("Problem rolling back bundle: "+err.Error(), "")
+			rolledBack = false
+		}
+	}
+	bi.undo = nil
+	return rolledBack
+}
+
+func (bi *bundleImport) record(kind string, name string, action string) {
+	bi.result.Actions = append(bi.result.Actions, bundleAction{Kind: kind, Name: name, Action: action})
+}
+
+// The action importing a resource takes, from the lookup of the stored resource by name and the
+// canonical forms of the stored and the imported resource
+func bundleActionFor(lookup error, stored interface{}, imported interface{}) (string, error) {
+	switch lookup {
+	case nil:
+	case ErrNotFound:
+		return bundleCreate, nil
+	default:
+		return "", lookup
+	}
+
+	s, err := jsonValue(stored)
+	if err != nil {
+		return "", err
+	}
+	i, err := jsonValue(imported)
+	if err != nil {
+		return "", err
+	}
+	changes := []models.ProfileChange{}
+	diffValues("", s, i, &changes)
+	if len(changes) == 0 {
+		return bundleUnchanged, nil
+	}
+	return bundleUpdate, nil
+}
+
+func (bi *bundleImport) importAddressable(a models.Addressable) error {
+	var stored models.Addressable
+	err := getAddressableByName(&stored, a.Name)
+	action, err := bundleActionFor(err, bundleAddressable(stored), bundleAddressable(a))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun {
+		switch action {
+		case bundleCreate:
+			if err := addAddressable(&a); err != nil {
+				return err
+			}
+			id := a.Id.Hex()
+			bi.undo = append(bi.undo, func() error { return deleteById(ADDCOL, id) })
+		case bundleUpdate:
+			a.Id = stored.Id
+			a.BaseObject = stored.BaseObject
+			a.Modified = makeTimestamp()
+			if err := replaceAddressable(&a); err != nil {
+				return err
+			}
+			bi.undo = append(bi.undo, func() error { return replaceAddressable(&stored) })
+			bi.notify = append(bi.notify, func() error { return notifyAddressableAssociates(a, http.MethodPut) })
+		}
+	}
+	bi.record(ADDRESSABLE, a.Name, action)
+	return nil
+}
+
+func (bi *bundleImport) importDeviceService(ds models.DeviceService) error {
+	var stored models.DeviceService
+	err := getDeviceServiceByName(&stored, ds.Service.Name)
+	action, err := bundleActionFor(err, bundleDeviceService(stored), bundleDeviceService(ds))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun && action != bundleUnchanged {
+		if err := getAddressableByName(&ds.Service.Addressable, ds.Service.Addressable.Name); err != nil {
+			return err
+		}
+		switch action {
+		case bundleCreate:
+			if err := addDeviceService(&ds); err != nil {
+				return err
+			}
+			id := ds.Service.Id.Hex()
+			bi.undo = append(bi.undo, func() error { return deleteById(DSCOL, id) })
+		case bundleUpdate:
+			ds.Service.Id = stored.Service.Id
+			ds.Service.BaseObject = stored.Service.BaseObject
+			ds.Service.LastConnected = stored.Service.LastConnected
+			ds.Service.LastReported = stored.Service.LastReported
+			if err := updateDeviceService(ds); err != nil {
+				return err
+			}
+			bi.undo = append(bi.undo, func() error { return updateDeviceService(stored) })
+		}
+	}
+	bi.record(DEVICESERVICE, ds.Service.Name, action)
+	return nil
+}
+
+// Profiles are stored with new commands, the changes are recorded as revisions
+func (bi *bundleImport) importDeviceProfile(dp models.DeviceProfile) error {
+	var stored models.DeviceProfile
+	err := getDeviceProfileByName(&stored, dp.Name)
+	action, err := bundleActionFor(err, profileDefinition(stored), profileDefinition(dp))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun {
+		switch action {
+		case bundleCreate:
+			dp = profileDefinition(dp)
+			if err := addDeviceProfile(&dp); err != nil {
+				return err
+			}
+			added := dp
+			bi.undo = append(bi.undo, func() error {
+				if err := deleteDeviceProfileRevisions(added.Id.Hex()); err != nil {
+					return err
+				}
+				if err := deleteDeviceProfileById(added.Id.Hex()); err != nil {
+					return err
+				}
+				return deleteCommands(added, models.DeviceProfile{})
+			})
+			if _, err := recordProfileRevision(dp, nil, revisionCreate, bi.author); err != nil {
+				return err
+			}
+		case bundleUpdate:
+			// The replaced commands are kept until the whole bundle is applied,
+			// the undo restores the stored profile and drops the revisions of the import
+			first, err := nextProfileRevision(stored.Id)
+			if err != nil {
+				return err
+			}
+			if err := defineDeviceProfile(&dp, stored); err != nil {
+				return err
+			}
+			updated := dp
+			bi.undo = append(bi.undo, func() error {
+				restored := stored
+				if err := updateDeviceProfile(&restored); err != nil {
+					return err
+				}
+				if err := deleteCommands(updated, stored); err != nil {
+					return err
+				}
+				return deleteDeviceProfileRevisionsFrom(stored.Id.Hex(), first)
+			})
+			if err := updateDeviceProfile(&dp); err != nil {
+				return err
+			}
+			if _, err := recordProfileRevision(dp, &stored, revisionUpdate, bi.author); err != nil {
+				return err
+			}
+			bi.cleanup = append(bi.cleanup, func() error { return deleteCommands(stored, updated) })
+			bi.notify = append(bi.notify, func() error { return notifyProfileAssociates(updated, http.MethodPut) })
+		}
+	}
+	bi.record(DEVICEPROFILE, dp.Name, action)
+	return nil
+}
+
+func (bi *bundleImport) importDevice(d models.Device) error {
+	var stored models.Device
+	err := getDeviceByName(&stored, d.Name)
+	action, err := bundleActionFor(err, bundleDevice(stored), bundleDevice(d))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun && action != bundleUnchanged {
+		if err := getAddressableByName(&d.Addressable, d.Addressable.Name); err != nil {
+			return err
+		}
+		if err := getDeviceServiceByName(&d.Service, d.Service.Service.Name); err != nil {
+			return err
+		}
+		if err := getDeviceProfileByName(&d.Profile, d.Profile.Name); err != nil {
+			return err
+		}
+		if err := checkProfileRevision(d.Profile.Id, d.ProfileRevision); err != nil {
+			return err
+		}
+		switch action {
+		case bundleCreate:
+			if err := addDevice(&d); err != nil {
+				return err
+			}
+			id := d.Id.Hex()
+			bi.undo = append(bi.undo, func() error { return deleteById(DEVICECOL, id) })
+			bi.notify = append(bi.notify, func() error { return notifyDeviceAssociates(d, http.MethodPost) })
+		case bundleUpdate:
+			d.Id = stored.Id
+			d.BaseObject = stored.BaseObject
+			d.Modified = makeTimestamp()
+			d.LastConnected = stored.LastConnected
+			d.LastReported = stored.LastReported
+			if err := UpdateDevice(d); err != nil {
+				return err
+			}
+			bi.undo = append(bi.undo, func() error { return UpdateDevice(stored) })
+			bi.notify = append(bi.notify, func() error { return notifyDeviceAssociates(d, http.MethodPut) })
+		}
+	}
+	bi.record(DEVICE, d.Name, action)
+	return nil
+}
+
+func (bi *bundleImport) importSchedule(s models.Schedule) error {
+	var stored models.Schedule
+	err := getScheduleByName(&stored, s.Name)
+	action, err := bundleActionFor(err, bundleSchedule(stored), bundleSchedule(s))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun {
+		switch action {
+		case bundleCreate:
+			if err := addSchedule(&s); err != nil {
+				return err
+			}
+			id := s.Id.Hex()
+			bi.undo = append(bi.undo, func() error { return deleteById(SCOL, id) })
+			bi.notify = append(bi.notify, func() error { return notifyScheduleAssociates(s, http.MethodPost) })
+		case bundleUpdate:
+			s.Id = stored.Id
+			s.BaseObject = stored.BaseObject
+			if err := updateSchedule(s); err != nil {
+				return err
+			}
+			bi.undo = append(bi.undo, func() error { return updateSchedule(stored) })
+			bi.notify = append(bi.notify, func() error { return notifyScheduleAssociates(s, http.MethodPut) })
+		}
+	}
+	bi.record(SCHEDULE, s.Name, action)
+	return nil
+}
+
+func (bi *bundleImport) importScheduleEvent(se models.ScheduleEvent) error {
+	var stored models.ScheduleEvent
+	err := getScheduleEventByName(&stored, se.Name)
+	action, err := bundleActionFor(err, bundleScheduleEvent(stored), bundleScheduleEvent(se))
+	if err != nil {
+		return err
+	}
+
+	if !bi.dryRun && action != bundleUnchanged {
+		if err := getAddressableByName(&se.Addressable, se.Addressable.Name); err != nil {
+			return err
+		}
+		switch action {
+		case bundleCreate:
+			if err := addScheduleEvent(&se); err != nil {
+				return err
+			}
+			id := se.Id.Hex()
+			bi.undo = append(bi.undo, func() error { return deleteById(SECOL, id) })
+			bi.notify = append(bi.notify, func() error { return notifyScheduleEventAssociates(se, http.MethodPost) })
+		case bundleUpdate:
+			se.Id = stored.Id
+			se.BaseObject = stored.BaseObject
+			if err := updateScheduleEvent(se); err != nil {
+				return err
+			}
+			bi.undo = append(bi.undo, func() error { return updateScheduleEvent(stored) })
+			bi.notify = append(bi.notify, func() error { return notifyScheduleEventAssociates(se, http.MethodPut) })
+		}
+	}
+	bi.record(SCHEDULEEVENT, se.Name, action)
+	return nil
+}
+
+/* ------ Canonical form ------*/
+// The resources as they are compared by the import, without the database IDs, the timestamps
+// and the state reported at runtime, referencing each other by name
+
+func bundleAddressable(a models.Addressable) models.Addressable {
+	a.Id = ""
+	a.BaseObject = models.BaseObject{}
+	return a
+}
+
+func bundleDeviceService(ds models.DeviceService) models.DeviceService {
+	ds.Service.Id = ""
+	ds.Service.BaseObject = models.BaseObject{}
+	ds.Service.LastConnected = 0
+	ds.Service.LastReported = 0
+	ds.Service.Addressable = models.Addressable{Name: ds.Service.Addressable.Name}
+	return ds
+}
+
+func bundleDevice(d models.Device) models.Device {
+	d.Id = ""
+	d.BaseObject = models.BaseObject{}
+	d.LastConnected = 0
+	d.LastReported = 0
+	d.Addressable = models.Addressable{Name: d.Addressable.Name}
+	d.Service = models.DeviceService{Service: models.Service{Name: d.Service.Service.Name}}
+	d.Profile = models.DeviceProfile{Name: d.Profile.Name}
+	return d
+}
+
+func bundleSchedule(s models.Schedule) models.Schedule {
+	s.Id = ""
+	s.BaseObject = models.BaseObject{}
+	return s
+}
+
+func bundleScheduleEvent(se models.ScheduleEvent) models.ScheduleEvent {
+	se.Id = ""
+	se.BaseObject = models.BaseObject{}
+	se.Addressable = models.Addressable{Name: se.Addressable.Name}
+	return se
+}
+
+/* ------ Export ------*/
+
+// Export all the metadata as a bundle
+func restExportBundle(w http.ResponseWriter, r *http.Request) {
+	var b bundle
+	err := getAllAddressables(&b.Addressables)
+	if err == nil {
+		err = getAllDeviceServices(&b.DeviceServices)
+	}
+	if err == nil {
+		err = getAllDeviceProfiles(&b.DeviceProfiles)
+	}
+	if err == nil {
+		err = getAllDevices(&b.Devices)
+	}
+	if err == nil {
+		err = getAllSchedules(&b.Schedules)
+	}
+	if err == nil {
+		err = getAllScheduleEvents(&b.ScheduleEvents)
+	}
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeBundle(b, w, r)
+}
+
+// Export the devices, device services and device profiles with the label as a bundle,
+// along with the resources they use: the profiles and services of the devices, the addressables,
+// and the schedule events of the services with their schedules
+func restExportBundleByLabel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	label, err := url.QueryUnescape(vars[LABEL])
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	labels := []string{label}
+	var devices []models.Device
+	var services []models.DeviceService
+	var profiles []models.DeviceProfile
+	err = getDevicesWithLabel(&devices, labels)
+	if err == nil {
+		err = getDeviceServicesWithLabel(&services, labels)
+	}
+	if err == nil {
+		err = getDeviceProfilesWithLabel(&profiles, labels)
+	}
+	s := bundleSelection{selected: map[string]bool{}}
+	if err == nil {
+		err = selectBundle(devices, services, profiles, &s)
+	}
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeBundle(s.bundle, w, r)
+}
+
+// Resources selected for an export, with the names already selected by kind
+type bundleSelection struct {
+	bundle
+	selected map[string]bool
+}
+
+// Whether the resource is already selected, it is from now on
+func (s *bundleSelection) has(kind string, name string) bool {
+	key := kind + "/" + name
+	if s.selected[key] {
+		return true
+	}
+	s.selected[key] = true
+	return false
+}
+
+func selectBundle(devices []models.Device, services []models.DeviceService, profiles []models.DeviceProfile, s *bundleSelection) error {
+	for _, dp := range profiles {
+		if !s.has(DEVICEPROFILE, dp.Name) {
+			s.DeviceProfiles = append(s.DeviceProfiles, dp)
+		}
+	}
+	for _, ds := range services {
+		if err := s.addDeviceService(ds); err != nil {
+			return err
+		}
+	}
+	for _, d := range devices {
+		if s.has(DEVICE, d.Name) {
+			continue
+		}
+		s.Devices = append(s.Devices, d)
+		if err := s.addAddressable(d.Addressable.Name); err != nil {
+			return err
+		}
+		if err := s.addDeviceService(d.Service); err != nil {
+			return err
+		}
+		// The profile of the device may be pinned to a revision, the current profile is exported
+		if !s.has(DEVICEPROFILE, d.Profile.Name) {
+			var dp models.DeviceProfile
+			if err := getDeviceProfileByName(&dp, d.Profile.Name); err != nil {
+				return err
+			}
+			s.DeviceProfiles = append(s.DeviceProfiles, dp)
+		}
+	}
+	return nil
+}
+
+func (s *bundleSelection) addAddressable(name string) error {
+	if name == "" || s.has(ADDRESSABLE, name) {
+		return nil
+	}
+	var a models.Addressable
+	if err := getAddressableByName(&a, name); err != nil {
+		return err
+	}
+	s.Addressables = append(s.Addressables, a)
+	return nil
+}
+
+func (s *bundleSelection) addDeviceService(ds models.DeviceService) error {
+	if s.has(DEVICESERVICE, ds.Service.Name) {
+		return nil
+	}
+	s.DeviceServices = append(s.DeviceServices, ds)
+	if err := s.addAddressable(ds.Service.Addressable.Name); err != nil {
+		return err
+	}
+
+	var events []models.ScheduleEvent
+	if err := getScheduleEventsByServiceName(&events, ds.Service.Name); err != nil {
+		return err
+	}
+	for _, se := range events {
+		if s.has(SCHEDULEEVENT, se.Name) {
+			continue
+		}
+		s.ScheduleEvents = append(s.ScheduleEvents, se)
+		if err := s.addAddressable(se.Addressable.Name); err != nil {
+			return err
+		}
+		if err := s.addSchedule(se.Schedule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *bundleSelection) addSchedule(name string) error {
+	if name == "" || s.has(SCHEDULE, name) {
+		return nil
+	}
+	var sc models.Schedule
+	if err := getScheduleByName(&sc, name); err != nil {
+		return err
+	}
+	s.Schedules = append(s.Schedules, sc)
+	return nil
+}
+
+// Write the bundle in its canonical form, YAML when the request accepts it and JSON otherwise
+func writeBundle(b bundle, w http.ResponseWriter, r *http.Request) {
+	for _, n := range []int{len(b.Addressables), len(b.DeviceServices), len(b.DeviceProfiles), len(b.Devices), len(b.Schedules), len(b.ScheduleEvents)} {
+		if n > configuration.ReadMaxLimit {
+			err := errors.New("Max limit exceeded")
+			loggingClient.Error(err.Error(), "")
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+	}
+
+	v, err := bundleValue(exportedBundle(b))
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if !strings.Contains(r.Header.Get("Accept"), "yaml") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+		return
+	}
+
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+}
+
+// The bundle in the canonical form of its resources
+// The references to device services keep their states, which are required to decode a device
+func exportedBundle(b bundle) bundle {
+	var e bundle
+	for _, a := range b.Addressables {
+		e.Addressables = append(e.Addressables, bundleAddressable(a))
+	}
+	for _, ds := range b.DeviceServices {
+		e.DeviceServices = append(e.DeviceServices, bundleDeviceService(ds))
+	}
+	for _, dp := range b.DeviceProfiles {
+		e.DeviceProfiles = append(e.DeviceProfiles, profileDefinition(dp))
+	}
+	for _, d := range b.Devices {
+		ed := bundleDevice(d)
+		ed.Service.AdminState = d.Service.AdminState
+		ed.Service.OperatingState = d.Service.OperatingState
+		e.Devices = append(e.Devices, ed)
+	}
+	for _, s := range b.Schedules {
+		e.Schedules = append(e.Schedules, bundleSchedule(s))
+	}
+	for _, se := range b.ScheduleEvents {
+		e.ScheduleEvents = append(e.ScheduleEvents, bundleScheduleEvent(se))
+	}
+	return e
+}
+
+// Fields left out of an exported bundle when they are zero
+var bundleZeroFields = map[string]bool{"created": true, "modified": true, "origin": true, "lastConnected": true, "lastReported": true, "port": true}
+
+// The bundle as generic values with the JSON names of the fields, without the empty values and
+// the zero timestamps so that it can be edited by hand
+// The numbers are kept as integers when they are, not as floats
+func bundleValue(b bundle) (interface{}, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return compactValue(v), nil
+}
+
+func compactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			e = compactValue(e)
+			switch {
+			case isEmptyValue(e) || e == "":
+				delete(t, k)
+			case bundleZeroFields[k] && e == int64(0):
+				delete(t, k)
+			default:
+				t[k] = e
+			}
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = compactValue(e)
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
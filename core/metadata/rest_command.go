@@ -25,7 +25,6 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
 )
 
 func restGetAllCommands(w http.ResponseWriter, _ *http.Request) {
@@ -189,7 +188,7 @@ func restDeleteCommandById(w http.ResponseWriter, r *http.Request) {
 
 	if err := deleteCommandById(id); err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else if err == ErrCommandStillInUse {
 			http.Error(w, err.Error(), http.StatusConflict)
@@ -28,7 +28,6 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
 )
 
 func restGetAllDevices(w http.ResponseWriter, _ *http.Request) {
@@ -105,6 +104,11 @@ func restAddNewDevice(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if err = checkProfileRevision(d.Profile.Id, d.ProfileRevision); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 
 	// Check operating/admin state
 	if d.OperatingState == models.OperatingState("") || d.AdminState == models.AdminState("") {
@@ -114,6 +118,13 @@ func restAddNewDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the location if it is GeoJSON
+	if err = models.ValidateLocation(d.Location); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, "Invalid device location: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Add the device
 	err = addDevice(&d)
 	if err != nil {
@@ -146,6 +157,13 @@ func restUpdateDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the location if it is GeoJSON
+	if err = models.ValidateLocation(rd.Location); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, "Invalid device location: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Check if the device exists
 	var oldDevice models.Device
 	// First try ID
@@ -224,8 +242,18 @@ func updateDeviceFields(from models.Device, to *models.Device) error {
 			}
 		}
 
+		// The pinned revision belongs to the former profile
+		if dp.Id != to.Profile.Id {
+			to.ProfileRevision = 0
+		}
 		to.Profile = dp
 	}
+	if from.ProfileRevision != 0 {
+		to.ProfileRevision = from.ProfileRevision
+		if err := checkProfileRevision(to.Profile.Id, to.ProfileRevision); err != nil {
+			return err
+		}
+	}
 	if from.AdminState != "" {
 		to.AdminState = from.AdminState
 	}
@@ -258,14 +286,14 @@ func updateDeviceFields(from models.Device, to *models.Device) error {
 		err := getDeviceByName(&checkD, from.Name)
 		if err != nil {
 			// A problem occured accessing database
-			if err != mgo.ErrNotFound {
+			if err != ErrNotFound {
 				loggingClient.Error(err.Error(), "")
 				return err
 			}
 		}
 
 		// Found a device, make sure its the one we're trying to update
-		if err != mgo.ErrNotFound {
+		if err != ErrNotFound {
 			// Differnt IDs -> Name is not unique
 			if checkD.Id != to.Id {
 				err = errors.New("Duplicate name for Device")
@@ -310,7 +338,7 @@ func restGetDeviceByProfileId(w http.ResponseWriter, r *http.Request) {
 	var dp models.DeviceProfile
 	err := getDeviceProfileById(&dp, pid)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -340,7 +368,7 @@ func restGetDeviceByServiceId(w http.ResponseWriter, r *http.Request) {
 	var ds models.DeviceService
 	err := getDeviceServiceById(&ds, sid)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -374,7 +402,7 @@ func restGetDeviceByServiceName(w http.ResponseWriter, r *http.Request) {
 	var ds models.DeviceService
 	err = getDeviceServiceByName(&ds, sn)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -410,7 +438,7 @@ func restGetDeviceByAddressableName(w http.ResponseWriter, r *http.Request) {
 	var a models.Addressable
 	err = getAddressableByName(&a, an)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -446,7 +474,7 @@ func restGetDeviceByProfileName(w http.ResponseWriter, r *http.Request) {
 	var dp models.DeviceProfile
 	err = getDeviceProfileByName(&dp, pn)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -477,7 +505,7 @@ func restGetDeviceByAddressableId(w http.ResponseWriter, r *http.Request) {
 	var a models.Addressable
 	err := getAddressableById(&a, aid)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -504,7 +532,7 @@ func restGetDeviceById(w http.ResponseWriter, r *http.Request) {
 	var res models.Device
 	if err := getDeviceById(&res, did); err != nil {
 		loggingClient.Error(err.Error(), "")
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -531,7 +559,7 @@ func restSetDeviceOpStateById(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err := getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -577,7 +605,7 @@ func restSetDeviceOpStateByName(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -626,7 +654,7 @@ func restSetDeviceAdminStateById(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err := getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -667,7 +695,7 @@ func restSetDeviceAdminStateByName(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -701,6 +729,92 @@ func setAdminState(d models.Device, as string, w http.ResponseWriter) error {
 	return nil
 }
 
+func restSetDeviceProfileRevisionById(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var did string = vars[ID]
+
+	// Check if the device exists
+	var d models.Device
+	err := getDeviceById(&d, did)
+	if err != nil {
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	// Pin the profile revision
+	if err = setProfileRevision(d, vars[REVISION], w); err != nil {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("true"))
+}
+
+func restSetDeviceProfileRevisionByName(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	n, err := url.QueryUnescape(vars[NAME])
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	// Check if the device exists
+	var d models.Device
+	err = getDeviceByName(&d, n)
+	if err != nil {
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	// Pin the profile revision
+	if err = setProfileRevision(d, vars[REVISION], w); err != nil {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("true"))
+}
+
+// Pin the device to a revision of its profile, 0 follows the latest revision
+// 409 conflict if the profile has no such revision
+func setProfileRevision(d models.Device, revision string, w http.ResponseWriter) error {
+	n, err := strconv.Atoi(revision)
+	if err != nil || n < 0 {
+		err = errors.New("Invalid profile revision " + revision)
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+	if err = checkProfileRevision(d.Profile.Id, n); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusConflict)
+		return err
+	}
+
+	d.ProfileRevision = n
+	if err = UpdateDevice(d); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return err
+	}
+
+	// Notify
+	notifyDeviceAssociates(d, http.MethodPut)
+
+	return nil
+}
+
 func restDeleteDeviceById(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	var did string = vars[ID]
@@ -709,7 +823,7 @@ func restDeleteDeviceById(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	//err := getDeviceById(&d, did)
 	if err := getDeviceById(&d, did); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -809,7 +923,7 @@ func restSetDeviceLastConnectedById(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -849,7 +963,7 @@ func restSetLastConnectedByIdNotify(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -887,7 +1001,7 @@ func restSetDeviceLastConnectedByName(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -931,7 +1045,7 @@ func restSetDeviceLastConnectedByNameNotify(w http.ResponseWriter, r *http.Reque
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -979,7 +1093,7 @@ func restSetDeviceLastReportedById(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -1018,7 +1132,7 @@ func restSetDeviceLastReportedByIdNotify(w http.ResponseWriter, r *http.Request)
 	var d models.Device
 	err = getDeviceById(&d, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -1056,7 +1170,7 @@ func restSetDeviceLastReportedByName(w http.ResponseWriter, r *http.Request) {
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -1100,7 +1214,7 @@ func restSetDeviceLastReportedByNameNotify(w http.ResponseWriter, r *http.Reques
 	var d models.Device
 	err = getDeviceByName(&d, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -1144,7 +1258,7 @@ func restGetDeviceByName(w http.ResponseWriter, r *http.Request) {
 	var res models.Device
 	err = getDeviceByName(&res, dn)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
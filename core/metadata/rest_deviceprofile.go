@@ -26,7 +26,6 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
 	"gopkg.in/yaml.v2"
 )
 
@@ -58,6 +57,11 @@ func restAddDeviceProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkProfileLint(dp, w); err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
 	// Check if there are duplicate names in the device profile command list
 	for _, c1 := range dp.Commands {
 		count := 0
@@ -83,6 +87,10 @@ func restAddDeviceProfile(w http.ResponseWriter, r *http.Request) {
 		loggingClient.Error(err.Error(), "")
 		return
 	}
+	// The profile is added, a missing first revision is recorded on its next change
+	if _, err := recordProfileRevision(dp, nil, revisionCreate, requestAuthor(r)); err != nil {
+		loggingClient.Error(err.Error(), "")
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(dp.Id.Hex()))
@@ -112,14 +120,20 @@ func restUpdateDeviceProfile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Lint the profile as it will be after the update, before anything is changed
+	if err := checkProfileLint(lintedProfileUpdate(from, to), w); err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
 	// Update the device profile fields based on the passed JSON
+	previous := to
 	if err := updateDeviceProfileFields(from, &to, w); err != nil {
 		loggingClient.Error(err.Error(), "")
 		return
 	}
-	if err := updateDeviceProfile(&to); err != nil {
+	if _, err := saveDeviceProfile(&to, previous, revisionUpdate, requestAuthor(r), w); err != nil {
 		loggingClient.Error(err.Error(), "")
-		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -168,15 +182,8 @@ func updateDeviceProfileFields(from models.DeviceProfile, to *models.DeviceProfi
 			return err
 		}
 
-		// taking lazy approach to commands - remove them all and add them
-		// all back in. TODO - someday make this a two phase commit so
-		// commands don't get wiped out before profile
-
-		// Delete the old commands
-		if err := deleteCommands(*to, w); err != nil {
-			return err
-		}
-
+		// The commands are replaced wholesale, the old ones are deleted
+		// once the profile doesn't reference them anymore (see saveDeviceProfile)
 		to.Commands = from.Commands
 
 		// Add the new commands
@@ -188,6 +195,23 @@ func updateDeviceProfileFields(from models.DeviceProfile, to *models.DeviceProfi
 	return nil
 }
 
+// The fields of the device profile checked by lintDeviceProfile, once the update is applied
+func lintedProfileUpdate(from models.DeviceProfile, to models.DeviceProfile) models.DeviceProfile {
+	if from.Name != "" {
+		to.Name = from.Name
+	}
+	if from.DeviceResources != nil {
+		to.DeviceResources = from.DeviceResources
+	}
+	if from.Resources != nil {
+		to.Resources = from.Resources
+	}
+	if from.Commands != nil {
+		to.Commands = from.Commands
+	}
+	return to
+}
+
 // Check for duplicate names in device profiles
 func checkDuplicateProfileNames(dp models.DeviceProfile, w http.ResponseWriter) error {
 	profiles := []models.DeviceProfile{}
@@ -227,12 +251,13 @@ func checkDuplicateCommands(dp models.DeviceProfile, w http.ResponseWriter) erro
 	return nil
 }
 
-// Delete all of the commands that are a part of the device profile
-func deleteCommands(dp models.DeviceProfile, w http.ResponseWriter) error {
-	for _, command := range dp.Commands {
-		err := deleteCommandById(command.Id.Hex())
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+// Delete the commands of the previous definition of the device profile which it doesn't use anymore
+func deleteCommands(previous models.DeviceProfile, dp models.DeviceProfile) error {
+	for _, command := range previous.Commands {
+		if hasCommand(dp, command.Id) {
+			continue
+		}
+		if err := deleteCommandById(command.Id.Hex()); err != nil {
 			return err
 		}
 	}
@@ -257,7 +282,7 @@ func restGetProfileByProfileId(w http.ResponseWriter, r *http.Request) {
 	var did string = vars["id"]
 	var res models.DeviceProfile
 	if err := getDeviceProfileById(&res, did); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -276,7 +301,7 @@ func restDeleteProfileByProfileId(w http.ResponseWriter, r *http.Request) {
 	// Check if the device profile exists
 	var dp models.DeviceProfile
 	if err := getDeviceProfileById(&dp, did); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -308,7 +333,7 @@ func restDeleteProfileByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the device profile exists
 	var dp models.DeviceProfile
 	if err = getDeviceProfileByName(&dp, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -360,6 +385,9 @@ func deleteDeviceProfile(dp models.DeviceProfile, w http.ResponseWriter) error {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return err
 	}
+	if err := deleteDeviceProfileRevisions(dp.Id.Hex()); err != nil {
+		loggingClient.Error(err.Error(), "")
+	}
 
 	// TODO: Notify Associates
 	if err := notifyProfileAssociates(dp, http.MethodDelete); err != nil {
@@ -391,7 +419,7 @@ func restAddProfileByYaml(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addDeviceProfileYaml(data, w)
+	addDeviceProfileYaml(data, requestAuthor(r), w)
 }
 
 // Add a device profile with YAML content
@@ -405,10 +433,10 @@ func restAddProfileByYamlRaw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addDeviceProfileYaml(body, w)
+	addDeviceProfileYaml(body, requestAuthor(r), w)
 }
 
-func addDeviceProfileYaml(data []byte, w http.ResponseWriter) {
+func addDeviceProfileYaml(data []byte, author string, w http.ResponseWriter) {
 	var dp models.DeviceProfile
 
 	err := yaml.Unmarshal(data, &dp)
@@ -418,6 +446,11 @@ func addDeviceProfileYaml(data []byte, w http.ResponseWriter) {
 		return
 	}
 
+	if err := checkProfileLint(dp, w); err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
 	// Check if there are duplicate names in the device profile command list
 	for _, c1 := range dp.Commands {
 		count := 0
@@ -443,6 +476,9 @@ func addDeviceProfileYaml(data []byte, w http.ResponseWriter) {
 		loggingClient.Error(err.Error(), "")
 		return
 	}
+	if _, err := recordProfileRevision(dp, nil, revisionCreate, author); err != nil {
+		loggingClient.Error(err.Error(), "")
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(dp.Id.Hex()))
@@ -549,7 +585,7 @@ func restGetProfileByName(w http.ResponseWriter, r *http.Request) {
 	// Get the device
 	var res models.DeviceProfile
 	if err := getDeviceProfileByName(&res, dn); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -576,7 +612,7 @@ func restGetYamlProfileByName(w http.ResponseWriter, r *http.Request) {
 	err = getDeviceProfileByName(&dp, name)
 	if err != nil {
 		// Not found, return nil
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -613,7 +649,7 @@ func restGetYamlProfileById(w http.ResponseWriter, r *http.Request) {
 	var dp models.DeviceProfile
 	err := getDeviceProfileById(&dp, id)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			w.WriteHeader(http.StatusNotFound)
 			w.Write([]byte(nil))
 			return
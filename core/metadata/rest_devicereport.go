@@ -25,7 +25,6 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	mgo "gopkg.in/mgo.v2"
 )
 
 func restGetAllDeviceReports(w http.ResponseWriter, _ *http.Request) {
@@ -64,7 +63,7 @@ func restAddDeviceReport(w http.ResponseWriter, r *http.Request) {
 	// Check if the device exists
 	var d models.Device
 	if err := getDeviceByName(&d, dr.Device); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device referenced by Device Report doesn't exist", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -76,7 +75,7 @@ func restAddDeviceReport(w http.ResponseWriter, r *http.Request) {
 	// Check if the Schedule Event exists
 	var se models.ScheduleEvent
 	if err := getScheduleEventByName(&se, dr.Event); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule Event referenced by Device Report doesn't exist", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -120,7 +119,7 @@ func restUpdateDeviceReport(w http.ResponseWriter, r *http.Request) {
 	if err := getDeviceReportById(&to, from.Id.Hex()); err != nil {
 		// Try by name
 		if err = getDeviceReportByName(&to, from.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, err.Error(), http.StatusNotFound)
 			} else {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -182,7 +181,7 @@ func updateDeviceReportFields(from models.DeviceReport, to *models.DeviceReport,
 func validateDevice(d string, w http.ResponseWriter) error {
 	var device models.Device
 	if err := getDeviceByName(&device, d); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device was not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -197,7 +196,7 @@ func validateDevice(d string, w http.ResponseWriter) error {
 func validateEvent(e string, w http.ResponseWriter) error {
 	var event models.ScheduleEvent
 	if err := getScheduleEventByName(&event, e); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Event was not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -214,7 +213,7 @@ func restGetReportById(w http.ResponseWriter, r *http.Request) {
 	var res models.DeviceReport
 	err := getDeviceReportById(&res, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -239,7 +238,7 @@ func restGetReportByName(w http.ResponseWriter, r *http.Request) {
 	var res models.DeviceReport
 	err = getDeviceReportByName(&res, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -309,7 +308,7 @@ func restDeleteReportById(w http.ResponseWriter, r *http.Request) {
 	// Check if the device report exists
 	var dr models.DeviceReport
 	if err := getDeviceReportById(&dr, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -339,7 +338,7 @@ func restDeleteReportByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the device report exists
 	var dr models.DeviceReport
 	if err = getDeviceReportByName(&dr, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -27,7 +27,6 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -40,7 +39,7 @@ func getAddressableByIdOrName(a *models.Addressable, w http.ResponseWriter) erro
 	if err := getAddressableById(a, id.Hex()); err != nil {
 		// Try by name
 		if err = getAddressableByName(a, name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Addressable not found", http.StatusServiceUnavailable)
 			} else {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -95,7 +94,7 @@ func restAddDeviceService(w http.ResponseWriter, r *http.Request) {
 	// First try by name
 	err = getAddressableByName(&ds.Service.Addressable, ds.Service.Addressable.Name)
 	if err != nil {
-		if err != mgo.ErrNotFound {
+		if err != ErrNotFound {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		}
 		loggingClient.Error(err.Error(), "")
@@ -141,7 +140,7 @@ func restGetAddressablesForAssociatedDevicesById(w http.ResponseWriter, r *http.
 
 	// Check if the device service exists
 	if err := getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -175,7 +174,7 @@ func restGetAddressablesForAssociatedDevicesByName(w http.ResponseWriter, r *htt
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -294,14 +293,14 @@ func updateDeviceServiceFields(from models.DeviceService, to *models.DeviceServi
 		err := getDeviceServiceByName(&checkDS, from.Service.Name)
 		if err != nil {
 			// A problem occured accessing database
-			if err != mgo.ErrNotFound {
+			if err != ErrNotFound {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
 				return err
 			}
 		}
 
 		// Found a device service, make sure its the one we're trying to update
-		if err != mgo.ErrNotFound {
+		if err != ErrNotFound {
 			// Differnt IDs -> Name is not unique
 			if checkDS.Service.Id != to.Service.Id {
 				err = errors.New("Duplicate name for Device Service")
@@ -340,7 +339,7 @@ func restGetServiceByAddressableName(w http.ResponseWriter, r *http.Request) {
 	// Check if the addressable exists
 	var a models.Addressable
 	if err = getAddressableByName(&a, an); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Addressable not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -367,7 +366,7 @@ func restGetServiceByAddressableId(w http.ResponseWriter, r *http.Request) {
 	// Check if the Addressable exists
 	var a models.Addressable
 	if err := getAddressableById(&a, sid); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Addressable not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -420,7 +419,7 @@ func restGetServiceByName(w http.ResponseWriter, r *http.Request) {
 	var res models.DeviceService
 	err = getDeviceServiceByName(&res, dn)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -440,7 +439,7 @@ func restDeleteServiceById(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists and get it
 	var ds models.DeviceService
 	if err := getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -469,7 +468,7 @@ func restDeleteServiceByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -539,7 +538,7 @@ func restUpdateServiceLastConnectedById(w http.ResponseWriter, r *http.Request)
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -576,7 +575,7 @@ func restUpdateServiceLastConnectedByName(w http.ResponseWriter, r *http.Request
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -611,7 +610,7 @@ func restGetServiceById(w http.ResponseWriter, r *http.Request) {
 	var res models.DeviceService
 
 	if err := getDeviceServiceById(&res, did); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -640,7 +639,7 @@ func restUpdateServiceOpStateById(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err := getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -679,7 +678,7 @@ func restUpdateServiceOpStateByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -723,7 +722,7 @@ func restUpdateServiceAdminStateById(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err := getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -763,7 +762,7 @@ func restUpdateServiceAdminStateByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -806,7 +805,7 @@ func restUpdateServiceLastReportedById(w http.ResponseWriter, r *http.Request) {
 	// Check if the devicde service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceById(&ds, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -843,7 +842,7 @@ func restUpdateServiceLastReportedByName(w http.ResponseWriter, r *http.Request)
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 		} else {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
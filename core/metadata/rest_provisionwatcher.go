@@ -25,7 +25,6 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
-	"gopkg.in/mgo.v2"
 )
 
 func restGetProvisionWatchers(w http.ResponseWriter, _ *http.Request) {
@@ -81,7 +80,7 @@ func restDeleteProvisionWatcherByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the provision watcher exists
 	var pw models.ProvisionWatcher
 	if err = getProvisionWatcherByName(&pw, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			errMessage := "Provision watcher not found: " + err.Error()
 			http.Error(w, errMessage, http.StatusNotFound)
 			loggingClient.Error(errMessage, "")
@@ -121,7 +120,7 @@ func restGetProvisionWatcherById(w http.ResponseWriter, r *http.Request) {
 	var res models.ProvisionWatcher
 
 	if err := getProvisionWatcherById(&res, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			errMessage := "Problem getting provision watcher by ID: " + err.Error()
 			loggingClient.Error(errMessage, "")
 			http.Error(w, errMessage, http.StatusNotFound)
@@ -148,7 +147,7 @@ func restGetProvisionWatcherByName(w http.ResponseWriter, r *http.Request) {
 
 	err = getProvisionWatcherByName(&res, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			loggingClient.Error("Provision watcher not found: "+err.Error(), "")
 		} else {
@@ -196,7 +195,7 @@ func restGetProvisionWatchersByProfileName(w http.ResponseWriter, r *http.Reques
 	// Check if the device profile exists
 	var dp models.DeviceProfile
 	if err = getDeviceProfileByName(&dp, pn); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device profile not found", http.StatusNotFound)
 			loggingClient.Error("Device profile not found: "+err.Error(), "")
 		} else {
@@ -252,7 +251,7 @@ func restGetProvisionWatchersByServiceName(w http.ResponseWriter, r *http.Reques
 	// Check if the device service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, sn); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Device service not found", http.StatusNotFound)
 			loggingClient.Error("Device service not found: "+err.Error(), "")
 		} else {
@@ -321,7 +320,7 @@ func restAddProvisionWatcher(w http.ResponseWriter, r *http.Request) {
 	if err := getDeviceProfileById(&pw.Profile, pw.Profile.Id.Hex()); err != nil {
 		// Try by name
 		if err = getDeviceProfileByName(&pw.Profile, pw.Profile.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				loggingClient.Error("Device profile not found for provision watcher: "+err.Error(), "")
 				http.Error(w, "Device profile not found for provision watcher", http.StatusConflict)
 			} else {
@@ -337,7 +336,7 @@ func restAddProvisionWatcher(w http.ResponseWriter, r *http.Request) {
 	if err := getDeviceServiceById(&pw.Service, pw.Service.Service.Id.Hex()); err != nil {
 		// Try by name
 		if err = getDeviceServiceByName(&pw.Service, pw.Service.Service.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Device service not found for provision watcher", http.StatusConflict)
 				loggingClient.Error("Device service not found for provision watcher: "+err.Error(), "")
 			} else {
@@ -386,7 +385,7 @@ func restUpdateProvisionWatcher(w http.ResponseWriter, r *http.Request) {
 	if err := getProvisionWatcherById(&to, from.Id.Hex()); err != nil {
 		// Try by name
 		if err = getProvisionWatcherByName(&to, from.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Provision watcher not found", http.StatusNotFound)
 				loggingClient.Error("Provision watcher not found: "+err.Error(), "")
 			} else {
@@ -430,13 +429,13 @@ func updateProvisionWatcherFields(from models.ProvisionWatcher, to *models.Provi
 		var checkPW models.ProvisionWatcher
 		err := getProvisionWatcherByName(&checkPW, from.Name)
 		if err != nil {
-			if err != mgo.ErrNotFound {
+			if err != ErrNotFound {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
 				return err
 			}
 		}
 		// Found one, compare the IDs to see if its another provision watcher
-		if err != mgo.ErrNotFound {
+		if err != ErrNotFound {
 			if checkPW.Id != to.Id {
 				err = errors.New("Duplicate name for the provision watcher")
 				http.Error(w, err.Error(), http.StatusConflict)
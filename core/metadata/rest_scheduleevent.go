@@ -28,7 +28,6 @@ import (
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/gorilla/mux"
 	"github.com/robfig/cron"
-	"gopkg.in/mgo.v2"
 )
 
 func isIntervalValid(frequency string) bool {
@@ -86,7 +85,7 @@ func restAddScheduleEvent(w http.ResponseWriter, r *http.Request) {
 	}
 	var s models.Schedule
 	if err := getScheduleByName(&s, se.Schedule); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule not found for schedule event", http.StatusNotFound)
 			loggingClient.Error("Schedule not found for schedule event: "+err.Error(), "")
 		} else {
@@ -111,7 +110,7 @@ func restAddScheduleEvent(w http.ResponseWriter, r *http.Request) {
 
 	/*if len(se.Addressable.Name) != 0 {
 		if err := getAddressableByName(&se.Addressable, se.Addressable.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Unknown Addressable", http.StatusNotFound)
 				LOGGER.Println(err.Error())
 				return
@@ -122,7 +121,7 @@ func restAddScheduleEvent(w http.ResponseWriter, r *http.Request) {
 		}
 	} else if len(se.Addressable.Id.Hex()) != 0 {
 		if err := getAddressableById(&se.Addressable, se.Addressable.Id.Hex()); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Unknown Addressable", http.StatusNotFound)
 				LOGGER.Println(err.Error())
 				return
@@ -201,7 +200,7 @@ func getScheduleEventByIdOrName(from models.ScheduleEvent, w http.ResponseWriter
 	if err := getScheduleEventById(&se, from.Id.Hex()); err != nil {
 		// Try by Name
 		if err = getScheduleEventByName(&se, from.Name); err != nil {
-			if err == mgo.ErrNotFound {
+			if err == ErrNotFound {
 				http.Error(w, "Schedule Event not found", http.StatusNotFound)
 				loggingClient.Error(err.Error(), "")
 			} else {
@@ -228,7 +227,7 @@ func updateScheduleEventFields(from models.ScheduleEvent, to *models.ScheduleEve
 		if err := getAddressableById(&from.Addressable, from.Addressable.Id.Hex()); err != nil {
 			// Try by name
 			if err = getAddressableByName(&from.Addressable, from.Addressable.Name); err != nil {
-				if err == mgo.ErrNotFound {
+				if err == ErrNotFound {
 					http.Error(w, "Addressable not found for schedule event", http.StatusNotFound)
 				} else {
 					http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -245,7 +244,7 @@ func updateScheduleEventFields(from models.ScheduleEvent, to *models.ScheduleEve
 			// Verify that the new service exists
 			var checkDS models.DeviceService
 			if err := getDeviceServiceByName(&checkDS, from.Service); err != nil {
-				if err == mgo.ErrNotFound {
+				if err == ErrNotFound {
 					http.Error(w, "Device Service not found for schedule event", http.StatusNotFound)
 				} else {
 					http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -262,7 +261,7 @@ func updateScheduleEventFields(from models.ScheduleEvent, to *models.ScheduleEve
 			// Verify that the new schedule exists
 			var checkS models.Schedule
 			if err := getScheduleByName(&checkS, from.Schedule); err != nil {
-				if err == mgo.ErrNotFound {
+				if err == ErrNotFound {
 					http.Error(w, "Schedule not found for schedule event", http.StatusNotFound)
 				} else {
 					http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -326,7 +325,7 @@ func restGetScheduleEventByName(w http.ResponseWriter, r *http.Request) {
 	var res models.ScheduleEvent
 	err = getScheduleEventByName(&res, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule event not found", http.StatusNotFound)
 			loggingClient.Error("Schedule event not found: "+err.Error(), "")
 		} else {
@@ -375,7 +374,7 @@ func restDeleteScheduleEventByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the schedule event exists
 	var se models.ScheduleEvent
 	if err := getScheduleEventByName(&se, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule event not found", http.StatusNotFound)
 			loggingClient.Error("Schedule event not found: "+err.Error(), "")
 		} else {
@@ -429,7 +428,7 @@ func restGetScheduleEventById(w http.ResponseWriter, r *http.Request) {
 	var res models.ScheduleEvent
 	err := getScheduleEventById(&res, did)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule event not found", http.StatusNotFound)
 			loggingClient.Error("Schedule event not found: "+err.Error(), "")
 		} else {
@@ -452,7 +451,7 @@ func restGetScheduleEventByAddressableId(w http.ResponseWriter, r *http.Request)
 	// Check if the addressable exists
 	var a models.Addressable
 	if err := getAddressableById(&a, aid); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Addressable not found for schedule event", http.StatusNotFound)
 			loggingClient.Error("Addressable not found for schedule event: "+err.Error(), "")
 		} else {
@@ -488,7 +487,7 @@ func restGetScheduleEventByAddressableName(w http.ResponseWriter, r *http.Reques
 	// Check if the addressable exists
 	var a models.Addressable
 	if err = getAddressableByName(&a, an); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			loggingClient.Error("Addressable not found for schedule event: "+err.Error(), "")
 			http.Error(w, "Addressable not found for schedule event", http.StatusNotFound)
 		} else {
@@ -524,7 +523,7 @@ func restGetScheduleEventsByServiceName(w http.ResponseWriter, r *http.Request)
 	// Check if the service exists
 	var ds models.DeviceService
 	if err = getDeviceServiceByName(&ds, sn); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Service not found for schedule event", http.StatusNotFound)
 			loggingClient.Error("Device service not found for schedule event: "+err.Error(), "")
 		} else {
@@ -580,7 +579,7 @@ func restAddSchedule(w http.ResponseWriter, r *http.Request) {
 	// Check if the name is unique
 	var checkS models.Schedule
 	if err := getScheduleByName(&checkS, s.Name); err != nil {
-		if err != mgo.ErrNotFound {
+		if err != ErrNotFound {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 			loggingClient.Error("Schedule not found: "+err.Error(), "")
 			return
@@ -714,7 +713,7 @@ func updateScheduleFields(from models.Schedule, to *models.Schedule, w http.Resp
 		// Check if new name is unique
 		var checkS models.Schedule
 		if err := getScheduleByName(&checkS, from.Name); err != nil {
-			if err != mgo.ErrNotFound {
+			if err != ErrNotFound {
 				http.Error(w, err.Error(), http.StatusServiceUnavailable)
 			}
 		} else {
@@ -750,7 +749,7 @@ func restGetScheduleById(w http.ResponseWriter, r *http.Request) {
 	var res models.Schedule
 	err := getScheduleById(&res, sid)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule not found", http.StatusNotFound)
 			loggingClient.Error("Schedule not found: "+err.Error(), "")
 		} else {
@@ -776,7 +775,7 @@ func restGetScheduleByName(w http.ResponseWriter, r *http.Request) {
 	var res models.Schedule
 	err = getScheduleByName(&res, n)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule not found", http.StatusNotFound)
 			loggingClient.Error("Schedule not found: "+err.Error(), "")
 		} else {
@@ -797,7 +796,7 @@ func restDeleteScheduleById(w http.ResponseWriter, r *http.Request) {
 	// Check if the schedule exists
 	var s models.Schedule
 	if err := getScheduleById(&s, id); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			http.Error(w, "Schedule not found", http.StatusNotFound)
 			loggingClient.Error("Schedule not found: "+err.Error(), "")
 		} else {
@@ -828,7 +827,7 @@ func restDeleteScheduleByName(w http.ResponseWriter, r *http.Request) {
 	// Check if the schedule exists
 	var s models.Schedule
 	if err = getScheduleByName(&s, n); err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrNotFound {
 			loggingClient.Error("Schedule not found: "+err.Error(), "")
 			http.Error(w, "Schedule not found", http.StatusNotFound)
 		} else {
@@ -0,0 +1,479 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
+)
+
+// Actions recorded with the device profile revisions
+const (
+	revisionCreate   = "create"
+	revisionUpdate   = "update"
+	revisionRollback = "rollback"
+)
+
+// Author of a change, the authenticated subject or the From header of the request
+func requestAuthor(r *http.Request) string {
+	if p, ok := auth.FromContext(r.Context()); ok && p.Subject != "" {
+		return p.Subject
+	}
+	return r.Header.Get("From")
+}
+
+// The definition of the profile kept by its revisions, without the database IDs and timestamps
+func profileDefinition(dp models.DeviceProfile) models.DeviceProfile {
+	dp.Id = ""
+	dp.BaseObject = models.BaseObject{}
+	if dp.Commands != nil {
+		commands := make([]models.Command, len(dp.Commands))
+		for i, c := range dp.Commands {
+			c.Id = ""
+			c.BaseObject = models.BaseObject{}
+			commands[i] = c
+		}
+		dp.Commands = commands
+	}
+	return dp
+}
+
+// Changes between two definitions of a profile, the paths follow the JSON names
+// (i.e. commands[0].get.path)
+func profileChanges(before models.DeviceProfile, after models.DeviceProfile) ([]models.ProfileChange, error) {
+	b, err := jsonValue(profileDefinition(before))
+	if err != nil {
+		return nil, err
+	}
+	a, err := jsonValue(profileDefinition(after))
+	if err != nil {
+		return nil, err
+	}
+	changes := []models.ProfileChange{}
+	diffValues("", b, a, &changes)
+	return changes, nil
+}
+
+// The value as decoded from its JSON encoding
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	err = json.Unmarshal(data, &res)
+	return res, err
+}
+
+func diffValues(path string, before interface{}, after interface{}, changes *[]models.ProfileChange) {
+	if isEmptyValue(before) && isEmptyValue(after) {
+		return
+	}
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := []string{}
+			for k := range b {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, ok := b[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				field := k
+				if path != "" {
+					field = path + "." + k
+				}
+				diffValues(field, b[k], a[k], changes)
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			for i := 0; i < len(b) || i < len(a); i++ {
+				var bi, ai interface{}
+				if i < len(b) {
+					bi = b[i]
+				}
+				if i < len(a) {
+					ai = a[i]
+				}
+				diffValues(fmt.Sprintf("%s[%d]", path, i), bi, ai, changes)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, models.ProfileChange{Path: path, Before: before, After: after})
+	}
+}
+
+// Null, empty lists and empty objects aren't told apart
+func isEmptyValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// Store the profile as its next revision, with the changes since the last revision
+// Profiles without revisions get their previous definition recorded first
+func recordProfileRevision(dp models.DeviceProfile, previous *models.DeviceProfile, action string, author string) (models.DeviceProfileRevision, error) {
+	var revisions []models.DeviceProfileRevision
+	if err := getDeviceProfileRevisions(&revisions, dp.Id.Hex()); err != nil {
+		return models.DeviceProfileRevision{}, err
+	}
+
+	var last *models.DeviceProfileRevision
+	if len(revisions) > 0 {
+		last = &revisions[len(revisions)-1]
+	} else if previous != nil {
+		baseline, err := addProfileRevision(*previous, nil, revisionCreate, "")
+		if err != nil {
+			return baseline, err
+		}
+		last = &baseline
+	}
+	return addProfileRevision(dp, last, action, author)
+}
+
+func addProfileRevision(dp models.DeviceProfile, last *models.DeviceProfileRevision, action string, author string) (models.DeviceProfileRevision, error) {
+	dpr := models.DeviceProfileRevision{
+		ProfileId: dp.Id,
+		Revision:  1,
+		Action:    action,
+		Author:    author,
+		Profile:   profileDefinition(dp),
+	}
+	var before models.DeviceProfile
+	if last != nil {
+		dpr.Revision = last.Revision + 1
+		before = last.Profile
+	}
+
+	changes, err := profileChanges(before, dpr.Profile)
+	if err != nil {
+		return dpr, err
+	}
+	dpr.Changes = changes
+	err = addDeviceProfileRevision(&dpr)
+	return dpr, err
+}
+
+// Store the changed profile, delete the commands it replaced and record the revision
+// previous is the profile as it was stored before the change
+func saveDeviceProfile(dp *models.DeviceProfile, previous models.DeviceProfile, action string, author string, w http.ResponseWriter) (models.DeviceProfileRevision, error) {
+	dpr, err := storeDeviceProfile(dp, previous, action, author)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	}
+	return dpr, err
+}
+
+func storeDeviceProfile(dp *models.DeviceProfile, previous models.DeviceProfile, action string, author string) (models.DeviceProfileRevision, error) {
+	if err := updateDeviceProfile(dp); err != nil {
+		return models.DeviceProfileRevision{}, err
+	}
+	if err := deleteCommands(previous, *dp); err != nil {
+		return models.DeviceProfileRevision{}, err
+	}
+	return recordProfileRevision(*dp, &previous, action, author)
+}
+
+// Replace the stored profile by another definition, with new commands
+// dp is the definition, it takes the ID of the previous profile
+func redefineDeviceProfile(dp *models.DeviceProfile, previous models.DeviceProfile, action string, author string) (models.DeviceProfileRevision, error) {
+	if err := defineDeviceProfile(dp, previous); err != nil {
+		return models.DeviceProfileRevision{}, err
+	}
+	return storeDeviceProfile(dp, previous, action, author)
+}
+
+// Give the definition the ID of the previous profile and add its commands, the profile isn't stored
+func defineDeviceProfile(dp *models.DeviceProfile, previous models.DeviceProfile) error {
+	*dp = profileDefinition(*dp)
+	dp.Id = previous.Id
+	dp.Created = previous.Created
+	dp.Origin = previous.Origin
+	for i := range dp.Commands {
+		if err := addCommand(&dp.Commands[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Number of the next revision recorded for the profile
+func nextProfileRevision(pid bson.ObjectId) (int, error) {
+	var revisions []models.DeviceProfileRevision
+	if err := getDeviceProfileRevisions(&revisions, pid.Hex()); err != nil {
+		return 0, err
+	}
+	if len(revisions) == 0 {
+		return 1, nil
+	}
+	return revisions[len(revisions)-1].Revision + 1, nil
+}
+
+func hasCommand(dp models.DeviceProfile, id bson.ObjectId) bool {
+	for _, c := range dp.Commands {
+		if c.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Names of the commands of the revision the current profile no longer has
+// Core command addresses the commands by ID, the deleted ones can't be used by pinned devices
+func missingRevisionCommands(dpr models.DeviceProfileRevision, current models.DeviceProfile) []string {
+	var missing []string
+	for _, c := range dpr.Profile.Commands {
+		if _, ok := profileCommand(current, c.Name); !ok {
+			missing = append(missing, c.Name)
+		}
+	}
+	return missing
+}
+
+func profileCommand(dp models.DeviceProfile, name string) (models.Command, bool) {
+	for _, c := range dp.Commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return models.Command{}, false
+}
+
+// Replace the profile of a device pinned to a revision by the definition of the revision
+// The profile keeps its ID and the commands take the IDs of the current commands with their names,
+// the commands the profile no longer has are left out
+func pinDeviceProfile(d *models.Device) error {
+	if d.ProfileRevision == 0 {
+		return nil
+	}
+	var dpr models.DeviceProfileRevision
+	if err := getDeviceProfileRevision(&dpr, d.Profile.Id.Hex(), d.ProfileRevision); err != nil {
+		if err != ErrNotFound {
+			return err
+		}
+		loggingClient.Warn(fmt.Sprintf("Device %s is pinned to the missing revision %d of its profile", d.Name, d.ProfileRevision))
+		return nil
+	}
+
+	current := d.Profile
+	d.Profile = dpr.Profile
+	d.Profile.Id = current.Id
+	d.Profile.BaseObject = current.BaseObject
+	if missing := missingRevisionCommands(dpr, current); len(missing) > 0 {
+		loggingClient.Warn(fmt.Sprintf("Device %s is pinned to the revision %d of its profile, the commands %s no longer exist",
+			d.Name, d.ProfileRevision, strings.Join(missing, ", ")))
+	}
+	commands := []models.Command{}
+	for _, rc := range dpr.Profile.Commands {
+		if c, ok := profileCommand(current, rc.Name); ok {
+			rc.Id = c.Id
+			rc.BaseObject = c.BaseObject
+			commands = append(commands, rc)
+		}
+	}
+	d.Profile.Commands = commands
+	return nil
+}
+
+func pinDeviceProfiles(d []models.Device) error {
+	for i := range d {
+		if err := pinDeviceProfile(&d[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check the revision exists for the profile and its commands still exist, 0 follows the latest revision
+func checkProfileRevision(pid bson.ObjectId, revision int) error {
+	if revision == 0 {
+		return nil
+	}
+	var dpr models.DeviceProfileRevision
+	err := getDeviceProfileRevision(&dpr, pid.Hex(), revision)
+	if err == ErrNotFound {
+		return fmt.Errorf("Device profile has no revision %d", revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	var current models.DeviceProfile
+	if err := getDeviceProfileById(&current, pid.Hex()); err != nil {
+		return err
+	}
+	if missing := missingRevisionCommands(dpr, current); len(missing) > 0 {
+		return fmt.Errorf("Commands %s of the device profile revision %d no longer exist", strings.Join(missing, ", "), revision)
+	}
+	return nil
+}
+
+// Get the revision of the request path
+// 404 if the device profile or the revision doesn't exist
+func pathProfileRevision(r *http.Request, w http.ResponseWriter) (models.DeviceProfileRevision, error) {
+	var dpr models.DeviceProfileRevision
+	vars := mux.Vars(r)
+	revision, err := strconv.Atoi(vars[REVISION])
+	if err != nil {
+		http.Error(w, "Invalid revision "+vars[REVISION], http.StatusBadRequest)
+		return dpr, err
+	}
+	if err := getDeviceProfileRevision(&dpr, vars[ID], revision); err != nil {
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		return dpr, err
+	}
+	return dpr, nil
+}
+
+func restGetProfileRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var id string = vars[ID]
+
+	// Check if the device profile exists
+	var dp models.DeviceProfile
+	if err := getDeviceProfileById(&dp, id); err != nil {
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	res := []models.DeviceProfileRevision{}
+	if err := getDeviceProfileRevisions(&res, id); err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if len(res) > configuration.ReadMaxLimit {
+		err := errors.New("Max limit exceeded with request for profile revisions")
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&res)
+}
+
+func restGetProfileRevision(w http.ResponseWriter, r *http.Request) {
+	dpr, err := pathProfileRevision(r, w)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dpr)
+}
+
+// The profile definition of the revision in YAML
+func restGetYamlProfileRevision(w http.ResponseWriter, r *http.Request) {
+	dpr, err := pathProfileRevision(r, w)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	out, err := yaml.Marshal(dpr.Profile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(out)
+}
+
+// Restore the definition of a revision, recorded as a new revision
+// The profile keeps its ID, its commands are replaced by new ones
+func restRollbackDeviceProfile(w http.ResponseWriter, r *http.Request) {
+	dpr, err := pathProfileRevision(r, w)
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	var previous models.DeviceProfile
+	if err := getDeviceProfileById(&previous, dpr.ProfileId.Hex()); err != nil {
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	dp := dpr.Profile
+	if dp.Name != previous.Name {
+		// Another profile may have taken the name since
+		dp.Id = previous.Id
+		if err := checkDuplicateProfileNames(dp, w); err != nil {
+			loggingClient.Error(err.Error(), "")
+			return
+		}
+	}
+	if err := checkProfileLint(dp, w); err != nil {
+		loggingClient.Error(err.Error(), "")
+		return
+	}
+
+	res, err := redefineDeviceProfile(&dp, previous, revisionRollback, requestAuthor(r))
+	if err != nil {
+		loggingClient.Error(err.Error(), "")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
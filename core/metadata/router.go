@@ -20,13 +20,21 @@ package metadata
 import (
 	"net/http"
 
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
 	"github.com/gorilla/mux"
 )
 
 func LoadRestRoutes() http.Handler {
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware(metrics.GorillaRoute))
+	r.Use(auth.Middleware(authenticator, policy))
 	b := r.PathPrefix("/api/v1").Subrouter()
 	b.HandleFunc("/ping", ping)
+	b.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+	b.Handle("/health/live", health.LiveHandler()).Methods(http.MethodGet)
+	b.Handle("/health/ready", health.ReadyHandler()).Methods(http.MethodGet)
 
 	loadDeviceRoutes(b)
 	loadDeviceProfileRoutes(b)
@@ -37,8 +45,22 @@ func LoadRestRoutes() http.Handler {
 	loadProvisionWatcherRoutes(b)
 	loadAddressableRoutes(b)
 	loadCommandRoutes(b)
+	loadBundleRoutes(b)
 	return r
 }
+
+// Scopes required by the metadata routes: reads need read, changes need write,
+// removals and admin state changes need admin. The configuration can add to or override them (AuthScopes).
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().
+		Public("/api/v1/ping", health.ApiLiveRoute, health.ApiReadyRoute).
+		Method(http.MethodDelete, auth.ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/"+DEVICE+"/{"+ID+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/"+DEVICE+"/"+NAME+"/{"+NAME+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/"+DEVICESERVICE+"/{"+ID+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/"+DEVICESERVICE+"/"+NAME+"/{"+NAME+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", auth.ScopeAdmin)
+}
+
 func loadDeviceRoutes(b *mux.Router) {
 	// /api/v1/" + DEVICE
 	b.HandleFunc("/"+DEVICE, restAddNewDevice).Methods(http.MethodPost)
@@ -60,6 +82,7 @@ func loadDeviceRoutes(b *mux.Router) {
 	d.HandleFunc("/"+ID+"/{"+ID+"}", restDeleteDeviceById).Methods(http.MethodDelete)
 	d.HandleFunc("/{"+ID+"}/"+OPSTATE+"/{"+OPSTATE+"}", restSetDeviceOpStateById).Methods(http.MethodPut)
 	d.HandleFunc("/{"+ID+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", restSetDeviceAdminStateById).Methods(http.MethodPut)
+	d.HandleFunc("/{"+ID+"}/"+PROFILEREVISION+"/{"+REVISION+"}", restSetDeviceProfileRevisionById).Methods(http.MethodPut)
 	d.HandleFunc("/{"+ID+"}/"+URLLASTREPORTED+"/{"+LASTREPORTED+"}", restSetDeviceLastReportedById).Methods(http.MethodPut)
 	d.HandleFunc("/{"+ID+"}/"+URLLASTREPORTED+"/{"+LASTREPORTED+"}/{"+LASTREPORTEDNOTIFY+"}", restSetDeviceLastReportedByIdNotify).Methods(http.MethodPut)
 	d.HandleFunc("/{"+ID+"}/"+URLLASTCONNECTED+"/{"+LASTCONNECTED+"}", restSetDeviceLastConnectedById).Methods(http.MethodPut)
@@ -71,6 +94,7 @@ func loadDeviceRoutes(b *mux.Router) {
 	n.HandleFunc("/{"+NAME+"}", restDeleteDeviceByName).Methods(http.MethodDelete)
 	n.HandleFunc("/{"+NAME+"}/"+OPSTATE+"/{"+OPSTATE+"}", restSetDeviceOpStateByName).Methods(http.MethodPut)
 	n.HandleFunc("/{"+NAME+"}/"+URLADMINSTATE+"/{"+ADMINSTATE+"}", restSetDeviceAdminStateByName).Methods(http.MethodPut)
+	n.HandleFunc("/{"+NAME+"}/"+PROFILEREVISION+"/{"+REVISION+"}", restSetDeviceProfileRevisionByName).Methods(http.MethodPut)
 	n.HandleFunc("/{"+NAME+"}/"+URLLASTREPORTED+"/{"+LASTREPORTED+"}", restSetDeviceLastReportedByName).Methods(http.MethodPut)
 	n.HandleFunc("/{"+NAME+"}/"+URLLASTREPORTED+"/{"+LASTREPORTED+"}/{"+LASTREPORTEDNOTIFY+"}", restSetDeviceLastReportedByNameNotify).Methods(http.MethodPut)
 	n.HandleFunc("/{"+NAME+"}/"+URLLASTCONNECTED+"/{"+LASTCONNECTED+"}", restSetDeviceLastConnectedByName).Methods(http.MethodPut)
@@ -88,6 +112,7 @@ func loadDeviceProfileRoutes(b *mux.Router) {
 	dp.HandleFunc("/"+ID+"/{"+ID+"}", restDeleteProfileByProfileId).Methods(http.MethodDelete)
 	dp.HandleFunc("/"+UPLOADFILE, restAddProfileByYaml).Methods(http.MethodPost)
 	dp.HandleFunc("/"+UPLOAD, restAddProfileByYamlRaw).Methods(http.MethodPost)
+	dp.HandleFunc("/"+VALIDATE, restValidateDeviceProfile).Methods(http.MethodPost)
 	dp.HandleFunc("/"+MODEL+"/{"+MODEL+"}", restGetProfileByModel).Methods(http.MethodGet)
 	dp.HandleFunc("/"+LABEL+"/{"+LABEL+"}", restGetProfileWithLabel).Methods(http.MethodGet)
 
@@ -106,6 +131,13 @@ func loadDeviceProfileRoutes(b *mux.Router) {
 	// TODO add functionality
 	dpy.HandleFunc("/"+NAME+"/{"+NAME+"}", restGetYamlProfileByName).Methods(http.MethodGet)
 	dpy.HandleFunc("/{"+ID+"}", restGetYamlProfileById).Methods(http.MethodGet)
+	dpy.HandleFunc("/{"+ID+"}/"+REVISION+"/{"+REVISION+"}", restGetYamlProfileRevision).Methods(http.MethodGet)
+
+	// Registered last, the fixed paths above take precedence
+	// /api/v1/" + DEVICEPROFILE + "/{id}/" + REVISION + "
+	dp.HandleFunc("/{"+ID+"}/"+REVISION, restGetProfileRevisions).Methods(http.MethodGet)
+	dp.HandleFunc("/{"+ID+"}/"+REVISION+"/{"+REVISION+"}", restGetProfileRevision).Methods(http.MethodGet)
+	dp.HandleFunc("/{"+ID+"}/"+REVISION+"/{"+REVISION+"}/"+ROLLBACK, restRollbackDeviceProfile).Methods(http.MethodPost)
 }
 func loadDeviceReportRoutes(b *mux.Router) {
 	// /api/v1/devicereport
@@ -248,6 +280,14 @@ func loadCommandRoutes(b *mux.Router) {
 	c.HandleFunc("/"+NAME+"/{"+NAME+"}", restGetCommandByName).Methods(http.MethodGet)
 	//c.HandleFunc("/" + NAME + "/{" + NAME + "}", restDeleteCommandByName).Methods(http.MethodDelete)
 }
+func loadBundleRoutes(b *mux.Router) {
+	// /api/v1/bundle
+	b.HandleFunc("/"+BUNDLE, restImportBundle).Methods(http.MethodPost)
+	b.HandleFunc("/"+BUNDLE, restExportBundle).Methods(http.MethodGet)
+
+	bl := b.PathPrefix("/" + BUNDLE).Subrouter()
+	bl.HandleFunc("/"+LABEL+"/{"+LABEL+"}", restExportBundleByLabel).Methods(http.MethodGet)
+}
 func ping(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte("pong"))
@@ -0,0 +1,965 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+// The REST tests run the real router against the in-memory repository, the device service
+// callbacks made by the handlers are captured by callbackServer
+var testRoutes http.Handler
+var callbackServer *httptest.Server
+var callbacks = make(chan testCallback, 100)
+
+type testCallback struct {
+	method string
+	body   string
+}
+
+func TestMain(m *testing.M) {
+	loggingClient = logger.NewMockClient()
+	configuration = ConfigurationStruct{ReadMaxLimit: 100}
+	repository = newMemoryRepository()
+	testRoutes = LoadRestRoutes()
+
+	callbackServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		callbacks <- testCallback{method: r.Method, body: string(body)}
+	}))
+	code := m.Run()
+	callbackServer.Close()
+	os.Exit(code)
+}
+
+// Start every test from an empty repository
+func resetRepository() {
+	repository = newMemoryRepository()
+	for len(callbacks) > 0 {
+		<-callbacks
+	}
+}
+
+// Send a request to the router, the body is JSON encoded unless it's nil or already a string
+func doRequest(method string, path string, body interface{}) *httptest.ResponseRecorder {
+	// The server always sets a body, some handlers close it even for a GET
+	var reader io.Reader = http.NoBody
+	switch b := body.(type) {
+	case string:
+		reader = bytes.NewBufferString(b)
+	case nil:
+	default:
+		data, _ := json.Marshal(b)
+		reader = bytes.NewReader(data)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	w := httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+	return w
+}
+
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, code int, what string) bool {
+	if w.Code != code {
+		t.Errorf("%s: expected status %d, got %d (%s)", what, code, w.Code, w.Body.String())
+		return false
+	}
+	return true
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("Error decoding %q: %v", w.Body.String(), err)
+	}
+}
+
+// Wait for the device service callback of the given method for the resource id
+func expectCallback(t *testing.T, method string, id string) {
+	want := fmt.Sprintf(`"id":"%s"`, id)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case c := <-callbacks:
+			if c.method == method && bytes.Contains([]byte(c.body), []byte(want)) {
+				return
+			}
+		case <-timeout:
+			t.Errorf("No %s callback received for %s", method, id)
+			return
+		}
+	}
+}
+
+func testAddressable(name string) models.Addressable {
+	host, port, _ := net.SplitHostPort(callbackServer.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return models.Addressable{Name: name, Protocol: "http", HTTPMethod: http.MethodPost, Address: host, Port: p, Path: "/callback", Topic: "topic-" + name}
+}
+
+func testProfile(name string, commands ...string) models.DeviceProfile {
+	dp := models.DeviceProfile{Name: name, Manufacturer: "Dell", Model: "M-" + name, Labels: []string{"test"}}
+	for _, c := range commands {
+		dp.Commands = append(dp.Commands, models.Command{Name: c})
+	}
+	return dp
+}
+
+func testDevice(name string) models.Device {
+	return models.Device{
+		Name:           name,
+		AdminState:     models.Unlocked,
+		OperatingState: models.Enabled,
+		Labels:         []string{"test"},
+		Addressable:    models.Addressable{Name: "device-address"},
+		Service:        testServiceRef(),
+		Profile:        models.DeviceProfile{Name: "test-profile"},
+	}
+}
+
+// A reference to the test device service, the states have to be valid to be sent as JSON
+func testServiceRef() models.DeviceService {
+	ds := models.DeviceService{AdminState: models.Unlocked}
+	ds.Name = "test-service"
+	ds.OperatingState = models.Enabled
+	return ds
+}
+
+// Add an addressable, a device service, a profile with two commands and a device
+// using them, and return the id of the device
+func addTestResources(t *testing.T) string {
+	w := doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("service-address"))
+	expectStatus(t, w, http.StatusOK, "add service addressable")
+	w = doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("device-address"))
+	expectStatus(t, w, http.StatusOK, "add device addressable")
+
+	ds := models.DeviceService{AdminState: models.Unlocked}
+	ds.Name = "test-service"
+	ds.OperatingState = models.Enabled
+	ds.Labels = []string{"test"}
+	ds.Addressable = models.Addressable{Name: "service-address"}
+	w = doRequest(http.MethodPost, "/api/v1/deviceservice", ds)
+	expectStatus(t, w, http.StatusOK, "add device service")
+
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("test-profile", "temperature", "humidity"))
+	expectStatus(t, w, http.StatusOK, "add device profile")
+
+	w = doRequest(http.MethodPost, "/api/v1/device", testDevice("test-device"))
+	if !expectStatus(t, w, http.StatusOK, "add device") {
+		t.FailNow()
+	}
+	return w.Body.String()
+}
+
+func TestPing(t *testing.T) {
+	w := doRequest(http.MethodGet, "/api/v1/ping", nil)
+	expectStatus(t, w, http.StatusOK, "ping")
+	if w.Body.String() != "pong" {
+		t.Errorf("Expected pong, got %s", w.Body.String())
+	}
+}
+
+func TestAddressable(t *testing.T) {
+	resetRepository()
+
+	w := doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("a1"))
+	expectStatus(t, w, http.StatusOK, "add addressable")
+	id := w.Body.String()
+
+	w = doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("a1"))
+	expectStatus(t, w, http.StatusConflict, "add duplicate addressable")
+
+	var a models.Addressable
+	w = doRequest(http.MethodGet, "/api/v1/addressable/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "get addressable by id")
+	decodeResponse(t, w, &a)
+	if a.Name != "a1" || a.Created == 0 {
+		t.Errorf("Unexpected addressable %+v", a)
+	}
+
+	w = doRequest(http.MethodGet, "/api/v1/addressable/name/a1", nil)
+	expectStatus(t, w, http.StatusOK, "get addressable by name")
+	w = doRequest(http.MethodGet, "/api/v1/addressable/name/unknown", nil)
+	expectStatus(t, w, http.StatusNotFound, "get unknown addressable")
+
+	var as []models.Addressable
+	w = doRequest(http.MethodGet, "/api/v1/addressable/topic/topic-a1", nil)
+	expectStatus(t, w, http.StatusOK, "get addressables by topic")
+	decodeResponse(t, w, &as)
+	if len(as) != 1 {
+		t.Errorf("Expected 1 addressable by topic, got %d", len(as))
+	}
+
+	a.Path = "/updated"
+	w = doRequest(http.MethodPut, "/api/v1/addressable", a)
+	expectStatus(t, w, http.StatusOK, "update addressable")
+	w = doRequest(http.MethodGet, "/api/v1/addressable/"+id, nil)
+	decodeResponse(t, w, &a)
+	if a.Path != "/updated" {
+		t.Errorf("Addressable path wasn't updated: %s", a.Path)
+	}
+
+	w = doRequest(http.MethodDelete, "/api/v1/addressable/id/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "delete addressable")
+	w = doRequest(http.MethodGet, "/api/v1/addressable/"+id, nil)
+	expectStatus(t, w, http.StatusNotFound, "get deleted addressable")
+}
+
+func TestAddressableInUse(t *testing.T) {
+	resetRepository()
+	addTestResources(t)
+
+	w := doRequest(http.MethodDelete, "/api/v1/addressable/name/service-address", nil)
+	expectStatus(t, w, http.StatusConflict, "delete addressable used by a device service")
+	w = doRequest(http.MethodDelete, "/api/v1/addressable/name/device-address", nil)
+	expectStatus(t, w, http.StatusConflict, "delete addressable used by a device")
+}
+
+func TestDeviceService(t *testing.T) {
+	resetRepository()
+	addTestResources(t)
+
+	var ds models.DeviceService
+	w := doRequest(http.MethodGet, "/api/v1/deviceservice/name/test-service", nil)
+	expectStatus(t, w, http.StatusOK, "get device service by name")
+	decodeResponse(t, w, &ds)
+	if ds.Addressable.Name != "service-address" {
+		t.Errorf("Device service addressable wasn't resolved: %+v", ds.Addressable)
+	}
+
+	var services []models.DeviceService
+	w = doRequest(http.MethodGet, "/api/v1/deviceservice/addressablename/service-address", nil)
+	expectStatus(t, w, http.StatusOK, "get device services by addressable name")
+	decodeResponse(t, w, &services)
+	if len(services) != 1 {
+		t.Errorf("Expected 1 device service by addressable, got %d", len(services))
+	}
+
+	w = doRequest(http.MethodPut, "/api/v1/deviceservice/name/test-service/adminstate/LOCKED", nil)
+	expectStatus(t, w, http.StatusOK, "lock device service")
+	w = doRequest(http.MethodGet, "/api/v1/deviceservice/"+ds.Id.Hex(), nil)
+	decodeResponse(t, w, &ds)
+	if ds.AdminState != models.Locked {
+		t.Errorf("Expected device service to be locked, got %s", ds.AdminState)
+	}
+
+	var addressables []models.Addressable
+	w = doRequest(http.MethodGet, "/api/v1/deviceservice/deviceaddressablesbyname/test-service", nil)
+	expectStatus(t, w, http.StatusOK, "get addressables for the service devices")
+	decodeResponse(t, w, &addressables)
+	if len(addressables) != 1 || addressables[0].Name != "device-address" {
+		t.Errorf("Unexpected device addressables %+v", addressables)
+	}
+
+	ds.Name = "test-service"
+	w = doRequest(http.MethodPost, "/api/v1/deviceservice", ds)
+	expectStatus(t, w, http.StatusConflict, "add duplicate device service")
+
+	// Deleting the service removes its devices
+	w = doRequest(http.MethodDelete, "/api/v1/deviceservice/name/test-service", nil)
+	expectStatus(t, w, http.StatusOK, "delete device service")
+	w = doRequest(http.MethodGet, "/api/v1/device/name/test-device", nil)
+	expectStatus(t, w, http.StatusNotFound, "get device of deleted service")
+}
+
+func TestDeviceProfile(t *testing.T) {
+	resetRepository()
+
+	w := doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("p1", "c1", "c1"))
+	expectStatus(t, w, http.StatusConflict, "add profile with duplicate commands")
+
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("p1", "c1", "c2"))
+	expectStatus(t, w, http.StatusOK, "add profile")
+	id := w.Body.String()
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("p1", "c3"))
+	expectStatus(t, w, http.StatusConflict, "add duplicate profile")
+
+	var dp models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "get profile by id")
+	decodeResponse(t, w, &dp)
+	if len(dp.Commands) != 2 || dp.Commands[0].Name != "c1" || !dp.Commands[0].Id.Valid() {
+		t.Errorf("Profile commands weren't stored: %+v", dp.Commands)
+	}
+
+	var profiles []models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/manufacturer/Dell/model/M-p1", nil)
+	expectStatus(t, w, http.StatusOK, "get profiles by manufacturer and model")
+	decodeResponse(t, w, &profiles)
+	if len(profiles) != 1 {
+		t.Errorf("Expected 1 profile by manufacturer and model, got %d", len(profiles))
+	}
+
+	yaml := "name: yaml-profile\nmanufacturer: Dell\nmodel: Y\nlabels: [yaml]\ncommands:\n- name: c4\n"
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile/upload", yaml)
+	expectStatus(t, w, http.StatusOK, "upload yaml profile")
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/label/yaml", nil)
+	decodeResponse(t, w, &profiles)
+	if len(profiles) != 1 || profiles[0].Name != "yaml-profile" {
+		t.Errorf("Unexpected profiles by label %+v", profiles)
+	}
+
+	w = doRequest(http.MethodDelete, "/api/v1/deviceprofile/name/yaml-profile", nil)
+	expectStatus(t, w, http.StatusOK, "delete profile")
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/yaml-profile", nil)
+	expectStatus(t, w, http.StatusNotFound, "get deleted profile")
+}
+
+func TestDeviceProfileInUse(t *testing.T) {
+	resetRepository()
+	addTestResources(t)
+
+	w := doRequest(http.MethodDelete, "/api/v1/deviceprofile/name/test-profile", nil)
+	expectStatus(t, w, http.StatusConflict, "delete profile used by a device")
+
+	var dp models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/test-profile", nil)
+	decodeResponse(t, w, &dp)
+	w = doRequest(http.MethodDelete, "/api/v1/command/id/"+dp.Commands[0].Id.Hex(), nil)
+	expectStatus(t, w, http.StatusConflict, "delete command used by a profile")
+
+	var commands []models.Command
+	w = doRequest(http.MethodGet, "/api/v1/command/name/humidity", nil)
+	expectStatus(t, w, http.StatusOK, "get commands by name")
+	decodeResponse(t, w, &commands)
+	if len(commands) != 1 {
+		t.Errorf("Expected 1 command by name, got %d", len(commands))
+	}
+}
+
+func TestDevice(t *testing.T) {
+	resetRepository()
+	id := addTestResources(t)
+	expectCallback(t, http.MethodPost, id)
+
+	w := doRequest(http.MethodPost, "/api/v1/device", testDevice("test-device"))
+	expectStatus(t, w, http.StatusConflict, "add duplicate device")
+
+	d := testDevice("no-profile")
+	d.Profile.Name = "unknown"
+	w = doRequest(http.MethodPost, "/api/v1/device", d)
+	expectStatus(t, w, http.StatusConflict, "add device with unknown profile")
+
+	var device models.Device
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "get device by id")
+	decodeResponse(t, w, &device)
+	if device.Profile.Name != "test-profile" || device.Service.Name != "test-service" || device.Addressable.Name != "device-address" {
+		t.Errorf("Device references weren't resolved: %+v", device)
+	}
+
+	var devices []models.Device
+	for _, path := range []string{"label/test", "profilename/test-profile", "servicename/test-service", "addressablename/device-address"} {
+		w = doRequest(http.MethodGet, "/api/v1/device/"+path, nil)
+		expectStatus(t, w, http.StatusOK, "get devices by "+path)
+		decodeResponse(t, w, &devices)
+		if len(devices) != 1 {
+			t.Errorf("Expected 1 device by %s, got %d", path, len(devices))
+		}
+	}
+
+	w = doRequest(http.MethodPut, "/api/v1/device/name/test-device/adminstate/LOCKED", nil)
+	expectStatus(t, w, http.StatusOK, "lock device")
+	expectCallback(t, http.MethodPut, id)
+	w = doRequest(http.MethodPut, "/api/v1/device/name/test-device/adminstate/BROKEN", nil)
+	expectStatus(t, w, http.StatusServiceUnavailable, "set invalid admin state")
+
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	decodeResponse(t, w, &device)
+	if device.AdminState != models.Locked {
+		t.Errorf("Expected device to be locked, got %s", device.AdminState)
+	}
+
+	device.Name = "renamed-device"
+	w = doRequest(http.MethodPut, "/api/v1/device", device)
+	expectStatus(t, w, http.StatusOK, "update device")
+	w = doRequest(http.MethodGet, "/api/v1/device/name/renamed-device", nil)
+	expectStatus(t, w, http.StatusOK, "get renamed device")
+	w = doRequest(http.MethodGet, "/api/v1/device/name/test-device", nil)
+	expectStatus(t, w, http.StatusNotFound, "get device by its old name")
+
+	w = doRequest(http.MethodDelete, "/api/v1/device/id/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "delete device")
+	expectCallback(t, http.MethodDelete, id)
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	expectStatus(t, w, http.StatusNotFound, "get deleted device")
+
+	// The profile can go once nothing refers to it
+	w = doRequest(http.MethodDelete, "/api/v1/deviceprofile/name/test-profile", nil)
+	expectStatus(t, w, http.StatusOK, "delete unused profile")
+}
+
+func TestScheduleEventsAndReports(t *testing.T) {
+	resetRepository()
+	addTestResources(t)
+
+	w := doRequest(http.MethodPost, "/api/v1/schedule", models.Schedule{Name: "s1", Frequency: "PT1M"})
+	expectStatus(t, w, http.StatusOK, "add schedule")
+	w = doRequest(http.MethodPost, "/api/v1/schedule", models.Schedule{Name: "s1"})
+	expectStatus(t, w, http.StatusConflict, "add duplicate schedule")
+
+	se := models.ScheduleEvent{Name: "e1", Schedule: "unknown", Addressable: models.Addressable{Name: "service-address"}, Service: "test-service"}
+	w = doRequest(http.MethodPost, "/api/v1/scheduleevent", se)
+	expectStatus(t, w, http.StatusNotFound, "add event with unknown schedule")
+	se.Schedule = "s1"
+	w = doRequest(http.MethodPost, "/api/v1/scheduleevent", se)
+	expectStatus(t, w, http.StatusOK, "add schedule event")
+
+	var events []models.ScheduleEvent
+	w = doRequest(http.MethodGet, "/api/v1/scheduleevent/servicename/test-service", nil)
+	expectStatus(t, w, http.StatusOK, "get events by service name")
+	decodeResponse(t, w, &events)
+	if len(events) != 1 || events[0].Addressable.Name != "service-address" {
+		t.Errorf("Unexpected schedule events %+v", events)
+	}
+
+	dr := models.DeviceReport{Name: "r1", Device: "test-device", Event: "unknown"}
+	w = doRequest(http.MethodPost, "/api/v1/devicereport", dr)
+	expectStatus(t, w, http.StatusNotFound, "add report with unknown event")
+	dr.Event = "e1"
+	w = doRequest(http.MethodPost, "/api/v1/devicereport", dr)
+	expectStatus(t, w, http.StatusOK, "add device report")
+
+	w = doRequest(http.MethodDelete, "/api/v1/schedule/name/s1", nil)
+	expectStatus(t, w, http.StatusConflict, "delete schedule used by an event")
+
+	// Removing the device removes its reports
+	w = doRequest(http.MethodDelete, "/api/v1/device/name/test-device", nil)
+	expectStatus(t, w, http.StatusOK, "delete device")
+	w = doRequest(http.MethodGet, "/api/v1/devicereport/name/r1", nil)
+	expectStatus(t, w, http.StatusNotFound, "get report of deleted device")
+
+	w = doRequest(http.MethodDelete, "/api/v1/scheduleevent/name/e1", nil)
+	expectStatus(t, w, http.StatusOK, "delete schedule event")
+	w = doRequest(http.MethodDelete, "/api/v1/schedule/name/s1", nil)
+	expectStatus(t, w, http.StatusOK, "delete schedule")
+}
+
+func TestProvisionWatcher(t *testing.T) {
+	resetRepository()
+	addTestResources(t)
+
+	pw := models.ProvisionWatcher{
+		Name:           "w1",
+		Identifiers:    map[string]string{"MAC": "00-05-1B-A1-99-99"},
+		Profile:        models.DeviceProfile{Name: "test-profile"},
+		Service:        testServiceRef(),
+		OperatingState: models.Enabled,
+	}
+	w := doRequest(http.MethodPost, "/api/v1/provisionwatcher", pw)
+	expectStatus(t, w, http.StatusOK, "add provision watcher")
+
+	var watchers []models.ProvisionWatcher
+	for _, path := range []string{"profilename/test-profile", "servicename/test-service", "identifier/MAC/00-05-1B-A1-99-99"} {
+		w = doRequest(http.MethodGet, "/api/v1/provisionwatcher/"+path, nil)
+		expectStatus(t, w, http.StatusOK, "get provision watchers by "+path)
+		decodeResponse(t, w, &watchers)
+		if len(watchers) != 1 {
+			t.Errorf("Expected 1 provision watcher by %s, got %d", path, len(watchers))
+		}
+	}
+
+	w = doRequest(http.MethodDelete, "/api/v1/provisionwatcher/name/w1", nil)
+	expectStatus(t, w, http.StatusOK, "delete provision watcher")
+	w = doRequest(http.MethodGet, "/api/v1/provisionwatcher/name/w1", nil)
+	expectStatus(t, w, http.StatusNotFound, "get deleted provision watcher")
+}
+
+func TestReadMaxLimit(t *testing.T) {
+	resetRepository()
+	defer func() { configuration.ReadMaxLimit = 100 }()
+
+	for i := 0; i < 3; i++ {
+		doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("a"+strconv.Itoa(i)))
+	}
+	configuration.ReadMaxLimit = 2
+	w := doRequest(http.MethodGet, "/api/v1/addressable", nil)
+	expectStatus(t, w, http.StatusRequestEntityTooLarge, "get addressables over the limit")
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	resetRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every name is added twice, only one of them may succeed
+			doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("a"+strconv.Itoa(i/2)))
+			doRequest(http.MethodGet, "/api/v1/addressable", nil)
+		}(i)
+	}
+	wg.Wait()
+
+	var as []models.Addressable
+	w := doRequest(http.MethodGet, "/api/v1/addressable", nil)
+	expectStatus(t, w, http.StatusOK, "get all addressables")
+	decodeResponse(t, w, &as)
+	if len(as) != 10 {
+		t.Errorf("Expected 10 addressables, got %d", len(as))
+	}
+}
+
+func TestValidateDeviceProfile(t *testing.T) {
+	resetRepository()
+
+	var l profileLint
+	w := doRequest(http.MethodPost, "/api/v1/deviceprofile/validate", lintTestProfile())
+	expectStatus(t, w, http.StatusOK, "validate profile")
+	decodeResponse(t, w, &l)
+	if !l.Valid || len(l.Errors) != 0 || len(l.Warnings) != 0 {
+		t.Errorf("Expected a valid profile, got %+v", l)
+	}
+
+	yaml := "name: broken\ndeviceResources:\n- name: temperature\n  properties:\n    value: {type: Float}\n" +
+		"resources:\n- name: temperature\n  get:\n  - {operation: get, object: humidity}\n"
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/deviceprofile/validate", bytes.NewBufferString(yaml))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	w = httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusOK, "validate yaml profile")
+	decodeResponse(t, w, &l)
+	if l.Valid || len(l.Errors) != 1 || l.Errors[0].Field != "resources[0].get[0].object" {
+		t.Errorf("Expected the unknown object error, got %+v", l)
+	}
+
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile/validate", "{")
+	expectStatus(t, w, http.StatusBadRequest, "validate malformed profile")
+
+	// Validating doesn't add the profile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/thermostat", nil)
+	expectStatus(t, w, http.StatusNotFound, "get validated profile")
+}
+
+func TestStrictProfileValidation(t *testing.T) {
+	resetRepository()
+	defer func() { configuration.StrictProfileValidation = false }()
+
+	dp := lintTestProfile()
+	dp.Resources[0].Get[0].Object = "humidity"
+	w := doRequest(http.MethodPost, "/api/v1/deviceprofile", dp)
+	expectStatus(t, w, http.StatusOK, "add profile with lint errors")
+
+	configuration.StrictProfileValidation = true
+	dp.Name = "strict"
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile", dp)
+	expectStatus(t, w, http.StatusBadRequest, "add profile with lint errors in strict mode")
+	var l profileLint
+	decodeResponse(t, w, &l)
+	if l.Valid || len(l.Errors) != 1 {
+		t.Errorf("Expected the lint errors, got %+v", l)
+	}
+
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile/upload", "name: strict-yaml\ncommands:\n- name: c1\n- name: \"\"\n")
+	expectStatus(t, w, http.StatusBadRequest, "upload profile with lint errors in strict mode")
+
+	// The update is checked before the profile is changed
+	update := models.DeviceProfile{Name: "thermostat", Resources: []models.ProfileResource{{Name: "temperature", Get: []models.ResourceOperation{{Object: "temperature"}}}}}
+	w = doRequest(http.MethodPut, "/api/v1/deviceprofile", update)
+	expectStatus(t, w, http.StatusOK, "fix profile in strict mode")
+	update.Resources[0].Get[0].Object = "pressure"
+	w = doRequest(http.MethodPut, "/api/v1/deviceprofile", update)
+	expectStatus(t, w, http.StatusBadRequest, "break profile in strict mode")
+
+	var stored models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/thermostat", nil)
+	decodeResponse(t, w, &stored)
+	if len(stored.Resources) != 1 || stored.Resources[0].Get[0].Object != "temperature" {
+		t.Errorf("Rejected update was stored: %+v", stored.Resources)
+	}
+}
+
+func TestDeviceProfileRevisions(t *testing.T) {
+	resetRepository()
+
+	w := doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("p1", "c1", "c2"))
+	expectStatus(t, w, http.StatusOK, "add profile")
+	id := w.Body.String()
+
+	// Replace the commands, the old ones are deleted once the profile is stored
+	update := models.DeviceProfile{Name: "p1", Model: "M2", Commands: []models.Command{{Name: "c3"}}}
+	data, _ := json.Marshal(update)
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/deviceprofile", bytes.NewReader(data))
+	req.Header.Set("From", "operator@example.com")
+	w = httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusOK, "update profile commands")
+	w = doRequest(http.MethodGet, "/api/v1/command/name/c1", nil)
+	var commands []models.Command
+	decodeResponse(t, w, &commands)
+	if len(commands) != 0 {
+		t.Errorf("Replaced commands weren't deleted: %+v", commands)
+	}
+
+	var revisions []models.DeviceProfileRevision
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/"+id+"/revision", nil)
+	expectStatus(t, w, http.StatusOK, "get revisions")
+	decodeResponse(t, w, &revisions)
+	if len(revisions) != 2 || revisions[0].Action != revisionCreate || revisions[1].Action != revisionUpdate {
+		t.Fatalf("Unexpected revisions %+v", revisions)
+	}
+	if revisions[1].Revision != 2 || revisions[1].Author != "operator@example.com" || revisions[1].Created == 0 {
+		t.Errorf("Unexpected update revision %+v", revisions[1])
+	}
+	var paths []string
+	for _, c := range revisions[1].Changes {
+		paths = append(paths, c.Path)
+	}
+	expected := []string{"commands[0].name", "commands[1]", "model"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected the changes of %v, got %v", expected, paths)
+	}
+
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/yaml/"+id+"/revision/1", nil)
+	expectStatus(t, w, http.StatusOK, "get revision yaml")
+	if !strings.Contains(w.Body.String(), "model: M-p1") {
+		t.Errorf("Unexpected revision yaml %s", w.Body.String())
+	}
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/"+id+"/revision/9", nil)
+	expectStatus(t, w, http.StatusNotFound, "get unknown revision")
+
+	var rollback models.DeviceProfileRevision
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile/"+id+"/revision/1/rollback", nil)
+	expectStatus(t, w, http.StatusOK, "roll back profile")
+	decodeResponse(t, w, &rollback)
+	if rollback.Revision != 3 || rollback.Action != revisionRollback {
+		t.Errorf("Unexpected rollback revision %+v", rollback)
+	}
+	var dp models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/"+id, nil)
+	decodeResponse(t, w, &dp)
+	if dp.Model != "M-p1" || len(dp.Commands) != 2 || dp.Commands[1].Name != "c2" || !dp.Commands[1].Id.Valid() {
+		t.Errorf("Profile wasn't rolled back: %+v", dp)
+	}
+
+	w = doRequest(http.MethodDelete, "/api/v1/deviceprofile/id/"+id, nil)
+	expectStatus(t, w, http.StatusOK, "delete profile")
+	revisions = nil
+	repository.GetDeviceProfileRevisions(&revisions, id)
+	if len(revisions) != 0 {
+		t.Errorf("Revisions of the deleted profile were kept: %+v", revisions)
+	}
+}
+
+func TestDeviceProfileRevisionPin(t *testing.T) {
+	resetRepository()
+	id := addTestResources(t)
+
+	var dp models.DeviceProfile
+	w := doRequest(http.MethodGet, "/api/v1/deviceprofile/name/test-profile", nil)
+	decodeResponse(t, w, &dp)
+	w = doRequest(http.MethodPut, "/api/v1/deviceprofile", models.DeviceProfile{Name: "test-profile", Model: "M2"})
+	expectStatus(t, w, http.StatusOK, "update profile")
+
+	w = doRequest(http.MethodPut, "/api/v1/device/"+id+"/profilerevision/5", nil)
+	expectStatus(t, w, http.StatusConflict, "pin unknown revision")
+	w = doRequest(http.MethodPut, "/api/v1/device/name/test-device/profilerevision/1", nil)
+	expectStatus(t, w, http.StatusOK, "pin revision")
+
+	var device models.Device
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	decodeResponse(t, w, &device)
+	if device.ProfileRevision != 1 || device.Profile.Model != "M-test-profile" || device.Profile.Id != dp.Id ||
+		len(device.Profile.Commands) != 2 || device.Profile.Commands[0].Id != dp.Commands[0].Id {
+		t.Errorf("Device doesn't use the pinned profile: %+v", device)
+	}
+
+	w = doRequest(http.MethodPut, "/api/v1/device/"+id+"/profilerevision/0", nil)
+	expectStatus(t, w, http.StatusOK, "unpin revision")
+	device = models.Device{}
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	decodeResponse(t, w, &device)
+	if device.ProfileRevision != 0 || device.Profile.Model != "M2" {
+		t.Errorf("Device doesn't follow the latest profile: %+v", device)
+	}
+
+	d := testDevice("pinned-device")
+	d.ProfileRevision = 3
+	w = doRequest(http.MethodPost, "/api/v1/device", d)
+	expectStatus(t, w, http.StatusConflict, "add device pinned to an unknown revision")
+
+	// The pinned device loses the commands removed from the profile, they can't be pinned any more
+	w = doRequest(http.MethodPut, "/api/v1/device/"+id+"/profilerevision/2", nil)
+	expectStatus(t, w, http.StatusOK, "pin revision with the commands")
+	update := models.DeviceProfile{Name: "test-profile", Commands: []models.Command{{Name: "temperature"}}}
+	w = doRequest(http.MethodPut, "/api/v1/deviceprofile", update)
+	expectStatus(t, w, http.StatusOK, "remove a command")
+	device = models.Device{}
+	w = doRequest(http.MethodGet, "/api/v1/device/"+id, nil)
+	decodeResponse(t, w, &device)
+	if device.ProfileRevision != 2 || len(device.Profile.Commands) != 1 ||
+		device.Profile.Commands[0].Name != "temperature" || !device.Profile.Commands[0].Id.Valid() {
+		t.Errorf("Device uses a deleted command: %+v", device)
+	}
+	w = doRequest(http.MethodPut, "/api/v1/device/"+id+"/profilerevision/1", nil)
+	expectStatus(t, w, http.StatusConflict, "pin revision with a deleted command")
+	if !strings.Contains(w.Body.String(), "humidity") {
+		t.Errorf("Unexpected pin error %s", w.Body.String())
+	}
+}
+
+// A site with every kind of resource, the schedule event runs on the test device service
+func testBundle() bundle {
+	ds := testServiceRef()
+	ds.Labels = []string{"site"}
+	ds.Addressable = models.Addressable{Name: "service-address"}
+	dp := testProfile("test-profile", "temperature", "humidity")
+	d := testDevice("test-device")
+	d.Labels = []string{"site"}
+	return bundle{
+		Addressables:   []models.Addressable{testAddressable("service-address"), testAddressable("device-address")},
+		DeviceServices: []models.DeviceService{ds},
+		DeviceProfiles: []models.DeviceProfile{dp},
+		Devices:        []models.Device{d},
+		Schedules:      []models.Schedule{{Name: "s1", Frequency: "PT1M"}},
+		ScheduleEvents: []models.ScheduleEvent{{Name: "e1", Schedule: "s1", Service: "test-service", Addressable: models.Addressable{Name: "service-address"}}},
+	}
+}
+
+func bundleActions(result bundleResult) map[string]string {
+	actions := map[string]string{}
+	for _, a := range result.Actions {
+		actions[a.Kind+" "+a.Name] = a.Action
+	}
+	return actions
+}
+
+// Fails to add devices, for the rollback of the bundle imports
+type failingRepository struct {
+	Repository
+}
+
+func (r failingRepository) AddDevice(d *models.Device) error {
+	return fmt.Errorf("device %s not added", d.Name)
+}
+
+func TestImportBundle(t *testing.T) {
+	resetRepository()
+
+	var result bundleResult
+	w := doRequest(http.MethodPost, "/api/v1/bundle", testBundle())
+	expectStatus(t, w, http.StatusOK, "import bundle")
+	decodeResponse(t, w, &result)
+	if len(result.Actions) != 7 || result.Actions[0].Kind != ADDRESSABLE || result.Actions[6].Kind != SCHEDULEEVENT {
+		t.Fatalf("Unexpected actions %+v", result.Actions)
+	}
+	for _, a := range result.Actions {
+		if a.Action != bundleCreate {
+			t.Errorf("Expected %s %s to be created, got %s", a.Kind, a.Name, a.Action)
+		}
+	}
+
+	var device models.Device
+	w = doRequest(http.MethodGet, "/api/v1/device/name/test-device", nil)
+	expectStatus(t, w, http.StatusOK, "get imported device")
+	decodeResponse(t, w, &device)
+	if device.Service.Service.Name != "test-service" || len(device.Profile.Commands) != 2 || !device.Addressable.Id.Valid() {
+		t.Errorf("Unexpected imported device %+v", device)
+	}
+	var events []models.ScheduleEvent
+	w = doRequest(http.MethodGet, "/api/v1/scheduleevent", nil)
+	decodeResponse(t, w, &events)
+	if len(events) != 1 || events[0].Addressable.Protocol != "http" {
+		t.Errorf("Unexpected imported schedule events %+v", events)
+	}
+
+	// Applying the bundle again changes nothing
+	result = bundleResult{}
+	w = doRequest(http.MethodPost, "/api/v1/bundle", testBundle())
+	expectStatus(t, w, http.StatusOK, "import bundle again")
+	decodeResponse(t, w, &result)
+	for _, a := range result.Actions {
+		if a.Action != bundleUnchanged {
+			t.Errorf("Expected %s %s to be unchanged, got %s", a.Kind, a.Name, a.Action)
+		}
+	}
+
+	// A dry run reports the changes without making them
+	b := testBundle()
+	b.Devices[0].Description = "changed"
+	b.DeviceProfiles[0].Model = "M2"
+	b.Addressables = append(b.Addressables, testAddressable("new-address"))
+	result = bundleResult{}
+	w = doRequest(http.MethodPost, "/api/v1/bundle?dryrun=true", b)
+	expectStatus(t, w, http.StatusOK, "dry run")
+	decodeResponse(t, w, &result)
+	actions := bundleActions(result)
+	if !result.DryRun || actions["device test-device"] != bundleUpdate || actions["deviceprofile test-profile"] != bundleUpdate ||
+		actions["addressable new-address"] != bundleCreate || actions["deviceservice test-service"] != bundleUnchanged {
+		t.Errorf("Unexpected dry run actions %+v", result)
+	}
+	w = doRequest(http.MethodGet, "/api/v1/addressable/name/new-address", nil)
+	expectStatus(t, w, http.StatusNotFound, "get addressable of the dry run")
+
+	result = bundleResult{}
+	w = doRequest(http.MethodPost, "/api/v1/bundle", b)
+	expectStatus(t, w, http.StatusOK, "import changed bundle")
+	decodeResponse(t, w, &result)
+	device = models.Device{}
+	w = doRequest(http.MethodGet, "/api/v1/device/name/test-device", nil)
+	decodeResponse(t, w, &device)
+	if device.Description != "changed" || device.Profile.Model != "M2" {
+		t.Errorf("Device wasn't updated: %+v", device)
+	}
+	var revisions []models.DeviceProfileRevision
+	repository.GetDeviceProfileRevisions(&revisions, device.Profile.Id.Hex())
+	if len(revisions) != 2 || revisions[1].Action != revisionUpdate {
+		t.Errorf("Unexpected profile revisions %+v", revisions)
+	}
+	var commands []models.Command
+	w = doRequest(http.MethodGet, "/api/v1/command", nil)
+	decodeResponse(t, w, &commands)
+	if len(commands) != 2 {
+		t.Errorf("Replaced commands weren't deleted: %+v", commands)
+	}
+
+	b = testBundle()
+	b.Devices[0].Profile.Name = "unknown-profile"
+	b.ScheduleEvents[0].Schedule = ""
+	w = doRequest(http.MethodPost, "/api/v1/bundle", b)
+	expectStatus(t, w, http.StatusBadRequest, "import bundle with broken references")
+	if !strings.Contains(w.Body.String(), "unknown deviceprofile unknown-profile") {
+		t.Errorf("Unexpected validation error %s", w.Body.String())
+	}
+	w = doRequest(http.MethodPost, "/api/v1/bundle", "{")
+	expectStatus(t, w, http.StatusBadRequest, "import invalid JSON")
+}
+
+func TestImportBundleRollback(t *testing.T) {
+	resetRepository()
+	w := doRequest(http.MethodPost, "/api/v1/addressable", testAddressable("service-address"))
+	expectStatus(t, w, http.StatusOK, "add addressable")
+
+	b := testBundle()
+	b.Addressables[0].Path = "/changed"
+	repository = failingRepository{repository}
+	var result bundleResult
+	w = doRequest(http.MethodPost, "/api/v1/bundle", b)
+	expectStatus(t, w, http.StatusServiceUnavailable, "import bundle")
+	decodeResponse(t, w, &result)
+	if !result.RolledBack || !strings.Contains(result.Error, "device test-device not added") {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	var a models.Addressable
+	w = doRequest(http.MethodGet, "/api/v1/addressable/name/service-address", nil)
+	decodeResponse(t, w, &a)
+	if a.Path != "/callback" {
+		t.Errorf("Addressable update wasn't undone: %+v", a)
+	}
+	for _, path := range []string{"/api/v1/addressable/name/device-address", "/api/v1/deviceservice/name/test-service", "/api/v1/deviceprofile/name/test-profile"} {
+		w = doRequest(http.MethodGet, path, nil)
+		expectStatus(t, w, http.StatusNotFound, "get "+path)
+	}
+	var commands []models.Command
+	w = doRequest(http.MethodGet, "/api/v1/command", nil)
+	decodeResponse(t, w, &commands)
+	if len(commands) != 0 {
+		t.Errorf("Commands of the profile weren't deleted: %+v", commands)
+	}
+}
+
+func TestImportBundleProfileRollback(t *testing.T) {
+	resetRepository()
+	w := doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("test-profile", "temperature", "humidity"))
+	expectStatus(t, w, http.StatusOK, "add profile")
+	var stored models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/test-profile", nil)
+	decodeResponse(t, w, &stored)
+
+	b := testBundle()
+	b.DeviceProfiles[0].Model = "M2"
+	repository = failingRepository{repository}
+	w = doRequest(http.MethodPost, "/api/v1/bundle", b)
+	expectStatus(t, w, http.StatusServiceUnavailable, "import bundle")
+
+	// The profile is back with its commands and without the revisions of the import
+	var dp models.DeviceProfile
+	w = doRequest(http.MethodGet, "/api/v1/deviceprofile/name/test-profile", nil)
+	decodeResponse(t, w, &dp)
+	if dp.Model != "M-test-profile" || len(dp.Commands) != 2 ||
+		dp.Commands[0].Id != stored.Commands[0].Id || dp.Commands[1].Id != stored.Commands[1].Id {
+		t.Errorf("Profile update wasn't undone: %+v", dp)
+	}
+	var commands []models.Command
+	w = doRequest(http.MethodGet, "/api/v1/command", nil)
+	decodeResponse(t, w, &commands)
+	if len(commands) != 2 {
+		t.Errorf("Commands of the import weren't deleted: %+v", commands)
+	}
+	var revisions []models.DeviceProfileRevision
+	repository.GetDeviceProfileRevisions(&revisions, stored.Id.Hex())
+	if len(revisions) != 1 || revisions[0].Action != revisionCreate {
+		t.Errorf("Unexpected profile revisions %+v", revisions)
+	}
+}
+
+func TestExportBundle(t *testing.T) {
+	resetRepository()
+	w := doRequest(http.MethodPost, "/api/v1/bundle", testBundle())
+	expectStatus(t, w, http.StatusOK, "import bundle")
+	w = doRequest(http.MethodPost, "/api/v1/deviceprofile", testProfile("other-profile"))
+	expectStatus(t, w, http.StatusOK, "add profile")
+
+	var b bundle
+	w = doRequest(http.MethodGet, "/api/v1/bundle", nil)
+	expectStatus(t, w, http.StatusOK, "export bundle")
+	decodeResponse(t, w, &b)
+	if len(b.Addressables) != 2 || len(b.DeviceProfiles) != 2 || len(b.Devices) != 1 || len(b.ScheduleEvents) != 1 {
+		t.Errorf("Unexpected export %+v", b)
+	}
+
+	// The devices with the label come with their service, profile, addressables and schedules
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/bundle/label/site", http.NoBody)
+	req.Header.Set("Accept", "application/x-yaml")
+	w = httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusOK, "export bundle with label")
+	exported := w.Body.String()
+	if strings.Contains(exported, "other-profile") || strings.Contains(exported, "created") || !strings.Contains(exported, "port: "+strconv.Itoa(testAddressable("").Port)) {
+		t.Errorf("Unexpected export %s", exported)
+	}
+
+	resetRepository()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/bundle", strings.NewReader(exported))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	w = httptest.NewRecorder()
+	testRoutes.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusOK, "import exported bundle")
+	var result bundleResult
+	decodeResponse(t, w, &result)
+	actions := bundleActions(result)
+	if len(actions) != 7 || actions["scheduleevent e1"] != bundleCreate || actions["device test-device"] != bundleCreate {
+		t.Errorf("Unexpected actions %+v", result.Actions)
+	}
+}
@@ -0,0 +1,1374 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+/*
+ * SQL database of a type, registered by sql_mysql.go and sql_sqlite.go
+ */
+type sqlDialect struct {
+	driver       string                  // Name of the database/sql driver
+	dataSource   func(dsn string) string // Data source name with the options needed by the repository
+	maxOpenConns int                     // 0 for no limit
+	// Violation of a foreign key constraint
+	isForeignKeyViolation func(err error) bool
+}
+
+var sqlDialects = map[enums.DATABASE]sqlDialect{}
+
+// Repository stored in a SQL database, see sql_schema.go for the tables
+type sqlRepository struct {
+	dialect    sqlDialect
+	dataSource string
+	db         *sql.DB
+}
+
+// Repository for the database type, not connected yet
+func newSqlRepository(database enums.DATABASE, dataSource string) (Repository, error) {
+	dialect, ok := sqlDialects[database]
+	if !ok {
+		return nil, errors.New("Database type " + database.String() + " is not supported by this build")
+	}
+	return &sqlRepository{dialect: dialect, dataSource: dataSource}, nil
+}
+
+// Collections of the metadata and their tables
+var sqlTables = map[string]string{
+	ADDCOL:    "addressables",
+	DSCOL:     "device_services",
+	DPCOL:     "device_profiles",
+	COMCOL:    "commands",
+	DEVICECOL: "devices",
+	SCOL:      "schedules",
+	SECOL:     "schedule_events",
+	DRCOL:     "device_reports",
+	PWCOL:     "provision_watchers",
+}
+
+// Fields set by SetById and SetByName and their columns
+var sqlColumns = map[string]string{
+	ADMINSTATE:     "admin_state",
+	OPERATINGSTATE: "operating_state",
+	LASTCONNECTED:  "last_connected",
+	LASTREPORTED:   "last_reported",
+}
+
+const sqlOrder = " ORDER BY created, id"
+
+// Queries and statements run by the repository, a *sql.DB or a *sql.Tx
+type sqlQuerier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (r *sqlRepository) Connect() error {
+	db, err := sql.Open(r.dialect.driver, r.dialect.dataSource(r.dataSource))
+	if err != nil {
+		return err
+	}
+	if r.dialect.maxOpenConns > 0 {
+		db.SetMaxOpenConns(r.dialect.maxOpenConns)
+	}
+
+	// Create the tables missing
+	for _, statement := range sqlSchema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return err
+		}
+	}
+	r.db = db
+	return nil
+}
+
+func (r *sqlRepository) Ping() error {
+	if r.db == nil {
+		return errors.New("not connected to the database")
+	}
+	return r.db.Ping()
+}
+
+func (r *sqlRepository) Close() error {
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Close()
+}
+
+// Run f in a transaction, committed when f succeeds
+func (r *sqlRepository) transaction(f func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Run the query and scan each row, the rows are closed on return
+func (r *sqlRepository) query(scan func(rows *sql.Rows) error, query string, args ...interface{}) error {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *sqlRepository) count(query string, args ...interface{}) (int, error) {
+	var count int
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// ErrDuplicateName when the name is already used in the table
+func (r *sqlRepository) checkName(table string, n string) error {
+	count, err := r.count("SELECT COUNT(*) FROM "+table+" WHERE name = ?", n)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateName
+	}
+	return nil
+}
+
+// ErrStillInUse when a statement removing rows violates a foreign key
+func (r *sqlRepository) deleteError(err error) error {
+	if err != nil && r.dialect.isForeignKeyViolation(err) {
+		return ErrStillInUse
+	}
+	return err
+}
+
+// ErrNotFound when the statement didn't affect any row
+func notFound(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// The IDs are stored in hexadecimal, a reference is NULL when the ID is not set
+func sqlId(id bson.ObjectId) string {
+	return id.Hex()
+}
+func sqlRef(id bson.ObjectId) interface{} {
+	if id == "" {
+		return nil
+	}
+	return id.Hex()
+}
+func sqlNameRef(n string) interface{} {
+	if n == "" {
+		return nil
+	}
+	return n
+}
+func objectId(id string) bson.ObjectId {
+	if !bson.IsObjectIdHex(id) {
+		return ""
+	}
+	return bson.ObjectIdHex(id)
+}
+
+// Nested values are stored encoded in BSON, as they are in mongo
+func bsonValue(v interface{}) ([]byte, error) {
+	return bson.Marshal(bson.M{"value": v})
+}
+func setBsonValue(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var doc struct {
+		Value bson.Raw `bson:"value"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return doc.Value.Unmarshal(v)
+}
+
+// Placeholders of the values for an IN clause
+func sqlIn(values []string) (string, []interface{}) {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", args
+}
+
+/* ----------------------------- Labels ----------------------------------*/
+// Labels of the owner, in the order they were given
+func (r *sqlRepository) labels(table string, owner string) ([]string, error) {
+	labels := []string{}
+	err := r.query(func(rows *sql.Rows) error {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return err
+		}
+		labels = append(labels, label)
+		return nil
+	}, "SELECT label FROM "+table+" WHERE owner_id = ? ORDER BY position", owner)
+	return labels, err
+}
+func setLabels(tx sqlQuerier, table string, owner string, labels []string) error {
+	if _, err := tx.Exec("DELETE FROM "+table+" WHERE owner_id = ?", owner); err != nil {
+		return err
+	}
+	for i, label := range labels {
+		if _, err := tx.Exec("INSERT INTO "+table+" (owner_id, position, label) VALUES (?, ?, ?)", owner, i, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Condition on the owners with one of the labels
+func withLabel(table string, l []string) (string, []interface{}) {
+	in, args := sqlIn(l)
+	if len(l) == 0 {
+		return "WHERE 1 = 0", nil
+	}
+	return "WHERE id IN (SELECT owner_id FROM " + table + " WHERE label IN " + in + ")", args
+}
+
+/* -----------------------------------Addressable --------------------------*/
+const addressableColumns = "id, created, modified, origin, name, protocol, method, address, port, path, publisher, user_name, password, topic"
+
+func (r *sqlRepository) addressables(where string, args ...interface{}) ([]models.Addressable, error) {
+	var as []models.Addressable
+	err := r.query(func(rows *sql.Rows) error {
+		var a models.Addressable
+		var id string
+		if err := rows.Scan(&id, &a.Created, &a.Modified, &a.Origin, &a.Name, &a.Protocol, &a.HTTPMethod, &a.Address,
+			&a.Port, &a.Path, &a.Publisher, &a.User, &a.Password, &a.Topic); err != nil {
+			return err
+		}
+		a.Id = objectId(id)
+		as = append(as, a)
+		return nil
+	}, "SELECT "+addressableColumns+" FROM addressables "+where+sqlOrder, args...)
+	return as, err
+}
+func (r *sqlRepository) addressable(a *models.Addressable, where string, args ...interface{}) error {
+	as, err := r.addressables(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(as) == 0 {
+		return ErrNotFound
+	}
+	*a = as[0]
+	return nil
+}
+
+// Addressable referenced by a row, empty when the reference is NULL
+func (r *sqlRepository) addressableRef(id sql.NullString) (models.Addressable, error) {
+	var a models.Addressable
+	if !id.Valid {
+		return a, nil
+	}
+	err := r.addressable(&a, "WHERE id = ?", id.String)
+	return a, err
+}
+func (r *sqlRepository) UpdateAddressable(a *models.Addressable) error {
+	_, err := r.db.Exec("UPDATE addressables SET created = ?, modified = ?, origin = ?, name = ?, protocol = ?, method = ?, address = ?, "+
+		"port = ?, path = ?, publisher = ?, user_name = ?, password = ?, topic = ? WHERE id = ?",
+		a.Created, a.Modified, a.Origin, a.Name, a.Protocol, a.HTTPMethod, a.Address,
+		a.Port, a.Path, a.Publisher, a.User, a.Password, a.Topic, sqlId(a.Id))
+	return err
+}
+func (r *sqlRepository) AddAddressable(a *models.Addressable) error {
+	if err := r.checkName("addressables", a.Name); err != nil {
+		return err
+	}
+	a.Created = makeTimestamp()
+	a.Id = bson.NewObjectId()
+	_, err := r.db.Exec("INSERT INTO addressables ("+addressableColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sqlId(a.Id), a.Created, a.Modified, a.Origin, a.Name, a.Protocol, a.HTTPMethod, a.Address,
+		a.Port, a.Path, a.Publisher, a.User, a.Password, a.Topic)
+	return err
+}
+func (r *sqlRepository) GetAddressableById(a *models.Addressable, id string) error {
+	return r.addressable(a, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetAddressableByName(a *models.Addressable, n string) error {
+	return r.addressable(a, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetAllAddressables(a *[]models.Addressable) error {
+	as, err := r.addressables("")
+	*a = append(*a, as...)
+	return err
+}
+func (r *sqlRepository) GetAddressablesByTopic(a *[]models.Addressable, t string) error {
+	as, err := r.addressables("WHERE topic = ?", t)
+	*a = append(*a, as...)
+	return err
+}
+func (r *sqlRepository) GetAddressablesByPort(a *[]models.Addressable, p int) error {
+	as, err := r.addressables("WHERE port = ?", p)
+	*a = append(*a, as...)
+	return err
+}
+func (r *sqlRepository) GetAddressablesByPublisher(a *[]models.Addressable, p string) error {
+	as, err := r.addressables("WHERE publisher = ?", p)
+	*a = append(*a, as...)
+	return err
+}
+func (r *sqlRepository) GetAddressablesByAddress(a *[]models.Addressable, add string) error {
+	as, err := r.addressables("WHERE address = ?", add)
+	*a = append(*a, as...)
+	return err
+}
+func (r *sqlRepository) IsAddressableAssociatedToDevice(a models.Addressable) (bool, error) {
+	count, err := r.count("SELECT COUNT(*) FROM devices WHERE addressable_id = ?", sqlId(a.Id))
+	return count > 0, err
+}
+func (r *sqlRepository) IsAddressableAssociatedToDeviceService(a models.Addressable) (bool, error) {
+	count, err := r.count("SELECT COUNT(*) FROM device_services WHERE addressable_id = ?", sqlId(a.Id))
+	return count > 0, err
+}
+
+/* ----------------------------- Device Service ----------------------------------*/
+const deviceServiceColumns = "id, created, modified, origin, description, name, last_connected, last_reported, operating_state, admin_state, addressable_id"
+
+func (r *sqlRepository) deviceServices(where string, args ...interface{}) ([]models.DeviceService, error) {
+	var dss []models.DeviceService
+	var addressables []sql.NullString
+	err := r.query(func(rows *sql.Rows) error {
+		var ds models.DeviceService
+		var id string
+		var addressable sql.NullString
+		if err := rows.Scan(&id, &ds.Service.Created, &ds.Service.Modified, &ds.Service.Origin, &ds.Service.Description, &ds.Service.Name,
+			&ds.Service.LastConnected, &ds.Service.LastReported, &ds.Service.OperatingState, &ds.AdminState, &addressable); err != nil {
+			return err
+		}
+		ds.Service.Id = objectId(id)
+		dss = append(dss, ds)
+		addressables = append(addressables, addressable)
+		return nil
+	}, "SELECT "+deviceServiceColumns+" FROM device_services "+where+sqlOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the references once the rows are closed
+	for i := range dss {
+		if dss[i].Service.Addressable, err = r.addressableRef(addressables[i]); err != nil {
+			return nil, err
+		}
+		if dss[i].Service.Labels, err = r.labels("device_service_labels", sqlId(dss[i].Service.Id)); err != nil {
+			return nil, err
+		}
+	}
+	return dss, nil
+}
+func (r *sqlRepository) deviceService(ds *models.DeviceService, where string, args ...interface{}) error {
+	dss, err := r.deviceServices(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(dss) == 0 {
+		return ErrNotFound
+	}
+	*ds = dss[0]
+	return nil
+}
+func (r *sqlRepository) deviceServiceRef(id sql.NullString) (models.DeviceService, error) {
+	var ds models.DeviceService
+	if !id.Valid {
+		return ds, nil
+	}
+	err := r.deviceService(&ds, "WHERE id = ?", id.String)
+	return ds, err
+}
+func (r *sqlRepository) UpdateDeviceService(ds models.DeviceService) error {
+	ds.Service.Modified = makeTimestamp()
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE device_services SET created = ?, modified = ?, origin = ?, description = ?, name = ?, last_connected = ?, "+
+			"last_reported = ?, operating_state = ?, admin_state = ?, addressable_id = ? WHERE id = ?",
+			ds.Service.Created, ds.Service.Modified, ds.Service.Origin, ds.Service.Description, ds.Service.Name, ds.Service.LastConnected,
+			ds.Service.LastReported, string(ds.Service.OperatingState), string(ds.AdminState), sqlRef(ds.Service.Addressable.Id), sqlId(ds.Service.Id))
+		if err != nil {
+			return err
+		}
+		return setLabels(tx, "device_service_labels", sqlId(ds.Service.Id), ds.Service.Labels)
+	})
+}
+func (r *sqlRepository) GetDeviceServicesByAddressableName(d *[]models.DeviceService, n string) error {
+	dss, err := r.deviceServices("WHERE addressable_id IN (SELECT id FROM addressables WHERE name = ?)", n)
+	*d = append(*d, dss...)
+	return err
+}
+func (r *sqlRepository) GetDeviceServicesByAddressableId(d *[]models.DeviceService, id string) error {
+	dss, err := r.deviceServices("WHERE addressable_id = ?", id)
+	*d = append(*d, dss...)
+	return err
+}
+func (r *sqlRepository) GetDeviceServicesWithLabel(d *[]models.DeviceService, l []string) error {
+	where, args := withLabel("device_service_labels", l)
+	dss, err := r.deviceServices(where, args...)
+	*d = append(*d, dss...)
+	return err
+}
+func (r *sqlRepository) GetDeviceServiceById(d *models.DeviceService, id string) error {
+	return r.deviceService(d, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetDeviceServiceByName(d *models.DeviceService, n string) error {
+	return r.deviceService(d, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetAllDeviceServices(d *[]models.DeviceService) error {
+	dss, err := r.deviceServices("")
+	*d = append(*d, dss...)
+	return err
+}
+func (r *sqlRepository) AddDeviceService(ds *models.DeviceService) error {
+	if err := r.checkName("device_services", ds.Service.Name); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	ds.Service.Created = ts
+	ds.Service.Modified = ts
+	ds.Service.Id = bson.NewObjectId()
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO device_services ("+deviceServiceColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			sqlId(ds.Service.Id), ds.Service.Created, ds.Service.Modified, ds.Service.Origin, ds.Service.Description, ds.Service.Name,
+			ds.Service.LastConnected, ds.Service.LastReported, string(ds.Service.OperatingState), string(ds.AdminState), sqlRef(ds.Service.Addressable.Id))
+		if err != nil {
+			return err
+		}
+		return setLabels(tx, "device_service_labels", sqlId(ds.Service.Id), ds.Service.Labels)
+	})
+}
+
+/* ------------------------Command -------------------------------------*/
+const commandColumns = "c.id, c.created, c.modified, c.origin, c.name, c.get_command, c.put_command"
+
+func (r *sqlRepository) commands(query string, args ...interface{}) ([]models.Command, error) {
+	var cs []models.Command
+	err := r.query(func(rows *sql.Rows) error {
+		var c models.Command
+		var id string
+		var get, put []byte
+		if err := rows.Scan(&id, &c.Created, &c.Modified, &c.Origin, &c.Name, &get, &put); err != nil {
+			return err
+		}
+		c.Id = objectId(id)
+		if err := setBsonValue(get, &c.Get); err != nil {
+			return err
+		}
+		if err := setBsonValue(put, &c.Put); err != nil {
+			return err
+		}
+		cs = append(cs, c)
+		return nil
+	}, query, args...)
+	return cs, err
+}
+func insertCommand(tx sqlQuerier, c *models.Command) error {
+	c.Created = makeTimestamp()
+	c.Id = bson.NewObjectId()
+	get, err := bsonValue(c.Get)
+	if err != nil {
+		return err
+	}
+	put, err := bsonValue(c.Put)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO commands (id, created, modified, origin, name, get_command, put_command) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		sqlId(c.Id), c.Created, c.Modified, c.Origin, c.Name, get, put)
+	return err
+}
+func (r *sqlRepository) GetCommandById(c *models.Command, id string) error {
+	cs, err := r.commands("SELECT "+commandColumns+" FROM commands c WHERE c.id = ?", id)
+	if err != nil {
+		return err
+	}
+	if len(cs) == 0 {
+		return ErrNotFound
+	}
+	*c = cs[0]
+	return nil
+}
+func (r *sqlRepository) GetCommandByName(c *[]models.Command, n string) error {
+	cs, err := r.commands("SELECT "+commandColumns+" FROM commands c WHERE c.name = ? ORDER BY c.created, c.id", n)
+	*c = append(*c, cs...)
+	return err
+}
+func (r *sqlRepository) AddCommand(c *models.Command) error {
+	return insertCommand(r.db, c)
+}
+func (r *sqlRepository) GetAllCommands(c *[]models.Command) error {
+	cs, err := r.commands("SELECT " + commandColumns + " FROM commands c ORDER BY c.created, c.id")
+	*c = append(*c, cs...)
+	return err
+}
+func (r *sqlRepository) UpdateCommand(c *models.Command) error {
+	get, err := bsonValue(c.Get)
+	if err != nil {
+		return err
+	}
+	put, err := bsonValue(c.Put)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("UPDATE commands SET created = ?, modified = ?, origin = ?, name = ?, get_command = ?, put_command = ? WHERE id = ?",
+		c.Created, c.Modified, c.Origin, c.Name, get, put, sqlId(c.Id))
+	return err
+}
+func (r *sqlRepository) DeleteCommandById(id string) error {
+	// Check if the command is still in use
+	count, err := r.count("SELECT COUNT(*) FROM device_profile_commands WHERE command_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCommandStillInUse
+	}
+	result, err := r.db.Exec("DELETE FROM commands WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return notFound(result)
+}
+
+/* -----------------------------Device Profile -----------------------------*/
+const deviceProfileColumns = "id, created, modified, origin, description, name, manufacturer, model, objects, device_resources, resources"
+
+func (r *sqlRepository) deviceProfiles(where string, args ...interface{}) ([]models.DeviceProfile, error) {
+	var dps []models.DeviceProfile
+	err := r.query(func(rows *sql.Rows) error {
+		var dp models.DeviceProfile
+		var id string
+		var objects, deviceResources, resources []byte
+		if err := rows.Scan(&id, &dp.Created, &dp.Modified, &dp.Origin, &dp.Description, &dp.Name, &dp.Manufacturer, &dp.Model,
+			&objects, &deviceResources, &resources); err != nil {
+			return err
+		}
+		dp.Id = objectId(id)
+		if err := setBsonValue(objects, &dp.Objects); err != nil {
+			return err
+		}
+		if err := setBsonValue(deviceResources, &dp.DeviceResources); err != nil {
+			return err
+		}
+		if err := setBsonValue(resources, &dp.Resources); err != nil {
+			return err
+		}
+		dps = append(dps, dp)
+		return nil
+	}, "SELECT "+deviceProfileColumns+" FROM device_profiles "+where+sqlOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the references once the rows are closed
+	for i := range dps {
+		if dps[i].Labels, err = r.labels("device_profile_labels", sqlId(dps[i].Id)); err != nil {
+			return nil, err
+		}
+		dps[i].Commands, err = r.commands("SELECT "+commandColumns+" FROM commands c "+
+			"JOIN device_profile_commands pc ON pc.command_id = c.id WHERE pc.profile_id = ? ORDER BY pc.position", sqlId(dps[i].Id))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dps, nil
+}
+func (r *sqlRepository) deviceProfile(dp *models.DeviceProfile, where string, args ...interface{}) error {
+	dps, err := r.deviceProfiles(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(dps) == 0 {
+		return ErrNotFound
+	}
+	*dp = dps[0]
+	return nil
+}
+func (r *sqlRepository) deviceProfileRef(id sql.NullString) (models.DeviceProfile, error) {
+	var dp models.DeviceProfile
+	if !id.Valid {
+		return dp, nil
+	}
+	err := r.deviceProfile(&dp, "WHERE id = ?", id.String)
+	return dp, err
+}
+
+// Columns of the profile, its labels and the references to its commands
+func setDeviceProfile(tx sqlQuerier, dp *models.DeviceProfile, insert bool) error {
+	objects, err := bsonValue(dp.Objects)
+	if err != nil {
+		return err
+	}
+	deviceResources, err := bsonValue(dp.DeviceResources)
+	if err != nil {
+		return err
+	}
+	resources, err := bsonValue(dp.Resources)
+	if err != nil {
+		return err
+	}
+	if insert {
+		_, err = tx.Exec("INSERT INTO device_profiles ("+deviceProfileColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			sqlId(dp.Id), dp.Created, dp.Modified, dp.Origin, dp.Description, dp.Name, dp.Manufacturer, dp.Model,
+			objects, deviceResources, resources)
+	} else {
+		_, err = tx.Exec("UPDATE device_profiles SET created = ?, modified = ?, origin = ?, description = ?, name = ?, manufacturer = ?, "+
+			"model = ?, objects = ?, device_resources = ?, resources = ? WHERE id = ?",
+			dp.Created, dp.Modified, dp.Origin, dp.Description, dp.Name, dp.Manufacturer,
+			dp.Model, objects, deviceResources, resources, sqlId(dp.Id))
+	}
+	if err != nil {
+		return err
+	}
+	if err := setLabels(tx, "device_profile_labels", sqlId(dp.Id), dp.Labels); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM device_profile_commands WHERE profile_id = ?", sqlId(dp.Id)); err != nil {
+		return err
+	}
+	for i, c := range dp.Commands {
+		_, err := tx.Exec("INSERT INTO device_profile_commands (profile_id, position, command_id) VALUES (?, ?, ?)", sqlId(dp.Id), i, sqlId(c.Id))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (r *sqlRepository) UpdateDeviceProfile(dp *models.DeviceProfile) error {
+	dp.Modified = makeTimestamp()
+	return r.transaction(func(tx *sql.Tx) error {
+		return setDeviceProfile(tx, dp, false)
+	})
+}
+func (r *sqlRepository) AddDeviceProfile(dp *models.DeviceProfile) error {
+	if err := r.checkName("device_profiles", dp.Name); err != nil {
+		return err
+	}
+	return r.transaction(func(tx *sql.Tx) error {
+		for i := 0; i < len(dp.Commands); i++ {
+			if err := insertCommand(tx, &dp.Commands[i]); err != nil {
+				return err
+			}
+		}
+		ts := makeTimestamp()
+		dp.Created = ts
+		dp.Modified = ts
+		dp.Id = bson.NewObjectId()
+		return setDeviceProfile(tx, dp, true)
+	})
+}
+func (r *sqlRepository) GetAllDeviceProfiles(dp *[]models.DeviceProfile) error {
+	dps, err := r.deviceProfiles("")
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfileById(dp *models.DeviceProfile, id string) error {
+	return r.deviceProfile(dp, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetDeviceProfilesByModel(dp *[]models.DeviceProfile, m string) error {
+	dps, err := r.deviceProfiles("WHERE model = ?", m)
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfilesWithLabel(dp *[]models.DeviceProfile, l []string) error {
+	where, args := withLabel("device_profile_labels", l)
+	dps, err := r.deviceProfiles(where, args...)
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfilesByManufacturerModel(dp *[]models.DeviceProfile, man string, mod string) error {
+	dps, err := r.deviceProfiles("WHERE manufacturer = ? AND model = ?", man, mod)
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfilesByManufacturer(dp *[]models.DeviceProfile, man string) error {
+	dps, err := r.deviceProfiles("WHERE manufacturer = ?", man)
+	*dp = append(*dp, dps...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfileByName(dp *models.DeviceProfile, n string) error {
+	return r.deviceProfile(dp, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetDeviceProfilesUsingCommand(dp *[]models.DeviceProfile, c models.Command) error {
+	dps, err := r.deviceProfiles("WHERE id IN (SELECT profile_id FROM device_profile_commands WHERE command_id = ?)", sqlId(c.Id))
+	*dp = append(*dp, dps...)
+	return err
+}
+
+/* ------------------------Device Profile Revision -------------------------*/
+const deviceProfileRevisionColumns = "id, created, modified, origin, profile_id, revision, action, author, changes, profile"
+
+func (r *sqlRepository) deviceProfileRevisions(where string, args ...interface{}) ([]models.DeviceProfileRevision, error) {
+	var dprs []models.DeviceProfileRevision
+	err := r.query(func(rows *sql.Rows) error {
+		var dpr models.DeviceProfileRevision
+		var id, pid string
+		var changes, profile []byte
+		if err := rows.Scan(&id, &dpr.Created, &dpr.Modified, &dpr.Origin, &pid, &dpr.Revision, &dpr.Action, &dpr.Author,
+			&changes, &profile); err != nil {
+			return err
+		}
+		dpr.Id = objectId(id)
+		dpr.ProfileId = objectId(pid)
+		if err := setBsonValue(changes, &dpr.Changes); err != nil {
+			return err
+		}
+		if err := setBsonValue(profile, &dpr.Profile); err != nil {
+			return err
+		}
+		dprs = append(dprs, dpr)
+		return nil
+	}, "SELECT "+deviceProfileRevisionColumns+" FROM device_profile_revisions "+where+" ORDER BY revision", args...)
+	return dprs, err
+}
+func (r *sqlRepository) AddDeviceProfileRevision(dpr *models.DeviceProfileRevision) error {
+	count, err := r.count("SELECT COUNT(*) FROM device_profile_revisions WHERE profile_id = ? AND revision = ?",
+		sqlId(dpr.ProfileId), dpr.Revision)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateName
+	}
+	changes, err := bsonValue(dpr.Changes)
+	if err != nil {
+		return err
+	}
+	profile, err := bsonValue(dpr.Profile)
+	if err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	dpr.Created = ts
+	dpr.Modified = ts
+	dpr.Id = bson.NewObjectId()
+	_, err = r.db.Exec("INSERT INTO device_profile_revisions ("+deviceProfileRevisionColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sqlId(dpr.Id), dpr.Created, dpr.Modified, dpr.Origin, sqlId(dpr.ProfileId), dpr.Revision, dpr.Action, dpr.Author,
+		changes, profile)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfileRevisions(dpr *[]models.DeviceProfileRevision, pid string) error {
+	dprs, err := r.deviceProfileRevisions("WHERE profile_id = ?", pid)
+	*dpr = append(*dpr, dprs...)
+	return err
+}
+func (r *sqlRepository) GetDeviceProfileRevision(dpr *models.DeviceProfileRevision, pid string, revision int) error {
+	dprs, err := r.deviceProfileRevisions("WHERE profile_id = ? AND revision = ?", pid, revision)
+	if err != nil {
+		return err
+	}
+	if len(dprs) == 0 {
+		return ErrNotFound
+	}
+	*dpr = dprs[0]
+	return nil
+}
+func (r *sqlRepository) DeleteDeviceProfileRevisions(pid string) error {
+	_, err := r.db.Exec("DELETE FROM device_profile_revisions WHERE profile_id = ?", pid)
+	return err
+}
+func (r *sqlRepository) DeleteDeviceProfileRevisionsFrom(pid string, revision int) error {
+	_, err := r.db.Exec("DELETE FROM device_profile_revisions WHERE profile_id = ? AND revision >= ?", pid, revision)
+	return err
+}
+
+/* ----------------------------- Device ---------------------------------- */
+const deviceColumns = "id, created, modified, origin, description, name, admin_state, operating_state, addressable_id, " +
+	"last_connected, last_reported, location, service_id, profile_id, profile_revision"
+
+func (r *sqlRepository) devices(where string, args ...interface{}) ([]models.Device, error) {
+	var ds []models.Device
+	var refs [][3]sql.NullString
+	err := r.query(func(rows *sql.Rows) error {
+		var d models.Device
+		var id string
+		var location []byte
+		var ref [3]sql.NullString
+		if err := rows.Scan(&id, &d.Created, &d.Modified, &d.Origin, &d.Description, &d.Name, &d.AdminState, &d.OperatingState, &ref[0],
+			&d.LastConnected, &d.LastReported, &location, &ref[1], &ref[2], &d.ProfileRevision); err != nil {
+			return err
+		}
+		d.Id = objectId(id)
+		if err := setBsonValue(location, &d.Location); err != nil {
+			return err
+		}
+		ds = append(ds, d)
+		refs = append(refs, ref)
+		return nil
+	}, "SELECT "+deviceColumns+" FROM devices "+where+sqlOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the references once the rows are closed
+	for i := range ds {
+		if ds[i].Addressable, err = r.addressableRef(refs[i][0]); err != nil {
+			return nil, err
+		}
+		if ds[i].Service, err = r.deviceServiceRef(refs[i][1]); err != nil {
+			return nil, err
+		}
+		if ds[i].Profile, err = r.deviceProfileRef(refs[i][2]); err != nil {
+			return nil, err
+		}
+		if ds[i].Labels, err = r.labels("device_labels", sqlId(ds[i].Id)); err != nil {
+			return nil, err
+		}
+	}
+	return ds, nil
+}
+func (r *sqlRepository) device(d *models.Device, where string, args ...interface{}) error {
+	ds, err := r.devices(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(ds) == 0 {
+		return ErrNotFound
+	}
+	*d = ds[0]
+	return nil
+}
+func (r *sqlRepository) UpdateDevice(d models.Device) error {
+	location, err := bsonValue(d.Location)
+	if err != nil {
+		return err
+	}
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE devices SET created = ?, modified = ?, origin = ?, description = ?, name = ?, admin_state = ?, "+
+			"operating_state = ?, addressable_id = ?, last_connected = ?, last_reported = ?, location = ?, service_id = ?, profile_id = ?, profile_revision = ? WHERE id = ?",
+			d.Created, d.Modified, d.Origin, d.Description, d.Name, string(d.AdminState),
+			string(d.OperatingState), sqlRef(d.Addressable.Id), d.LastConnected, d.LastReported, location, sqlRef(d.Service.Service.Id), sqlRef(d.Profile.Id),
+			d.ProfileRevision, sqlId(d.Id))
+		if err != nil {
+			return err
+		}
+		return setLabels(tx, "device_labels", sqlId(d.Id), d.Labels)
+	})
+}
+func (r *sqlRepository) GetDeviceById(d *models.Device, id string) error {
+	return r.device(d, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetDeviceByName(d *models.Device, n string) error {
+	return r.device(d, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetAllDevices(d *[]models.Device) error {
+	ds, err := r.devices("")
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByProfileId(d *[]models.Device, pid string) error {
+	ds, err := r.devices("WHERE profile_id = ?", pid)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByProfileName(d *[]models.Device, pn string) error {
+	ds, err := r.devices("WHERE profile_id IN (SELECT id FROM device_profiles WHERE name = ?)", pn)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByServiceId(d *[]models.Device, sid string) error {
+	ds, err := r.devices("WHERE service_id = ?", sid)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByServiceName(d *[]models.Device, sn string) error {
+	ds, err := r.devices("WHERE service_id IN (SELECT id FROM device_services WHERE name = ?)", sn)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByAddressableId(d *[]models.Device, aid string) error {
+	ds, err := r.devices("WHERE addressable_id = ?", aid)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesByAddressableName(d *[]models.Device, an string) error {
+	ds, err := r.devices("WHERE addressable_id IN (SELECT id FROM addressables WHERE name = ?)", an)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) GetDevicesWithLabel(d *[]models.Device, l []string) error {
+	where, args := withLabel("device_labels", l)
+	ds, err := r.devices(where, args...)
+	*d = append(*d, ds...)
+	return err
+}
+func (r *sqlRepository) AddDevice(d *models.Device) error {
+	if err := r.checkName("devices", d.Name); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	d.Created = ts
+	d.Modified = ts
+	d.Id = bson.NewObjectId()
+	location, err := bsonValue(d.Location)
+	if err != nil {
+		return err
+	}
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO devices ("+deviceColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			sqlId(d.Id), d.Created, d.Modified, d.Origin, d.Description, d.Name, string(d.AdminState), string(d.OperatingState), sqlRef(d.Addressable.Id),
+			d.LastConnected, d.LastReported, location, sqlRef(d.Service.Service.Id), sqlRef(d.Profile.Id), d.ProfileRevision)
+		if err != nil {
+			return err
+		}
+		return setLabels(tx, "device_labels", sqlId(d.Id), d.Labels)
+	})
+}
+
+/* -------------------------- Schedule ---------------------------------*/
+const scheduleColumns = "id, created, modified, origin, name, start_time, end_time, frequency, cron, run_once"
+
+func (r *sqlRepository) schedules(where string, args ...interface{}) ([]models.Schedule, error) {
+	var ss []models.Schedule
+	err := r.query(func(rows *sql.Rows) error {
+		var s models.Schedule
+		var id string
+		if err := rows.Scan(&id, &s.Created, &s.Modified, &s.Origin, &s.Name, &s.Start, &s.End, &s.Frequency, &s.Cron, &s.RunOnce); err != nil {
+			return err
+		}
+		s.Id = objectId(id)
+		ss = append(ss, s)
+		return nil
+	}, "SELECT "+scheduleColumns+" FROM schedules "+where+sqlOrder, args...)
+	return ss, err
+}
+func (r *sqlRepository) schedule(s *models.Schedule, where string, args ...interface{}) error {
+	ss, err := r.schedules(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(ss) == 0 {
+		return ErrNotFound
+	}
+	*s = ss[0]
+	return nil
+}
+func (r *sqlRepository) GetAllSchedules(s *[]models.Schedule) error {
+	ss, err := r.schedules("")
+	*s = append(*s, ss...)
+	return err
+}
+func (r *sqlRepository) AddSchedule(s *models.Schedule) error {
+	if err := r.checkName("schedules", s.Name); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	s.Created = ts
+	s.Modified = ts
+	s.Id = bson.NewObjectId()
+	_, err := r.db.Exec("INSERT INTO schedules ("+scheduleColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sqlId(s.Id), s.Created, s.Modified, s.Origin, s.Name, s.Start, s.End, s.Frequency, s.Cron, s.RunOnce)
+	return err
+}
+func (r *sqlRepository) GetScheduleByName(s *models.Schedule, n string) error {
+	return r.schedule(s, "WHERE name = ?", n)
+}
+func (r *sqlRepository) UpdateSchedule(s models.Schedule) error {
+	s.Modified = makeTimestamp()
+	_, err := r.db.Exec("UPDATE schedules SET created = ?, modified = ?, origin = ?, name = ?, start_time = ?, end_time = ?, frequency = ?, "+
+		"cron = ?, run_once = ? WHERE id = ?",
+		s.Created, s.Modified, s.Origin, s.Name, s.Start, s.End, s.Frequency, s.Cron, s.RunOnce, sqlId(s.Id))
+	return err
+}
+func (r *sqlRepository) GetScheduleById(s *models.Schedule, id string) error {
+	return r.schedule(s, "WHERE id = ?", id)
+}
+
+/* ----------------------- Schedule Event ------------------------------*/
+const scheduleEventColumns = "id, created, modified, origin, name, schedule_name, addressable_id, parameters, service"
+
+func (r *sqlRepository) scheduleEvents(where string, args ...interface{}) ([]models.ScheduleEvent, error) {
+	var ses []models.ScheduleEvent
+	var addressables []sql.NullString
+	err := r.query(func(rows *sql.Rows) error {
+		var se models.ScheduleEvent
+		var id string
+		var schedule, addressable sql.NullString
+		if err := rows.Scan(&id, &se.Created, &se.Modified, &se.Origin, &se.Name, &schedule, &addressable, &se.Parameters, &se.Service); err != nil {
+			return err
+		}
+		se.Id = objectId(id)
+		se.Schedule = schedule.String
+		ses = append(ses, se)
+		addressables = append(addressables, addressable)
+		return nil
+	}, "SELECT "+scheduleEventColumns+" FROM schedule_events "+where+sqlOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the references once the rows are closed
+	for i := range ses {
+		if ses[i].Addressable, err = r.addressableRef(addressables[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ses, nil
+}
+func (r *sqlRepository) scheduleEvent(se *models.ScheduleEvent, where string, args ...interface{}) error {
+	ses, err := r.scheduleEvents(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(ses) == 0 {
+		return ErrNotFound
+	}
+	*se = ses[0]
+	return nil
+}
+func (r *sqlRepository) GetAllScheduleEvents(se *[]models.ScheduleEvent) error {
+	ses, err := r.scheduleEvents("")
+	*se = append(*se, ses...)
+	return err
+}
+func (r *sqlRepository) AddScheduleEvent(se *models.ScheduleEvent) error {
+	if err := r.checkName("schedule_events", se.Name); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	se.Created = ts
+	se.Modified = ts
+	se.Id = bson.NewObjectId()
+	_, err := r.db.Exec("INSERT INTO schedule_events ("+scheduleEventColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		sqlId(se.Id), se.Created, se.Modified, se.Origin, se.Name, sqlNameRef(se.Schedule), sqlRef(se.Addressable.Id), se.Parameters, se.Service)
+	return err
+}
+func (r *sqlRepository) GetScheduleEventByName(se *models.ScheduleEvent, n string) error {
+	return r.scheduleEvent(se, "WHERE name = ?", n)
+}
+func (r *sqlRepository) UpdateScheduleEvent(se models.ScheduleEvent) error {
+	se.Modified = makeTimestamp()
+	_, err := r.db.Exec("UPDATE schedule_events SET created = ?, modified = ?, origin = ?, name = ?, schedule_name = ?, addressable_id = ?, "+
+		"parameters = ?, service = ? WHERE id = ?",
+		se.Created, se.Modified, se.Origin, se.Name, sqlNameRef(se.Schedule), sqlRef(se.Addressable.Id),
+		se.Parameters, se.Service, sqlId(se.Id))
+	return err
+}
+func (r *sqlRepository) GetScheduleEventById(se *models.ScheduleEvent, id string) error {
+	return r.scheduleEvent(se, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetScheduleEventsByScheduleName(se *[]models.ScheduleEvent, n string) error {
+	ses, err := r.scheduleEvents("WHERE schedule_name = ?", n)
+	*se = append(*se, ses...)
+	return err
+}
+func (r *sqlRepository) GetScheduleEventsByAddressableId(se *[]models.ScheduleEvent, id string) error {
+	ses, err := r.scheduleEvents("WHERE addressable_id = ?", id)
+	*se = append(*se, ses...)
+	return err
+}
+func (r *sqlRepository) GetScheduleEventsByServiceName(se *[]models.ScheduleEvent, n string) error {
+	ses, err := r.scheduleEvents("WHERE service = ?", n)
+	*se = append(*se, ses...)
+	return err
+}
+
+/* ------------------------Device Report -------------------------------*/
+const deviceReportColumns = "id, created, modified, origin, name, device_name, event_name, expected"
+
+func (r *sqlRepository) deviceReports(where string, args ...interface{}) ([]models.DeviceReport, error) {
+	var drs []models.DeviceReport
+	err := r.query(func(rows *sql.Rows) error {
+		var dr models.DeviceReport
+		var id string
+		var device, event sql.NullString
+		var expected []byte
+		if err := rows.Scan(&id, &dr.Created, &dr.Modified, &dr.Origin, &dr.Name, &device, &event, &expected); err != nil {
+			return err
+		}
+		dr.Id = objectId(id)
+		dr.Device = device.String
+		dr.Event = event.String
+		if err := setBsonValue(expected, &dr.Expected); err != nil {
+			return err
+		}
+		drs = append(drs, dr)
+		return nil
+	}, "SELECT "+deviceReportColumns+" FROM device_reports "+where+sqlOrder, args...)
+	return drs, err
+}
+func (r *sqlRepository) deviceReport(dr *models.DeviceReport, where string, args ...interface{}) error {
+	drs, err := r.deviceReports(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(drs) == 0 {
+		return ErrNotFound
+	}
+	*dr = drs[0]
+	return nil
+}
+func (r *sqlRepository) GetAllDeviceReports(dr *[]models.DeviceReport) error {
+	drs, err := r.deviceReports("")
+	*dr = append(*dr, drs...)
+	return err
+}
+func (r *sqlRepository) GetDeviceReportByDeviceName(dr *[]models.DeviceReport, n string) error {
+	drs, err := r.deviceReports("WHERE device_name = ?", n)
+	*dr = append(*dr, drs...)
+	return err
+}
+func (r *sqlRepository) GetDeviceReportByName(dr *models.DeviceReport, n string) error {
+	return r.deviceReport(dr, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetDeviceReportById(dr *models.DeviceReport, id string) error {
+	return r.deviceReport(dr, "WHERE id = ?", id)
+}
+func (r *sqlRepository) AddDeviceReport(dr *models.DeviceReport) error {
+	if err := r.checkName("device_reports", dr.Name); err != nil {
+		return err
+	}
+	dr.Created = makeTimestamp()
+	dr.Id = bson.NewObjectId()
+	expected, err := bsonValue(dr.Expected)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("INSERT INTO device_reports ("+deviceReportColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		sqlId(dr.Id), dr.Created, dr.Modified, dr.Origin, dr.Name, sqlNameRef(dr.Device), sqlNameRef(dr.Event), expected)
+	return err
+}
+func (r *sqlRepository) UpdateDeviceReport(dr *models.DeviceReport) error {
+	expected, err := bsonValue(dr.Expected)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec("UPDATE device_reports SET created = ?, modified = ?, origin = ?, name = ?, device_name = ?, event_name = ?, "+
+		"expected = ? WHERE id = ?",
+		dr.Created, dr.Modified, dr.Origin, dr.Name, sqlNameRef(dr.Device), sqlNameRef(dr.Event),
+		expected, sqlId(dr.Id))
+	return err
+}
+func (r *sqlRepository) GetDeviceReportsByScheduleEventName(dr *[]models.DeviceReport, n string) error {
+	drs, err := r.deviceReports("WHERE event_name = ?", n)
+	*dr = append(*dr, drs...)
+	return err
+}
+
+/* ----------------------Provision Watcher -----------------------------*/
+const provisionWatcherColumns = "id, created, modified, origin, name, profile_id, service_id, operating_state"
+
+func (r *sqlRepository) provisionWatchers(where string, args ...interface{}) ([]models.ProvisionWatcher, error) {
+	var pws []models.ProvisionWatcher
+	var refs [][2]sql.NullString
+	err := r.query(func(rows *sql.Rows) error {
+		var pw models.ProvisionWatcher
+		var id string
+		var ref [2]sql.NullString
+		if err := rows.Scan(&id, &pw.Created, &pw.Modified, &pw.Origin, &pw.Name, &ref[0], &ref[1], &pw.OperatingState); err != nil {
+			return err
+		}
+		pw.Id = objectId(id)
+		pws = append(pws, pw)
+		refs = append(refs, ref)
+		return nil
+	}, "SELECT "+provisionWatcherColumns+" FROM provision_watchers "+where+sqlOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the references once the rows are closed
+	for i := range pws {
+		if pws[i].Profile, err = r.deviceProfileRef(refs[i][0]); err != nil {
+			return nil, err
+		}
+		if pws[i].Service, err = r.deviceServiceRef(refs[i][1]); err != nil {
+			return nil, err
+		}
+		identifiers := map[string]string{}
+		err = r.query(func(rows *sql.Rows) error {
+			var k, v string
+			if err := rows.Scan(&k, &v); err != nil {
+				return err
+			}
+			identifiers[k] = v
+			return nil
+		}, "SELECT name, value FROM provision_watcher_identifiers WHERE watcher_id = ?", sqlId(pws[i].Id))
+		if err != nil {
+			return nil, err
+		}
+		pws[i].Identifiers = identifiers
+	}
+	return pws, nil
+}
+func (r *sqlRepository) provisionWatcher(pw *models.ProvisionWatcher, where string, args ...interface{}) error {
+	pws, err := r.provisionWatchers(where, args...)
+	if err != nil {
+		return err
+	}
+	if len(pws) == 0 {
+		return ErrNotFound
+	}
+	*pw = pws[0]
+	return nil
+}
+func setIdentifiers(tx sqlQuerier, pw *models.ProvisionWatcher) error {
+	if _, err := tx.Exec("DELETE FROM provision_watcher_identifiers WHERE watcher_id = ?", sqlId(pw.Id)); err != nil {
+		return err
+	}
+	for k, v := range pw.Identifiers {
+		if _, err := tx.Exec("INSERT INTO provision_watcher_identifiers (watcher_id, name, value) VALUES (?, ?, ?)", sqlId(pw.Id), k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (r *sqlRepository) GetProvisionWatcherById(pw *models.ProvisionWatcher, id string) error {
+	return r.provisionWatcher(pw, "WHERE id = ?", id)
+}
+func (r *sqlRepository) GetAllProvisionWatchers(pw *[]models.ProvisionWatcher) error {
+	pws, err := r.provisionWatchers("")
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) GetProvisionWatcherByName(pw *models.ProvisionWatcher, n string) error {
+	return r.provisionWatcher(pw, "WHERE name = ?", n)
+}
+func (r *sqlRepository) GetProvisionWatchersByProfileId(pw *[]models.ProvisionWatcher, id string) error {
+	pws, err := r.provisionWatchers("WHERE profile_id = ?", id)
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) GetProvisionWatchersByProfileName(pw *[]models.ProvisionWatcher, n string) error {
+	pws, err := r.provisionWatchers("WHERE profile_id IN (SELECT id FROM device_profiles WHERE name = ?)", n)
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) GetProvisionWatchersByServiceId(pw *[]models.ProvisionWatcher, id string) error {
+	pws, err := r.provisionWatchers("WHERE service_id = ?", id)
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) GetProvisionWatchersByServiceName(pw *[]models.ProvisionWatcher, n string) error {
+	pws, err := r.provisionWatchers("WHERE service_id IN (SELECT id FROM device_services WHERE name = ?)", n)
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) GetProvisionWatchersByIdentifier(pw *[]models.ProvisionWatcher, k string, v string) error {
+	pws, err := r.provisionWatchers("WHERE id IN (SELECT watcher_id FROM provision_watcher_identifiers WHERE name = ? AND value = ?)", k, v)
+	*pw = append(*pw, pws...)
+	return err
+}
+func (r *sqlRepository) AddProvisionWatcher(pw *models.ProvisionWatcher) error {
+	if err := r.checkName("provision_watchers", pw.Name); err != nil {
+		return err
+	}
+	ts := makeTimestamp()
+	pw.Created = ts
+	pw.Modified = ts
+	pw.Id = bson.NewObjectId()
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO provision_watchers ("+provisionWatcherColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			sqlId(pw.Id), pw.Created, pw.Modified, pw.Origin, pw.Name, sqlRef(pw.Profile.Id), sqlRef(pw.Service.Service.Id), string(pw.OperatingState))
+		if err != nil {
+			return err
+		}
+		return setIdentifiers(tx, pw)
+	})
+}
+func (r *sqlRepository) UpdateProvisionWatcher(pw models.ProvisionWatcher) error {
+	pw.Modified = makeTimestamp()
+	return r.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE provision_watchers SET created = ?, modified = ?, origin = ?, name = ?, profile_id = ?, service_id = ?, "+
+			"operating_state = ? WHERE id = ?",
+			pw.Created, pw.Modified, pw.Origin, pw.Name, sqlRef(pw.Profile.Id), sqlRef(pw.Service.Service.Id),
+			string(pw.OperatingState), sqlId(pw.Id))
+		if err != nil {
+			return err
+		}
+		return setIdentifiers(tx, &pw)
+	})
+}
+
+/* ------------------------ Collection --------------------------------*/
+func sqlTable(collection string) (string, error) {
+	table, ok := sqlTables[collection]
+	if !ok {
+		return "", errors.New("Unknown collection: " + collection)
+	}
+	return table, nil
+}
+func (r *sqlRepository) deleteWhere(collection string, column string, value string) error {
+	table, err := sqlTable(collection)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.Exec("DELETE FROM "+table+" WHERE "+column+" = ?", value)
+	if err != nil {
+		return r.deleteError(err)
+	}
+	return notFound(result)
+}
+func (r *sqlRepository) DeleteById(collection string, id string) error {
+	return r.deleteWhere(collection, "id", id)
+}
+func (r *sqlRepository) DeleteByName(collection string, n string) error {
+	return r.deleteWhere(collection, "name", n)
+}
+func (r *sqlRepository) setWhere(collection string, column string, key string, field string, value interface{}) error {
+	table, err := sqlTable(collection)
+	if err != nil {
+		return err
+	}
+	fieldColumn, ok := sqlColumns[field]
+	if !ok {
+		return errors.New("Unknown field: " + field)
+	}
+	result, err := r.db.Exec("UPDATE "+table+" SET "+fieldColumn+" = ?, modified = ? WHERE "+column+" = ?", value, makeTimestamp(), key)
+	if err != nil {
+		return err
+	}
+	return notFound(result)
+}
+func (r *sqlRepository) SetById(collection string, id string, field string, value string) error {
+	return r.setWhere(collection, "id", id, field, value)
+}
+func (r *sqlRepository) SetByIdInt(collection string, id string, field string, value int64) error {
+	return r.setWhere(collection, "id", id, field, value)
+}
+func (r *sqlRepository) SetByName(collection string, n string, field string, value string) error {
+	return r.setWhere(collection, "name", n, field, value)
+}
+func (r *sqlRepository) SetByNameInt(collection string, n string, field string, value int64) error {
+	return r.setWhere(collection, "name", n, field, value)
+}
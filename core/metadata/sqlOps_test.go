@@ -0,0 +1,310 @@
+//go:build cgo
+// +build cgo
+
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+func newTestSqlRepository(t *testing.T) (Repository, func()) {
+	dir, err := ioutil.TempDir("", "metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := newSqlRepository(enums.SQLITE, filepath.Join(dir, "metadata.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return r, func() {
+		r.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// Addressable, device service, profile with a command and device
+func addTestDevice(t *testing.T, r Repository) models.Device {
+	a := models.Addressable{Name: "addressable", Protocol: "HTTP", Address: "localhost", Port: 48081, Path: "/api"}
+	if err := r.AddAddressable(&a); err != nil {
+		t.Fatal(err)
+	}
+	ds := models.DeviceService{AdminState: "UNLOCKED"}
+	ds.Service.Name = "service"
+	ds.Service.OperatingState = "ENABLED"
+	ds.Service.Labels = []string{"service"}
+	ds.Service.Addressable = a
+	if err := r.AddDeviceService(&ds); err != nil {
+		t.Fatal(err)
+	}
+	dp := models.DeviceProfile{
+		Name:         "profile",
+		Manufacturer: "manufacturer",
+		Model:        "model",
+		Labels:       []string{"profile"},
+		Objects:      "objects",
+		DeviceResources: []models.DeviceObject{{
+			Name:       "temperature",
+			Properties: models.ProfileProperty{Value: models.PropertyValue{Type: "Float"}},
+			Attributes: map[string]string{"register": "1"},
+		}},
+		Resources: []models.ProfileResource{}, // Empty lists are read back empty rather than nil
+		Commands:  []models.Command{{Name: "temperature", Get: &models.Get{Action: models.Action{Path: "/temperature", Responses: []models.Response{}}}}},
+	}
+	if err := r.AddDeviceProfile(&dp); err != nil {
+		t.Fatal(err)
+	}
+	d := models.Device{
+		Name:           "device",
+		AdminState:     "UNLOCKED",
+		OperatingState: "ENABLED",
+		Addressable:    a,
+		Labels:         []string{"first", "second"},
+		Location:       "room",
+		Service:        ds,
+		Profile:        dp,
+	}
+	if err := r.AddDevice(&d); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSqlDevice(t *testing.T) {
+	r, closeRepository := newTestSqlRepository(t)
+	defer closeRepository()
+	d := addTestDevice(t, r)
+
+	var got models.Device
+	if err := r.GetDeviceById(&got, d.Id.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, d) {
+		t.Errorf("Device read back:\n%v\nexpected:\n%v", got, d)
+	}
+	if got.Profile.Commands[0].Get.Path != "/temperature" || got.Profile.DeviceResources[0].Attributes["register"] != "1" {
+		t.Errorf("Profile read back with the device: %v", got.Profile)
+	}
+
+	if err := r.GetDeviceByName(&got, "missing"); err != ErrNotFound {
+		t.Errorf("Missing device: %v, expected ErrNotFound", err)
+	}
+	if err := r.AddDevice(&models.Device{Name: "device"}); err != ErrDuplicateName {
+		t.Errorf("Device added twice: %v, expected ErrDuplicateName", err)
+	}
+
+	var devices []models.Device
+	if err := r.GetDevicesWithLabel(&devices, []string{"second", "other"}); err != nil || len(devices) != 1 {
+		t.Errorf("Devices with label: %v %v", devices, err)
+	}
+	devices = nil
+	if err := r.GetDevicesByServiceName(&devices, "service"); err != nil || len(devices) != 1 {
+		t.Errorf("Devices by service name: %v %v", devices, err)
+	}
+	devices = nil
+	if err := r.GetDevicesByAddressableName(&devices, "addressable"); err != nil || len(devices) != 1 {
+		t.Errorf("Devices by addressable name: %v %v", devices, err)
+	}
+
+	if err := r.SetById(DEVICECOL, d.Id.Hex(), ADMINSTATE, "LOCKED"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetByNameInt(DEVICECOL, d.Name, LASTCONNECTED, 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.GetDeviceByName(&got, d.Name); err != nil {
+		t.Fatal(err)
+	}
+	if got.AdminState != "LOCKED" || got.LastConnected != 42 {
+		t.Errorf("Device fields set: %v %v", got.AdminState, got.LastConnected)
+	}
+
+	got.Labels = []string{"third"}
+	got.Description = "updated"
+	if err := r.UpdateDevice(got); err != nil {
+		t.Fatal(err)
+	}
+	devices = nil
+	if err := r.GetDevicesWithLabel(&devices, []string{"first"}); err != nil || len(devices) != 0 {
+		t.Errorf("Devices with a label removed: %v %v", devices, err)
+	}
+}
+
+func TestSqlForeignKeys(t *testing.T) {
+	r, closeRepository := newTestSqlRepository(t)
+	defer closeRepository()
+	d := addTestDevice(t, r)
+
+	if err := r.DeleteById(ADDCOL, d.Addressable.Id.Hex()); err != ErrStillInUse {
+		t.Errorf("Addressable still used by the device deleted: %v", err)
+	}
+	if err := r.DeleteByName(DSCOL, d.Service.Service.Name); err != ErrStillInUse {
+		t.Errorf("Service still used by the device deleted: %v", err)
+	}
+	if err := r.DeleteCommandById(d.Profile.Commands[0].Id.Hex()); err != ErrCommandStillInUse {
+		t.Errorf("Command still used by the profile deleted: %v", err)
+	}
+	if err := r.AddDevice(&models.Device{Name: "unknown", Profile: models.DeviceProfile{Id: d.Id}}); err == nil {
+		t.Errorf("Device added with a missing profile")
+	}
+
+	// Reports follow the device renames
+	s := models.Schedule{Name: "schedule", Frequency: "PT1M"}
+	if err := r.AddSchedule(&s); err != nil {
+		t.Fatal(err)
+	}
+	se := models.ScheduleEvent{Name: "event", Schedule: s.Name, Addressable: d.Addressable}
+	if err := r.AddScheduleEvent(&se); err != nil {
+		t.Fatal(err)
+	}
+	dr := models.DeviceReport{Name: "report", Device: d.Name, Event: se.Name, Expected: []string{"temperature"}}
+	if err := r.AddDeviceReport(&dr); err != nil {
+		t.Fatal(err)
+	}
+	d.Name = "renamed"
+	if err := r.UpdateDevice(d); err != nil {
+		t.Fatal(err)
+	}
+	var reports []models.DeviceReport
+	if err := r.GetDeviceReportByDeviceName(&reports, "renamed"); err != nil || len(reports) != 1 {
+		t.Fatalf("Reports of the renamed device: %v %v", reports, err)
+	}
+	if !reflect.DeepEqual(reports[0].Expected, dr.Expected) {
+		t.Errorf("Expected values read back: %v", reports[0].Expected)
+	}
+	if err := r.DeleteById(DEVICECOL, d.Id.Hex()); err != ErrStillInUse {
+		t.Errorf("Device still used by a report deleted: %v", err)
+	}
+
+	// Deleted in the order of the references
+	if err := r.DeleteById(DRCOL, dr.Id.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteById(DEVICECOL, d.Id.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteById(DPCOL, d.Profile.Id.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteCommandById(d.Profile.Commands[0].Id.Hex()); err != nil {
+		t.Errorf("Command of the deleted profile: %v", err)
+	}
+	if err := r.DeleteById(DEVICECOL, d.Id.Hex()); err != ErrNotFound {
+		t.Errorf("Device deleted twice: %v, expected ErrNotFound", err)
+	}
+}
+
+func TestSqlProvisionWatcher(t *testing.T) {
+	r, closeRepository := newTestSqlRepository(t)
+	defer closeRepository()
+	d := addTestDevice(t, r)
+
+	pw := models.ProvisionWatcher{
+		Name:           "watcher",
+		Identifiers:    map[string]string{"MAC": "00-05-1B-A1-99-99"},
+		Profile:        d.Profile,
+		Service:        d.Service,
+		OperatingState: "ENABLED",
+	}
+	if err := r.AddProvisionWatcher(&pw); err != nil {
+		t.Fatal(err)
+	}
+	var pws []models.ProvisionWatcher
+	if err := r.GetProvisionWatchersByIdentifier(&pws, "MAC", "00-05-1B-A1-99-99"); err != nil || len(pws) != 1 {
+		t.Fatalf("Watchers by identifier: %v %v", pws, err)
+	}
+	if !reflect.DeepEqual(pws[0], pw) {
+		t.Errorf("Watcher read back:\n%v\nexpected:\n%v", pws[0], pw)
+	}
+	if err := r.DeleteById(DPCOL, d.Profile.Id.Hex()); err != ErrStillInUse {
+		t.Errorf("Profile still used by the watcher and device deleted: %v", err)
+	}
+}
+
+func TestSqlDeviceProfileRevision(t *testing.T) {
+	r, closeRepository := newTestSqlRepository(t)
+	defer closeRepository()
+	d := addTestDevice(t, r)
+
+	dpr := models.DeviceProfileRevision{
+		ProfileId: d.Profile.Id,
+		Revision:  1,
+		Action:    "create",
+		Author:    "author",
+		Changes:   []models.ProfileChange{{Path: "model", After: "model"}},
+		Profile:   profileDefinition(d.Profile),
+	}
+	if err := r.AddDeviceProfileRevision(&dpr); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddDeviceProfileRevision(&models.DeviceProfileRevision{ProfileId: d.Profile.Id, Revision: 1}); err != ErrDuplicateName {
+		t.Errorf("Revision added twice: %v, expected ErrDuplicateName", err)
+	}
+
+	var got models.DeviceProfileRevision
+	if err := r.GetDeviceProfileRevision(&got, d.Profile.Id.Hex(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, dpr) {
+		t.Errorf("Revision read back:\n%v\nexpected:\n%v", got, dpr)
+	}
+	if err := r.GetDeviceProfileRevision(&got, d.Profile.Id.Hex(), 2); err != ErrNotFound {
+		t.Errorf("Missing revision: %v, expected ErrNotFound", err)
+	}
+
+	d.ProfileRevision = 1
+	if err := r.UpdateDevice(d); err != nil {
+		t.Fatal(err)
+	}
+	var device models.Device
+	if err := r.GetDeviceById(&device, d.Id.Hex()); err != nil || device.ProfileRevision != 1 {
+		t.Errorf("Pinned revision read back: %v %v", device.ProfileRevision, err)
+	}
+
+	if err := r.AddDeviceProfileRevision(&models.DeviceProfileRevision{ProfileId: d.Profile.Id, Revision: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteDeviceProfileRevisionsFrom(d.Profile.Id.Hex(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.GetDeviceProfileRevision(&got, d.Profile.Id.Hex(), 2); err != ErrNotFound {
+		t.Errorf("Revision 2 after delete: %v, expected ErrNotFound", err)
+	}
+	if err := r.GetDeviceProfileRevision(&got, d.Profile.Id.Hex(), 1); err != nil {
+		t.Errorf("Revision 1 was deleted: %v", err)
+	}
+
+	if err := r.DeleteDeviceProfileRevisions(d.Profile.Id.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	var revisions []models.DeviceProfileRevision
+	if err := r.GetDeviceProfileRevisions(&revisions, d.Profile.Id.Hex()); err != nil || len(revisions) != 0 {
+		t.Errorf("Revisions after delete: %v %v", revisions, err)
+	}
+}
@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+import (
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+	"github.com/go-sql-driver/mysql"
+)
+
+func init() {
+	sqlDialects[enums.MYSQL] = sqlDialect{
+		driver:                "mysql",
+		dataSource:            mysqlDataSource,
+		isForeignKeyViolation: isMysqlForeignKeyViolation,
+	}
+}
+
+// The rows matched rather than changed are counted, an update to the same values finds its row
+func mysqlDataSource(dsn string) string {
+	if strings.Contains(dsn, "clientFoundRows=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&clientFoundRows=true"
+	}
+	return dsn + "?clientFoundRows=true"
+}
+
+// Errors 1451 and 1452: a parent row still referenced or a child row referencing a missing row
+func isMysqlForeignKeyViolation(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	return ok && (mysqlErr.Number == 1451 || mysqlErr.Number == 1452)
+}
@@ -0,0 +1,201 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+package metadata
+
+/*
+ * Tables of the SQL repository, created when missing on Connect. The statements are valid for
+ * MySQL and SQLite.
+ *
+ * The IDs are the hexadecimal bson.ObjectIds of the models. The references between the models
+ * are foreign keys: a resource can't be deleted while it is still referenced, and the names
+ * referenced by schedule events and device reports follow the renames. The lists and nested
+ * values that are never queried (location, objects, resources, get and put...) are stored
+ * encoded in BSON.
+ */
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS addressables (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		protocol VARCHAR(255) NOT NULL,
+		method VARCHAR(255) NOT NULL,
+		address VARCHAR(255) NOT NULL,
+		port INTEGER NOT NULL,
+		path TEXT NOT NULL,
+		publisher VARCHAR(255) NOT NULL,
+		user_name VARCHAR(255) NOT NULL,
+		password VARCHAR(255) NOT NULL,
+		topic VARCHAR(255) NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_services (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		description TEXT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		last_connected BIGINT NOT NULL,
+		last_reported BIGINT NOT NULL,
+		operating_state VARCHAR(32) NOT NULL,
+		admin_state VARCHAR(32) NOT NULL,
+		addressable_id CHAR(24),
+		FOREIGN KEY (addressable_id) REFERENCES addressables (id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_service_labels (
+		owner_id CHAR(24) NOT NULL,
+		position INTEGER NOT NULL,
+		label VARCHAR(255) NOT NULL,
+		PRIMARY KEY (owner_id, position),
+		FOREIGN KEY (owner_id) REFERENCES device_services (id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE IF NOT EXISTS commands (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		get_command MEDIUMBLOB,
+		put_command MEDIUMBLOB
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_profiles (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		description TEXT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		manufacturer VARCHAR(255) NOT NULL,
+		model VARCHAR(255) NOT NULL,
+		objects MEDIUMBLOB,
+		device_resources MEDIUMBLOB,
+		resources MEDIUMBLOB
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_profile_labels (
+		owner_id CHAR(24) NOT NULL,
+		position INTEGER NOT NULL,
+		label VARCHAR(255) NOT NULL,
+		PRIMARY KEY (owner_id, position),
+		FOREIGN KEY (owner_id) REFERENCES device_profiles (id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_profile_commands (
+		profile_id CHAR(24) NOT NULL,
+		position INTEGER NOT NULL,
+		command_id CHAR(24) NOT NULL,
+		PRIMARY KEY (profile_id, position),
+		FOREIGN KEY (profile_id) REFERENCES device_profiles (id) ON DELETE CASCADE,
+		FOREIGN KEY (command_id) REFERENCES commands (id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_profile_revisions (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		profile_id CHAR(24) NOT NULL,
+		revision INTEGER NOT NULL,
+		action VARCHAR(32) NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		changes MEDIUMBLOB,
+		profile MEDIUMBLOB,
+		UNIQUE (profile_id, revision),
+		FOREIGN KEY (profile_id) REFERENCES device_profiles (id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE IF NOT EXISTS devices (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		description TEXT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		admin_state VARCHAR(32) NOT NULL,
+		operating_state VARCHAR(32) NOT NULL,
+		addressable_id CHAR(24),
+		last_connected BIGINT NOT NULL,
+		last_reported BIGINT NOT NULL,
+		location MEDIUMBLOB,
+		service_id CHAR(24),
+		profile_id CHAR(24),
+		profile_revision INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY (addressable_id) REFERENCES addressables (id),
+		FOREIGN KEY (service_id) REFERENCES device_services (id),
+		FOREIGN KEY (profile_id) REFERENCES device_profiles (id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_labels (
+		owner_id CHAR(24) NOT NULL,
+		position INTEGER NOT NULL,
+		label VARCHAR(255) NOT NULL,
+		PRIMARY KEY (owner_id, position),
+		FOREIGN KEY (owner_id) REFERENCES devices (id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE IF NOT EXISTS schedules (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		start_time VARCHAR(32) NOT NULL,
+		end_time VARCHAR(32) NOT NULL,
+		frequency VARCHAR(32) NOT NULL,
+		cron VARCHAR(255) NOT NULL,
+		run_once BOOLEAN NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS schedule_events (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		schedule_name VARCHAR(255),
+		addressable_id CHAR(24),
+		parameters TEXT NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		FOREIGN KEY (schedule_name) REFERENCES schedules (name) ON UPDATE CASCADE,
+		FOREIGN KEY (addressable_id) REFERENCES addressables (id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS device_reports (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		device_name VARCHAR(255),
+		event_name VARCHAR(255),
+		expected MEDIUMBLOB,
+		FOREIGN KEY (device_name) REFERENCES devices (name) ON UPDATE CASCADE,
+		FOREIGN KEY (event_name) REFERENCES schedule_events (name) ON UPDATE CASCADE
+	)`,
+	`CREATE TABLE IF NOT EXISTS provision_watchers (
+		id CHAR(24) NOT NULL PRIMARY KEY,
+		created BIGINT NOT NULL,
+		modified BIGINT NOT NULL,
+		origin BIGINT NOT NULL,
+		name VARCHAR(255) NOT NULL UNIQUE,
+		profile_id CHAR(24),
+		service_id CHAR(24),
+		operating_state VARCHAR(32) NOT NULL,
+		FOREIGN KEY (profile_id) REFERENCES device_profiles (id),
+		FOREIGN KEY (service_id) REFERENCES device_services (id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS provision_watcher_identifiers (
+		watcher_id CHAR(24) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		value VARCHAR(255) NOT NULL,
+		PRIMARY KEY (watcher_id, name),
+		FOREIGN KEY (watcher_id) REFERENCES provision_watchers (id) ON DELETE CASCADE
+	)`,
+}
@@ -0,0 +1,56 @@
+//go:build cgo
+// +build cgo
+
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: core-metadata-go service
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package metadata
+
+import (
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/enums"
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLite is only available to the builds with cgo, the database is usually a local file
+func init() {
+	sqlDialects[enums.SQLITE] = sqlDialect{
+		driver:     "sqlite3",
+		dataSource: sqliteDataSource,
+		// A single connection: the writes of SQLite are serialized and the in-memory databases
+		// are private to their connection
+		maxOpenConns:          1,
+		isForeignKeyViolation: isSqliteForeignKeyViolation,
+	}
+}
+
+// The foreign keys are only enforced when enabled on the connection
+func sqliteDataSource(dsn string) string {
+	if strings.Contains(dsn, "_foreign_keys=") || strings.Contains(dsn, "_fk=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=1"
+	}
+	return dsn + "?_foreign_keys=1"
+}
+
+func isSqliteForeignKeyViolation(err error) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	return ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+}
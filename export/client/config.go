@@ -12,9 +12,21 @@ const (
 	defaultDistroHost = "127.0.0.1"
 )
 
+// Fields tagged env are overridden by their variable, see pkg/config.ApplyEnvironment
 type Config struct {
 	Port       int
-	DistroHost string
+	DistroHost string `env:"EXPORT_CLIENT_DISTRO_HOST"`
+
+	// Authentication of the requests, the scope rules are added to the policy of the routes
+	AuthEnabled          bool   `env:"EXPORT_CLIENT_AUTH_ENABLED"`
+	AuthAPIKeys          string `env:"EXPORT_CLIENT_AUTH_API_KEYS"`
+	AuthJWTSecret        string `env:"EXPORT_CLIENT_AUTH_JWT_SECRET"`
+	AuthJWTPublicKeyFile string `env:"EXPORT_CLIENT_AUTH_JWT_PUBLIC_KEY_FILE"`
+	AuthScopes           string `env:"EXPORT_CLIENT_AUTH_SCOPES"`
+
+	// Credentials of the calls to the other services
+	AuthClientAPIKey string `env:"EXPORT_CLIENT_AUTH_CLIENT_API_KEY"`
+	AuthClientToken  string `env:"EXPORT_CLIENT_AUTH_CLIENT_TOKEN"`
 }
 
 var cfg Config
@@ -6,12 +6,22 @@
 
 package client
 
-import "github.com/edgexfoundry/edgex-go/export/mongo"
+import (
+	"github.com/edgexfoundry/edgex-go/export/mongo"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+)
 
 var repo *mongo.Repository
 
 // InitMongoRepository - Init Mongo DB
 func InitMongoRepository(r *mongo.Repository) {
 	repo = r
+	health.Register("database", pingMongo)
 	return
 }
+
+func pingMongo() error {
+	s := repo.Session.Copy()
+	defer s.Close()
+	return s.Ping()
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/export"
 	"github.com/edgexfoundry/edgex-go/export/mongo"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 	"github.com/go-zoo/bone"
 	"go.uber.org/zap"
 	"gopkg.in/mgo.v2/bson"
@@ -291,7 +292,7 @@ func delRegByName(w http.ResponseWriter, r *http.Request) {
 
 func notifyUpdatedRegistrations(update export.NotifyUpdate) {
 	go func() {
-		client := &http.Client{}
+		client := &http.Client{Transport: auth.Transport(nil)}
 		url := "http://" + cfg.DistroHost + ":" + strconv.Itoa(distroPort) +
 			"/api/v1/notify/registrations"
 
@@ -11,10 +11,16 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
 	"github.com/go-zoo/bone"
 	"go.uber.org/zap"
 )
 
+var authenticator auth.Authenticator
+var policy = authPolicy()
+
 func replyPing(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -27,27 +33,48 @@ func httpServer() http.Handler {
 	mux := bone.New()
 
 	// Status
-	mux.Get("/status", http.HandlerFunc(getStatus))
+	mux.Get("/status", metrics.Instrument("/status", http.HandlerFunc(getStatus)))
 
-	mux.Get("/api/v1/ping", http.HandlerFunc(replyPing))
+	mux.Get("/api/v1/ping", metrics.Instrument("/api/v1/ping", http.HandlerFunc(replyPing)))
 
 	// Registration
-	mux.Get("/api/v1/registration/:id", http.HandlerFunc(getRegByID))
-	mux.Get("/api/v1/registration/reference/:type", http.HandlerFunc(getRegList))
-	mux.Get("/api/v1/registration", http.HandlerFunc(getAllReg))
-	mux.Get("/api/v1/registration/name/:name", http.HandlerFunc(getRegByName))
-	mux.Post("/api/v1/registration", http.HandlerFunc(addReg))
-	mux.Put("/api/v1/registration", http.HandlerFunc(updateReg))
-	mux.Delete("/api/v1/registration/id/:id", http.HandlerFunc(delRegByID))
-	mux.Delete("/api/v1/registration/name/:name", http.HandlerFunc(delRegByName))
-
-	return mux
+	mux.Get("/api/v1/registration/:id", metrics.Instrument("/api/v1/registration/:id", http.HandlerFunc(getRegByID)))
+	mux.Get("/api/v1/registration/reference/:type", metrics.Instrument("/api/v1/registration/reference/:type", http.HandlerFunc(getRegList)))
+	mux.Get("/api/v1/registration", metrics.Instrument("/api/v1/registration", http.HandlerFunc(getAllReg)))
+	mux.Get("/api/v1/registration/name/:name", metrics.Instrument("/api/v1/registration/name/:name", http.HandlerFunc(getRegByName)))
+	mux.Post("/api/v1/registration", metrics.Instrument("/api/v1/registration", http.HandlerFunc(addReg)))
+	mux.Put("/api/v1/registration", metrics.Instrument("/api/v1/registration", http.HandlerFunc(updateReg)))
+	mux.Delete("/api/v1/registration/id/:id", metrics.Instrument("/api/v1/registration/id/:id", http.HandlerFunc(delRegByID)))
+	mux.Delete("/api/v1/registration/name/:name", metrics.Instrument("/api/v1/registration/name/:name", http.HandlerFunc(delRegByName)))
+
+	mux.Get(metrics.ApiMetricsRoute, metrics.Handler())
+	mux.Get(health.ApiLiveRoute, health.LiveHandler())
+	mux.Get(health.ApiReadyRoute, health.ReadyHandler())
+
+	return auth.Middleware(authenticator, policy)(mux)
+}
+
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().Public("/api/v1/ping", health.ApiLiveRoute, health.ApiReadyRoute)
 }
 
 func StartHTTPServer(config Config, errChan chan error) {
 	cfg = config
 
 	go func() {
+		var err error
+		authenticator, err = auth.Setup(auth.Config{
+			Enabled:          cfg.AuthEnabled,
+			APIKeys:          cfg.AuthAPIKeys,
+			JWTSecret:        cfg.AuthJWTSecret,
+			JWTPublicKeyFile: cfg.AuthJWTPublicKeyFile,
+			Scopes:           cfg.AuthScopes,
+		}, policy)
+		if err != nil {
+			errChan <- fmt.Errorf("could not configure authentication: %v", err)
+			return
+		}
+
 		p := fmt.Sprintf(":%d", cfg.Port)
 		logger.Info("Starting Export Client", zap.String("url", p))
 		errChan <- http.ListenAndServe(p, httpServer())
@@ -13,26 +13,34 @@ import (
 	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/export"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/support/registry"
 
 	"go.uber.org/zap"
 )
 
-const (
-	clientPort int = 48071
-)
+// Endpoint of the client API, the configured host and port are used when the registry can't resolve it
+func getClientEndpoint(path string) registry.Endpoint {
+	return registry.Endpoint{
+		ServiceName: cfg.ClientServiceName,
+		URL:         "http://" + cfg.ClientHost + ":" + strconv.Itoa(cfg.ClientPort) + path,
+	}
+}
 
-func getRegistrationBaseURL(host string) string {
-	return "http://" + host + ":" + strconv.Itoa(clientPort) +
-		"/api/v1/registration"
+func getRegistrationBaseURL() string {
+	return getClientEndpoint("/api/v1/registration").Resolve()
 }
 
+// Client of export-client, authenticated when the service has client credentials
+var registrationClient = &http.Client{Transport: auth.Transport(nil)}
+
 func getRegistrations() []export.Registration {
-	url := getRegistrationBaseURL(cfg.ClientHost)
+	url := getRegistrationBaseURL()
 	return getRegistrationsURL(url)
 }
 
 func getRegistrationsURL(url string) []export.Registration {
-	response, err := http.Get(url)
+	response, err := registrationClient.Get(url)
 	if err != nil {
 		logger.Warn("Error getting all registrations", zap.String("url", url))
 		return nil
@@ -55,13 +63,13 @@ func getRegistrationsURL(url string) []export.Registration {
 }
 
 func getRegistrationByName(name string) *export.Registration {
-	url := getRegistrationBaseURL(cfg.ClientHost) + "/name/" + name
+	url := getRegistrationBaseURL() + "/name/" + name
 	return getRegistrationByNameURL(url)
 }
 
 func getRegistrationByNameURL(url string) *export.Registration {
 
-	response, err := http.Get(url)
+	response, err := registrationClient.Get(url)
 	if err != nil {
 		logger.Error("Error getting all registrations", zap.String("url", url))
 		return nil
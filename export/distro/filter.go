@@ -63,6 +63,8 @@ func (filter valueDescFilterDetails) Filter(event *models.Event) (bool, *models.
 		Modified: event.Modified,
 		Origin:   event.Origin,
 		Readings: []models.Reading{},
+		Tags:     event.Tags,
+		Location: event.Location,
 	}
 
 	for _, filterId := range filter.valueDescIDs {
@@ -75,3 +77,62 @@ func (filter valueDescFilterDetails) Filter(event *models.Event) (bool, *models.
 	}
 	return len(auxEvent.Readings) > 0, auxEvent
 }
+
+type tagFilterDetails struct {
+	tags map[string]string
+}
+
+func newTagFilter(filter export.Filter) Filterer {
+	filterer := tagFilterDetails{
+		tags: filter.Tags,
+	}
+	return filterer
+}
+
+// Keep the readings carrying all the tags of the filter. The tags of a reading refine the tags
+// of its event, an event without readings is accepted if it carries the tags itself.
+func (filter tagFilterDetails) Filter(event *models.Event) (bool, *models.Event) {
+
+	if event == nil {
+		return false, nil
+	}
+
+	if len(event.Readings) == 0 {
+		return filter.matches(event.Tags, nil), event
+	}
+
+	auxEvent := &models.Event{
+		Pushed:   event.Pushed,
+		Device:   event.Device,
+		Created:  event.Created,
+		Modified: event.Modified,
+		Origin:   event.Origin,
+		Readings: []models.Reading{},
+		Tags:     event.Tags,
+		Location: event.Location,
+	}
+
+	for _, reading := range event.Readings {
+		if filter.matches(event.Tags, reading.Tags) {
+			auxEvent.Readings = append(auxEvent.Readings, reading)
+		}
+	}
+	if len(auxEvent.Readings) == len(event.Readings) {
+		logger.Debug("Event accepted", zap.Any("Event", event))
+		return true, event
+	}
+	return len(auxEvent.Readings) > 0, auxEvent
+}
+
+func (filter tagFilterDetails) matches(eventTags, readingTags map[string]string) bool {
+	for k, v := range filter.tags {
+		value, ok := readingTags[k]
+		if !ok {
+			value, ok = eventTags[k]
+		}
+		if !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
@@ -136,3 +136,41 @@ func TestFilterValue(t *testing.T) {
 		t.Fatal("Event should be one reading, there are ", len(res.Readings))
 	}
 }
+
+func TestFilterTags(t *testing.T) {
+	logger = zap.NewNop()
+	defer logger.Sync()
+
+	f := export.Filter{Tags: map[string]string{"site": "plant-1"}}
+	filter := newTagFilter(f)
+
+	accepted, _ := filter.Filter(nil)
+	if accepted {
+		t.Fatal("Event should be filtered out")
+	}
+
+	// Tag carried by the event
+	event := models.Event{Tags: map[string]string{"site": "plant-1"}}
+	event.Readings = append(event.Readings, models.Reading{Name: descriptor1})
+	accepted, res := filter.Filter(&event)
+	if !accepted || res != &event {
+		t.Fatal("Event should be accepted as is")
+	}
+
+	// Readings refining the tag of the event
+	event.Readings = append(event.Readings, models.Reading{Name: descriptor2, Tags: map[string]string{"site": "plant-2"}})
+	accepted, res = filter.Filter(&event)
+	if !accepted || len(res.Readings) != 1 || res.Readings[0].Name != descriptor1 {
+		t.Fatal("Only the first reading should be accepted")
+	}
+
+	// Tag missing
+	accepted, _ = filter.Filter(&models.Event{Readings: []models.Reading{{Name: descriptor1}}})
+	if accepted {
+		t.Fatal("Event should be filtered")
+	}
+	accepted, _ = filter.Filter(&models.Event{Tags: map[string]string{"site": "plant-1"}})
+	if !accepted {
+		t.Fatal("Event without readings should be accepted")
+	}
+}
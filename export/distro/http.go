@@ -10,10 +10,12 @@ package distro
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +26,9 @@ type httpSender struct {
 
 const mimeTypeJSON = "application/json"
 
+// Passes the trace of the exported events on to the endpoints
+var httpClient = &http.Client{Transport: tracing.Transport(nil)}
+
 // NewHTTPSender - create http sender
 func NewHTTPSender(addr models.Addressable) Sender {
 
@@ -34,10 +39,16 @@ func NewHTTPSender(addr models.Addressable) Sender {
 	return sender
 }
 
-func (sender httpSender) Send(data []byte) {
+func (sender httpSender) Send(ctx context.Context, data []byte) {
 	switch sender.method {
 	case http.MethodPost:
-		response, err := http.Post(sender.url, mimeTypeJSON, bytes.NewReader(data))
+		req, err := http.NewRequest(http.MethodPost, sender.url, bytes.NewReader(data))
+		if err != nil {
+			logger.Error("Error: ", zap.Error(err))
+			return
+		}
+		req.Header.Set("Content-Type", mimeTypeJSON)
+		response, err := httpClient.Do(req.WithContext(ctx))
 		if err != nil {
 			logger.Error("Error: ", zap.Error(err))
 			return
@@ -8,6 +8,7 @@ package distro
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -101,7 +102,7 @@ func TestHttpSender(t *testing.T) {
 				addressableTest.Port = port
 			}
 			sender := NewHTTPSender(addressableTest)
-			sender.Send(msg)
+			sender.Send(context.Background(), msg)
 		})
 	}
 }
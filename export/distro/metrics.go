@@ -0,0 +1,18 @@
+//
+// Copyright (c) 2018 Cavium
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package distro
+
+import (
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+)
+
+var exportSends = metrics.NewCounter("edgex_export_sends_total",
+	"Number of events sent to export endpoints, by registration.", "registration")
+
+func init() {
+	metrics.RegisterQueue("registration_changes", func() int { return len(registrationChanges) })
+}
@@ -9,6 +9,7 @@
 package distro
 
 import (
+	"context"
 	"crypto/tls"
 	"strconv"
 	"strings"
@@ -65,7 +66,7 @@ func NewMqttSender(addr models.Addressable) Sender {
 	return sender
 }
 
-func (sender *mqttSender) Send(data []byte) {
+func (sender *mqttSender) Send(ctx context.Context, data []byte) {
 	if !sender.client.IsConnected() {
 		logger.Info("Connecting to mqtt server")
 		if token := sender.client.Connect(); token.Wait() && token.Error() != nil {
@@ -13,6 +13,7 @@ package distro
 //   registration channel)
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -20,6 +21,10 @@ import (
 	export "github.com/edgexfoundry/edgex-go/export"
 
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
 
 	"go.uber.org/zap"
 )
@@ -35,7 +40,7 @@ func newRegistrationInfo() *registrationInfo {
 	reg := &registrationInfo{}
 
 	reg.chRegistration = make(chan *export.Registration)
-	reg.chEvent = make(chan *models.Event)
+	reg.chEvent = make(chan EventMessage)
 	return reg
 }
 
@@ -113,6 +118,11 @@ func (reg *registrationInfo) update(newReg export.Registration) bool {
 		logger.Debug("Device ID filter added: ", zap.Any("filters", newReg.Filter.DeviceIDs))
 	}
 
+	if len(newReg.Filter.Tags) > 0 {
+		reg.filter = append(reg.filter, newTagFilter(newReg.Filter))
+		logger.Debug("Tag filter added: ", zap.Any("filters", newReg.Filter.Tags))
+	}
+
 	if len(newReg.Filter.ValueDescriptorIDs) > 0 {
 		reg.filter = append(reg.filter, newValueDescFilter(newReg.Filter))
 		logger.Debug("Value descriptor filter added: ", zap.Any("filters", newReg.Filter.ValueDescriptorIDs))
@@ -121,7 +131,10 @@ func (reg *registrationInfo) update(newReg export.Registration) bool {
 	return true
 }
 
-func (reg registrationInfo) processEvent(event *models.Event) {
+func (reg registrationInfo) processEvent(ctx context.Context, event *models.Event) {
+	span, ctx := tracing.StartChildSpan(ctx, "export "+reg.registration.Name)
+	defer span.Finish()
+
 	// Valid Event Filter, needed?
 
 	for _, f := range reg.filter {
@@ -129,6 +142,7 @@ func (reg registrationInfo) processEvent(event *models.Event) {
 		accepted, event = f.Filter(event)
 		if !accepted {
 			logger.Info("Event filtered")
+			span.SetTag("filtered", true)
 			return
 		}
 	}
@@ -149,7 +163,8 @@ func (reg registrationInfo) processEvent(event *models.Event) {
 		encrypted = reg.encrypt.Transform(compressed)
 	}
 
-	reg.sender.Send(encrypted)
+	reg.sender.Send(ctx, encrypted)
+	exportSends.Inc(reg.registration.Name)
 	logger.Debug("Sent event with registration:",
 		zap.Any("Event", event),
 		zap.String("Name", reg.registration.Name))
@@ -160,8 +175,8 @@ func registrationLoop(reg *registrationInfo) {
 		zap.String("Name", reg.registration.Name))
 	for {
 		select {
-		case event := <-reg.chEvent:
-			reg.processEvent(event)
+		case msg := <-reg.chEvent:
+			reg.processEvent(msg.Context, msg.Event)
 
 		case newReg := <-reg.chRegistration:
 			if newReg == nil {
@@ -224,9 +239,24 @@ func updateRunningRegistrations(running map[string]*registrationInfo,
 }
 
 // Loop - registration loop
-func Loop(config Config, errChan chan error, eventCh chan *models.Event) {
+func Loop(config Config, errChan chan error, eventCh chan EventMessage) {
 
 	cfg = config
+	metrics.RegisterQueue("distro_events", func() int { return len(eventCh) })
+	health.Register("export-client", health.ResolvedURLCheck(getClientEndpoint("/api/v1/ping").Resolve))
+
+	var err error
+	authenticator, err = auth.Setup(auth.Config{
+		Enabled:          cfg.AuthEnabled,
+		APIKeys:          cfg.AuthAPIKeys,
+		JWTSecret:        cfg.AuthJWTSecret,
+		JWTPublicKeyFile: cfg.AuthJWTPublicKeyFile,
+		Scopes:           cfg.AuthScopes,
+	}, policy)
+	if err != nil {
+		logger.Error("Could not configure authentication", zap.Error(err))
+		return
+	}
 
 	go func() {
 		p := fmt.Sprintf(":%d", cfg.Port)
@@ -281,14 +311,14 @@ func Loop(config Config, errChan chan error, eventCh chan *models.Event) {
 					zap.Any("update", update))
 			}
 
-		case event := <-eventCh:
+		case msg := <-eventCh:
 			logger.Info("EVENT")
 			for k, reg := range registrations {
 				if reg.deleteMe {
 					delete(registrations, k)
 				} else {
 					// TODO only sent event if it is not blocking
-					reg.chEvent <- event
+					reg.chEvent <- msg
 				}
 			}
 		}
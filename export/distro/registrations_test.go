@@ -7,6 +7,7 @@
 package distro
 
 import (
+	"context"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	"github.com/edgexfoundry/edgex-go/export"
 	"go.uber.org/zap"
@@ -71,7 +72,7 @@ type dummyStruct struct {
 	lastSize int
 }
 
-func (sender *dummyStruct) Send(data []byte) {
+func (sender *dummyStruct) Send(ctx context.Context, data []byte) {
 	sender.count += 1
 	sender.lastSize = len(data)
 }
@@ -92,7 +93,7 @@ func TestRegistrationInfoEvent(t *testing.T) {
 
 	ri := newRegistrationInfo()
 	// no configured should not panic
-	ri.processEvent(&models.Event{})
+	ri.processEvent(context.Background(), &models.Event{})
 
 	dummy := &dummyStruct{}
 
@@ -108,9 +109,9 @@ func TestRegistrationInfoEvent(t *testing.T) {
 
 	ri.filter = append(ri.filter, filter)
 
-	ri.processEvent(&models.Event{
+	ri.processEvent(context.Background(), &models.Event{
 		Device: dummyDev})
-	ri.processEvent(&models.Event{
+	ri.processEvent(context.Background(), &models.Event{
 		Device: filterOutDev})
 	if dummy.count != 1 {
 		t.Fatal("It should send an event")
@@ -158,7 +159,7 @@ func TestRegistrationInfoLoop(t *testing.T) {
 	}
 
 	go func() {
-		ri.chEvent <- &models.Event{}
+		ri.chEvent <- EventMessage{Context: context.Background(), Event: &models.Event{}}
 		ri.chRegistration <- nil
 	}()
 	ri.format = &dummyStruct{}
@@ -210,7 +211,7 @@ func BenchmarkProcessEvent(b *testing.B) {
 
 	b.Run("nil", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			ri.processEvent(&event)
+			ri.processEvent(context.Background(), &event)
 		}
 		b.SetBytes(int64(Dummy.lastSize))
 	})
@@ -220,7 +221,7 @@ func BenchmarkProcessEvent(b *testing.B) {
 
 	b.Run("json_gzip", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			ri.processEvent(&event)
+			ri.processEvent(context.Background(), &event)
 		}
 		b.SetBytes(int64(Dummy.lastSize))
 	})
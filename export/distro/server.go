@@ -13,6 +13,9 @@ import (
 	"net/http"
 
 	"github.com/edgexfoundry/edgex-go/export"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
 	"github.com/go-zoo/bone"
 	"go.uber.org/zap"
 )
@@ -22,6 +25,9 @@ const (
 	apiV1Ping                = "/api/v1/ping"
 )
 
+var authenticator auth.Authenticator
+var policy = authPolicy()
+
 func replyPing(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -66,8 +72,15 @@ func replyNotifyRegistrations(w http.ResponseWriter, r *http.Request) {
 // HTTPServer function
 func httpServer() http.Handler {
 	mux := bone.New()
-	mux.Get(apiV1Ping, http.HandlerFunc(replyPing))
-	mux.Put(apiV1NotifyRegistrations, http.HandlerFunc(replyNotifyRegistrations))
+	mux.Get(apiV1Ping, metrics.Instrument(apiV1Ping, http.HandlerFunc(replyPing)))
+	mux.Put(apiV1NotifyRegistrations, metrics.Instrument(apiV1NotifyRegistrations, http.HandlerFunc(replyNotifyRegistrations)))
+	mux.Get(metrics.ApiMetricsRoute, metrics.Handler())
+	mux.Get(health.ApiLiveRoute, health.LiveHandler())
+	mux.Get(health.ApiReadyRoute, health.ReadyHandler())
+
+	return auth.Middleware(authenticator, policy)(mux)
+}
 
-	return mux
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().Public(apiV1Ping, health.ApiLiveRoute, health.ApiReadyRoute)
 }
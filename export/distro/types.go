@@ -9,21 +9,34 @@
 package distro
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
 	export "github.com/edgexfoundry/edgex-go/export"
 )
 
 const (
-	defaultPort       = 48070
-	defaultClientHost = "127.0.0.1"
-	defaultDataHost   = "127.0.0.1"
-	defaultMQTTSCert  = "dummy.crt"
-	defaultMQTTSKey   = "dummy.key"
+	defaultPort              = 48070
+	defaultClientHost        = "127.0.0.1"
+	defaultClientPort        = 48071
+	defaultClientServiceName = "export-client"
+	defaultDataHost          = "127.0.0.1"
+	defaultDataPort          = 5563
+	defaultDataServiceName   = "core-data"
+	defaultMQTTSCert         = "dummy.crt"
+	defaultMQTTSKey          = "dummy.key"
 )
 
 // Sender - Send interface
 type Sender interface {
-	Send(data []byte)
+	// The data is sent within the trace of ctx, if any
+	Send(ctx context.Context, data []byte)
+}
+
+// EventMessage - event received from core data with the context of the trace it is part of
+type EventMessage struct {
+	Context context.Context
+	Event   *models.Event
 }
 
 // Formater - Format interface
@@ -51,27 +64,57 @@ type registrationInfo struct {
 	filter       []Filterer
 
 	chRegistration chan *export.Registration
-	chEvent        chan *models.Event
+	chEvent        chan EventMessage
 
 	deleteMe bool
 }
 
+// Fields tagged env are overridden by their variable, see pkg/config.ApplyEnvironment
 type Config struct {
-	Port       int
-	ClientHost string
-	DataHost   string
-	MQTTSCert  string
-	MQTTSKey   string
+	Port int
+
+	// Export client, resolved through the registry by its service name when registered there
+	ClientHost        string `env:"EXPORT_DISTRO_CLIENT_HOST"`
+	ClientPort        int    `env:"EXPORT_DISTRO_CLIENT_PORT"`
+	ClientServiceName string `env:"EXPORT_DISTRO_CLIENT_SERVICE"`
+
+	// Event publisher of core data, its host is resolved through the registry by the service name
+	// of core data when registered there
+	DataHost        string `env:"EXPORT_DISTRO_DATA_HOST"`
+	DataPort        int    `env:"EXPORT_DISTRO_DATA_PORT"`
+	DataServiceName string `env:"EXPORT_DISTRO_DATA_SERVICE"`
+
+	MQTTSCert string `env:"EXPORT_DISTRO_MQTTS_CERT_FILE"`
+	MQTTSKey  string `env:"EXPORT_DISTRO_MQTTS_KEY_FILE"`
+
+	// Authentication of the requests, the scope rules are added to the policy of the routes
+	AuthEnabled          bool   `env:"EXPORT_DISTRO_AUTH_ENABLED"`
+	AuthAPIKeys          string `env:"EXPORT_DISTRO_AUTH_API_KEYS"`
+	AuthJWTSecret        string `env:"EXPORT_DISTRO_AUTH_JWT_SECRET"`
+	AuthJWTPublicKeyFile string `env:"EXPORT_DISTRO_AUTH_JWT_PUBLIC_KEY_FILE"`
+	AuthScopes           string `env:"EXPORT_DISTRO_AUTH_SCOPES"`
+
+	// Credentials of the calls to the other services
+	AuthClientAPIKey string `env:"EXPORT_DISTRO_AUTH_CLIENT_API_KEY"`
+	AuthClientToken  string `env:"EXPORT_DISTRO_AUTH_CLIENT_TOKEN"`
+
+	// Tracing is enabled when an exporter is set
+	TracingExporter    string `env:"EXPORT_DISTRO_TRACING_EXPORTER"`
+	TracingDestination string `env:"EXPORT_DISTRO_TRACING_DESTINATION"`
 }
 
 var cfg Config
 
 func GetDefaultConfig() Config {
 	return Config{
-		Port:       defaultPort,
-		ClientHost: defaultClientHost,
-		DataHost:   defaultDataHost,
-		MQTTSCert:  defaultMQTTSCert,
-		MQTTSKey:   defaultMQTTSKey,
+		Port:              defaultPort,
+		ClientHost:        defaultClientHost,
+		ClientPort:        defaultClientPort,
+		ClientServiceName: defaultClientServiceName,
+		DataHost:          defaultDataHost,
+		DataPort:          defaultDataPort,
+		DataServiceName:   defaultDataServiceName,
+		MQTTSCert:         defaultMQTTSCert,
+		MQTTSKey:          defaultMQTTSKey,
 	}
 }
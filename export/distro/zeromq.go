@@ -7,53 +7,37 @@
 package distro
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
-	"github.com/edgexfoundry/edgex-go/core/domain/models"
-	zmq "github.com/pebbe/zmq4"
+	"github.com/edgexfoundry/edgex-go/pkg/messagebus"
+	"github.com/edgexfoundry/edgex-go/pkg/tracing"
+	"github.com/edgexfoundry/edgex-go/support/registry"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"go.uber.org/zap"
 )
 
-const (
-	zeroMQPort = 5563
-)
-
-func ZeroMQReceiver(eventCh chan *models.Event) {
-	go initZmq(eventCh)
-}
-
-func initZmq(eventCh chan *models.Event) {
-	q, _ := zmq.NewSocket(zmq.SUB)
-	defer q.Close()
-
-	logger.Info("Connecting to zmq...")
-	url := fmt.Sprintf("tcp://%s:%d", cfg.DataHost, zeroMQPort)
-	q.Connect(url)
-	logger.Info("Connected to zmq")
-	q.SetSubscribe("")
-
-	for {
-		msg, err := q.RecvMessage(0)
-		if err != nil {
-			id, _ := q.GetIdentity()
-			logger.Error("Error getting mesage", zap.String("id", id))
-		} else {
-			for _, str := range msg {
-				event := parseEvent(str)
-				logger.Info("Event received", zap.Any("event", event))
-				eventCh <- event
-			}
+// Host of core data, the publisher listens on its own port on the same host
+func getDataHost(config Config) string {
+	if config.DataServiceName != "" {
+		if instance, err := registry.ServiceInstance(config.DataServiceName); err == nil {
+			return instance.Address
 		}
 	}
+	return config.DataHost
 }
 
-func parseEvent(str string) *models.Event {
-	event := models.Event{}
-
-	if err := json.Unmarshal([]byte(str), &event); err != nil {
-		logger.Error("Failed to parse event", zap.Error(err))
-		return nil
-	}
-	return &event
+func ZeroMQReceiver(config Config, eventCh chan EventMessage) {
+	url := fmt.Sprintf("tcp://%s:%d", getDataHost(config), config.DataPort)
+	messagebus.Subscribe(url, logger, func(m messagebus.Message) {
+		logger.Info("Event received", zap.Any("event", m.Event))
+
+		// The export of the event continues the trace of its publication
+		span := opentracing.StartSpan("receive event",
+			opentracing.FollowsFrom(tracing.FromMessageHeaders(m.Headers)), ext.SpanKindConsumer)
+		event := m.Event
+		eventCh <- EventMessage{Context: opentracing.ContextWithSpan(context.Background(), span), Event: &event}
+		span.Finish()
+	})
 }
@@ -10,4 +10,6 @@ package export
 type Filter struct {
 	DeviceIDs          []string `bson:"deviceIdentifiers,omitempty" json:"deviceIdentifiers,omitempty"`
 	ValueDescriptorIDs []string `bson:"valueDescriptorIdentifiers,omitempty" json:"valueDescriptorIdentifiers,omitempty"`
+	// Tags the readings must carry, themselves or through their event
+	Tags map[string]string `bson:"tags,omitempty" json:"tags,omitempty"`
 }
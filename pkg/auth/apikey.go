@@ -0,0 +1,69 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header carrying a static API key
+const APIKeyHeader = "X-API-Key"
+
+// Static API keys and the scope each one grants
+type APIKeys map[string]Scope
+
+// Parse comma separated key:scope pairs
+func ParseAPIKeys(s string) (APIKeys, error) {
+	keys := make(APIKeys)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("API key %q must be formatted as key:scope", pair)
+		}
+		scope, err := ParseScope(pair[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		keys[pair[:i]] = scope
+	}
+	return keys, nil
+}
+
+func (k APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	// Compare digests in constant time so the timing does not leak the keys
+	digest := sha256.Sum256([]byte(key))
+	for known, scope := range k {
+		d := sha256.Sum256([]byte(known))
+		if subtle.ConstantTimeCompare(digest[:], d[:]) == 1 {
+			return Principal{Subject: "apikey", Scope: scope}, nil
+		}
+	}
+	return Principal{}, ErrInvalidCredentials
+}
@@ -0,0 +1,189 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package auth authenticates REST requests with static API keys or JWT bearer tokens
+// and authorizes them against the scope each route requires.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Scope granted to a caller or required by a route, each scope includes the lower ones
+type Scope int
+
+const (
+	ScopeNone Scope = iota
+	ScopeRead
+	ScopeWrite
+	ScopeAdmin
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeRead:
+		return "read"
+	case ScopeWrite:
+		return "write"
+	case ScopeAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+func ParseScope(s string) (Scope, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "read":
+		return ScopeRead, nil
+	case "write":
+		return ScopeWrite, nil
+	case "admin":
+		return ScopeAdmin, nil
+	default:
+		return ScopeNone, fmt.Errorf("unknown scope %q", s)
+	}
+}
+
+// Authenticated caller
+type Principal struct {
+	Subject string
+	Scope   Scope
+}
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// An Authenticator identifies the caller of a request.
+// It returns ErrNoCredentials when the request carries none of the credentials it handles.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Try each authenticator in turn until one finds credentials in the request
+type chain []Authenticator
+
+func (c chain) Authenticate(r *http.Request) (Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if err != ErrNoCredentials {
+			return p, err
+		}
+	}
+	return Principal{}, ErrNoCredentials
+}
+
+// Settings shared by the services, usually part of their configuration
+type Config struct {
+	Enabled bool
+	// Comma separated key:scope pairs, e.g. "k3y:admin,0th3r:read"
+	APIKeys string
+	// Shared secret of HS256 tokens
+	JWTSecret string
+	// PEM encoded RSA public key verifying RS256 tokens
+	JWTPublicKeyFile string
+	// Comma separated scope rules of the service, see Policy.Configure
+	Scopes string
+}
+
+// Add the scope rules of the configuration to the policy of the service,
+// then build the authenticator, nil when authentication is disabled
+func Setup(cfg Config, p *Policy) (Authenticator, error) {
+	if _, err := p.Configure(cfg.Scopes); err != nil {
+		return nil, err
+	}
+	return NewAuthenticator(cfg)
+}
+
+// Build the authenticator described by the configuration, nil when authentication is disabled
+func NewAuthenticator(cfg Config) (Authenticator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var c chain
+	if cfg.APIKeys != "" {
+		keys, err := ParseAPIKeys(cfg.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, keys)
+	}
+	if cfg.JWTSecret != "" || cfg.JWTPublicKeyFile != "" {
+		j := &JWT{}
+		if cfg.JWTSecret != "" {
+			j.Secret = []byte(cfg.JWTSecret)
+		}
+		if cfg.JWTPublicKeyFile != "" {
+			pem, err := ioutil.ReadFile(cfg.JWTPublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read JWT public key: %v", err)
+			}
+			if j.PublicKey, err = ParseRSAPublicKey(pem); err != nil {
+				return nil, err
+			}
+		}
+		c = append(c, j)
+	}
+	if len(c) == 0 {
+		return nil, errors.New("authentication enabled without API keys or JWT keys")
+	}
+	return c, nil
+}
+
+type principalKey struct{}
+
+// Caller of an authenticated request
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Reject requests whose caller can't be authenticated (401) or lacks the scope of the route (403).
+// A nil authenticator disables the checks.
+func Middleware(a Authenticator, p *Policy) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		if a == nil {
+			return h
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required := p.Required(r.Method, r.URL.Path)
+			if required == ScopeNone {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := a.Authenticate(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="edgex"`)
+				http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if principal.Scope < required {
+				http.Error(w, "Forbidden: "+required.String()+" scope required", http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+		})
+	}
+}
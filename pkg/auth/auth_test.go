@@ -0,0 +1,313 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const testSecret = "s3cr3t"
+
+func testPolicy() *Policy {
+	return NewPolicy().
+		Public("/api/v1/ping", "/api/v1/health/ready").
+		Route(http.MethodDelete, "/api/v1/event/scruball", ScopeAdmin).
+		Route(http.MethodPut, "/api/v1/device/{id}/adminstate/:state", ScopeAdmin)
+}
+
+func TestPolicy(t *testing.T) {
+	p := testPolicy()
+	tests := []struct {
+		method string
+		path   string
+		want   Scope
+	}{
+		{http.MethodGet, "/api/v1/ping", ScopeNone},
+		{http.MethodPost, "/api/v1/ping", ScopeNone},
+		{http.MethodGet, "/api/v1/event", ScopeRead},
+		{http.MethodPost, "/api/v1/event", ScopeWrite},
+		{http.MethodDelete, "/api/v1/event/id/1234", ScopeWrite},
+		{http.MethodDelete, "/api/v1/event/scruball", ScopeAdmin},
+		{http.MethodPut, "/api/v1/device/1234/adminstate/LOCKED", ScopeAdmin},
+		{http.MethodPut, "/api/v1/device//adminstate/LOCKED", ScopeWrite},
+		{http.MethodGet, "/api/v1/device/1234/adminstate/LOCKED", ScopeRead},
+	}
+	for _, tt := range tests {
+		if got := p.Required(tt.method, tt.path); got != tt.want {
+			t.Errorf("Required(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyConfigure(t *testing.T) {
+	p, err := NewPolicy().Public("/api/v1/ping").
+		Configure(" DELETE:admin, put /api/v1/device/{id}/adminstate/:state:admin,/api/v1/config:none,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method string
+		path   string
+		want   Scope
+	}{
+		{http.MethodDelete, "/api/v1/ping", ScopeNone},
+		{http.MethodDelete, "/api/v1/event/id/1234", ScopeAdmin},
+		{http.MethodPut, "/api/v1/device/1234/adminstate/LOCKED", ScopeAdmin},
+		{http.MethodPut, "/api/v1/device", ScopeWrite},
+		{http.MethodPost, "/api/v1/config", ScopeNone},
+	}
+	for _, tt := range tests {
+		if got := p.Required(tt.method, tt.path); got != tt.want {
+			t.Errorf("Required(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+
+	// The configuration overrides the rules of the service
+	p, err = NewPolicy().Route(http.MethodDelete, "/api/v1/event/scruball", ScopeAdmin).
+		Configure("DELETE /api/v1/event/scruball:write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Required(http.MethodDelete, "/api/v1/event/scruball"); got != ScopeWrite {
+		t.Errorf("Required(DELETE /api/v1/event/scruball) = %s, want %s", got, ScopeWrite)
+	}
+
+	for _, bad := range []string{"DELETE", "DELETE:root", "PUT /a /b:read", "PUT event:read"} {
+		if _, err := NewPolicy().Configure(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("reader:read, admin:key:admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys["reader"] != ScopeRead || keys["admin:key"] != ScopeAdmin {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	for _, bad := range []string{"nokey", "key:root", ":read"} {
+		if _, err := ParseAPIKeys(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func signed(t *testing.T, method jwt.SigningMethod, key interface{}, claims Claims) string {
+	s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func claims(scope string, ttl time.Duration) Claims {
+	return Claims{Scope: scope, StandardClaims: jwt.StandardClaims{
+		Subject: "tester", ExpiresAt: time.Now().Add(ttl).Unix()}}
+}
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	j := &JWT{Secret: []byte(testSecret), PublicKey: &rsaKey.PublicKey}
+	hsOnly := &JWT{Secret: []byte(testSecret)}
+
+	tests := []struct {
+		name    string
+		a       *JWT
+		token   string
+		want    Scope
+		wantErr bool
+	}{
+		{"HS256", j, signed(t, jwt.SigningMethodHS256, []byte(testSecret), claims("read write", time.Hour)), ScopeWrite, false},
+		{"RS256", j, signed(t, jwt.SigningMethodRS256, rsaKey, claims("admin", time.Hour)), ScopeAdmin, false},
+		{"unknown scope ignored", j, signed(t, jwt.SigningMethodHS256, []byte(testSecret), claims("root read", time.Hour)), ScopeRead, false},
+		{"wrong secret", j, signed(t, jwt.SigningMethodHS256, []byte("other"), claims("admin", time.Hour)), ScopeNone, true},
+		{"wrong RSA key", j, signed(t, jwt.SigningMethodRS256, otherKey, claims("admin", time.Hour)), ScopeNone, true},
+		{"expired", j, signed(t, jwt.SigningMethodHS256, []byte(testSecret), claims("admin", -time.Hour)), ScopeNone, true},
+		{"RS256 not configured", hsOnly, signed(t, jwt.SigningMethodRS256, rsaKey, claims("admin", time.Hour)), ScopeNone, true},
+		{"alg none", j, signed(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("admin", time.Hour)), ScopeNone, true},
+		{"garbage", j, "not.a.token", ScopeNone, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+			p, err := tt.a.Authenticate(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p.Scope != tt.want {
+				t.Errorf("Authenticate() scope = %s, want %s", p.Scope, tt.want)
+			}
+		})
+	}
+
+	if _, err := j.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil)); err != ErrNoCredentials {
+		t.Errorf("expected ErrNoCredentials without header, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	a, err := NewAuthenticator(Config{Enabled: true, APIKeys: "r:read,a:admin", JWTSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var caller Principal
+	h := Middleware(a, testPolicy())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = FromContext(r.Context())
+	}))
+
+	writer := signed(t, jwt.SigningMethodHS256, []byte(testSecret), claims("write", time.Hour))
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		bearer string
+		want   int
+	}{
+		{"public", http.MethodGet, "/api/v1/ping", "", "", http.StatusOK},
+		{"anonymous", http.MethodGet, "/api/v1/event", "", "", http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/api/v1/event", "nope", "", http.StatusUnauthorized},
+		{"read key reads", http.MethodGet, "/api/v1/event", "r", "", http.StatusOK},
+		{"read key can't write", http.MethodPost, "/api/v1/event", "r", "", http.StatusForbidden},
+		{"token writes", http.MethodPost, "/api/v1/event", "", writer, http.StatusOK},
+		{"token can't scrub", http.MethodDelete, "/api/v1/event/scruball", "", writer, http.StatusForbidden},
+		{"admin key scrubs", http.MethodDelete, "/api/v1/event/scruball", "a", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+			if tt.bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 responses should carry WWW-Authenticate")
+			}
+		})
+	}
+	if caller.Subject != "apikey" || caller.Scope != ScopeAdmin {
+		t.Errorf("unexpected principal in context %v", caller)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	a, err := NewAuthenticator(Config{APIKeys: "r:read"})
+	if err != nil || a != nil {
+		t.Fatalf("disabled auth should return no authenticator, got %v %v", a, err)
+	}
+	h := Middleware(a, NewPolicy())(http.NotFoundHandler())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/event/scruball", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("disabled auth should pass requests through, got %d", w.Code)
+	}
+
+	if _, err := NewAuthenticator(Config{Enabled: true}); err == nil {
+		t.Error("expected an error when enabled without credentials")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var key, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, authorization = r.Header.Get(APIKeyHeader), r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+	defer SetClientCredentials(Credentials{})
+	client := &http.Client{Transport: Transport(nil)}
+
+	SetClientCredentials(Credentials{APIKey: "k3y", Token: "t0ken"})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if key != "k3y" || authorization != "Bearer t0ken" {
+		t.Errorf("credentials not sent, got %q %q", key, authorization)
+	}
+	if req.Header.Get(APIKeyHeader) != "" {
+		t.Error("the transport modified the request of the caller")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set(APIKeyHeader, "own")
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if key != "own" || authorization != "" {
+		t.Errorf("credentials of the request overridden, got %q %q", key, authorization)
+	}
+
+	SetClientCredentials(Credentials{})
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if key != "" || authorization != "" {
+		t.Errorf("credentials sent without configuration, got %q %q", key, authorization)
+	}
+}
+
+func TestPublicKeyFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	dir, _ := ioutil.TempDir("", "auth")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)
+
+	a, err := NewAuthenticator(Config{Enabled: true, JWTPublicKeyFile: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+signed(t, jwt.SigningMethodRS256, rsaKey, claims("read", time.Hour)))
+	if p, err := a.Authenticate(r); err != nil || p.Scope != ScopeRead {
+		t.Errorf("Authenticate() = %v, %v", p, err)
+	}
+
+	if _, err := NewAuthenticator(Config{Enabled: true, JWTPublicKeyFile: filepath.Join(dir, "missing.pem")}); err == nil {
+		t.Error("expected an error for a missing key file")
+	}
+}
@@ -0,0 +1,84 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package auth
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Credentials a service presents when it calls the other services
+type Credentials struct {
+	// Sent in the X-API-Key header
+	APIKey string
+	// JWT sent as a bearer token
+	Token string
+}
+
+var (
+	credentialsMutex sync.RWMutex
+	credentials      Credentials
+)
+
+// Set the credentials added by Transport, usually once at startup from the configuration
+func SetClientCredentials(c Credentials) {
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+	credentials = c
+}
+
+func clientCredentials() Credentials {
+	credentialsMutex.RLock()
+	defer credentialsMutex.RUnlock()
+	return credentials
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Round tripper adding the client credentials to the requests that carry none of their own.
+// Only the clients of the EdgeX services use it, the credentials must not leak to devices or
+// export endpoints. A nil base means http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := clientCredentials()
+	if (c.APIKey == "" && c.Token == "") || req.Header.Get(APIKeyHeader) != "" || req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// Round trippers must not modify the request they are given
+	out := new(http.Request)
+	*out = *req
+	out.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		out.Header[k] = v
+	}
+	if c.APIKey != "" {
+		out.Header.Set(APIKeyHeader, c.APIKey)
+	}
+	if c.Token != "" {
+		out.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,82 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package auth
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// Claims of the bearer tokens. Scope lists the granted scopes separated by spaces.
+type Claims struct {
+	Scope string `json:"scope"`
+	jwt.StandardClaims
+}
+
+// JWT verifies bearer tokens signed with HS256 (Secret) or RS256 (PublicKey).
+// Only the algorithms whose key is set are accepted.
+type JWT struct {
+	Secret    []byte
+	PublicKey *rsa.PublicKey
+}
+
+func ParseRSAPublicKey(pem []byte) (*rsa.PublicKey, error) {
+	key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RSA public key: %v", err)
+	}
+	return key, nil
+}
+
+func (j *JWT) Authenticate(r *http.Request) (Principal, error) {
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, "Bearer ") {
+		return Principal{}, ErrNoCredentials
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(h, "Bearer "), claims, j.key)
+	if err != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	p := Principal{Subject: claims.Subject}
+	for _, s := range strings.Fields(claims.Scope) {
+		if scope, err := ParseScope(s); err == nil && scope > p.Scope {
+			p.Scope = scope
+		}
+	}
+	return p, nil
+}
+
+func (j *JWT) key(t *jwt.Token) (interface{}, error) {
+	switch t.Method {
+	case jwt.SigningMethodHS256:
+		if j.Secret != nil {
+			return j.Secret, nil
+		}
+	case jwt.SigningMethodRS256:
+		if j.PublicKey != nil {
+			return j.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+}
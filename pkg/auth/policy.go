@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Policy tells the scope each route of a service requires.
+// Routes are path templates whose {var} or :var segments match any value.
+// Rules are checked in the order they were added, then the default of the method applies:
+// read for GET and HEAD, write for anything else.
+type Policy struct {
+	rules   []rule
+	methods map[string]Scope
+}
+
+type rule struct {
+	method   string // empty for any method
+	segments []string
+	scope    Scope
+}
+
+func NewPolicy() *Policy {
+	return &Policy{methods: map[string]Scope{
+		http.MethodGet:     ScopeRead,
+		http.MethodHead:    ScopeRead,
+		http.MethodOptions: ScopeNone,
+	}}
+}
+
+// Change the default scope of a method
+func (p *Policy) Method(method string, scope Scope) *Policy {
+	p.methods[method] = scope
+	return p
+}
+
+// Require a scope for a route, for the given method or any method if empty
+func (p *Policy) Route(method string, template string, scope Scope) *Policy {
+	p.rules = append(p.rules, rule{method: method, segments: split(template), scope: scope})
+	return p
+}
+
+// Leave routes open to everyone for any method
+func (p *Policy) Public(templates ...string) *Policy {
+	for _, t := range templates {
+		p.Route("", t, ScopeNone)
+	}
+	return p
+}
+
+// Add the rules of a configuration. Rules are comma separated and formatted as
+// "METHOD /route:scope", "/route:scope" for any method, or "METHOD:scope" to change the
+// default of a method, e.g. "DELETE:admin,PUT /api/v1/device/{id}/adminstate/{state}:admin".
+// The scope none leaves a route public. The route rules come before those of the policy,
+// so the configuration overrides the defaults of the service.
+func (p *Policy) Configure(rules string) (*Policy, error) {
+	configured := NewPolicy()
+	methods := map[string]Scope{}
+	for _, r := range strings.Split(rules, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		i := strings.LastIndex(r, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("scope rule %q has no scope", r)
+		}
+		scope := ScopeNone
+		if s := r[i+1:]; strings.ToLower(strings.TrimSpace(s)) != ScopeNone.String() {
+			var err error
+			if scope, err = ParseScope(s); err != nil {
+				return nil, fmt.Errorf("scope rule %q: %v", r, err)
+			}
+		}
+
+		fields := strings.Fields(r[:i])
+		switch {
+		case len(fields) == 1 && strings.HasPrefix(fields[0], "/"):
+			configured.Route("", fields[0], scope)
+		case len(fields) == 1:
+			methods[strings.ToUpper(fields[0])] = scope
+		case len(fields) == 2 && strings.HasPrefix(fields[1], "/"):
+			configured.Route(strings.ToUpper(fields[0]), fields[1], scope)
+		default:
+			return nil, fmt.Errorf("scope rule %q must be formatted as [METHOD] [/route]:scope", r)
+		}
+	}
+
+	p.rules = append(configured.rules, p.rules...)
+	for method, scope := range methods {
+		p.Method(method, scope)
+	}
+	return p, nil
+}
+
+// Scope required to call method on path, ScopeNone for public routes
+func (p *Policy) Required(method string, path string) Scope {
+	segments := split(path)
+	for _, r := range p.rules {
+		if (r.method == "" || r.method == method) && r.matches(segments) {
+			return r.scope
+		}
+	}
+	if s, ok := p.methods[method]; ok {
+		return s
+	}
+	return ScopeWrite
+}
+
+func (r rule) matches(segments []string) bool {
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, s := range r.segments {
+		if isVariable(s) {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isVariable(segment string) bool {
+	return strings.HasPrefix(segment, ":") || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+}
+
+func split(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
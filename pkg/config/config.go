@@ -22,6 +22,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -29,37 +30,70 @@ import (
 const (
 	configDirectory = "./res"
 	configDefault = "configuration.toml"
-	configDocker = "configuration-docker.toml"
 	configUnitTest = "configuration-test.toml"
+	defaultProfile = "default"
 )
 
 var confDir = flag.String("confdir", "", "Specify local configuration directory")
 
+// Load the configuration of the profile, then apply the environment overrides and validate it
 func LoadFromFile(profile string, configuration interface{}) error {
+	_, err := Load(profile, configuration)
+	return err
+}
+
+/*
+ * Load the configuration of the profile: the configuration.toml base, then the
+ * configuration-<profile>.toml file of the profile over it, then the environment overrides.
+ * The base is optional when a profile is given. The configuration is validated and the
+ * report tells where every field comes from.
+ */
+func Load(profile string, configuration interface{}) (Report, error) {
 	path := determinePath()
-	fileName := determineConfigFile(profile)
-	contents, err := ioutil.ReadFile(path + "/" + fileName)
-	if err != nil {
-		return fmt.Errorf("could not load configuration file (%s): %v", path, err.Error())
+	files := determineConfigFiles(profile)
+	origins := make(map[string]string)
+	for i, fileName := range files {
+		contents, err := ioutil.ReadFile(path + "/" + fileName)
+		if os.IsNotExist(err) && i == 0 && len(files) > 1 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not load configuration file (%s): %v", path, err.Error())
+		}
+
+		// Decode the configuration from TOML, over the previous files
+		md, err := toml.Decode(string(contents), configuration)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse configuration file (%s): %v", path, err.Error())
+		}
+		for _, key := range md.Keys() {
+			origins[strings.ToLower(key[0])] = fileName
+		}
 	}
 
-	// Decode the configuration from TOML
-	err = toml.Unmarshal(contents, configuration)
+	variables, err := applyEnvironment(configuration)
 	if err != nil {
-		return fmt.Errorf("unable to parse configuration file (%s): %v", path, err.Error())
+		return nil, err
+	}
+	for field, variable := range variables {
+		origins[strings.ToLower(field)] = "environment " + variable
 	}
 
-	return nil
+	if err := Validate(configuration); err != nil {
+		return nil, err
+	}
+	return newReport(configuration, origins), nil
 }
 
-func determineConfigFile(profile string) string {
+// Files of the profile in the order they apply
+func determineConfigFiles(profile string) []string {
 	switch profile {
-	case "docker":
-		return configDocker
+	case "", defaultProfile:
+		return []string{configDefault}
 	case "unit-test":
-		return configUnitTest
+		return []string{configDefault, configUnitTest}
 	default:
-		return configDefault
+		return []string{configDefault, "configuration-" + profile + ".toml"}
 	}
 }
 
@@ -18,8 +18,11 @@
 package config
 
 import (
-	"testing"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 )
 
 type TestConfigurationStruct struct {
@@ -50,3 +53,92 @@ func TestLoadFromEnvironment(t *testing.T) {
 		t.Errorf("configuration.ApplicationName is zero length.")
 	}
 }
+
+type layeredConfiguration struct {
+	ApplicationName string
+	ServicePort     int    `validate:"min=1,max=65535"`
+	MongoDBHost     string `validate:"required"`
+	MongoDBPassword string
+	ReadMaxLimit    int
+	BrokerURL       string `env:"TEST_BROKER_URL"`
+}
+
+func writeConfigurationFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadLayeredProfile(t *testing.T) {
+	dir := writeConfigurationFiles(t, map[string]string{
+		configDefault: "ApplicationName = 'core-test'\nServicePort = 48080\nMongoDBHost = 'localhost'\nMongoDBPassword = 'password'\nReadMaxLimit = 100\n",
+		"configuration-staging.toml": "MongoDBHost = 'edgex-mongo'\n",
+	})
+	defer os.RemoveAll(dir)
+	defer os.Setenv("EDGEX_CONF_DIR", os.Getenv("EDGEX_CONF_DIR"))
+	os.Setenv("EDGEX_CONF_DIR", dir)
+	os.Setenv("EDGEX_CORE_TEST_READMAXLIMIT", "200")
+	defer os.Unsetenv("EDGEX_CORE_TEST_READMAXLIMIT")
+	os.Setenv("TEST_BROKER_URL", "tcp://broker:1883")
+	defer os.Unsetenv("TEST_BROKER_URL")
+
+	configuration := &layeredConfiguration{}
+	report, err := Load("staging", configuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := layeredConfiguration{ApplicationName: "core-test", ServicePort: 48080, MongoDBHost: "edgex-mongo",
+		MongoDBPassword: "password", ReadMaxLimit: 200, BrokerURL: "tcp://broker:1883"}
+	if *configuration != expected {
+		t.Errorf("unexpected configuration %v", *configuration)
+	}
+
+	origins := map[string]string{
+		"ApplicationName": configDefault,
+		"MongoDBHost":     "configuration-staging.toml",
+		"ReadMaxLimit":    "environment EDGEX_CORE_TEST_READMAXLIMIT",
+		"BrokerURL":       "environment TEST_BROKER_URL",
+	}
+	for _, s := range report {
+		if origin, ok := origins[s.Field]; ok && s.Origin != origin {
+			t.Errorf("%s comes from %s, not %s", s.Field, s.Origin, origin)
+		}
+		if s.Field == "MongoDBPassword" && s.Value != secretMask {
+			t.Errorf("password reported as %s", s.Value)
+		}
+	}
+	if strings.Contains(report.String(), "'password'") {
+		t.Error("secret in the report")
+	}
+
+	if err := LoadFromFile("missing", &layeredConfiguration{}); err == nil {
+		t.Error("missing profile file should fail")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := writeConfigurationFiles(t, map[string]string{
+		configDefault: "ApplicationName = 'core-test'\nServicePort = 480800\n",
+	})
+	defer os.RemoveAll(dir)
+	defer os.Setenv("EDGEX_CONF_DIR", os.Getenv("EDGEX_CONF_DIR"))
+	os.Setenv("EDGEX_CONF_DIR", dir)
+
+	err := LoadFromFile("", &layeredConfiguration{})
+	if err == nil || !strings.Contains(err.Error(), "ServicePort") || !strings.Contains(err.Error(), "MongoDBHost") {
+		t.Errorf("every invalid field should be reported: %v", err)
+	}
+
+	os.Setenv("EDGEX_CORE_TEST_SERVICEPORT", "port")
+	defer os.Unsetenv("EDGEX_CORE_TEST_SERVICEPORT")
+	if err := LoadFromFile("", &layeredConfiguration{}); err == nil || !strings.Contains(err.Error(), "EDGEX_CORE_TEST_SERVICEPORT") {
+		t.Errorf("invalid variable should be reported: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The variable overriding a field is named by its env tag, or derived from the application name
+// and the field name: EDGEX_CORE_DATA_MONGODBHOST overrides MongoDBHost of core-data, and of
+// edgex-core-data as the docker profile names it.
+const (
+	envTag    = "env"
+	envPrefix = "EDGEX_"
+)
+
+/*
+ * Override the fields of the configuration struct from the environment. Empty variables
+ * are ignored. Fields without env tag are only overridden when the struct has an
+ * ApplicationName to derive their variable from. The valid variables are applied
+ * even when the error reports invalid ones.
+ */
+func ApplyEnvironment(configuration interface{}) error {
+	_, err := applyEnvironment(configuration)
+	return err
+}
+
+// Variables applied, by field name
+func applyEnvironment(configuration interface{}) (map[string]string, error) {
+	v := reflect.ValueOf(configuration).Elem()
+	prefix := ""
+	if app := v.FieldByName("ApplicationName"); app.IsValid() && app.Kind() == reflect.String {
+		if name := strings.TrimPrefix(strings.ToLower(app.String()), "edgex-"); name != "" {
+			prefix = envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1)) + "_"
+		}
+	}
+
+	applied := make(map[string]string)
+	var invalid []string
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		variable := envVariable(f, prefix)
+		if variable == "" {
+			continue
+		}
+		value := os.Getenv(variable)
+		if value == "" {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s for %s: %v", variable, f.Name, err))
+			continue
+		}
+		applied[f.Name] = variable
+	}
+	if len(invalid) > 0 {
+		return applied, errors.New("invalid environment variables: " + strings.Join(invalid, ", "))
+	}
+	return applied, nil
+}
+
+// Name of the variable overriding the field, empty when it has none
+func envVariable(f reflect.StructField, prefix string) string {
+	if tag := f.Tag.Get(envTag); tag != "" {
+		if tag == "-" {
+			return ""
+		}
+		return tag
+	}
+	if prefix == "" {
+		return ""
+	}
+	return prefix + strings.ToUpper(f.Name)
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("fields of kind %s can't be set from the environment", field.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+// Fields of a configuration struct tagged `reload:"true"` can change while the service
+// runs, changes of the other fields need a restart.
+const reloadTag = "reload"
+
+// Time given to an editor to finish writing the configuration file
+const fileSettleTime = 200 * time.Millisecond
+
+// Change of a reloadable field
+type Change struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Applies the updates of a configuration struct. Every update is applied to a copy of the
+// configuration in use, which is then swapped in, so readers never see it half updated.
+type Reloader struct {
+	mutex       sync.Mutex
+	current     interface{} // Pointer to the configuration struct in use
+	swap        func(next interface{})
+	subscribers []func(changes []Change)
+	logger      logger.LoggingClient
+}
+
+// Reloader of the configuration struct pointed by current, swap replaces the configuration in use
+func NewReloader(current interface{}, swap func(next interface{}), l logger.LoggingClient) *Reloader {
+	return &Reloader{current: current, swap: swap, logger: l}
+}
+
+// Call fn after every change of the reloadable fields, with the new configuration swapped in
+func (r *Reloader) Subscribe(fn func(changes []Change)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Apply the fields that differ between two successive versions of a configuration source.
+// Only the fields of the source are compared, so a source doesn't revert the fields set by
+// another one. Changes of fields needing a restart are refused and reported by the error,
+// the reloadable changes are applied in any case.
+func (r *Reloader) Apply(source string, previous interface{}, next interface{}) ([]Change, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cur := reflect.ValueOf(r.current).Elem()
+	prev, nxt := reflect.ValueOf(previous).Elem(), reflect.ValueOf(next).Elem()
+	if prev.Type() != cur.Type() || nxt.Type() != cur.Type() {
+		return nil, fmt.Errorf("configuration from %s is a %s, not a %s", source, nxt.Type(), cur.Type())
+	}
+
+	updated := reflect.New(cur.Type())
+	updated.Elem().Set(cur)
+	var changes []Change
+	var refused []string
+	for i := 0; i < cur.NumField(); i++ {
+		f := cur.Type().Field(i)
+		if reflect.DeepEqual(prev.Field(i).Interface(), nxt.Field(i).Interface()) {
+			continue
+		}
+		if f.Tag.Get(reloadTag) != "true" {
+			refused = append(refused, f.Name)
+			continue
+		}
+		if reflect.DeepEqual(cur.Field(i).Interface(), nxt.Field(i).Interface()) {
+			continue
+		}
+		changes = append(changes, Change{Field: f.Name, Old: cur.Field(i).Interface(), New: nxt.Field(i).Interface()})
+		updated.Elem().Field(i).Set(nxt.Field(i))
+	}
+
+	for _, c := range changes {
+		r.logger.Info(fmt.Sprintf("configuration from %s: %s changed from %v to %v", source, c.Field, c.Old, c.New))
+	}
+	if len(changes) > 0 {
+		r.current = updated.Interface()
+		r.swap(r.current)
+		for _, fn := range r.subscribers {
+			fn(changes)
+		}
+	}
+	if len(refused) > 0 {
+		err := fmt.Errorf("configuration from %s: changes of %s refused, they need a restart", source, strings.Join(refused, ", "))
+		r.logger.Warn(err.Error())
+		return changes, err
+	}
+	return changes, nil
+}
+
+// Worker reloading the configuration files of the profile after every write, until stop is closed.
+// Runs with lifecycle.Service.Go.
+func (r *Reloader) WatchFile(profile string) func(stop <-chan struct{}) {
+	dir := determinePath()
+	return func(stop <-chan struct{}) {
+		previous := r.newConfiguration()
+		if err := LoadFromFile(profile, previous); err != nil {
+			r.logger.Error(fmt.Sprintf("configuration file not watched: %v", err))
+			<-stop
+			return
+		}
+
+		changed := make(chan struct{}, 1)
+		for _, fileName := range determineConfigFiles(profile) {
+			path := dir + "/" + fileName
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			}
+			if err := watchFile(path, stop, changed); err != nil {
+				r.logger.Error(fmt.Sprintf("configuration file %s not watched: %v", path, err))
+				continue
+			}
+			r.logger.Info("watching the configuration file " + path)
+		}
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-changed:
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(fileSettleTime):
+			}
+			// Writes seen while settling are part of this reload
+			select {
+			case <-changed:
+			default:
+			}
+
+			next := r.newConfiguration()
+			if err := LoadFromFile(profile, next); err != nil {
+				r.logger.Error(fmt.Sprintf("configuration file not reloaded: %v", err))
+				continue
+			}
+			r.Apply("file", previous, next)
+			previous = next
+		}
+	}
+}
+
+// Zero configuration struct of the type in use
+func (r *Reloader) newConfiguration() interface{} {
+	return reflect.New(reflect.TypeOf(r.current).Elem()).Interface()
+}
+
+// Copy of the configuration in use
+func (r *Reloader) Current() interface{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := reflect.New(reflect.TypeOf(r.current).Elem())
+	c.Elem().Set(reflect.ValueOf(r.current).Elem())
+	return c.Interface()
+}
@@ -0,0 +1,134 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+type reloadConfiguration struct {
+	ApplicationName string
+	ServicePort     int
+	ReadMaxLimit    int    `reload:"true"`
+	LoggingFile     string `reload:"true"`
+}
+
+func newTestReloader(current *reloadConfiguration) (*Reloader, *[]*reloadConfiguration) {
+	var swapped []*reloadConfiguration
+	r := NewReloader(current, func(next interface{}) {
+		swapped = append(swapped, next.(*reloadConfiguration))
+	}, logger.NewMockClient())
+	return r, &swapped
+}
+
+func TestReloaderApply(t *testing.T) {
+	current := &reloadConfiguration{ApplicationName: "test", ServicePort: 48080, ReadMaxLimit: 100, LoggingFile: "a.log"}
+	r, swapped := newTestReloader(current)
+	var notified [][]Change
+	r.Subscribe(func(changes []Change) { notified = append(notified, changes) })
+
+	// The source only knows the port and limit, the other fields are kept
+	previous := &reloadConfiguration{ServicePort: 48080, ReadMaxLimit: 100}
+	next := &reloadConfiguration{ServicePort: 48081, ReadMaxLimit: 200}
+	changes, err := r.Apply("test", previous, next)
+	if err == nil {
+		t.Error("change of the port should be refused")
+	}
+	if len(changes) != 1 || changes[0] != (Change{Field: "ReadMaxLimit", Old: 100, New: 200}) {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if len(*swapped) != 1 || *(*swapped)[0] != (reloadConfiguration{ApplicationName: "test", ServicePort: 48080, ReadMaxLimit: 200, LoggingFile: "a.log"}) {
+		t.Errorf("unexpected configurations swapped in %v", *swapped)
+	}
+	if current.ReadMaxLimit != 100 {
+		t.Error("configuration in use modified instead of swapped")
+	}
+	if len(notified) != 1 {
+		t.Errorf("%d notifications", len(notified))
+	}
+
+	// Unchanged fields of the source and values already in use change nothing
+	if changes, err := r.Apply("test", next, &reloadConfiguration{ServicePort: 48081, ReadMaxLimit: 200}); err != nil || len(changes) != 0 {
+		t.Errorf("unexpected changes %v, %v", changes, err)
+	}
+	if len(*swapped) != 1 || len(notified) != 1 {
+		t.Error("configuration swapped without change")
+	}
+	if c := r.Current().(*reloadConfiguration); c.ReadMaxLimit != 200 {
+		t.Errorf("unexpected current configuration %v", c)
+	}
+
+	if _, err := r.Apply("test", &struct{}{}, &struct{}{}); err == nil {
+		t.Error("configuration of another type should be rejected")
+	}
+}
+
+func TestReloaderWatchFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("EDGEX_CONF_DIR", os.Getenv("EDGEX_CONF_DIR"))
+	os.Setenv("EDGEX_CONF_DIR", dir)
+
+	path := filepath.Join(dir, configUnitTest)
+	write := func(contents string) {
+		// Replaced like editors do
+		if err := ioutil.WriteFile(path+".swp", []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(path+".swp", path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("ApplicationName = \"test\"\nReadMaxLimit = 100\n")
+
+	current := &reloadConfiguration{ApplicationName: "test", ReadMaxLimit: 100}
+	changed := make(chan []Change, 1)
+	r := NewReloader(current, func(interface{}) {}, logger.NewMockClient())
+	r.Subscribe(func(changes []Change) { changed <- changes })
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		r.WatchFile("unit-test")(stop)
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	// Give the watcher time to start
+	time.Sleep(100 * time.Millisecond)
+	write("ApplicationName = \"test\"\nReadMaxLimit = 500\n")
+	select {
+	case changes := <-changed:
+		if len(changes) != 1 || changes[0].Field != "ReadMaxLimit" || changes[0].New != 500 {
+			t.Errorf("unexpected changes %v", changes)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("change of the file not applied")
+	}
+}
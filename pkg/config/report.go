@@ -0,0 +1,83 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	originDefault = "default"
+	secretMask    = "*****"
+)
+
+// Parts of the field names of secrets, their values are never reported
+var secretNames = []string{"password", "pass", "secret", "apikeys", "token"}
+
+// Effective value of a configuration field and where it comes from: "default",
+// the configuration file or the environment variable
+type Setting struct {
+	Field  string
+	Value  string
+	Origin string
+}
+
+// Effective configuration, in the order of the fields
+type Report []Setting
+
+func (r Report) String() string {
+	var b bytes.Buffer
+	for _, s := range r {
+		fmt.Fprintf(&b, "%s = %s (%s)\n", s.Field, s.Value, s.Origin)
+	}
+	return b.String()
+}
+
+// Report of the configuration struct, origins holds the origins by lowercase field name
+func newReport(configuration interface{}, origins map[string]string) Report {
+	v := reflect.ValueOf(configuration).Elem()
+	var r Report
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		origin, ok := origins[strings.ToLower(f.Name)]
+		if !ok {
+			origin = originDefault
+		}
+		value := fmt.Sprint(v.Field(i).Interface())
+		if isSecret(f.Name) && value != "" {
+			value = secretMask
+		}
+		r = append(r, Setting{Field: f.Name, Value: value, Origin: origin})
+	}
+	return r
+}
+
+func isSecret(field string) bool {
+	name := strings.ToLower(field)
+	for _, s := range secretNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
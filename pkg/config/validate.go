@@ -0,0 +1,92 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rules of a field, separated by commas: `validate:"required,min=1,max=65535"`.
+// required refuses the zero value, min and max bound the numbers.
+const validateTag = "validate"
+
+/*
+ * Check the fields of the configuration struct against their validate tags.
+ * The error lists every invalid field.
+ */
+func Validate(configuration interface{}) error {
+	v := reflect.ValueOf(configuration).Elem()
+	var invalid []string
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		tag := f.Tag.Get(validateTag)
+		if tag == "" {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if err := checkRule(v.Field(i), rule); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s %v", f.Name, err))
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func checkRule(field reflect.Value, rule string) error {
+	name, arg := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, arg = rule[:i], rule[i+1:]
+	}
+
+	switch name {
+	case "required":
+		if reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			return errors.New("is required")
+		}
+		return nil
+	case "min", "max":
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("has an invalid rule %s", rule)
+		}
+		var value float64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(field.Int())
+		case reflect.Float32, reflect.Float64:
+			value = field.Float()
+		default:
+			return fmt.Errorf("has a rule %s for a %s", rule, field.Kind())
+		}
+		if name == "min" && value < bound {
+			return fmt.Errorf("is %v, less than %s", value, arg)
+		}
+		if name == "max" && value > bound {
+			return fmt.Errorf("is %v, more than %s", value, arg)
+		}
+		return nil
+	default:
+		return fmt.Errorf("has an unknown rule %s", rule)
+	}
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package config
+
+import (
+	"os"
+	"time"
+)
+
+const filePollInterval = time.Second
+
+// Signal changed after every write of the file until stop is closed, replaced by
+// an inotify watch on linux
+var watchFile = pollFile
+
+// Poll the modification time of the file
+func pollFile(path string, stop <-chan struct{}, changed chan<- struct{}) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		modified := info.ModTime()
+		ticker := time.NewTicker(filePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(modified) {
+				continue
+			}
+			modified = info.ModTime()
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,183 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package health reports whether a service is alive and whether the dependencies it
+// needs to serve requests (database, message bus, other services) are available.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Paths every service serves its health reports on
+const (
+	ApiLiveRoute  = "/api/v1/health/live"
+	ApiReadyRoute = "/api/v1/health/ready"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Maximum time a single dependency check may take before it is reported down
+var CheckTimeout = 5 * time.Second
+
+// A check returns nil when the dependency is available
+type Check func() error
+
+// Result of one dependency check
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report returned by the health endpoints
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+var (
+	mux    sync.Mutex
+	checks = make(map[string]Check)
+)
+
+// Register a dependency check, replacing any previous one with the same name
+func Register(name string, check Check) {
+	mux.Lock()
+	defer mux.Unlock()
+	checks[name] = check
+}
+
+// Remove all the registered checks
+func Reset() {
+	mux.Lock()
+	defer mux.Unlock()
+	checks = make(map[string]Check)
+}
+
+// Run every registered check concurrently and report the service ready only if all pass
+func Ready() Report {
+	mux.Lock()
+	names := make([]string, 0, len(checks))
+	for n := range checks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	toRun := make([]Check, len(names))
+	for i, n := range names {
+		toRun[i] = checks[n]
+	}
+	mux.Unlock()
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range toRun {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = run(toRun[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(names))}
+	for i, n := range names {
+		report.Checks[n] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func run(check Check) CheckResult {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return CheckResult{Status: StatusDown, Error: err.Error()}
+		}
+		return CheckResult{Status: StatusUp}
+	case <-time.After(CheckTimeout):
+		return CheckResult{Status: StatusDown, Error: "timed out after " + CheckTimeout.String()}
+	}
+}
+
+// Answer as long as the process is able to serve HTTP requests
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp})
+	})
+}
+
+// Answer 200 when every dependency is available, 503 otherwise
+func ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Ready())
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status == StatusUp {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(&report)
+}
+
+// Check that another service answers a GET on url (usually its ping endpoint) with a 2xx status
+func URLCheck(url string) Check {
+	return ResolvedURLCheck(func() string { return url })
+}
+
+// URLCheck of the url returned by resolve before every check, for services that move
+func ResolvedURLCheck(resolve func() string) Check {
+	c := &http.Client{Timeout: CheckTimeout}
+	return func() error {
+		url := resolve()
+		if url == "" {
+			return errors.New("no URL configured")
+		}
+		resp, err := c.Get(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		return nil
+	}
+}
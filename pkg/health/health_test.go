@@ -0,0 +1,98 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getReport(t *testing.T, h http.Handler) (int, Report) {
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ApiReadyRoute, nil))
+	var report Report
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid report %s: %v", rr.Body.String(), err)
+	}
+	return rr.Code, report
+}
+
+func TestReady(t *testing.T) {
+	defer Reset()
+	Register("database", func() error { return nil })
+
+	code, report := getReport(t, ReadyHandler())
+	if code != http.StatusOK || report.Status != StatusUp {
+		t.Errorf("expected ready, got %d %v", code, report)
+	}
+
+	Register("messagebus", func() error { return errors.New("socket not bound") })
+	code, report = getReport(t, ReadyHandler())
+	if code != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Errorf("expected not ready, got %d %v", code, report)
+	}
+	if r := report.Checks["messagebus"]; r.Status != StatusDown || r.Error != "socket not bound" {
+		t.Errorf("unexpected messagebus result %v", r)
+	}
+	if r := report.Checks["database"]; r.Status != StatusUp {
+		t.Errorf("unexpected database result %v", r)
+	}
+}
+
+func TestLiveIgnoresChecks(t *testing.T) {
+	defer Reset()
+	Register("database", func() error { return errors.New("down") })
+
+	code, report := getReport(t, LiveHandler())
+	if code != http.StatusOK || report.Status != StatusUp || len(report.Checks) != 0 {
+		t.Errorf("expected live, got %d %v", code, report)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	defer Reset()
+	defer func(d time.Duration) { CheckTimeout = d }(CheckTimeout)
+	CheckTimeout = 10 * time.Millisecond
+
+	Register("slow", func() error { time.Sleep(time.Second); return nil })
+	if report := Ready(); report.Checks["slow"].Status != StatusDown {
+		t.Errorf("slow check should time out: %v", report)
+	}
+}
+
+func TestURLCheck(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/ping" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	if err := URLCheck(ts.URL + "/api/v1/ping")(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := URLCheck(ts.URL + "/other")(); err == nil {
+		t.Error("expected an error for a 404")
+	}
+	if err := URLCheck("")(); err == nil {
+		t.Error("expected an error without URL")
+	}
+}
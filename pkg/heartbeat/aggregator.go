@@ -0,0 +1,127 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package heartbeat
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Path support-logging serves the heartbeats of the services on
+const ApiHeartbeatRoute = "/api/v1/heartbeat"
+
+// Number of heartbeats a service can miss before it is reported missing
+const MissedBeats = 3
+
+// Last heartbeat of a service, missing when it stopped beating or never did
+type ServiceStatus struct {
+	Service  string    `json:"service"`
+	Missing  bool      `json:"missing"`
+	Received time.Time `json:"received,omitempty"`
+	Last     *Status   `json:"last,omitempty"`
+}
+
+// Keeps the last heartbeat of every service
+type Aggregator struct {
+	mutex    sync.Mutex
+	expected []string
+	last     map[string]Status
+	received map[string]time.Time // Clock of the aggregator, the services' clocks may drift
+	now      func() time.Time
+}
+
+// Aggregator reporting the expected services missing until their first heartbeat
+func NewAggregator(expected ...string) *Aggregator {
+	return &Aggregator{
+		expected: expected,
+		last:     make(map[string]Status),
+		received: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Keep the heartbeat as the last of its service
+func (a *Aggregator) Record(s Status) error {
+	if s.Service == "" {
+		return errors.New("heartbeat without service name")
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.last[s.Service] = s
+	a.received[s.Service] = a.now()
+	return nil
+}
+
+// Last heartbeat of every service, sorted by name
+func (a *Aggregator) Services() []ServiceStatus {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	names := make(map[string]bool)
+	for _, n := range a.expected {
+		names[n] = true
+	}
+	for n := range a.last {
+		names[n] = true
+	}
+
+	now := a.now()
+	services := make([]ServiceStatus, 0, len(names))
+	for n := range names {
+		s := ServiceStatus{Service: n, Missing: true}
+		if last, ok := a.last[n]; ok {
+			interval := time.Duration(last.Interval) * time.Millisecond
+			if interval <= 0 {
+				interval = defaultInterval
+			}
+			s.Received = a.received[n]
+			s.Last = &last
+			s.Missing = now.Sub(s.Received) > MissedBeats*interval
+		}
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Service < services[j].Service })
+	return services
+}
+
+// GET lists the last heartbeat of every service, POST records a heartbeat
+func (a *Aggregator) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(a.Services())
+		case http.MethodPost:
+			var s Status
+			if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := a.Record(s); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -15,23 +15,166 @@
  * @version: 0.5.0
  *******************************************************************************/
 
+// Package heartbeat periodically publishes a status record of the service: its name, version,
+// uptime, the health of its dependencies and its key counters.
 package heartbeat
 
 import (
+	"context"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
-	"github.com/edgexfoundry/edgex-go/support/logging-client"
+	"github.com/edgexfoundry/edgex-go"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
 )
 
-func Start(msg string, interval int, logger logger.LoggingClient) {
-	go sendBeats(msg, interval, logger)
+// Status record published by every heartbeat
+type Status struct {
+	Service   string             `json:"service"`
+	Version   string             `json:"version"`
+	Host      string             `json:"host,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	Started   time.Time          `json:"started"`
+	Timestamp time.Time          `json:"timestamp"`
+	Uptime    float64            `json:"uptime"`   // Seconds since Started
+	Interval  int64              `json:"interval"` // Milliseconds until the next heartbeat
+	Health    health.Report      `json:"health"`
+	Counters  map[string]float64 `json:"counters,omitempty"`
 }
 
-// Executes the basic heartbeat for all servicves. Writes entries to the supplied logger.
-func sendBeats(heartbeatMsg string, interval int, logger logger.LoggingClient) {
-	// Loop forever
-	for true {
-		logger.Info(heartbeatMsg)
-		time.Sleep(time.Millisecond * time.Duration(interval)) // Sleep based on supplied interval
+// Configuration of the heartbeat of a service
+type Config struct {
+	ServiceName string
+	Message     string        // Fixed message of the former heartbeat, logged by the log sink
+	Interval    time.Duration // Defaults to a minute
+	Sink        Sink
+}
+
+const defaultInterval = time.Minute
+
+var (
+	mux      sync.Mutex
+	counters = make(map[string]func() float64)
+)
+
+// Register a counter reported in every heartbeat, replacing any previous one with the same name
+func RegisterCounter(name string, value func() float64) {
+	mux.Lock()
+	defer mux.Unlock()
+	counters[name] = value
+}
+
+// Remove all the registered counters
+func ResetCounters() {
+	mux.Lock()
+	defer mux.Unlock()
+	counters = make(map[string]func() float64)
+}
+
+// Heartbeat of a service, started by Start or StartContext
+type Heartbeat struct {
+	config  Config
+	started time.Time
+	host    string
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mutex  sync.Mutex
+	last   Status
+	beaten bool
+	err    error // Error of the last publication
+}
+
+// Start publishing the heartbeats until Stop
+func Start(config Config) *Heartbeat {
+	return StartContext(context.Background(), config)
+}
+
+// Start publishing the heartbeats until Stop or the end of ctx
+func StartContext(ctx context.Context, config Config) *Heartbeat {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	h := &Heartbeat{config: config, started: time.Now(), cancel: cancel, done: make(chan struct{})}
+	h.host, _ = os.Hostname()
+	go h.run(ctx)
+	return h
+}
+
+func (h *Heartbeat) run(ctx context.Context) {
+	defer close(h.done)
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+	for {
+		h.beat()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Heartbeat) beat() {
+	s := h.Status()
+	var err error
+	if h.config.Sink != nil {
+		err = h.config.Sink.Publish(s)
+	}
+	h.mutex.Lock()
+	h.last = s
+	h.beaten = true
+	h.err = err
+	h.mutex.Unlock()
+}
+
+// Stop the heartbeats, waiting for a publication in progress
+func (h *Heartbeat) Stop() {
+	h.cancel()
+	<-h.done
+}
+
+// Current status of the service
+func (h *Heartbeat) Status() Status {
+	now := time.Now()
+	s := Status{
+		Service:   h.config.ServiceName,
+		Version:   edgex.Version,
+		Host:      h.host,
+		Message:   h.config.Message,
+		Started:   h.started,
+		Timestamp: now,
+		Uptime:    now.Sub(h.started).Seconds(),
+		Interval:  int64(h.config.Interval / time.Millisecond),
+		Health:    health.Ready(),
+	}
+
+	mux.Lock()
+	names := make([]string, 0, len(counters))
+	for n := range counters {
+		names = append(names, n)
+	}
+	values := make([]func() float64, len(names))
+	sort.Strings(names)
+	for i, n := range names {
+		values[i] = counters[n]
+	}
+	mux.Unlock()
+	if len(names) > 0 {
+		s.Counters = make(map[string]float64, len(names))
+		for i, n := range names {
+			s.Counters[n] = values[i]()
+		}
+	}
+	return s
+}
+
+// Last status published, false before the first heartbeat. The error is the one of its publication.
+func (h *Heartbeat) Last() (Status, bool, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.last, h.beaten, h.err
 }
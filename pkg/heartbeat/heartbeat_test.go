@@ -18,8 +18,16 @@
 package heartbeat
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/edgexfoundry/edgex-go"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
 )
 
 type HeartbeatLogger struct {
@@ -30,7 +38,8 @@ var beatCount int
 
 func TestHeartbeat(t *testing.T) {
 
-	go Start("This is a test", 500, HeartbeatLogger{})
+	h := Start(Config{ServiceName: "test", Message: "This is a test", Interval: 500 * time.Millisecond, Sink: NewLogSink(HeartbeatLogger{})})
+	defer h.Stop()
 	stop := time.Now().Add(time.Millisecond * time.Duration(2000))
 	for ; time.Now().Before(stop); time.Sleep(time.Millisecond * time.Duration(100)) {
 		if beatCount > 0 {
@@ -43,6 +52,81 @@ func TestHeartbeat(t *testing.T) {
 	}
 }
 
+type recordingSink struct {
+	statuses chan Status
+}
+
+func (r recordingSink) Publish(s Status) error {
+	r.statuses <- s
+	return nil
+}
+
+func TestHeartbeatStatus(t *testing.T) {
+	defer ResetCounters()
+	RegisterCounter("events", func() float64 { return 42 })
+
+	sink := recordingSink{statuses: make(chan Status, 10)}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := StartContext(ctx, Config{ServiceName: "core-data", Interval: 10 * time.Millisecond, Sink: sink})
+	s := <-sink.statuses
+	if s.Service != "core-data" || s.Version != edgex.Version || s.Interval != 10 || s.Counters["events"] != 42 ||
+		s.Health.Status != health.StatusUp {
+		t.Errorf("unexpected status %+v", s)
+	}
+	<-sink.statuses
+	if last, ok, err := h.Last(); !ok || err != nil || last.Service != "core-data" {
+		t.Errorf("unexpected last heartbeat %+v %v %v", last, ok, err)
+	}
+
+	// No heartbeat once the context is done
+	cancel()
+	<-h.done
+	for len(sink.statuses) > 0 {
+		<-sink.statuses
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(sink.statuses) != 0 {
+		t.Error("heartbeat published after the end of the context")
+	}
+	h.Stop()
+}
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator("core-data", "core-metadata")
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	h := a.Handler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, ApiHeartbeatRoute,
+		strings.NewReader(`{"service":"core-data","interval":1000}`)))
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("heartbeat not recorded: %d %s", rr.Code, rr.Body.String())
+	}
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, ApiHeartbeatRoute, strings.NewReader(`{"interval":1000}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("heartbeat without service accepted: %d", rr.Code)
+	}
+
+	// Metadata never beat, core data misses its heartbeats after three intervals
+	services := a.Services()
+	if len(services) != 2 || services[0].Service != "core-data" || services[0].Missing ||
+		services[1].Service != "core-metadata" || !services[1].Missing {
+		t.Errorf("unexpected services %+v", services)
+	}
+	now = now.Add(4 * time.Second)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ApiHeartbeatRoute, nil))
+	var listed []ServiceStatus
+	if err := json.NewDecoder(rr.Body).Decode(&listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || !listed[0].Missing || listed[0].Last == nil || listed[0].Last.Interval != 1000 {
+		t.Errorf("unexpected listed services %+v", listed)
+	}
+}
+
 // Log an INFO level message
 func (lc HeartbeatLogger) Info(msg string, labels ...string) error {
 	beatCount++
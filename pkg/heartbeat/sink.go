@@ -0,0 +1,187 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package heartbeat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+// Types of sink
+const (
+	SinkLog            = "log"             // Logging client of the service
+	SinkLoggingService = "support-logging" // Heartbeat aggregator of support-logging
+	SinkMetrics        = "metrics"         // Gauges of the metrics registry
+	SinkBus            = "bus"             // Topic of the message bus
+)
+
+// Destination of the heartbeats
+type Sink interface {
+	Publish(s Status) error
+}
+
+// Message bus the bus sink publishes on
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// Selection of the sink of a service
+type SinkConfig struct {
+	Type      string    // SinkLog (the default), SinkLoggingService, SinkMetrics or SinkBus
+	URL       string    // Heartbeat endpoint of support-logging
+	Topic     string    // Topic of the message bus
+	Publisher Publisher // Message bus of the service, needed by SinkBus
+}
+
+// Sink of the configured type, the log sink writes to lc
+func NewSink(config SinkConfig, lc logger.LoggingClient) (Sink, error) {
+	switch config.Type {
+	case SinkLog, "":
+		return NewLogSink(lc), nil
+	case SinkLoggingService:
+		if config.URL == "" {
+			return nil, errors.New("No URL configured for the heartbeat sink " + config.Type)
+		}
+		return NewHTTPSink(config.URL), nil
+	case SinkMetrics:
+		return NewMetricsSink(), nil
+	case SinkBus:
+		if config.Publisher == nil {
+			return nil, errors.New("No message bus available for the heartbeat sink " + config.Type)
+		}
+		return NewBusSink(config.Topic, config.Publisher), nil
+	default:
+		return nil, errors.New("Unknown heartbeat sink: " + config.Type)
+	}
+}
+
+type logSink struct {
+	lc logger.LoggingClient
+}
+
+// Log the message of the heartbeat followed by the status record
+func NewLogSink(lc logger.LoggingClient) Sink {
+	return logSink{lc: lc}
+}
+
+func (l logSink) Publish(s Status) error {
+	record, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if s.Message == "" {
+		return l.lc.Info(string(record))
+	}
+	return l.lc.Info(s.Message + " " + string(record))
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+// POST the status records to url, usually the heartbeat endpoint of support-logging
+func NewHTTPSink(url string) Sink {
+	return httpSink{url: url, client: &http.Client{Timeout: 10 * time.Second, Transport: auth.Transport(nil)}}
+}
+
+func (h httpSink) Publish(s Status) error {
+	record, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(record))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", h.url, resp.Status)
+	}
+	return nil
+}
+
+var (
+	gaugesOnce     sync.Once
+	uptimeGauge    *metrics.GaugeFunc
+	timestampGauge *metrics.GaugeFunc
+	healthyGauge   *metrics.GaugeFunc
+	counterGauge   *metrics.GaugeFunc
+)
+
+type metricsSink struct{}
+
+// Set the gauges edgex_heartbeat_* of the default metrics registry, served by metrics.Handler()
+func NewMetricsSink() Sink {
+	gaugesOnce.Do(func() {
+		uptimeGauge = metrics.NewGaugeFuncVec("edgex_heartbeat_uptime_seconds",
+			"Uptime of the service at its last heartbeat.", "service")
+		timestampGauge = metrics.NewGaugeFuncVec("edgex_heartbeat_timestamp_seconds",
+			"Unix time of the last heartbeat of the service.", "service")
+		healthyGauge = metrics.NewGaugeFuncVec("edgex_heartbeat_healthy",
+			"1 when the dependencies of the service were available at its last heartbeat.", "service")
+		counterGauge = metrics.NewGaugeFuncVec("edgex_heartbeat_counter",
+			"Key counters of the service at its last heartbeat.", "service", "counter")
+	})
+	return metricsSink{}
+}
+
+func constant(v float64) func() float64 {
+	return func() float64 { return v }
+}
+
+func (metricsSink) Publish(s Status) error {
+	uptimeGauge.Set(constant(s.Uptime), s.Service)
+	timestampGauge.Set(constant(float64(s.Timestamp.UnixNano())/1e9), s.Service)
+	healthy := 0.0
+	if s.Health.Status == health.StatusUp {
+		healthy = 1
+	}
+	healthyGauge.Set(constant(healthy), s.Service)
+	for name, value := range s.Counters {
+		counterGauge.Set(constant(value), s.Service, name)
+	}
+	return nil
+}
+
+type busSink struct {
+	topic     string
+	publisher Publisher
+}
+
+// Publish the JSON status records on the topic of the message bus
+func NewBusSink(topic string, publisher Publisher) Sink {
+	return busSink{topic: topic, publisher: publisher}
+}
+
+func (b busSink) Publish(s Status) error {
+	record, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return b.publisher.Publish(b.topic, record)
+}
@@ -0,0 +1,174 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package lifecycle runs the HTTP server and background workers of a service and shuts
+// them down gracefully on SIGINT or SIGTERM.
+//
+// On shutdown the server stops accepting connections and waits for in-flight requests,
+// then the workers are asked to stop and drain, then the shutdown hooks run in the order
+// they were registered (flush publishers, close database clients, deregister...).
+// Draining requests and workers shares a single timeout; hooks always run.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+// Used when the service configuration does not set a timeout
+const DefaultTimeout = 10 * time.Second
+
+type hook struct {
+	name string
+	fn   func() error
+}
+
+// Service ties the HTTP server, workers and resources of a service to its process lifetime
+type Service struct {
+	timeout time.Duration
+	logger  logger.LoggingClient
+
+	mux      sync.Mutex
+	hooks    []hook
+	workers  sync.WaitGroup
+	stop     chan struct{}
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// Create a service lifecycle, a zero or negative timeout selects DefaultTimeout
+func New(timeout time.Duration, l logger.LoggingClient) *Service {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Service{
+		timeout: timeout,
+		logger:  l,
+		stop:    make(chan struct{}),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Run a background worker. The stop channel is closed on shutdown, after which
+// the worker should finish the work it has queued and return.
+func (s *Service) Go(worker func(stop <-chan struct{})) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		worker(s.stop)
+	}()
+}
+
+// Register a function to run on shutdown, once requests and workers are drained
+func (s *Service) OnShutdown(name string, fn func() error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// Trigger the shutdown as if a signal had been received
+func (s *Service) Stop() {
+	s.quitOnce.Do(func() { close(s.quit) })
+}
+
+// Serve HTTP on addr until a termination signal, then shut down
+func (s *Service) ListenAndServe(addr string, h http.Handler) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.shutdown(nil)
+		return err
+	}
+	return s.Serve(ln, h)
+}
+
+// Serve HTTP on ln until a termination signal or Stop(), then shut down.
+// Returns the error of the server if it failed, nil after a requested shutdown.
+func (s *Service) Serve(ln net.Listener, h http.Handler) error {
+	srv := &http.Server{Handler: h}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var err error
+	select {
+	case sig := <-signals:
+		s.logger.Info(fmt.Sprintf("Received %s, shutting down", sig))
+	case <-s.quit:
+		s.logger.Info("Shutting down")
+	case err = <-serveErr:
+		s.logger.Error(fmt.Sprintf("HTTP server failed, shutting down: %v", err))
+	}
+
+	s.shutdown(srv)
+	return err
+}
+
+func (s *Service) shutdown(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if srv != nil {
+		if err := srv.Shutdown(ctx); err != nil {
+			s.logger.Warn(fmt.Sprintf("Requests still in flight after %s: %v", s.timeout, err))
+		}
+	}
+
+	close(s.stop)
+	if err := wait(ctx, &s.workers); err != nil {
+		s.logger.Warn(fmt.Sprintf("Background workers still running after %s", s.timeout))
+	}
+
+	s.mux.Lock()
+	hooks := s.hooks
+	s.mux.Unlock()
+	for _, h := range hooks {
+		if err := h.fn(); err != nil {
+			s.logger.Error(fmt.Sprintf("Shutdown of %s failed: %v", h.name, err))
+		} else {
+			s.logger.Info("Shut down " + h.name)
+		}
+	}
+}
+
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("timed out")
+	}
+}
@@ -0,0 +1,123 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package lifecycle
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/support/logging-client"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(time.Second, logger.NewMockClient())
+	var order []string
+
+	// A request in flight when the shutdown starts must complete
+	inFlight := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Queued work must be drained before the hooks run
+	queue := make(chan int, 3)
+	queue <- 1
+	queue <- 2
+	drained := 0
+	s.Go(func(stop <-chan struct{}) {
+		<-stop
+		for len(queue) > 0 {
+			<-queue
+			drained++
+		}
+		order = append(order, "worker")
+	})
+
+	s.OnShutdown("publisher", func() error { order = append(order, "publisher"); return nil })
+	s.OnShutdown("database", func() error { order = append(order, "database"); return errors.New("already closed") })
+	s.OnShutdown("registry", func() error { order = append(order, "registry"); return nil })
+
+	served := make(chan error)
+	go func() { served <- s.Serve(ln, handler) }()
+
+	status := make(chan int)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+
+	<-inFlight
+	s.Stop()
+
+	if code := <-status; code != http.StatusOK {
+		t.Errorf("in-flight request got status %d", code)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve() returned %v after a requested shutdown", err)
+	}
+	if drained != 2 {
+		t.Errorf("worker drained %d items, want 2", drained)
+	}
+	want := []string{"worker", "publisher", "database", "registry"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("shutdown order %v, want %v", order, want)
+	}
+	if _, err := http.Get("http://" + ln.Addr().String()); err == nil {
+		t.Error("server should not accept requests after shutdown")
+	}
+}
+
+func TestWorkerTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(50*time.Millisecond, logger.NewMockClient())
+	s.Go(func(stop <-chan struct{}) {
+		time.Sleep(time.Hour)
+	})
+	closed := false
+	s.OnShutdown("database", func() error { closed = true; return nil })
+
+	s.Stop()
+	start := time.Now()
+	s.Serve(ln, http.NotFoundHandler())
+
+	if time.Since(start) > time.Second {
+		t.Error("shutdown should give up on workers after the timeout")
+	}
+	if !closed {
+		t.Error("hooks should run even when workers time out")
+	}
+}
@@ -0,0 +1,131 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package messagebus receives the events published by core data on its ZeroMQ socket.
+package messagebus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/codec"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+)
+
+// Event received from core data, along with the headers of the trace of its publication
+type Message struct {
+	Event models.Event
+	// nil when the publisher was not tracing
+	Headers map[string]string
+}
+
+// Messages are a content type frame followed by the encoded event and optionally
+// by the JSON encoded headers of the trace.
+// Publishers predating the content type send a single JSON frame.
+// Headers that can't be parsed are dropped, the event still goes through.
+func Parse(frames [][]byte) (Message, error) {
+	contentType, data := codec.ContentTypeJSON, []byte(nil)
+	switch len(frames) {
+	case 1:
+		data = frames[0]
+	case 2, 3:
+		contentType, data = string(frames[0]), frames[1]
+	default:
+		return Message{}, fmt.Errorf("unexpected message layout of %d frames", len(frames))
+	}
+
+	c, err := codec.ForContentType(contentType)
+	if err != nil {
+		return Message{}, err
+	}
+	var m Message
+	if m.Event, err = c.DecodeEvent(data); err != nil {
+		return Message{}, err
+	}
+	if len(frames) == 3 {
+		if err := json.Unmarshal(frames[2], &m.Headers); err != nil {
+			m.Headers = nil
+		}
+	}
+	return m, nil
+}
+
+// State of the subscriber socket reported by the readiness endpoint
+type status struct {
+	sync.Mutex
+	err error
+}
+
+func (s *status) set(err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.err = err
+}
+
+func (s *status) check() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.err
+}
+
+// Subscribe to the publisher at url, e.g. tcp://localhost:5563, and hand its messages to handle
+// from a goroutine of its own. The state of the socket is the messagebus health check.
+// Messages that can't be parsed are logged and dropped.
+func Subscribe(url string, logger *zap.Logger, handle func(Message)) {
+	s := &status{err: errors.New("not connected to zmq yet")}
+	health.Register("messagebus", s.check)
+	go s.receive(url, logger, handle)
+}
+
+func (s *status) receive(url string, logger *zap.Logger, handle func(Message)) {
+	q, err := zmq.NewSocket(zmq.SUB)
+	if err != nil {
+		logger.Error("Failed to create zmq socket", zap.Error(err))
+		s.set(err)
+		return
+	}
+	defer q.Close()
+
+	logger.Info("Connecting to zmq...")
+	if err = q.Connect(url); err != nil {
+		logger.Error("Failed to connect to zmq", zap.String("url", url), zap.Error(err))
+		s.set(err)
+		return
+	}
+	logger.Info("Connected to zmq")
+	q.SetSubscribe("")
+	s.set(nil)
+
+	for {
+		frames, err := q.RecvMessageBytes(0)
+		if err != nil {
+			id, _ := q.GetIdentity()
+			logger.Error("Error getting message", zap.String("id", id))
+			continue
+		}
+		m, err := Parse(frames)
+		if err != nil {
+			logger.Error("Failed to parse event", zap.Error(err))
+			continue
+		}
+		handle(m)
+	}
+}
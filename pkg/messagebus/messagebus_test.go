@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package messagebus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/codec"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+const testDevice = "device1"
+
+func TestParse(t *testing.T) {
+	eventIn := models.Event{Device: testDevice, Readings: []models.Reading{{Name: "temperature", Value: "20"}}}
+
+	for _, name := range []string{"json", "cbor", "protobuf"} {
+		c, _ := codec.ForName(name)
+		data, err := c.EncodeEvent(eventIn)
+		if err != nil {
+			t.Fatalf("Error encoding event as %s: %v", name, err)
+		}
+
+		m, err := Parse([][]byte{[]byte(c.ContentType()), data})
+		if err != nil || !reflect.DeepEqual(eventIn, m.Event) {
+			t.Errorf("Objects should be equals for %s: %v %v %v", name, eventIn, m.Event, err)
+		}
+	}
+
+	// Publishers without a content type frame send plain JSON
+	data, _ := models.Event{Device: testDevice}.MarshalJSON()
+	if m, err := Parse([][]byte{data}); err != nil || m.Event.Device != testDevice || m.Headers != nil {
+		t.Errorf("Legacy JSON message not parsed: %v %v", m, err)
+	}
+
+	if _, err := Parse([][]byte{[]byte("text/plain"), data}); err == nil {
+		t.Error("Unsupported content type should not be parsed")
+	}
+	if _, err := Parse(nil); err == nil {
+		t.Error("Empty message should not be parsed")
+	}
+
+	// Traced messages carry the headers of the trace in a third frame
+	traced := [][]byte{[]byte(codec.ContentTypeJSON), data, []byte(`{"ot-tracer-traceid":"1"}`)}
+	if m, err := Parse(traced); err != nil || m.Event.Device != testDevice || m.Headers["ot-tracer-traceid"] != "1" {
+		t.Errorf("Traced message not parsed: %v %v", m, err)
+	}
+	traced[2] = []byte("{")
+	if m, err := Parse(traced); err != nil || m.Event.Device != testDevice || m.Headers != nil {
+		t.Errorf("Invalid headers should be dropped: %v %v", m, err)
+	}
+}
@@ -0,0 +1,101 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Path every service serves its metrics on
+const ApiMetricsRoute = "/api/v1/metrics"
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	httpRequests = NewCounter("edgex_http_requests_total",
+		"Number of HTTP requests handled, by route, method and status code.", "route", "method", "code")
+	httpLatency = NewHistogram("edgex_http_request_duration_seconds",
+		"Latency of HTTP requests, by route and method.", nil, "route", "method")
+	queueDepth = NewGaugeFuncVec("edgex_queue_depth",
+		"Number of items waiting in an internal queue.", "queue")
+)
+
+// Report the length of an internal queue, typically a buffered channel, as edgex_queue_depth
+func RegisterQueue(name string, length func() int) {
+	queueDepth.Set(func() float64 { return float64(length()) }, name)
+}
+
+// Serve the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		DefaultRegistry.Write(w)
+	})
+}
+
+// Captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Count and time the requests served by h under a fixed route name
+func Instrument(route string, h http.Handler) http.Handler {
+	return InstrumentFunc(func(*http.Request) string { return route }, h)
+}
+
+// Count and time the requests served by h, naming the route from the request.
+// The route name must be a template (e.g. /event/{id}), never the raw path,
+// to keep the number of series bounded.
+func InstrumentFunc(route func(r *http.Request) string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		name := route(r)
+		httpRequests.Inc(name, r.Method, strconv.Itoa(rec.status))
+		httpLatency.ObserveSince(start, name, r.Method)
+	})
+}
+
+// Middleware form of InstrumentFunc, for routers supporting handler middlewares
+func Middleware(route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return InstrumentFunc(route, h)
+	}
+}
+
+// Route name of a request matched by a gorilla/mux router: the path template of the matched route
+func GorillaRoute(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if t, err := route.GetPathTemplate(); err == nil {
+			return t
+		}
+	}
+	return "unmatched"
+}
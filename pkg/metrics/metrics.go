@@ -0,0 +1,353 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package metrics keeps the operational metrics of a service and renders them in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default latency buckets, in seconds
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds the metrics exposed by a service
+type Registry struct {
+	mux        sync.Mutex
+	collectors map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Registry used by the package level constructors and served by Handler()
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+}
+
+// Register a collector, replacing any previous one with the same name
+func (r *Registry) register(c collector) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.collectors[c.name()] = c
+}
+
+// Write all metrics, sorted by name, in the Prometheus text format
+func (r *Registry) Write(w io.Writer) {
+	r.mux.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for n := range r.collectors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, len(names))
+	for i, n := range names {
+		collectors[i] = r.collectors[n]
+	}
+	r.mux.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Label values of one series, kept in label order
+type series struct {
+	values []string
+}
+
+func (s series) key() string {
+	return strings.Join(s.values, "\xff")
+}
+
+func formatLabels(names []string, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for i, n := range names {
+		pairs = append(pairs, n+`="`+escape(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escape(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func checkLabels(metric string, labels []string, values []string) {
+	if len(labels) != len(values) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", metric, len(labels), len(values)))
+	}
+}
+
+// ********************** COUNTER ****************************
+
+// Counter is a monotonically increasing value, optionally split by labels
+type Counter struct {
+	metric string
+	help   string
+	labels []string
+
+	mux    sync.Mutex
+	series map[string]series
+	values map[string]float64
+}
+
+// Create a counter and register it with the default registry
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{metric: name, help: help, labels: labels,
+		series: make(map[string]series), values: make(map[string]float64)}
+	DefaultRegistry.register(c)
+	return c
+}
+
+// Increment the counter for the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add a non-negative amount to the counter for the given label values
+func (c *Counter) Add(v float64, labelValues ...string) {
+	checkLabels(c.metric, c.labels, labelValues)
+	if v < 0 {
+		return
+	}
+	s := series{values: labelValues}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	k := s.key()
+	if _, ok := c.series[k]; !ok {
+		c.series[k] = s
+	}
+	c.values[k] += v
+}
+
+// Current value of the counter for the given label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.values[series{values: labelValues}.key()]
+}
+
+func (c *Counter) name() string {
+	return c.metric
+}
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.metric, c.help, "counter")
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, k := range sortedKeys(c.series) {
+		fmt.Fprintf(w, "%s%s %s\n", c.metric, formatLabels(c.labels, c.series[k].values), formatFloat(c.values[k]))
+	}
+}
+
+// ********************** GAUGE ****************************
+
+// GaugeFunc reports a value sampled when the metrics are scraped, optionally split by labels
+type GaugeFunc struct {
+	metric string
+	help   string
+	labels []string
+
+	mux    sync.Mutex
+	series map[string]series
+	funcs  map[string]func() float64
+}
+
+// Create a gauge sampled from fn and register it with the default registry
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := NewGaugeFuncVec(name, help)
+	g.Set(fn)
+	return g
+}
+
+// Create a gauge whose series are added with Set and register it with the default registry
+func NewGaugeFuncVec(name, help string, labels ...string) *GaugeFunc {
+	g := &GaugeFunc{metric: name, help: help, labels: labels,
+		series: make(map[string]series), funcs: make(map[string]func() float64)}
+	DefaultRegistry.register(g)
+	return g
+}
+
+// Set the sampling function of the series for the given label values, replacing any previous one
+func (g *GaugeFunc) Set(fn func() float64, labelValues ...string) {
+	checkLabels(g.metric, g.labels, labelValues)
+	s := series{values: labelValues}
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.series[s.key()] = s
+	g.funcs[s.key()] = fn
+}
+
+// Remove the series for the given label values
+func (g *GaugeFunc) Delete(labelValues ...string) {
+	k := series{values: labelValues}.key()
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	delete(g.series, k)
+	delete(g.funcs, k)
+}
+
+func (g *GaugeFunc) name() string {
+	return g.metric
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.metric, g.help, "gauge")
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	for _, k := range sortedKeys(g.series) {
+		fmt.Fprintf(w, "%s%s %s\n", g.metric, formatLabels(g.labels, g.series[k].values), formatFloat(g.funcs[k]()))
+	}
+}
+
+// ********************** HISTOGRAM ****************************
+
+type histogramValues struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts observations in cumulative buckets, optionally split by labels
+type Histogram struct {
+	metric  string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mux    sync.Mutex
+	series map[string]series
+	values map[string]*histogramValues
+}
+
+// Create a histogram and register it with the default registry
+// Nil buckets select DefaultBuckets
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &Histogram{metric: name, help: help, labels: labels, buckets: buckets,
+		series: make(map[string]series), values: make(map[string]*histogramValues)}
+	DefaultRegistry.register(h)
+	return h
+}
+
+// Record an observation for the given label values
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	checkLabels(h.metric, h.labels, labelValues)
+	s := series{values: labelValues}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	k := s.key()
+	hv, ok := h.values[k]
+	if !ok {
+		hv = &histogramValues{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+		h.values[k] = hv
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+// Record the time elapsed since start, in seconds
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Number of observations for the given label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if hv, ok := h.values[series{values: labelValues}.key()]; ok {
+		return hv.count
+	}
+	return 0
+}
+
+func (h *Histogram) name() string {
+	return h.metric
+}
+
+func (h *Histogram) write(w io.Writer) {
+	writeHeader(w, h.metric, h.help, "histogram")
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for _, k := range sortedKeys(h.series) {
+		values := h.series[k].values
+		hv := h.values[k]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, formatLabels(h.labels, values, "le", formatFloat(upper)), hv.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metric, formatLabels(h.labels, values, "le", "+Inf"), hv.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metric, formatLabels(h.labels, values), formatFloat(hv.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metric, formatLabels(h.labels, values), hv.count)
+	}
+}
+
+func sortedKeys(m map[string]series) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
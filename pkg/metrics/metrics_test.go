@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package metrics
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape() string {
+	var b bytes.Buffer
+	DefaultRegistry.Write(&b)
+	return b.String()
+}
+
+func expectLines(t *testing.T, out string, lines ...string) {
+	for _, l := range lines {
+		if !strings.Contains(out, l+"\n") {
+			t.Errorf("missing line %q in output:\n%s", l, out)
+		}
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter("test_counter_total", "A test counter.", "kind")
+	c.Inc("a")
+	c.Add(2.5, "a")
+	c.Inc(`quo"te`)
+	c.Add(-1, "a")
+
+	if v := c.Value("a"); v != 3.5 {
+		t.Errorf("Value() = %v, want 3.5", v)
+	}
+	expectLines(t, scrape(),
+		"# HELP test_counter_total A test counter.",
+		"# TYPE test_counter_total counter",
+		`test_counter_total{kind="a"} 3.5`,
+		`test_counter_total{kind="quo\"te"} 1`)
+}
+
+func TestCounterWrongLabels(t *testing.T) {
+	c := NewCounter("test_labels_total", "Counter with one label.", "kind")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for missing label values")
+		}
+	}()
+	c.Inc()
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_duration_seconds", "A test histogram.", []float64{1, 5})
+	h.Observe(0.5)
+	h.Observe(3)
+	h.Observe(10)
+
+	if c := h.Count(); c != 3 {
+		t.Errorf("Count() = %d, want 3", c)
+	}
+	expectLines(t, scrape(),
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{le="1"} 1`,
+		`test_duration_seconds_bucket{le="5"} 2`,
+		`test_duration_seconds_bucket{le="+Inf"} 3`,
+		"test_duration_seconds_sum 13.5",
+		"test_duration_seconds_count 3")
+}
+
+func TestGaugeFunc(t *testing.T) {
+	ch := make(chan int, 5)
+	ch <- 1
+	ch <- 2
+	RegisterQueue("test", func() int { return len(ch) })
+
+	out := scrape()
+	expectLines(t, out, `edgex_queue_depth{queue="test"} 2`)
+	if !strings.Contains(out, "go_goroutines ") {
+		t.Error("go_goroutines should always be reported")
+	}
+
+	queueDepth.Delete("test")
+	if strings.Contains(scrape(), `queue="test"`) {
+		t.Error("deleted series should not be reported")
+	}
+}
+
+func TestInstrumentAndHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/thing/", Instrument("/api/v1/thing/{id}", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", http.StatusNotFound)
+		})))
+	mux.Handle(ApiMetricsRoute, Handler())
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, id := range []string{"1", "2"} {
+		resp, err := http.Get(ts.URL + "/api/v1/thing/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(ts.URL + ApiMetricsRoute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %s", ct)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	expectLines(t, string(body),
+		`edgex_http_requests_total{route="/api/v1/thing/{id}",method="GET",code="404"} 2`,
+		`edgex_http_request_duration_seconds_count{route="/api/v1/thing/{id}",method="GET"} 2`)
+}
@@ -0,0 +1,150 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opentracing/basictracer-go"
+)
+
+// Finished span as handed to the exporters
+type Span struct {
+	Service   string                 `json:"service"`
+	TraceID   string                 `json:"traceId"`
+	SpanID    string                 `json:"spanId"`
+	ParentID  string                 `json:"parentId,omitempty"`
+	Operation string                 `json:"operation"`
+	Start     time.Time              `json:"start"`
+	Duration  time.Duration          `json:"duration"`
+	Tags      map[string]interface{} `json:"tags,omitempty"`
+	Logs      []Log                  `json:"logs,omitempty"`
+}
+
+// Timestamped fields logged on a span
+type Log struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+func newSpan(service string, raw basictracer.RawSpan) Span {
+	s := Span{
+		Service:   service,
+		TraceID:   strconv.FormatUint(raw.Context.TraceID, 16),
+		SpanID:    strconv.FormatUint(raw.Context.SpanID, 16),
+		Operation: raw.Operation,
+		Start:     raw.Start,
+		Duration:  raw.Duration,
+		Tags:      raw.Tags,
+	}
+	if raw.ParentSpanID != 0 {
+		s.ParentID = strconv.FormatUint(raw.ParentSpanID, 16)
+	}
+	for _, l := range raw.Logs {
+		fields := make(map[string]interface{}, len(l.Fields))
+		for _, f := range l.Fields {
+			fields[f.Key()] = f.Value()
+		}
+		s.Logs = append(s.Logs, Log{Timestamp: l.Timestamp, Fields: fields})
+	}
+	return s
+}
+
+// An Exporter sends finished spans to a collector or a local sink.
+// Export is called once for every span as soon as it finishes, possibly concurrently.
+type Exporter interface {
+	Export(span Span)
+	// Flush the pending spans and release the resources of the exporter
+	Close() error
+}
+
+// Build an exporter for the destination given in the configuration
+type ExporterFactory func(destination string) (Exporter, error)
+
+var (
+	exportersMutex sync.Mutex
+	exporters      = map[string]ExporterFactory{
+		"stdout": func(string) (Exporter, error) { return NewWriterExporter(os.Stdout), nil },
+		"file":   newFileExporter,
+	}
+)
+
+// Make an exporter available to the configuration under a name, e.g. to send spans to a collector
+func RegisterExporter(name string, factory ExporterFactory) {
+	exportersMutex.Lock()
+	defer exportersMutex.Unlock()
+	exporters[name] = factory
+}
+
+func newExporter(name string, destination string) (Exporter, error) {
+	if name == "" {
+		name = "stdout"
+	}
+	exportersMutex.Lock()
+	factory, ok := exporters[name]
+	exportersMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown tracing exporter %q", name)
+	}
+	return factory(destination)
+}
+
+// Writes every span as a line of JSON
+type writerExporter struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+	c     io.Closer
+}
+
+// Exporter writing spans as lines of JSON, it doesn't close w
+func NewWriterExporter(w io.Writer) Exporter {
+	return &writerExporter{enc: json.NewEncoder(w)}
+}
+
+func newFileExporter(path string) (Exporter, error) {
+	if path == "" {
+		return nil, errors.New("the file tracing exporter needs a destination file")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &writerExporter{enc: json.NewEncoder(f), c: f}, nil
+}
+
+func (e *writerExporter) Export(span Span) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	// A span that can't be written is dropped, tracing must not fail the traced work
+	e.enc.Encode(span)
+}
+
+func (e *writerExporter) Close() error {
+	if e.c == nil {
+		return nil
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.c.Close()
+}
@@ -0,0 +1,109 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package tracing
+
+import (
+	"net/http"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// Captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Record a server span for every request served by h, continuing the trace of the caller if
+// its headers carry one. The span is named after the method and the route of the request,
+// which must be a template (e.g. /event/{id}) rather than the raw path.
+// Handlers find the span in the context of the request.
+func Middleware(route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tracer := opentracing.GlobalTracer()
+			caller, _ := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+			span := tracer.StartSpan(r.Method+" "+route(r), ext.RPCServerOption(caller))
+			defer span.Finish()
+			ext.HTTPMethod.Set(span, r.Method)
+			ext.HTTPUrl.Set(span, r.URL.Path)
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(rec, r.WithContext(opentracing.ContextWithSpan(r.Context(), span)))
+
+			ext.HTTPStatusCode.Set(span, uint16(rec.status))
+			if rec.status >= http.StatusInternalServerError {
+				ext.Error.Set(span, true)
+			}
+		})
+	}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// Round tripper recording a client span for the requests whose context is part of a trace,
+// and passing the trace on to the server in the request headers.
+// Requests made outside of a trace go through untouched. A nil base means http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	parent := opentracing.SpanFromContext(req.Context())
+	if parent == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	tracer := opentracing.GlobalTracer()
+	span := tracer.StartSpan(req.Method+" "+req.URL.Host,
+		opentracing.ChildOf(parent.Context()), ext.SpanKindRPCClient)
+	defer span.Finish()
+	ext.HTTPMethod.Set(span, req.Method)
+	ext.HTTPUrl.Set(span, req.URL.String())
+
+	// Round trippers must not modify the request they are given
+	out := new(http.Request)
+	*out = *req
+	out.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		out.Header[k] = v
+	}
+	tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(out.Header))
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("event", "error", "message", err.Error())
+		return resp, err
+	}
+	ext.HTTPStatusCode.Set(span, uint16(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		ext.Error.Set(span, true)
+	}
+	return resp, nil
+}
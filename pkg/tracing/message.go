@@ -0,0 +1,49 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package tracing
+
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Headers carrying the trace of ctx along with a message, nil when ctx is not part of a trace
+func MessageHeaders(ctx context.Context) map[string]string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	headers := make(map[string]string)
+	err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(headers))
+	if err != nil || len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// Trace carried by the headers of a message, nil if there is none
+func FromMessageHeaders(headers map[string]string) opentracing.SpanContext {
+	if len(headers) == 0 {
+		return nil
+	}
+	sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, opentracing.TextMapCarrier(headers))
+	if err != nil {
+		return nil
+	}
+	return sc
+}
@@ -0,0 +1,92 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+// Package tracing records OpenTracing spans of the work done by the services and hands them
+// to a pluggable exporter. The trace context travels between services in HTTP headers and
+// in the headers of the messages published on the bus.
+package tracing
+
+import (
+	"context"
+	"io"
+
+	"github.com/opentracing/basictracer-go"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Settings shared by the services, usually part of their configuration
+type Config struct {
+	Enabled bool
+	// Name of the registered exporter receiving the spans, "stdout" when empty
+	Exporter string
+	// Where the exporter sends the spans, e.g. the path of the "file" exporter
+	Destination string
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// Install the global tracer of the service. Spans of every trace are recorded and exported.
+// Closing the returned Closer flushes the exporter and restores the no-op tracer.
+func Init(service string, cfg Config) (io.Closer, error) {
+	if !cfg.Enabled {
+		return closerFunc(func() error { return nil }), nil
+	}
+
+	exporter, err := newExporter(cfg.Exporter, cfg.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := basictracer.DefaultOptions()
+	opts.ShouldSample = func(uint64) bool { return true }
+	opts.Recorder = &recorder{service: service, exporter: exporter}
+	opentracing.SetGlobalTracer(basictracer.NewWithOptions(opts))
+
+	return closerFunc(func() error {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		return exporter.Close()
+	}), nil
+}
+
+// Hands the finished spans over to the exporter
+type recorder struct {
+	service  string
+	exporter Exporter
+}
+
+func (r *recorder) RecordSpan(raw basictracer.RawSpan) {
+	if !raw.Context.Sampled {
+		return
+	}
+	r.exporter.Export(newSpan(r.service, raw))
+}
+
+// Start a span child of the span of ctx and return a context holding it.
+// Outside of a trace the span is a no-op and ctx is returned as is, so background work
+// doesn't start traces of its own.
+func StartChildSpan(ctx context.Context, operation string, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	parent := opentracing.SpanFromContext(ctx)
+	if parent == nil {
+		return opentracing.NoopTracer{}.StartSpan(operation), ctx
+	}
+	opts = append(opts, opentracing.ChildOf(parent.Context()))
+	span := parent.Tracer().StartSpan(operation, opts...)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
@@ -0,0 +1,201 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+type memoryExporter struct {
+	mutex sync.Mutex
+	spans []Span
+}
+
+func (m *memoryExporter) Export(s Span) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.spans = append(m.spans, s)
+}
+
+func (m *memoryExporter) Close() error {
+	return nil
+}
+
+func (m *memoryExporter) find(t *testing.T, operation string) Span {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, s := range m.spans {
+		if s.Operation == operation {
+			return s
+		}
+	}
+	t.Fatalf("no span %q in %v", operation, m.spans)
+	return Span{}
+}
+
+func initMemory(t *testing.T) (*memoryExporter, io.Closer) {
+	m := &memoryExporter{}
+	RegisterExporter("memory", func(string) (Exporter, error) { return m, nil })
+	closer, err := Init("test", Config{Enabled: true, Exporter: "memory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, closer
+}
+
+func TestHTTPPropagation(t *testing.T) {
+	m, closer := initMemory(t)
+	defer closer.Close()
+
+	server := httptest.NewServer(Middleware(func(*http.Request) string { return "/api/v1/ping" })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opentracing.SpanFromContext(r.Context()) == nil {
+				t.Error("handler context misses the server span")
+			}
+			w.WriteHeader(http.StatusTeapot)
+		})))
+	defer server.Close()
+
+	root := opentracing.StartSpan("root")
+	ctx := opentracing.ContextWithSpan(context.Background(), root)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/ping", nil)
+	client := &http.Client{Transport: Transport(nil)}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	root.Finish()
+
+	if req.Header.Get("Ot-Tracer-Traceid") != "" {
+		t.Error("the transport modified the request of the caller")
+	}
+
+	rootSpan := m.find(t, "root")
+	clientSpan := m.find(t, "GET "+req.URL.Host)
+	serverSpan := m.find(t, "GET /api/v1/ping")
+	if clientSpan.TraceID != rootSpan.TraceID || serverSpan.TraceID != rootSpan.TraceID {
+		t.Errorf("spans belong to different traces: %v %v %v", rootSpan, clientSpan, serverSpan)
+	}
+	if clientSpan.ParentID != rootSpan.SpanID || serverSpan.ParentID != clientSpan.SpanID {
+		t.Errorf("unexpected parents: %v %v %v", rootSpan, clientSpan, serverSpan)
+	}
+	if serverSpan.Service != "test" || serverSpan.Tags["http.status_code"] != uint16(http.StatusTeapot) {
+		t.Errorf("unexpected server span %v", serverSpan)
+	}
+}
+
+func TestTransportOutsideTrace(t *testing.T) {
+	m, closer := initMemory(t)
+	defer closer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Ot-Tracer-Traceid") != "" {
+			t.Error("untraced request carries a trace")
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(m.spans) != 0 {
+		t.Errorf("untraced request recorded spans %v", m.spans)
+	}
+}
+
+func TestMessageHeaders(t *testing.T) {
+	m, closer := initMemory(t)
+	defer closer.Close()
+
+	if h := MessageHeaders(context.Background()); h != nil {
+		t.Errorf("untraced context gave headers %v", h)
+	}
+	if sc := FromMessageHeaders(nil); sc != nil {
+		t.Errorf("no headers gave a trace %v", sc)
+	}
+
+	publish := opentracing.StartSpan("publish")
+	headers := MessageHeaders(opentracing.ContextWithSpan(context.Background(), publish))
+	publish.Finish()
+
+	receive := opentracing.StartSpan("receive", opentracing.FollowsFrom(FromMessageHeaders(headers)))
+	receive.Finish()
+
+	if p, r := m.find(t, "publish"), m.find(t, "receive"); r.TraceID != p.TraceID || r.ParentID != p.SpanID {
+		t.Errorf("receive span %v does not follow %v", r, p)
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tracing")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "spans.json")
+
+	if _, err := Init("test", Config{Enabled: true, Exporter: "file"}); err == nil {
+		t.Error("expected an error without destination")
+	}
+	if _, err := Init("test", Config{Enabled: true, Exporter: "zipkin"}); err == nil {
+		t.Error("expected an error for an unknown exporter")
+	}
+
+	closer, err := Init("test", Config{Enabled: true, Exporter: "file", Destination: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	span := opentracing.StartSpan("work")
+	span.SetTag("device", "dev1")
+	span.LogKV("event", "done")
+	span.Finish()
+	closer.Close()
+
+	if _, ok := opentracing.GlobalTracer().(opentracing.NoopTracer); !ok {
+		t.Error("closing should restore the no-op tracer")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("no span written")
+	}
+	var s Span
+	if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Operation != "work" || s.Service != "test" || s.Tags["device"] != "dev1" || len(s.Logs) != 1 {
+		t.Errorf("unexpected span %v", s)
+	}
+}
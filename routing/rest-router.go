@@ -19,6 +19,11 @@ package routing
 
 import "net/http"
 
+// Middleware wraps the handlers of a router, e.g. to authenticate requests
+type Middleware func(http.Handler) http.Handler
+
 type RestRouter interface {
+	// Add middlewares applied, in order, to every route. Must be called before LoadRoutes.
+	Use(mw ...Middleware)
 	LoadRoutes() http.Handler
 }
\ No newline at end of file
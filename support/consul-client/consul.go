@@ -20,10 +20,9 @@ package consulclient
 
 import (
 	"errors"
-	"reflect"
 	"strconv"
-	"strings"
 
+	"github.com/edgexfoundry/edgex-go/pkg/health"
 	consulapi "github.com/hashicorp/consul/api"
 )
 
@@ -34,26 +33,49 @@ type ConsulConfig struct {
 	ServiceName    string
 	ServiceAddress string
 	ServicePort    int
-	CheckAddress   string
+	CheckAddress   string // Defaults to the readiness endpoint of the service
 	CheckInterval  string
 }
 
 var consul *consulapi.Client = nil // Call consulInit to initialize this variable
 
+// Connect to the consul agent, without registering any service
+func ConsulConnect(consulAddress string, consulPort int) error {
+	defaultConfig := consulapi.DefaultConfig()
+	defaultConfig.Address = consulAddress + ":" + strconv.Itoa(consulPort)
+	client, err := consulapi.NewClient(defaultConfig)
+	if err != nil {
+		return err
+	}
+	consul = client
+	if resolver != nil {
+		resolver.Close()
+	}
+	resolver = NewResolver(consul)
+	return nil
+}
+
 // Initialize consul by connecting to the agent and registering the service/check
 func ConsulInit(config ConsulConfig) error {
 	var err error // Declare error to be used throughout function
 
 	// Connect to the Consul Agent
-	defaultConfig := consulapi.DefaultConfig()
-	defaultConfig.Address = config.ConsulAddress + ":" + strconv.Itoa(config.ConsulPort)
-	consul, err = consulapi.NewClient(defaultConfig)
+	err = ConsulConnect(config.ConsulAddress, config.ConsulPort)
 	if err != nil {
 		return err
 	}
 
+	return ConsulRegister(config)
+}
+
+// Register the service/check in the consul agent connected by ConsulConnect
+func ConsulRegister(config ConsulConfig) error {
+	if consul == nil {
+		return errors.New("Consul wasn't initialized, can't register " + config.ServiceName)
+	}
+
 	// Register the Service
-	err = consul.Agent().ServiceRegister(&consulapi.AgentServiceRegistration{
+	err := consul.Agent().ServiceRegister(&consulapi.AgentServiceRegistration{
 		Name:    config.ServiceName,
 		Address: config.ServiceAddress,
 		Port:    config.ServicePort,
@@ -62,13 +84,17 @@ func ConsulInit(config ConsulConfig) error {
 		return err
 	}
 
-	// Register the Health Check
+	// Register the Health Check, consul routes requests only to services ready to serve them
+	checkAddress := config.CheckAddress
+	if checkAddress == "" {
+		checkAddress = "http://" + config.ServiceAddress + ":" + strconv.Itoa(config.ServicePort) + health.ApiReadyRoute
+	}
 	err = consul.Agent().CheckRegister(&consulapi.AgentCheckRegistration{
 		Name:      "Health Check",
 		Notes:     "Check the health of the API",
 		ServiceID: config.ServiceName,
 		AgentServiceCheck: consulapi.AgentServiceCheck{
-			HTTP:     config.CheckAddress,
+			HTTP:     checkAddress,
 			Interval: config.CheckInterval,
 		},
 	})
@@ -79,7 +105,17 @@ func ConsulInit(config ConsulConfig) error {
 	return nil
 }
 
-// Look at the key/value pairs to update configuration
+// Remove the service and its health check from consul, a no-op if the service was never registered
+func ConsulDeregister(serviceName string) error {
+	if consul == nil {
+		return nil
+	}
+	resolver.Close()
+	return consul.Agent().ServiceDeregister(serviceName)
+}
+
+// Look at the key/value pairs to update configuration, the fields without pair are added to consul.
+// Nested structs, slices and maps are mapped to key paths, see kv.go.
 func CheckKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string) error {
 	// Consul wasn't initialized
 	if consul == nil {
@@ -87,117 +123,15 @@ func CheckKeyValuePairs(configurationStruct interface{}, applicationName string,
 		return err
 	}
 
-	kv := consul.KV()
-
-	// Reflection to get the field names (These will be part of the key names)
-	configValue := reflect.ValueOf(configurationStruct)
-	// Loop through the fields
-	for i := 0; i < configValue.Elem().NumField(); i++ {
-		fieldName := configValue.Elem().Type().Field(i).Name
-		fieldValue := configValue.Elem().Field(i)
-		keyPath := "config/" + applicationName + ";" + strings.Join(profiles, ";") + "/" + fieldName
-		var byteValue []byte // Byte array that will be passed to Consul
-
-		// Switch off of the value type
-		switch fieldValue.Kind() {
-		case reflect.Bool:
-			byteValue = []byte(strconv.FormatBool(fieldValue.Bool()))
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
-			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue, err := strconv.ParseBool(string(pair.Value))
-				if err != nil {
-					return err
-				}
-
-				fieldValue.SetBool(newValue) // Set the new value
-			}
-			break
-		case reflect.String:
-			byteValue = []byte(fieldValue.String())
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
-			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue := string(pair.Value)
-
-				fieldValue.SetString(newValue) // Set the new value
-			}
-			break
-		case reflect.Int:
-			byteValue = []byte(strconv.FormatInt(fieldValue.Int(), 10))
-
-			// Check if the key is already there
-			pair, _, err := kv.Get(keyPath, nil)
-			if err != nil {
-				return err
-			}
-			// Pair doesn't exist, create it
-			if pair == nil {
-				pair = &consulapi.KVPair{
-					Key:   keyPath,
-					Value: byteValue,
-				}
-				_, err = kv.Put(pair, nil)
-				if err != nil {
-					return err
-				}
-			} else { // Pair does exist, get the new value
-				pair, _, err = kv.Get(keyPath, nil)
-				if err != nil {
-					return err
-				}
-
-				newValue, err := strconv.ParseInt(string(pair.Value), 10, 64)
-				if err != nil {
-					return err
-				}
-
-				fieldValue.SetInt(newValue) // Set the new value
-			}
-			break
-		default:
-			err := errors.New("Can't get the type of field: " + keyPath)
-			return err
-		}
+	values, err := GetKeyValuePairs(applicationName, profiles)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	// Set the fields found in consul, then create the pairs of the others
+	missing, err := SetKeyValuePairs(configurationStruct, applicationName, profiles, values)
+	if err != nil {
+		return err
+	}
+	return PutKeyValuePairs(missing)
 }
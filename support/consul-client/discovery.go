@@ -0,0 +1,165 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package consulclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// Address and port of a healthy instance of a service
+type Instance struct {
+	Address string
+	Port    int
+}
+
+// host:port of the instance
+func (i Instance) Host() string {
+	return net.JoinHostPort(i.Address, strconv.Itoa(i.Port))
+}
+
+/*
+ * Resolves the healthy instances of the services registered in consul. The instances of a
+ * service are queried at its first resolution, then cached and kept up to date with blocking
+ * queries until Close. The cached instances are kept while consul can't be reached.
+ */
+type Resolver struct {
+	health   *consulapi.Health
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mutex    sync.Mutex
+	services map[string]*discoveredService
+}
+
+type discoveredService struct {
+	ready     chan struct{} // Closed after the first query
+	instances []Instance
+	err       error // Error of the queries while no instance was ever found
+	next      int   // The instances are taken in turn
+}
+
+// Resolver of the services registered in the consul agent of client
+func NewResolver(client *consulapi.Client) *Resolver {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Resolver{
+		health:   client.Health(),
+		ctx:      ctx,
+		cancel:   cancel,
+		services: make(map[string]*discoveredService),
+	}
+}
+
+// Healthy instance of the service, the instances are taken in turn
+func (r *Resolver) Resolve(serviceName string) (Instance, error) {
+	r.mutex.Lock()
+	s, ok := r.services[serviceName]
+	if !ok {
+		s = &discoveredService{ready: make(chan struct{})}
+		r.services[serviceName] = s
+		go r.watch(serviceName, s)
+	}
+	r.mutex.Unlock()
+
+	select {
+	case <-s.ready:
+	case <-r.ctx.Done():
+		return Instance{}, errors.New("resolver of " + serviceName + " closed")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(s.instances) == 0 {
+		if s.err != nil {
+			return Instance{}, s.err
+		}
+		return Instance{}, errors.New("no healthy instance of " + serviceName)
+	}
+	i := s.instances[s.next%len(s.instances)]
+	s.next++
+	return i, nil
+}
+
+// Stop following the services
+func (r *Resolver) Close() {
+	r.cancel()
+}
+
+// Follow the healthy instances of the service until the resolver is closed
+func (r *Resolver) watch(serviceName string, s *discoveredService) {
+	var index uint64
+	first := true
+	ready := func() {
+		if first {
+			close(s.ready)
+			first = false
+		}
+	}
+	for {
+		entries, meta, err := r.health.Service(serviceName, "", true,
+			(&consulapi.QueryOptions{WaitIndex: index, WaitTime: watchWaitTime}).WithContext(r.ctx))
+		if r.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.mutex.Lock()
+			s.err = err
+			r.mutex.Unlock()
+			ready()
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(watchRetryDelay):
+			}
+			continue
+		}
+		if meta.LastIndex == index && !first {
+			continue
+		}
+		index = meta.LastIndex
+
+		instances := make([]Instance, 0, len(entries))
+		for _, e := range entries {
+			// Services registered without address are reached at the address of their node
+			address := e.Service.Address
+			if address == "" && e.Node != nil {
+				address = e.Node.Address
+			}
+			instances = append(instances, Instance{Address: address, Port: e.Service.Port})
+		}
+		r.mutex.Lock()
+		s.instances = instances
+		s.err = nil
+		r.mutex.Unlock()
+		ready()
+	}
+}
+
+var resolver *Resolver = nil // Created by ConsulInit
+
+// Healthy instance of the service registered in consul, an error when consul wasn't initialized
+func ServiceInstance(serviceName string) (Instance, error) {
+	if resolver == nil {
+		return Instance{}, errors.New("Consul wasn't initialized, can't resolve " + serviceName)
+	}
+	return resolver.Resolve(serviceName)
+}
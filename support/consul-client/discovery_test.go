@@ -0,0 +1,122 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package consulclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// Health endpoint of a consul agent answering the blocking queries
+type fakeHealth struct {
+	mutex     sync.Mutex
+	index     uint64
+	instances map[string][]*consulapi.ServiceEntry
+	changed   chan struct{}
+}
+
+func newFakeHealth() *fakeHealth {
+	return &fakeHealth{index: 1, instances: make(map[string][]*consulapi.ServiceEntry), changed: make(chan struct{})}
+}
+
+func (f *fakeHealth) set(service string, entries ...*consulapi.ServiceEntry) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.instances[service] = entries
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeHealth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	service := strings.TrimPrefix(r.URL.Path, "/v1/health/service/")
+	wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	for {
+		f.mutex.Lock()
+		index, entries, changed := f.index, f.instances[service], f.changed
+		f.mutex.Unlock()
+		if index != wait {
+			w.Header().Set("X-Consul-Index", strconv.FormatUint(index, 10))
+			json.NewEncoder(w).Encode(entries)
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func entry(address string, port int) *consulapi.ServiceEntry {
+	return &consulapi.ServiceEntry{
+		Node:    &consulapi.Node{Address: "10.0.0.1"},
+		Service: &consulapi.AgentService{Address: address, Port: port},
+	}
+}
+
+func TestResolverFollowsInstances(t *testing.T) {
+	health := newFakeHealth()
+	health.set("core-metadata", entry("meta-1", 48081), entry("", 48082))
+	server := httptest.NewServer(health)
+	defer server.Close()
+
+	config := consulapi.DefaultConfig()
+	config.Address = strings.TrimPrefix(server.URL, "http://")
+	client, err := consulapi.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver(client)
+	defer r.Close()
+
+	// The instances are taken in turn, the node address stands for a missing service address
+	first, err := r.Resolve("core-metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := r.Resolve("core-metadata")
+	if first != (Instance{"meta-1", 48081}) || second != (Instance{"10.0.0.1", 48082}) {
+		t.Errorf("unexpected instances %v %v", first, second)
+	}
+
+	if _, err := r.Resolve("core-data"); err == nil {
+		t.Error("service without healthy instance resolved")
+	}
+
+	// The watch follows the changes of the instances
+	health.set("core-metadata", entry("meta-2", 48081))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		i, err := r.Resolve("core-metadata")
+		if err == nil && i.Address == "meta-2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("change of the instances not followed, resolved %v %v", i, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,382 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package consulclient
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+/*
+ * Mapping of a configuration to key/value pairs. Every scalar is a pair whose key is the path
+ * of the field under the prefix of the application and profiles:
+ *   - nested structs and maps add the field names and map keys to the path
+ *     (config/core-data;go/Writable/LogLevel)
+ *   - slices add the indexes of their elements (config/export-distro;go/Filters/0)
+ *   - time.Duration is written as "10s", time.Time as RFC 3339, the other scalars in decimal
+ * The pairs of a slice or map replace it entirely, so elements can be removed from consul.
+ */
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Prefix of the keys of the configuration of the application
+func KeyPrefix(applicationName string, profiles []string) string {
+	return "config/" + applicationName + ";" + strings.Join(profiles, ";") + "/"
+}
+
+func pairsByKey(pairs consulapi.KVPairs) map[string]string {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		values[pair.Key] = string(pair.Value)
+	}
+	return values
+}
+
+// Set the fields of the struct found in values. The fields without pair are added to
+// missing with their current value, unless missing is nil.
+func decodeStruct(v reflect.Value, dir string, values map[string]string, missing map[string]string) error {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if err := decodeField(v.Field(i), dir+f.Name, values, missing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeField(v reflect.Value, key string, values map[string]string, missing map[string]string) error {
+	if isScalar(v.Type()) {
+		value, ok := values[key]
+		if !ok {
+			if missing != nil {
+				return encodeField(v, key, missing)
+			}
+			return nil
+		}
+		if err := parseScalar(v, value); err != nil {
+			return fmt.Errorf("invalid value of %s: %v", key, err)
+		}
+		return nil
+	}
+
+	dir := key + "/"
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeStruct(v, dir, values, missing)
+	case reflect.Slice, reflect.Map:
+		children := childNames(dir, values)
+		if len(children) == 0 {
+			if missing != nil {
+				return encodeField(v, key, missing)
+			}
+			return nil
+		}
+		if v.Kind() == reflect.Slice {
+			return decodeSlice(v, dir, children, values)
+		}
+		return decodeMap(v, dir, children, values)
+	default:
+		return errors.New("Can't get the type of field: " + key)
+	}
+}
+
+// The elements of the slice are the indexes found under dir
+func decodeSlice(v reflect.Value, dir string, children []string, values map[string]string) error {
+	length := 0
+	for _, c := range children {
+		i, err := strconv.Atoi(c)
+		if err != nil || i < 0 {
+			return fmt.Errorf("invalid index %s of %s", c, strings.TrimSuffix(dir, "/"))
+		}
+		if i+1 > length {
+			length = i + 1
+		}
+	}
+	s := reflect.MakeSlice(v.Type(), length, length)
+	for i := 0; i < length; i++ {
+		if err := decodeField(s.Index(i), dir+strconv.Itoa(i), values, nil); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+// The entries of the map are the names found under dir
+func decodeMap(v reflect.Value, dir string, children []string, values map[string]string) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return errors.New("Can't get the type of field: " + strings.TrimSuffix(dir, "/"))
+	}
+	m := reflect.MakeMap(v.Type())
+	for _, c := range children {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if !v.IsNil() {
+			// Fields of the entry missing in consul keep their value
+			if current := v.MapIndex(reflect.ValueOf(c).Convert(v.Type().Key())); current.IsValid() {
+				elem.Set(current)
+			}
+		}
+		if err := decodeField(elem, dir+c, values, nil); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(c).Convert(v.Type().Key()), elem)
+	}
+	v.Set(m)
+	return nil
+}
+
+// First segments of the keys under dir
+func childNames(dir string, values map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for key := range values {
+		if !strings.HasPrefix(key, dir) {
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(key, dir), "/", 2)[0]
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Add the pairs of the value to pairs. Structs, maps and decoded TOML documents are supported.
+func encodeField(v reflect.Value, key string, pairs map[string]string) error {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if isScalar(v.Type()) {
+		value, err := formatScalar(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		pairs[key] = value
+		return nil
+	}
+
+	dir := key + "/"
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if err := encodeField(v.Field(i), dir+f.Name, pairs); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeField(v.Index(i), dir+strconv.Itoa(i), pairs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if err := encodeField(v.MapIndex(k), dir+fmt.Sprint(k.Interface()), pairs); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("Can't get the type of field: " + key)
+	}
+	return nil
+}
+
+func isScalar(t reflect.Type) bool {
+	if t == timeType || t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func parseScalar(v reflect.Value, value string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+func formatScalar(v reflect.Value) (string, error) {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+/*
+ * Key/value pairs of a configuration under the prefix of the application and profiles.
+ * The configuration is a struct, or a map such as a TOML document decoded in a
+ * map[string]interface{}.
+ */
+func EncodeKeyValuePairs(configuration interface{}, applicationName string, profiles []string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if err := encodeField(reflect.ValueOf(configuration), strings.TrimSuffix(KeyPrefix(applicationName, profiles), "/"), pairs); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+/*
+ * Set the fields of configurationStruct found in the pairs of the application. The pairs of the
+ * fields without value in values are returned with their current value, so that they can be stored.
+ */
+func SetKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string, values map[string]string) (map[string]string, error) {
+	missing := make(map[string]string)
+	if err := decodeStruct(reflect.ValueOf(configurationStruct).Elem(), KeyPrefix(applicationName, profiles), values, missing); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// Key/value pairs stored in consul for the application and profiles
+func GetKeyValuePairs(applicationName string, profiles []string) (map[string]string, error) {
+	if consul == nil {
+		return nil, errors.New("Consul wasn't initialized, can't get key/value pairs")
+	}
+	pairs, _, err := consul.KV().List(KeyPrefix(applicationName, profiles), nil)
+	if err != nil {
+		return nil, err
+	}
+	return pairsByKey(pairs), nil
+}
+
+// Store the pairs in consul
+func PutKeyValuePairs(pairs map[string]string) error {
+	if consul == nil {
+		return errors.New("Consul wasn't initialized, can't put key/value pairs")
+	}
+	kv := consul.KV()
+	for key, value := range pairs {
+		if _, err := kv.Put(&consulapi.KVPair{Key: key, Value: []byte(value)}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Key whose value differs between the local configuration and consul
+type Difference struct {
+	Key      string
+	Local    string
+	Remote   string
+	InLocal  bool
+	InRemote bool
+}
+
+// Differences between the local and remote pairs, sorted by key
+func DiffKeyValuePairs(local map[string]string, remote map[string]string) []Difference {
+	var diffs []Difference
+	for key, l := range local {
+		r, ok := remote[key]
+		if !ok || r != l {
+			diffs = append(diffs, Difference{Key: key, Local: l, Remote: r, InLocal: true, InRemote: ok})
+		}
+	}
+	for key, r := range remote {
+		if _, ok := local[key]; !ok {
+			diffs = append(diffs, Difference{Key: key, Remote: r, InRemote: true})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package consulclient
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type kvBroker struct {
+	Host string
+	Port int
+}
+
+type kvConfiguration struct {
+	Name     string
+	Enabled  bool
+	Limit    int64
+	Ratio    float64
+	Timeout  time.Duration
+	Broker   kvBroker
+	Filters  []string
+	Brokers  []kvBroker
+	Labels   map[string]string
+	internal string
+}
+
+func TestEncodeDecodeKeyValuePairs(t *testing.T) {
+	c := kvConfiguration{
+		Name:    "distro",
+		Enabled: true,
+		Limit:   1 << 40,
+		Ratio:   0.25,
+		Timeout: 1500 * time.Millisecond,
+		Broker:  kvBroker{"mqtt", 1883},
+		Filters: []string{"a", "b"},
+		Brokers: []kvBroker{{"one", 1}, {"two", 2}},
+		Labels:  map[string]string{"site": "lab"},
+	}
+	pairs, err := EncodeKeyValuePairs(&c, "export-distro", []string{"go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"config/export-distro;go/Name":           "distro",
+		"config/export-distro;go/Enabled":        "true",
+		"config/export-distro;go/Limit":          "1099511627776",
+		"config/export-distro;go/Ratio":          "0.25",
+		"config/export-distro;go/Timeout":        "1.5s",
+		"config/export-distro;go/Broker/Host":    "mqtt",
+		"config/export-distro;go/Broker/Port":    "1883",
+		"config/export-distro;go/Filters/0":      "a",
+		"config/export-distro;go/Filters/1":      "b",
+		"config/export-distro;go/Brokers/0/Host": "one",
+		"config/export-distro;go/Brokers/0/Port": "1",
+		"config/export-distro;go/Brokers/1/Host": "two",
+		"config/export-distro;go/Brokers/1/Port": "2",
+		"config/export-distro;go/Labels/site":    "lab",
+	}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+
+	decoded := kvConfiguration{}
+	if err := decodeStruct(reflect.ValueOf(&decoded).Elem(), "config/export-distro;go/", pairs, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Errorf("unexpected configuration %+v", decoded)
+	}
+}
+
+func TestDecodeKeyValuePairsMissing(t *testing.T) {
+	c := kvConfiguration{Name: "local", Filters: []string{"a", "b", "c"}, Labels: map[string]string{"site": "lab"}}
+	values := map[string]string{
+		"config/app;go/Timeout":     "10s",
+		"config/app;go/Filters/0":   "x",
+		"config/app;go/Labels/zone": "1",
+	}
+	missing := make(map[string]string)
+	if err := decodeStruct(reflect.ValueOf(&c).Elem(), "config/app;go/", values, missing); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pairs of a slice or map replace it, the fields missing in consul are reported
+	if c.Timeout != 10*time.Second || !reflect.DeepEqual(c.Filters, []string{"x"}) ||
+		!reflect.DeepEqual(c.Labels, map[string]string{"zone": "1"}) || c.Name != "local" {
+		t.Errorf("unexpected configuration %+v", c)
+	}
+	if missing["config/app;go/Name"] != "local" || missing["config/app;go/Broker/Port"] != "0" {
+		t.Errorf("unexpected missing pairs %v", missing)
+	}
+	if _, ok := missing["config/app;go/Filters/1"]; ok {
+		t.Error("element of a slice set in consul reported missing")
+	}
+
+	if err := decodeStruct(reflect.ValueOf(&c).Elem(), "config/app;go/", map[string]string{"config/app;go/Limit": "many"}, nil); err == nil {
+		t.Error("invalid value should be rejected")
+	}
+}
+
+func TestDiffKeyValuePairs(t *testing.T) {
+	local := map[string]string{"a": "1", "b": "2", "c": "3"}
+	remote := map[string]string{"a": "1", "b": "20", "d": "4"}
+	diffs := DiffKeyValuePairs(local, remote)
+	expected := []Difference{
+		{Key: "b", Local: "2", Remote: "20", InLocal: true, InRemote: true},
+		{Key: "c", Local: "3", InLocal: true},
+		{Key: "d", Remote: "4", InRemote: true},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("unexpected differences %+v", diffs)
+	}
+}
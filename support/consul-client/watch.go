@@ -0,0 +1,98 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package consulclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const (
+	watchWaitTime   = 5 * time.Minute  // Longest blocking query
+	watchRetryDelay = 10 * time.Second // Delay after a failed query
+)
+
+/*
+ * Follow the key/value pairs of the configuration with blocking queries until stop is closed.
+ * The pairs are decoded over a copy of configurationStruct, changed receives the versions
+ * before and after every change of the pairs, and failed the errors of the queries.
+ */
+func WatchKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string,
+	stop <-chan struct{}, changed func(previous, next interface{}), failed func(err error)) error {
+	if consul == nil {
+		return errors.New("Consul wasn't initialized, can't watch key/value pairs")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	prefix := KeyPrefix(applicationName, profiles)
+	// Every version is decoded over the same copy, only the pairs differ between them
+	base, _ := CopyWithKeyValuePairs(configurationStruct, applicationName, profiles, nil)
+	kv := consul.KV()
+	var previous interface{}
+	var index uint64
+	for {
+		pairs, meta, err := kv.List(prefix, (&consulapi.QueryOptions{WaitIndex: index, WaitTime: watchWaitTime}).WithContext(ctx))
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			failed(err)
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(watchRetryDelay):
+			}
+			continue
+		}
+		if meta.LastIndex == index {
+			continue
+		}
+		index = meta.LastIndex
+
+		next, err := CopyWithKeyValuePairs(base, applicationName, profiles, pairsByKey(pairs))
+		if err != nil {
+			failed(err)
+			continue
+		}
+		// The first version is the baseline of the changes
+		if previous != nil {
+			changed(previous, next)
+		}
+		previous = next
+	}
+}
+
+// Copy of configurationStruct with the fields found in the pairs of the application set
+func CopyWithKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string, values map[string]string) (interface{}, error) {
+	from := reflect.ValueOf(configurationStruct).Elem()
+	to := reflect.New(from.Type())
+	to.Elem().Set(from)
+
+	if err := decodeStruct(to.Elem(), KeyPrefix(applicationName, profiles), values, nil); err != nil {
+		return nil, err
+	}
+	return to.Interface(), nil
+}
@@ -30,6 +30,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 	"github.com/edgexfoundry/edgex-go/support/domain"
 )
 
@@ -174,7 +175,7 @@ func (lc EdgeXLogger) sendLog(logEntry support_domain.LogEntry) error {
 		return err
 	}
 	req.Header.Add("Content-Type", "application/json")
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	// Asynchronous call
 	go lc.makeRequest(client, req)
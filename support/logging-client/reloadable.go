@@ -0,0 +1,61 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// Logging client whose target can be replaced while it is used, when the logging
+// configuration is reloaded
+type ReloadableClient struct {
+	current atomic.Value
+}
+
+func NewReloadableClient(lc LoggingClient) *ReloadableClient {
+	r := &ReloadableClient{}
+	r.Set(lc)
+	return r
+}
+
+// Send the next logs to lc
+func (r *ReloadableClient) Set(lc LoggingClient) {
+	r.current.Store(&lc)
+}
+
+func (r *ReloadableClient) get() LoggingClient {
+	return *r.current.Load().(*LoggingClient)
+}
+
+func (r *ReloadableClient) Debug(msg string, labels ...string) error {
+	return r.get().Debug(msg, labels...)
+}
+
+func (r *ReloadableClient) Error(msg string, labels ...string) error {
+	return r.get().Error(msg, labels...)
+}
+
+func (r *ReloadableClient) Info(msg string, labels ...string) error {
+	return r.get().Info(msg, labels...)
+}
+
+func (r *ReloadableClient) Trace(msg string, labels ...string) error {
+	return r.get().Trace(msg, labels...)
+}
+
+func (r *ReloadableClient) Warn(msg string, labels ...string) error {
+	return r.get().Warn(msg, labels...)
+}
@@ -17,6 +17,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/heartbeat"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
 	"github.com/go-zoo/bone"
 
 	support_domain "github.com/edgexfoundry/edgex-go/support/domain"
@@ -27,6 +31,9 @@ const (
 )
 
 var persist persistence
+var authenticator auth.Authenticator
+var policy = authPolicy()
+var heartbeats = heartbeat.NewAggregator() // Last heartbeat of the services
 
 // Copied from core/metadata/mongoOps.go
 // FIXME share instead of copy
@@ -199,28 +206,42 @@ func httpServer() http.Handler {
 	mux := bone.New()
 	mv1 := mux.Prefix("/api/v1")
 
-	mv1.Get("/ping", http.HandlerFunc(replyPing))
-
-	mv1.Post("/logs", http.HandlerFunc(addLog))
-	mv1.Get("/logs/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/labels/:labels/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/originServices/:services/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/keywords/:keywords/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/logLevels/:levels/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/logLevels/:levels/originServices/:services/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end/:limit", http.HandlerFunc(getLogs))
-	mv1.Get("/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end/:limit", http.HandlerFunc(getLogs))
-
-	mv1.Delete("/logs/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/keywords/:keywords/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/labels/:labels/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/originServices/:services/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/logLevels/:levels/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/logLevels/:levels/originServices/:services/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end", http.HandlerFunc(delLogs))
-	mv1.Delete("/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end", http.HandlerFunc(delLogs))
-	return mux
+	mv1.Get("/ping", metrics.Instrument("/api/v1/ping", http.HandlerFunc(replyPing)))
+
+	mv1.Post("/logs", metrics.Instrument("/api/v1/logs", http.HandlerFunc(addLog)))
+	mv1.Post("/heartbeat", metrics.Instrument(heartbeat.ApiHeartbeatRoute, heartbeats.Handler()))
+	mv1.Get("/heartbeat", metrics.Instrument(heartbeat.ApiHeartbeatRoute, heartbeats.Handler()))
+	mv1.Get("/logs/:limit", metrics.Instrument("/api/v1/logs/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/:start/:end/:limit", metrics.Instrument("/api/v1/logs/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/labels/:labels/:start/:end/:limit", metrics.Instrument("/api/v1/logs/labels/:labels/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/originServices/:services/:start/:end/:limit", metrics.Instrument("/api/v1/logs/originServices/:services/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/keywords/:keywords/:start/:end/:limit", metrics.Instrument("/api/v1/logs/keywords/:keywords/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/logLevels/:levels/:start/:end/:limit", metrics.Instrument("/api/v1/logs/logLevels/:levels/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/logLevels/:levels/originServices/:services/:start/:end/:limit", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end/:limit", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end/:limit", http.HandlerFunc(getLogs)))
+	mv1.Get("/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end/:limit", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end/:limit", http.HandlerFunc(getLogs)))
+
+	mv1.Delete("/logs/:start/:end", metrics.Instrument("/api/v1/logs/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/keywords/:keywords/:start/:end", metrics.Instrument("/api/v1/logs/keywords/:keywords/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/labels/:labels/:start/:end", metrics.Instrument("/api/v1/logs/labels/:labels/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/originServices/:services/:start/:end", metrics.Instrument("/api/v1/logs/originServices/:services/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/logLevels/:levels/:start/:end", metrics.Instrument("/api/v1/logs/logLevels/:levels/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/logLevels/:levels/originServices/:services/:start/:end", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/labels/:labels/:start/:end", http.HandlerFunc(delLogs)))
+	mv1.Delete("/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end", metrics.Instrument("/api/v1/logs/logLevels/:levels/originServices/:services/labels/:labels/keywords/:keywords/:start/:end", http.HandlerFunc(delLogs)))
+
+	mux.Get(metrics.ApiMetricsRoute, metrics.Handler())
+	mux.Get(health.ApiLiveRoute, health.LiveHandler())
+	mux.Get(health.ApiReadyRoute, health.ReadyHandler())
+	return auth.Middleware(authenticator, policy)(mux)
+}
+
+// Scopes required by the logging routes: reads need read, adding logs needs write
+// and removing them needs admin. The configuration can add to or override them.
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().
+		Public("/api/v1/ping", health.ApiLiveRoute, health.ApiReadyRoute).
+		Method(http.MethodDelete, auth.ScopeAdmin)
 }
 
 func getPersistence(config Config) persistence {
@@ -236,6 +257,19 @@ func getPersistence(config Config) persistence {
 	return retValue
 }
 
+// Check that the persistence is configured and, for mongo, that the database answers
+func checkPersistence() error {
+	if persist == nil {
+		return errors.New("persistence not configured")
+	}
+	if ml, ok := persist.(*mongoLog); ok {
+		s := ml.session.Copy()
+		defer s.Close()
+		return s.Ping()
+	}
+	return nil
+}
+
 func StartHTTPServer(config Config, errChan chan error) {
 	go func() {
 		persist = getPersistence(config)
@@ -244,6 +278,23 @@ func StartHTTPServer(config Config, errChan chan error) {
 			return
 		}
 
+		var err error
+		authenticator, err = auth.Setup(auth.Config{
+			Enabled:          config.AuthEnabled,
+			APIKeys:          config.AuthAPIKeys,
+			JWTSecret:        config.AuthJWTSecret,
+			JWTPublicKeyFile: config.AuthJWTPublicKeyFile,
+			Scopes:           config.AuthScopes,
+		}, policy)
+		if err != nil {
+			errChan <- errors.New("Could not configure authentication: " + err.Error())
+			return
+		}
+		health.Register("persistence", checkPersistence)
+		if config.HeartbeatServices != "" {
+			heartbeats = heartbeat.NewAggregator(strings.Split(config.HeartbeatServices, ",")...)
+		}
+
 		p := fmt.Sprintf(":%d", config.Port)
 		errChan <- http.ListenAndServe(p, httpServer())
 	}()
@@ -7,6 +7,7 @@
 package logging
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/edgexfoundry/edgex-go/pkg/heartbeat"
 	"github.com/edgexfoundry/edgex-go/support/domain"
 )
 
@@ -97,6 +99,34 @@ func TestAddLog(t *testing.T) {
 	}
 }
 
+func TestHeartbeats(t *testing.T) {
+	ts := httptest.NewServer(httpServer())
+	defer ts.Close()
+
+	response, err := http.Post(ts.URL+"/api/v1/heartbeat", "application/json",
+		strings.NewReader(`{"service":"core-data","version":"0.5.0","interval":60000}`))
+	if err != nil {
+		t.Fatalf("Error sending heartbeat %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		t.Errorf("Returned status %d, should be %d", response.StatusCode, http.StatusAccepted)
+	}
+
+	response, err = http.Get(ts.URL + "/api/v1/heartbeat")
+	if err != nil {
+		t.Fatalf("Error getting heartbeats %v", err)
+	}
+	defer response.Body.Close()
+	var services []heartbeat.ServiceStatus
+	if err := json.NewDecoder(response.Body).Decode(&services); err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Service != "core-data" || services[0].Missing {
+		t.Errorf("Unexpected heartbeats %+v", services)
+	}
+}
+
 func TestGetLogs(t *testing.T) {
 	var labels = []string{"label1", "label2"}
 	var services = []string{"service1", "service2"}
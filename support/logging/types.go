@@ -7,9 +7,6 @@
 package logging
 
 import (
-	"os"
-	"strconv"
-
 	support_domain "github.com/edgexfoundry/edgex-go/support/domain"
 )
 
@@ -29,13 +26,11 @@ const (
 	defaultMongoUsername       = "logging"
 	defaultMongoPassword       = "password"
 
-	envMongoURL  = "SUPPORT_LOGGING_MONGO_URL"
-	envMongoPort = "SUPPORT_LOGGING_MONGO_PORT"
-
 	PersistenceMongo = "mongodb"
 	PersistenceFile  = "file"
 )
 
+// Fields tagged env are overridden by their variable, see pkg/config.ApplyEnvironment
 type Config struct {
 	Port        int
 	Persistence string
@@ -44,14 +39,24 @@ type Config struct {
 	LogFilename string
 
 	// Used by mongo
-	MongoURL            string
+	MongoURL            string `env:"SUPPORT_LOGGING_MONGO_URL"`
 	MongoUser           string
 	MongoPass           string
 	MongoDatabase       string
 	MongoCollection     string
-	MongoPort           int
+	MongoPort           int `env:"SUPPORT_LOGGING_MONGO_PORT"`
 	MongoConnectTimeout int
 	MongoSocketTimeout  int
+
+	// Services reported missing until their first heartbeat, separated by commas
+	HeartbeatServices string `env:"SUPPORT_LOGGING_HEARTBEAT_SERVICES"`
+
+	// Authentication of the requests, the scope rules are added to the policy of the routes
+	AuthEnabled          bool   `env:"SUPPORT_LOGGING_AUTH_ENABLED"`
+	AuthAPIKeys          string `env:"SUPPORT_LOGGING_AUTH_API_KEYS"`
+	AuthJWTSecret        string `env:"SUPPORT_LOGGING_AUTH_JWT_SECRET"`
+	AuthJWTPublicKeyFile string `env:"SUPPORT_LOGGING_AUTH_JWT_PUBLIC_KEY_FILE"`
+	AuthScopes           string `env:"SUPPORT_LOGGING_AUTH_SCOPES"`
 }
 
 type persistence interface {
@@ -64,38 +69,19 @@ type persistence interface {
 	reset()
 }
 
-func env(key, fallback string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return fallback
-	}
-
-	return value
-}
-
 func GetDefaultConfig() Config {
-	cfg := Config{
+	return Config{
 		Port:        defaultPort,
 		Persistence: defaultPersistence,
 		LogFilename: defaultLogFilename,
 
-		MongoURL: env(envMongoURL, defaultMongoURL),
-
+		MongoURL:            defaultMongoURL,
 		MongoUser:           defaultMongoUsername,
 		MongoPass:           defaultMongoPassword,
 		MongoDatabase:       defaultMongoDB,
 		MongoCollection:     defaultMongoCollection,
+		MongoPort:           defaultMongoPort,
 		MongoConnectTimeout: defaultMongoConnectTimeout,
 		MongoSocketTimeout:  defaultSocketTimeout,
 	}
-
-	MongoPortStr := env(envMongoPort, strconv.Itoa(defaultMongoPort))
-	MongoPort, err := strconv.Atoi(MongoPortStr)
-	if err == nil {
-		cfg.MongoPort = MongoPort
-	} else {
-		cfg.MongoPort = defaultMongoPort
-	}
-
-	return cfg
 }
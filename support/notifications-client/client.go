@@ -22,6 +22,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 )
 
 type CategoryEnum string
@@ -70,7 +72,7 @@ type Notification struct {
 
 // Send a POST call to the notifications service
 func (nc NotificationsClient) RecieveNotification(n Notification) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	// Get the JSON request body
 	requestBody, err := json.Marshal(n)
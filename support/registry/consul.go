@@ -0,0 +1,80 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package registry
+
+import (
+	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+)
+
+// Registry of the consul agent, see support/consul-client
+type consulRegistry struct {
+	host string
+	port int
+}
+
+func newConsulRegistry(host string, port int) (Registry, error) {
+	if err := consulclient.ConsulConnect(host, port); err != nil {
+		return nil, err
+	}
+	return &consulRegistry{host: host, port: port}, nil
+}
+
+func (c *consulRegistry) Register(service Service) error {
+	return consulclient.ConsulRegister(consulclient.ConsulConfig{
+		ConsulAddress:  c.host,
+		ConsulPort:     c.port,
+		ServiceName:    service.Name,
+		ServiceAddress: service.Address,
+		ServicePort:    service.Port,
+		CheckAddress:   service.CheckAddress,
+		CheckInterval:  service.CheckInterval,
+	})
+}
+
+func (c *consulRegistry) Deregister(serviceName string) error {
+	return consulclient.ConsulDeregister(serviceName)
+}
+
+func (c *consulRegistry) Resolve(serviceName string) (Instance, error) {
+	i, err := consulclient.ServiceInstance(serviceName)
+	if err != nil {
+		return Instance{}, err
+	}
+	return Instance{Address: i.Address, Port: i.Port}, nil
+}
+
+func (c *consulRegistry) CheckKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string) error {
+	return consulclient.CheckKeyValuePairs(configurationStruct, applicationName, profiles)
+}
+
+func (c *consulRegistry) WatchKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string,
+	stop <-chan struct{}, changed func(previous, next interface{}), failed func(err error)) error {
+	return consulclient.WatchKeyValuePairs(configurationStruct, applicationName, profiles, stop, changed, failed)
+}
+
+func (c *consulRegistry) KeyValuePairs(applicationName string, profiles []string) (map[string]string, error) {
+	return consulclient.GetKeyValuePairs(applicationName, profiles)
+}
+
+func (c *consulRegistry) PutKeyValuePairs(pairs map[string]string) error {
+	return consulclient.PutKeyValuePairs(pairs)
+}
+
+// The discovery of consul is stopped by Deregister
+func (c *consulRegistry) Close() error {
+	return nil
+}
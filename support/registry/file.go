@@ -0,0 +1,366 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	consulclient "github.com/edgexfoundry/edgex-go/support/consul-client"
+)
+
+/*
+ * Registry kept in a directory shared by the services, for the sites without consul agent:
+ *   - every instance of a service is a JSON file services/<name>/<address>-<port>.json, updated
+ *     by the service itself with the result of its health check. An instance whose check is older
+ *     than three intervals belongs to a stopped service and isn't resolved.
+ *   - every key/value pair is a file under kv/ whose path is the key
+ *     (kv/config/core-data;go/Writable/LogLevel)
+ * The files are replaced atomically, so that the services never read a partial write.
+ */
+
+const (
+	defaultCheckInterval = 10 * time.Second
+	staleChecks          = 3 // Number of missed checks before an instance is ignored
+)
+
+var fileWatchInterval = time.Second // Period of the polling of the key/value pairs
+
+// Instance of a service as stored in its file
+type fileInstance struct {
+	Name     string
+	Address  string
+	Port     int
+	Passing  bool
+	Checked  time.Time
+	Interval time.Duration
+}
+
+type registration struct {
+	instance fileInstance
+	stop     chan struct{}
+}
+
+type fileRegistry struct {
+	directory string
+	mutex     sync.Mutex
+	checks    map[string]*registration // Health checks of the services registered by this process
+	next      map[string]int           // The instances are taken in turn
+}
+
+func newFileRegistry(directory string) (Registry, error) {
+	if directory == "" {
+		return nil, errors.New("No directory configured for the file registry")
+	}
+	for _, dir := range []string{"services", "kv"} {
+		if err := os.MkdirAll(filepath.Join(directory, dir), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &fileRegistry{directory: directory, checks: make(map[string]*registration), next: make(map[string]int)}, nil
+}
+
+func (f *fileRegistry) Register(service Service) error {
+	interval := defaultCheckInterval
+	if service.CheckInterval != "" {
+		d, err := time.ParseDuration(service.CheckInterval)
+		if err != nil {
+			return errors.New("Invalid check interval of " + service.Name + ": " + err.Error())
+		}
+		interval = d
+	}
+	checkAddress := service.CheckAddress
+	if checkAddress == "" {
+		checkAddress = "http://" + Instance{service.Address, service.Port}.Host() + health.ApiReadyRoute
+	}
+	instance := fileInstance{Name: service.Name, Address: service.Address, Port: service.Port, Interval: interval}
+	if err := os.MkdirAll(filepath.Join(f.directory, "services", service.Name), 0755); err != nil {
+		return err
+	}
+	// The instance is critical until its first check passes
+	if err := f.writeInstance(instance); err != nil {
+		return err
+	}
+
+	reg := &registration{instance: instance, stop: make(chan struct{})}
+	f.mutex.Lock()
+	if previous, ok := f.checks[service.Name]; ok {
+		close(previous.stop)
+	}
+	f.checks[service.Name] = reg
+	f.mutex.Unlock()
+	go f.check(reg, checkAddress)
+	return nil
+}
+
+// Check the health of the instance every interval and record the result until it is deregistered
+func (f *fileRegistry) check(reg *registration, checkAddress string) {
+	instance := reg.instance
+	client := http.Client{Timeout: instance.Interval}
+	ticker := time.NewTicker(instance.Interval)
+	defer ticker.Stop()
+	for {
+		resp, err := client.Get(checkAddress)
+		instance.Passing = err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300
+		if err == nil {
+			resp.Body.Close()
+		}
+		instance.Checked = time.Now()
+
+		// Deregister removes the file under the same lock, it isn't written back afterwards
+		f.mutex.Lock()
+		if f.checks[instance.Name] == reg {
+			f.writeInstance(instance)
+		}
+		f.mutex.Unlock()
+
+		select {
+		case <-reg.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *fileRegistry) instancePath(instance fileInstance) string {
+	return filepath.Join(f.directory, "services", instance.Name, instance.Address+"-"+strconv.Itoa(instance.Port)+".json")
+}
+
+func (f *fileRegistry) writeInstance(instance fileInstance) error {
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.instancePath(instance), data)
+}
+
+func (f *fileRegistry) Deregister(serviceName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	reg, ok := f.checks[serviceName]
+	if !ok {
+		return nil
+	}
+	delete(f.checks, serviceName)
+	close(reg.stop)
+
+	// Only the instance registered by this process is removed
+	if err := os.Remove(f.instancePath(reg.instance)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *fileRegistry) readInstances(serviceName string) ([]fileInstance, error) {
+	dir := filepath.Join(f.directory, "services", serviceName)
+	names, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var instances []fileInstance
+	for _, n := range names {
+		if n.IsDir() || strings.HasPrefix(n.Name(), ".") || !strings.HasSuffix(n.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, n.Name()))
+		if err != nil {
+			continue // Removed by its service since the listing
+		}
+		var i fileInstance
+		if err := json.Unmarshal(data, &i); err != nil {
+			continue
+		}
+		instances = append(instances, i)
+	}
+	return instances, nil
+}
+
+func (f *fileRegistry) Resolve(serviceName string) (Instance, error) {
+	instances, err := f.readInstances(serviceName)
+	if err != nil {
+		return Instance{}, err
+	}
+	var healthy []Instance
+	for _, i := range instances {
+		if i.Passing && time.Since(i.Checked) <= staleChecks*i.Interval {
+			healthy = append(healthy, Instance{Address: i.Address, Port: i.Port})
+		}
+	}
+	if len(healthy) == 0 {
+		return Instance{}, errors.New("no healthy instance of " + serviceName)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	i := healthy[f.next[serviceName]%len(healthy)]
+	f.next[serviceName]++
+	return i, nil
+}
+
+// Path of the file of the key, the keys can't leave the directory of the pairs
+func (f *fileRegistry) keyPath(key string) (string, error) {
+	for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.HasPrefix(segment, ".") {
+			return "", errors.New("Invalid key: " + key)
+		}
+	}
+	return filepath.Join(f.directory, "kv", filepath.FromSlash(key)), nil
+}
+
+func (f *fileRegistry) KeyValuePairs(applicationName string, profiles []string) (map[string]string, error) {
+	prefix := consulclient.KeyPrefix(applicationName, profiles)
+	root, err := f.keyPath(prefix)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make(map[string]string)
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		value, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		pairs[prefix+filepath.ToSlash(rel)] = string(value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+func (f *fileRegistry) PutKeyValuePairs(pairs map[string]string) error {
+	for key, value := range pairs {
+		path, err := f.keyPath(key)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, []byte(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fileRegistry) CheckKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string) error {
+	values, err := f.KeyValuePairs(applicationName, profiles)
+	if err != nil {
+		return err
+	}
+
+	// Set the fields found in the directory, then create the pairs of the others
+	missing, err := consulclient.SetKeyValuePairs(configurationStruct, applicationName, profiles, values)
+	if err != nil {
+		return err
+	}
+	return f.PutKeyValuePairs(missing)
+}
+
+// The pairs are polled, every change of the pairs is decoded over the same copy of the configuration
+func (f *fileRegistry) WatchKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string,
+	stop <-chan struct{}, changed func(previous, next interface{}), failed func(err error)) error {
+	base, _ := consulclient.CopyWithKeyValuePairs(configurationStruct, applicationName, profiles, nil)
+	var previous interface{}
+	var previousPairs map[string]string
+	ticker := time.NewTicker(fileWatchInterval)
+	defer ticker.Stop()
+	for {
+		pairs, err := f.KeyValuePairs(applicationName, profiles)
+		if err != nil {
+			failed(err)
+		} else if previous == nil || !reflect.DeepEqual(pairs, previousPairs) {
+			next, err := consulclient.CopyWithKeyValuePairs(base, applicationName, profiles, pairs)
+			if err != nil {
+				failed(err)
+			} else {
+				// The first version is the baseline of the changes
+				if previous != nil {
+					changed(previous, next)
+				}
+				previous = next
+			}
+			previousPairs = pairs
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop the health checks, the instances become stale
+func (f *fileRegistry) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for name, reg := range f.checks {
+		close(reg.stop)
+		delete(f.checks, name)
+	}
+	return nil
+}
+
+// Replace the file by renaming a temporary file of the same directory
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
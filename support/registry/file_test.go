@@ -0,0 +1,188 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package registry
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fileConfiguration struct {
+	Name    string
+	Limit   int
+	Filters []string
+}
+
+func newTestFileRegistry(t *testing.T) (*fileRegistry, func()) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := newFileRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r.(*fileRegistry), func() {
+		r.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func waitFor(t *testing.T, what string, ok func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatal(what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFileRegistryServices(t *testing.T) {
+	r, cleanup := newTestFileRegistry(t)
+	defer cleanup()
+
+	var ready int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&ready) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	err := r.Register(Service{Name: "core-metadata", Address: u.Hostname(), Port: port, CheckAddress: server.URL, CheckInterval: "20ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The instance isn't resolved while its check fails
+	if _, err := r.Resolve("core-metadata"); err == nil {
+		t.Error("instance resolved before its check passed")
+	}
+	atomic.StoreInt32(&ready, 1)
+	waitFor(t, "passing instance not resolved", func() bool {
+		i, err := r.Resolve("core-metadata")
+		return err == nil && i == Instance{u.Hostname(), port}
+	})
+
+	// Another process sees the instance until it goes stale
+	other, err := newFileRegistry(r.directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Resolve("core-metadata"); err != nil {
+		t.Errorf("instance not shared: %v", err)
+	}
+	r.Close()
+	waitFor(t, "stale instance resolved", func() bool {
+		_, err := other.Resolve("core-metadata")
+		return err != nil
+	})
+
+	if err := r.Register(Service{Name: "core-data", Address: "localhost", Port: 48080, CheckInterval: "1m"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Deregister("core-data"); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := ioutil.ReadDir(filepath.Join(r.directory, "services", "core-data")); len(files) != 0 {
+		t.Errorf("instance file left after deregistration: %v", files[0].Name())
+	}
+	if err := r.Register(Service{Name: "core-data", CheckInterval: "soon"}); err == nil {
+		t.Error("invalid check interval accepted")
+	}
+}
+
+func TestFileRegistryKeyValuePairs(t *testing.T) {
+	r, cleanup := newTestFileRegistry(t)
+	defer cleanup()
+
+	err := r.PutKeyValuePairs(map[string]string{"config/export-distro;go/Limit": "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fileConfiguration{Name: "distro", Limit: 1, Filters: []string{"a"}}
+	if err := r.CheckKeyValuePairs(&c, "export-distro", []string{"go"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Limit != 10 {
+		t.Errorf("value of the registry not applied: %+v", c)
+	}
+	pairs, err := r.KeyValuePairs("export-distro", []string{"go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"config/export-distro;go/Name":      "distro",
+		"config/export-distro;go/Limit":     "10",
+		"config/export-distro;go/Filters/0": "a",
+	}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+	if err := r.PutKeyValuePairs(map[string]string{"config/../../escape": "x"}); err == nil {
+		t.Error("key outside of the registry accepted")
+	}
+}
+
+func TestFileRegistryWatch(t *testing.T) {
+	r, cleanup := newTestFileRegistry(t)
+	defer cleanup()
+	defer func(interval time.Duration) { fileWatchInterval = interval }(fileWatchInterval)
+	fileWatchInterval = 10 * time.Millisecond
+
+	c := fileConfiguration{Name: "distro", Limit: 1}
+	if err := r.CheckKeyValuePairs(&c, "export-distro", []string{"go"}); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	changes := make(chan [2]*fileConfiguration, 1)
+	done := make(chan struct{})
+	go func() {
+		r.WatchKeyValuePairs(&c, "export-distro", []string{"go"}, stop, func(previous, next interface{}) {
+			changes <- [2]*fileConfiguration{previous.(*fileConfiguration), next.(*fileConfiguration)}
+		}, func(err error) {
+			t.Error(err)
+		})
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := r.PutKeyValuePairs(map[string]string{"config/export-distro;go/Limit": "5"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case change := <-changes:
+		if change[0].Limit != 1 || change[1].Limit != 5 || c.Limit != 1 {
+			t.Errorf("unexpected change %+v -> %+v", change[0], change[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("change of the pairs not followed")
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,172 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package registry
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+const (
+	CONSUL = "consul" // Consul agent, see support/consul-client
+	FILE   = "file"   // Directory shared by the services, see file.go
+)
+
+/*
+ * Registry of the services and of their configuration: registration and health checks of the
+ * services, discovery of their healthy instances and the key/value pairs of their configuration.
+ */
+type Registry interface {
+	// Register the service and its health check
+	Register(service Service) error
+	// Remove the service and its health check, a no-op if the service was never registered
+	Deregister(serviceName string) error
+	// Healthy instance of the service, the instances are taken in turn
+	Resolve(serviceName string) (Instance, error)
+	// Update the configuration from the key/value pairs, the fields without pair are added
+	CheckKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string) error
+	// Follow the key/value pairs until stop is closed, see consulclient.WatchKeyValuePairs
+	WatchKeyValuePairs(configurationStruct interface{}, applicationName string, profiles []string,
+		stop <-chan struct{}, changed func(previous, next interface{}), failed func(err error)) error
+	// Key/value pairs stored for the application and profiles
+	KeyValuePairs(applicationName string, profiles []string) (map[string]string, error)
+	// Store the pairs
+	PutKeyValuePairs(pairs map[string]string) error
+	// Stop the health checks and the discovery
+	Close() error
+}
+
+// Service registered with its health check
+type Service struct {
+	Name          string
+	Address       string
+	Port          int
+	CheckAddress  string // Defaults to the readiness endpoint of the service
+	CheckInterval string // Such as "10s"
+}
+
+// Address and port of a healthy instance of a service
+type Instance struct {
+	Address string
+	Port    int
+}
+
+// host:port of the instance
+func (i Instance) Host() string {
+	return net.JoinHostPort(i.Address, strconv.Itoa(i.Port))
+}
+
+// Selection of the registry of a service
+type Config struct {
+	Type       string // CONSUL (the default) or FILE
+	ConsulHost string
+	ConsulPort int
+	Directory  string // Shared directory of the FILE registry
+}
+
+// Registry of the configured type
+func New(config Config) (Registry, error) {
+	switch config.Type {
+	case CONSUL, "":
+		return newConsulRegistry(config.ConsulHost, config.ConsulPort)
+	case FILE:
+		return newFileRegistry(config.Directory)
+	default:
+		return nil, errors.New("Unknown registry type: " + config.Type)
+	}
+}
+
+var (
+	mutex   sync.Mutex
+	current Registry = nil // Set by Connect
+)
+
+// Connect to the registry of the configured type, used by the endpoints from then on
+func Connect(config Config) (Registry, error) {
+	r, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+	mutex.Lock()
+	previous := current
+	current = r
+	mutex.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+	return r, nil
+}
+
+// Registry the service is connected to, nil before Connect
+func Current() Registry {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return current
+}
+
+// Deregister the service from the current registry and close it, a no-op before Connect
+func Disconnect(serviceName string) error {
+	mutex.Lock()
+	r := current
+	current = nil
+	mutex.Unlock()
+	if r == nil {
+		return nil
+	}
+	err := r.Deregister(serviceName)
+	r.Close()
+	return err
+}
+
+// Healthy instance of the service, an error when no registry is connected
+func ServiceInstance(serviceName string) (Instance, error) {
+	r := Current()
+	if r == nil {
+		return Instance{}, errors.New("No registry connected, can't resolve " + serviceName)
+	}
+	return r.Resolve(serviceName)
+}
+
+/*
+ * Base URL of a service API. The address of the URL is replaced by a healthy instance of the
+ * service when it is registered, the URL is used as it is when no registry is connected or
+ * the service can't be resolved.
+ */
+type Endpoint struct {
+	ServiceName string
+	URL         string
+}
+
+// URL of the endpoint, with the address of an instance of the service when it resolves
+func (e Endpoint) Resolve() string {
+	if e.ServiceName == "" {
+		return e.URL
+	}
+	instance, err := ServiceInstance(e.ServiceName)
+	if err != nil {
+		return e.URL
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return e.URL
+	}
+	u.Host = instance.Host()
+	return u.String()
+}
@@ -0,0 +1,64 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package registry
+
+import (
+	"errors"
+	"testing"
+)
+
+// Registry resolving a fixed instance of metadata
+type fixedRegistry struct {
+	Registry
+}
+
+func (fixedRegistry) Resolve(serviceName string) (Instance, error) {
+	if serviceName != "core-metadata" {
+		return Instance{}, errors.New("no healthy instance of " + serviceName)
+	}
+	return Instance{"meta-1", 48081}, nil
+}
+
+func (fixedRegistry) Close() error {
+	return nil
+}
+
+func TestEndpointResolve(t *testing.T) {
+	e := Endpoint{ServiceName: "core-metadata", URL: "http://localhost:48081/api/v1/device"}
+	if url := e.Resolve(); url != e.URL {
+		t.Errorf("resolved %s without registry", url)
+	}
+
+	current = fixedRegistry{}
+	defer func() { current = nil }()
+	if url := e.Resolve(); url != "http://meta-1:48081/api/v1/device" {
+		t.Errorf("unexpected endpoint %s", url)
+	}
+	e = Endpoint{ServiceName: "core-data", URL: "http://localhost:48080/api/v1/reading"}
+	if url := e.Resolve(); url != e.URL {
+		t.Errorf("unresolved service replaced by %s", url)
+	}
+}
+
+func TestNewUnknownType(t *testing.T) {
+	if _, err := New(Config{Type: "etcd"}); err == nil {
+		t.Error("unknown registry type accepted")
+	}
+	if _, err := New(Config{Type: FILE}); err == nil {
+		t.Error("file registry without directory accepted")
+	}
+}
@@ -0,0 +1,340 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/clients/commandclients"
+	"github.com/edgexfoundry/edgex-go/core/clients/coredataclients"
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+	"github.com/edgexfoundry/edgex-go/support/registry"
+	"go.uber.org/zap"
+)
+
+// Readings kept per device and name for the aggregates, the oldest are dropped beyond
+const maxWindowSamples = 10000
+
+type sample struct {
+	origin int64
+	value  float64
+}
+
+// A rule with its compiled expressions and the state of every device
+type compiledRule struct {
+	rule       Rule
+	condition  *expression
+	values     []*expression // Value of the event actions, nil for the other actions
+	aggregated map[string]bool
+	matched    map[string]bool                // Devices the condition holds for
+	windows    map[string]map[string][]sample // Readings by device and name
+}
+
+func compileRule(r Rule) (*compiledRule, error) {
+	if r.Name == "" {
+		return nil, errors.New("rule without name")
+	}
+	if r.Window < 0 {
+		return nil, errors.New("negative rule window")
+	}
+	if len(r.Actions) == 0 {
+		return nil, errors.New("rule without action")
+	}
+	c := &compiledRule{
+		rule:       r,
+		values:     make([]*expression, len(r.Actions)),
+		aggregated: make(map[string]bool),
+		matched:    make(map[string]bool),
+		windows:    make(map[string]map[string][]sample),
+	}
+
+	var err error
+	if c.condition, err = compile(r.Condition); err != nil {
+		return nil, fmt.Errorf("invalid condition: %v", err)
+	}
+	expressions := []*expression{c.condition}
+	for i, a := range r.Actions {
+		if err := a.validate(); err != nil {
+			return nil, err
+		}
+		if a.Type != ActionEvent {
+			continue
+		}
+		if c.values[i], err = compile(a.Value); err != nil {
+			return nil, fmt.Errorf("invalid value of the %s reading: %v", a.Reading, err)
+		}
+		expressions = append(expressions, c.values[i])
+	}
+	for _, e := range expressions {
+		for _, name := range e.aggregated {
+			c.aggregated[name] = true
+		}
+	}
+	if len(c.aggregated) > 0 && r.Window == 0 {
+		return nil, errors.New("rule with aggregates but without window")
+	}
+	return c, nil
+}
+
+// Readings of an event and windows of its device seen by the expressions
+type eventScope struct {
+	event    *models.Event
+	readings map[string]interface{}
+	windows  map[string][]sample
+	from     int64 // Start of the window, excluded
+}
+
+func (s eventScope) value(name string) interface{} {
+	switch name {
+	case nameDevice:
+		return s.event.Device
+	case nameOrigin:
+		return float64(s.event.Origin)
+	}
+	return s.readings[name]
+}
+
+func (s eventScope) window(name string) []float64 {
+	var values []float64
+	for _, smp := range s.windows[name] {
+		if smp.origin > s.from {
+			values = append(values, smp.value)
+		}
+	}
+	return values
+}
+
+// Actions of a rule to run for the device of an event
+type firing struct {
+	rule   Rule
+	device string
+	values []string // Readings of the event actions
+}
+
+// Evaluates the rules on the events of core data
+type engine struct {
+	mutex sync.Mutex
+	rules []*compiledRule
+	now   func() time.Time
+}
+
+func newEngine() *engine {
+	return &engine{now: time.Now}
+}
+
+func (e *engine) millis() int64 {
+	return e.now().UnixNano() / int64(time.Millisecond)
+}
+
+// Replace the rules. The state of the rules removed or changed is forgotten,
+// invalid rules are ignored.
+func (e *engine) setRules(rules []Rule) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	old := make(map[string]*compiledRule, len(e.rules))
+	for _, c := range e.rules {
+		old[c.rule.Name] = c
+	}
+	compiled := make([]*compiledRule, 0, len(rules))
+	for _, r := range rules {
+		if c, ok := old[r.Name]; ok && reflect.DeepEqual(c.rule, r) {
+			compiled = append(compiled, c)
+			continue
+		}
+		c, err := compileRule(r)
+		if err != nil {
+			logger.Error("Invalid rule", zap.String("rule", r.Name), zap.Error(err))
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	e.rules = compiled
+}
+
+// Apply the rules to an event, returns the actions to run
+func (e *engine) process(event *models.Event) []firing {
+	if event == nil || event.Device == "" {
+		return nil
+	}
+	origin := event.Origin
+	if origin == 0 {
+		origin = e.millis()
+	}
+	readings := make(map[string]interface{}, len(event.Readings))
+	for _, r := range event.Readings {
+		readings[r.Name] = readingValue(r.Value)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var firings []firing
+	for _, c := range e.rules {
+		if c.rule.Device != "" && c.rule.Device != event.Device {
+			continue
+		}
+		s := eventScope{event: event, readings: readings, windows: c.record(event.Device, origin, readings), from: origin - c.rule.Window}
+
+		matched, err := c.condition.match(s)
+		if err != nil {
+			logger.Debug("Rule not evaluated", zap.String("rule", c.rule.Name), zap.String("device", event.Device), zap.Error(err))
+			continue
+		}
+		if matched && !c.matched[event.Device] {
+			firings = append(firings, c.fire(s))
+		}
+		c.matched[event.Device] = matched
+	}
+	return firings
+}
+
+// Add the numeric readings read by the aggregates to the windows of the device
+func (c *compiledRule) record(device string, origin int64, readings map[string]interface{}) map[string][]sample {
+	if len(c.aggregated) == 0 {
+		return nil
+	}
+	windows, ok := c.windows[device]
+	if !ok {
+		windows = make(map[string][]sample)
+		c.windows[device] = windows
+	}
+	for name := range c.aggregated {
+		samples := windows[name]
+		if v, ok := readings[name].(float64); ok {
+			samples = append(samples, sample{origin: origin, value: v})
+		}
+		// Drop the readings out of the window
+		i := 0
+		for i < len(samples) && (samples[i].origin <= origin-c.rule.Window || len(samples)-i > maxWindowSamples) {
+			i++
+		}
+		windows[name] = samples[i:]
+	}
+	return windows
+}
+
+func (c *compiledRule) fire(s eventScope) firing {
+	f := firing{rule: c.rule, device: s.event.Device, values: make([]string, len(c.values))}
+	for i, v := range c.values {
+		if v == nil {
+			continue
+		}
+		value, err := v.format(s)
+		if err != nil {
+			logger.Warn("Reading of the event action not evaluated", zap.String("rule", c.rule.Name), zap.Error(err))
+		}
+		f.values[i] = value
+	}
+	return f
+}
+
+// Where the actions go
+type actuator interface {
+	put(deviceId string, commandId string, body string) error
+	notify(n notifications.Notification) error
+	addEvent(e *models.Event) error
+}
+
+type restActuator struct {
+	commands commandclients.CommandClient
+	events   coredataclients.EventClient
+	notifier notifications.NotificationsClient
+}
+
+func newRestActuator(cfg Config) actuator {
+	return &restActuator{
+		commands: commandclients.NewCommandClient(registry.Endpoint{ServiceName: cfg.CommandServiceName, URL: cfg.CommandURL}),
+		events:   coredataclients.NewEventClient(registry.Endpoint{ServiceName: cfg.DataServiceName, URL: cfg.EventURL}),
+		notifier: notifications.NotificationsClient{RemoteUrl: cfg.NotificationURL, OwningService: applicationName},
+	}
+}
+
+func (a *restActuator) put(deviceId string, commandId string, body string) error {
+	_, err := a.commands.Put(deviceId, commandId, body)
+	return err
+}
+
+func (a *restActuator) notify(n notifications.Notification) error {
+	return a.notifier.RecieveNotification(n)
+}
+
+func (a *restActuator) addEvent(e *models.Event) error {
+	_, err := a.events.Add(e)
+	return err
+}
+
+// Run the actions of a rule, an action failing doesn't stop the next ones
+func (f firing) run(act actuator, now int64) {
+	logger.Info("Rule matched", zap.String("rule", f.rule.Name), zap.String("device", f.device))
+	for i, a := range f.rule.Actions {
+		var err error
+		switch a.Type {
+		case ActionCommand:
+			err = act.put(a.DeviceId, a.CommandId, a.Body)
+		case ActionNotification:
+			err = act.notify(f.notification(a, now))
+		case ActionEvent:
+			if f.values[i] == "" {
+				continue
+			}
+			err = act.addEvent(f.event(a, f.values[i], now))
+		}
+		if err != nil {
+			logger.Error("Rule action failed", zap.String("rule", f.rule.Name), zap.String("action", a.Type), zap.Error(err))
+		}
+	}
+}
+
+func (f firing) notification(a Action, now int64) notifications.Notification {
+	n := notifications.Notification{
+		Slug:        fmt.Sprintf("rule-%s-%s-%d", f.rule.Name, f.device, now),
+		Sender:      applicationName,
+		Category:    notifications.CategoryEnum(a.Category),
+		Severity:    notifications.SeverityEnum(a.Severity),
+		Content:     a.Content,
+		Description: f.rule.Description,
+		Labels:      []string{"rule", f.rule.Name, f.device},
+	}
+	if n.Category == "" {
+		n.Category = notifications.HW_HEALTH
+	}
+	if n.Severity == "" {
+		n.Severity = notifications.NORMAL
+	}
+	if n.Content == "" {
+		n.Content = fmt.Sprintf("Rule %s matched for device %s: %s", f.rule.Name, f.device, f.rule.Condition)
+	}
+	return n
+}
+
+func (f firing) event(a Action, value string, now int64) *models.Event {
+	device := a.Device
+	if device == "" {
+		device = f.rule.Name
+	}
+	return &models.Event{
+		Device:   device,
+		Origin:   now,
+		Readings: []models.Reading{{Device: device, Name: a.Reading, Value: value, Origin: now}},
+	}
+}
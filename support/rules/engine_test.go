@@ -0,0 +1,183 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+)
+
+type mockActuator struct {
+	puts          []string
+	notifications []notifications.Notification
+	events        []*models.Event
+}
+
+func (m *mockActuator) put(deviceId string, commandId string, body string) error {
+	m.puts = append(m.puts, deviceId+"/"+commandId+" "+body)
+	return errors.New("device unreachable")
+}
+
+func (m *mockActuator) notify(n notifications.Notification) error {
+	m.notifications = append(m.notifications, n)
+	return nil
+}
+
+func (m *mockActuator) addEvent(e *models.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+func temperatureEvent(device string, value string, origin int64) *models.Event {
+	return &models.Event{Device: device, Origin: origin, Readings: []models.Reading{{Name: "Temperature", Value: value}}}
+}
+
+var fanRule = Rule{
+	Name:      "fan",
+	Condition: "avg(Temperature) > 30",
+	Window:    3000,
+	Actions: []Action{
+		{Type: ActionCommand, DeviceId: "fan1", CommandId: "speed", Body: `{"speed":"high"}`},
+		{Type: ActionNotification},
+		{Type: ActionEvent, Reading: "AverageTemperature", Value: "avg(Temperature)"},
+	},
+}
+
+func TestEngineWindow(t *testing.T) {
+	e := newEngine()
+	e.setRules([]Rule{fanRule})
+
+	tests := []struct {
+		value  string
+		origin int64
+		fired  bool
+	}{
+		{"20", 1000, false},
+		{"35", 2000, false}, // 27.5 on average
+		{"41", 3000, true},  // 32
+		{"40", 4000, false}, // Still matched, 38.67
+		{"25", 5000, false},
+		{"20", 6000, false}, // 28.33, no longer matched
+		{"50", 7000, true},  // 31.67
+	}
+	for _, tt := range tests {
+		firings := e.process(temperatureEvent("dev1", tt.value, tt.origin))
+		if (len(firings) == 1) != tt.fired {
+			t.Errorf("reading %s at %d: %d firings, fired %v", tt.value, tt.origin, len(firings), tt.fired)
+		}
+	}
+
+	// Every device is evaluated on its own windows
+	if firings := e.process(temperatureEvent("dev2", "31", 7000)); len(firings) != 1 || firings[0].device != "dev2" {
+		t.Errorf("unexpected firings %v for another device", firings)
+	}
+	// The aggregates of an event without the reading still see the window, 35 on average
+	if firings := e.process(&models.Event{Device: "dev1", Origin: 7500}); len(firings) != 0 || !e.rules[0].matched["dev1"] {
+		t.Errorf("unexpected firings %v without reading", firings)
+	}
+}
+
+func TestEngineSetRules(t *testing.T) {
+	e := newEngine()
+	rule := Rule{Name: "hot", Device: "dev1", Condition: "Temperature > 30", Actions: []Action{{Type: ActionNotification}}}
+	invalid := Rule{Name: "invalid", Condition: "Temperature >", Actions: []Action{{Type: ActionNotification}}}
+	e.setRules([]Rule{rule, invalid})
+	if len(e.rules) != 1 {
+		t.Fatalf("%d rules, invalid rules should be ignored", len(e.rules))
+	}
+
+	if firings := e.process(temperatureEvent("dev2", "40", 1)); len(firings) != 0 {
+		t.Errorf("rule of another device fired")
+	}
+	if firings := e.process(temperatureEvent("dev1", "40", 1)); len(firings) != 1 {
+		t.Errorf("rule not fired")
+	}
+
+	// Unchanged rules keep their state
+	e.setRules([]Rule{rule})
+	if firings := e.process(temperatureEvent("dev1", "40", 2)); len(firings) != 0 {
+		t.Errorf("unchanged rule fired again")
+	}
+	rule.Condition = "Temperature > 35"
+	e.setRules([]Rule{rule})
+	if firings := e.process(temperatureEvent("dev1", "40", 3)); len(firings) != 1 {
+		t.Errorf("changed rule not fired")
+	}
+}
+
+func TestFiringRun(t *testing.T) {
+	e := newEngine()
+	e.setRules([]Rule{fanRule})
+	e.process(temperatureEvent("dev1", "30", 1000))
+	firings := e.process(temperatureEvent("dev1", "31", 2000))
+	if len(firings) != 1 {
+		t.Fatalf("%d firings", len(firings))
+	}
+
+	m := &mockActuator{}
+	firings[0].run(m, 5000)
+
+	// The notification and the event follow the failed command
+	if len(m.puts) != 1 || m.puts[0] != `fan1/speed {"speed":"high"}` {
+		t.Errorf("unexpected puts %v", m.puts)
+	}
+	if len(m.notifications) != 1 {
+		t.Fatalf("%d notifications", len(m.notifications))
+	}
+	n := m.notifications[0]
+	if n.Category != notifications.HW_HEALTH || n.Severity != notifications.NORMAL || n.Sender != applicationName || n.Slug != "rule-fan-dev1-5000" {
+		t.Errorf("unexpected notification %v", n)
+	}
+	if len(m.events) != 1 {
+		t.Fatalf("%d events", len(m.events))
+	}
+	ev := m.events[0]
+	if ev.Device != "fan" || ev.Origin != 5000 || len(ev.Readings) != 1 || ev.Readings[0].Name != "AverageTemperature" || ev.Readings[0].Value != "30.5" {
+		t.Errorf("unexpected event %v", ev)
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	notify := []Action{{Type: ActionNotification}}
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"valid", fanRule, false},
+		{"no name", Rule{Condition: "true", Actions: notify}, true},
+		{"no action", Rule{Name: "r", Condition: "true"}, true},
+		{"invalid condition", Rule{Name: "r", Condition: "a ==", Actions: notify}, true},
+		{"aggregate without window", Rule{Name: "r", Condition: "avg(a) > 1", Actions: notify}, true},
+		{"negative window", Rule{Name: "r", Condition: "true", Window: -1, Actions: notify}, true},
+		{"unknown action", Rule{Name: "r", Condition: "true", Actions: []Action{{Type: "EXEC"}}}, true},
+		{"command without ids", Rule{Name: "r", Condition: "true", Actions: []Action{{Type: ActionCommand, DeviceId: "d"}}}, true},
+		{"unknown severity", Rule{Name: "r", Condition: "true", Actions: []Action{{Type: ActionNotification, Severity: "HIGH"}}}, true},
+		{"event without value", Rule{Name: "r", Condition: "true", Actions: []Action{{Type: ActionEvent, Reading: "x"}}}, true},
+		{"invalid event value", Rule{Name: "r", Condition: "true", Actions: []Action{{Type: ActionEvent, Reading: "x", Value: "1 +"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Rule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,526 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+ * The expressions of the rules. They can only read the event being evaluated and the windows
+ * of its device, there are no assignments, loops nor calls outside the aggregate functions.
+ *
+ *   expr    := or
+ *   or      := and { "||" and }
+ *   and     := compare { "&&" compare }
+ *   compare := sum [ ("==" | "!=" | "<" | "<=" | ">" | ">=") sum ]
+ *   sum     := product { ("+" | "-") product }
+ *   product := unary { ("*" | "/" | "%") unary }
+ *   unary   := ("!" | "-") unary | primary
+ *   primary := number | string | "true" | "false" | name | aggregate "(" name ")" | "(" expr ")"
+ *
+ * A name is the value of the reading of that name in the event, numeric when it parses as a
+ * number. The names "device" and "origin" are the device and origin of the event. The aggregates
+ * avg, min, max, sum and count apply to the numeric readings of the device within the window of
+ * the rule; the name they take can be quoted when it isn't an identifier.
+ */
+
+const (
+	nameDevice = "device"
+	nameOrigin = "origin"
+)
+
+var aggregates = map[string]func(values []float64) float64{
+	"avg": func(values []float64) float64 {
+		s := 0.0
+		for _, v := range values {
+			s += v
+		}
+		return s / float64(len(values))
+	},
+	"min": func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	},
+	"max": func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	},
+	"sum": func(values []float64) float64 {
+		s := 0.0
+		for _, v := range values {
+			s += v
+		}
+		return s
+	},
+	"count": func(values []float64) float64 {
+		return float64(len(values))
+	},
+}
+
+// What an expression can read
+type scope interface {
+	// Value of a name, nil if the event has no such reading
+	value(name string) interface{}
+	// Numeric values of the readings of that name in the window
+	window(name string) []float64
+}
+
+// A compiled expression
+type expression struct {
+	source     string
+	root       node
+	aggregated []string // Names read through aggregates, their windows are needed
+}
+
+type node interface {
+	eval(s scope) (interface{}, error)
+}
+
+func compile(source string) (*expression, error) {
+	p := &parser{}
+	if err := p.tokenize(source); err != nil {
+		return nil, err
+	}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+	return &expression{source: source, root: root, aggregated: p.aggregated}, nil
+}
+
+func (e *expression) eval(s scope) (interface{}, error) {
+	return e.root.eval(s)
+}
+
+// Evaluate a condition, only true matches
+func (e *expression) match(s scope) (bool, error) {
+	v, err := e.eval(s)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %q is not a boolean: %v", e.source, v)
+	}
+	return b, nil
+}
+
+// Evaluate to the value of a reading
+func (e *expression) format(s scope) (string, error) {
+	v, err := e.eval(s)
+	if err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return v.(string), nil
+	}
+}
+
+// Values read from the readings are numbers when they parse as numbers
+func readingValue(value string) interface{} {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// Tokens
+
+const (
+	tokenEnd = iota
+	tokenNumber
+	tokenString
+	tokenName
+	tokenOperator
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")"}
+
+type parser struct {
+	tokens     []token
+	next       int
+	aggregated []string
+}
+
+func (p *parser) tokenize(source string) error {
+	i := 0
+	for i < len(source) {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(source) && (unicode.IsDigit(rune(source[j])) || strings.ContainsRune(".eE", rune(source[j])) ||
+				(strings.ContainsRune("+-", rune(source[j])) && strings.ContainsRune("eE", rune(source[j-1])))) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenNumber, text: source[i:j], pos: i})
+			i = j
+		case c == '"' || c == '\'':
+			j := strings.IndexRune(source[i+1:], c)
+			if j < 0 {
+				return fmt.Errorf("unterminated string at %d", i)
+			}
+			p.tokens = append(p.tokens, token{kind: tokenString, text: source[i+1 : i+1+j], pos: i})
+			i += j + 2
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(source) && (unicode.IsLetter(rune(source[j])) || unicode.IsDigit(rune(source[j])) || source[j] == '_') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenName, text: source[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(source[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			p.tokens = append(p.tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEnd, text: "end", pos: len(source)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// Consume the next token if it is one of the operators
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.next++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return fmt.Errorf("expected %q at %d, found %q", op, t.pos, t.text)
+	}
+	return nil
+}
+
+// Grammar
+
+func (p *parser) or() (node, error) {
+	return p.binary(p.and, "||")
+}
+
+func (p *parser) and() (node, error) {
+	return p.binary(p.compare, "&&")
+}
+
+func (p *parser) compare() (node, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<=", ">=", "<", ">"); ok {
+		right, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) sum() (node, error) {
+	return p.binary(p.product, "+", "-")
+}
+
+func (p *parser) product() (node, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+// Left associative operators
+func (p *parser) binary(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op, operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", t.text, t.pos)
+		}
+		return literalNode{f}, nil
+	case tokenString:
+		p.next++
+		return literalNode{t.text}, nil
+	case tokenName:
+		p.next++
+		switch t.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		}
+		if _, ok := p.accept("("); !ok {
+			return nameNode(t.text), nil
+		}
+		fn, ok := aggregates[t.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q at %d", t.text, t.pos)
+		}
+		arg := p.peek()
+		if arg.kind != tokenName && arg.kind != tokenString {
+			return nil, fmt.Errorf("expected a reading name at %d, found %q", arg.pos, arg.text)
+		}
+		p.next++
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		p.aggregated = append(p.aggregated, arg.text)
+		return aggregateNode{function: t.text, fn: fn, name: arg.text}, nil
+	case tokenOperator:
+		if _, ok := p.accept("("); ok {
+			n, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+// Nodes
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(s scope) (interface{}, error) {
+	return n.value, nil
+}
+
+type nameNode string
+
+func (n nameNode) eval(s scope) (interface{}, error) {
+	v := s.value(string(n))
+	if v == nil {
+		return nil, fmt.Errorf("no reading %s", string(n))
+	}
+	return v, nil
+}
+
+type aggregateNode struct {
+	function string
+	fn       func(values []float64) float64
+	name     string
+}
+
+func (n aggregateNode) eval(s scope) (interface{}, error) {
+	values := s.window(n.name)
+	if len(values) == 0 {
+		if n.function == "count" {
+			return 0.0, nil
+		}
+		return nil, fmt.Errorf("no reading %s in the window", n.name)
+	}
+	return n.fn(values), nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n unaryNode) eval(s scope) (interface{}, error) {
+	v, err := n.operand.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case bool:
+		if n.op == "!" {
+			return !v, nil
+		}
+	case float64:
+		if n.op == "-" {
+			return -v, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid operand %v of %s", v, n.op)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(s scope) (interface{}, error) {
+	l, err := n.left.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// The logical operators short circuit
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid operand %v of %s", l, n.op)
+		}
+		if lb == (n.op == "||") {
+			return lb, nil
+		}
+		r, err := n.right.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := r.(bool); !ok {
+			return nil, fmt.Errorf("invalid operand %v of %s", r, n.op)
+		}
+		return r, nil
+	}
+
+	r, err := n.right.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+
+	switch l := l.(type) {
+	case float64:
+		if r, ok := r.(float64); ok {
+			return arithmetic(n.op, l, r)
+		}
+	case string:
+		if r, ok := r.(string); ok {
+			switch n.op {
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			case "+":
+				return l + r, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid operands %v and %v of %s", l, r, n.op)
+}
+
+func arithmetic(op string, l, r float64) (interface{}, error) {
+	switch op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/", "%":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if op == "/" {
+			return l / r, nil
+		}
+		return math.Mod(l, r), nil
+	}
+	return nil, fmt.Errorf("invalid operands %v and %v of %s", l, r, op)
+}
@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+)
+
+type testScope struct {
+	values  map[string]interface{}
+	windows map[string][]float64
+}
+
+func (s testScope) value(name string) interface{} {
+	return s.values[name]
+}
+
+func (s testScope) window(name string) []float64 {
+	return s.windows[name]
+}
+
+func TestExpressionEval(t *testing.T) {
+	s := testScope{
+		values:  map[string]interface{}{"Temperature": 25.0, "Mode": "auto", "device": "dev1"},
+		windows: map[string][]float64{"Temperature": {20, 25, 30}},
+	}
+	tests := []struct {
+		source string
+		want   interface{}
+	}{
+		{"Temperature > 20", true},
+		{"Temperature * 2 + 1", 51.0},
+		{"-Temperature % 7", -4.0},
+		{"(1 + 2) * 3 == 9 && !(2 < 1)", true},
+		{"Mode == 'auto' || Missing > 1", true}, // Short circuit
+		{`device + "-" + Mode`, "dev1-auto"},
+		{"avg(Temperature)", 25.0},
+		{"max(Temperature) - min(Temperature)", 10.0},
+		{`sum("Temperature") / count(Temperature)`, 25.0},
+		{"count(Pressure)", 0.0},
+		{"1.5e3 >= 1500", true},
+	}
+	for _, tt := range tests {
+		e, err := compile(tt.source)
+		if err != nil {
+			t.Errorf("%s: %v", tt.source, err)
+			continue
+		}
+		if got, err := e.eval(s); err != nil || got != tt.want {
+			t.Errorf("%s = %v (%v), want %v", tt.source, got, err, tt.want)
+		}
+	}
+}
+
+func TestExpressionEvalErrors(t *testing.T) {
+	s := testScope{values: map[string]interface{}{"Temperature": 25.0, "Mode": "auto"}}
+	for _, source := range []string{
+		"Pressure > 1",
+		"Temperature / 0",
+		"Mode > 1",
+		"Mode && true",
+		"-Mode",
+		"avg(Temperature) > 1",
+	} {
+		e, err := compile(source)
+		if err != nil {
+			t.Errorf("%s: %v", source, err)
+			continue
+		}
+		if got, err := e.eval(s); err == nil {
+			t.Errorf("%s = %v, expected an error", source, got)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"Temperature >",
+		"(Temperature > 1",
+		"Temperature > 1)",
+		"exec(Temperature)",
+		"avg(1)",
+		"'unterminated",
+		"Temperature = 1",
+		"1..2",
+	} {
+		if _, err := compile(source); err == nil {
+			t.Errorf("%q compiled", source)
+		}
+	}
+}
+
+func TestCompileAggregated(t *testing.T) {
+	e, err := compile("avg(Temperature) > 20 && max('Humidity') < 80 || Pressure > 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Temperature", "Humidity"}; !reflect.DeepEqual(e.aggregated, want) {
+		t.Errorf("aggregated %v, want %v", e.aggregated, want)
+	}
+}
+
+func TestEventScope(t *testing.T) {
+	event := &models.Event{Device: "dev1", Origin: 1000}
+	s := eventScope{
+		event:    event,
+		readings: map[string]interface{}{"Temperature": readingValue("21.5"), "Mode": readingValue("auto")},
+		windows:  map[string][]sample{"Temperature": {{origin: 100, value: 1}, {origin: 500, value: 2}}},
+		from:     100,
+	}
+	if s.value("device") != "dev1" || s.value("origin") != 1000.0 || s.value("Temperature") != 21.5 || s.value("Mode") != "auto" {
+		t.Errorf("unexpected values of the scope")
+	}
+	if got := s.window("Temperature"); !reflect.DeepEqual(got, []float64{2}) {
+		t.Errorf("window %v, want the readings after the start", got)
+	}
+}
@@ -0,0 +1,122 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+const (
+	tmpFileSuffix string = ".tmp"
+)
+
+// The rules as a JSON array, rewritten on every change
+type fileRules struct {
+	mutex    sync.Mutex
+	filename string
+}
+
+func (fr *fileRules) read() ([]Rule, error) {
+	data, err := ioutil.ReadFile(fr.filename)
+	if os.IsNotExist(err) {
+		return []Rule{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rules := []Rule{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Written aside then renamed, the file is never left half written
+func (fr *fileRules) write(rules []Rule) error {
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFilename := fr.filename + tmpFileSuffix
+	if err := ioutil.WriteFile(tmpFilename, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilename, fr.filename)
+}
+
+func (fr *fileRules) all() ([]Rule, error) {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	return fr.read()
+}
+
+func (fr *fileRules) add(r Rule) error {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	rules, err := fr.read()
+	if err != nil {
+		return err
+	}
+	for _, old := range rules {
+		if old.Name == r.Name {
+			return errRuleExists
+		}
+	}
+	return fr.write(append(rules, r))
+}
+
+func (fr *fileRules) update(r Rule) error {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	rules, err := fr.read()
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		if rules[i].Name == r.Name {
+			rules[i] = r
+			return fr.write(rules)
+		}
+	}
+	return errRuleNotFound
+}
+
+func (fr *fileRules) remove(name string) error {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	rules, err := fr.read()
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		if rules[i].Name == name {
+			return fr.write(append(rules[:i], rules[i+1:]...))
+		}
+	}
+	return errRuleNotFound
+}
+
+func (fr *fileRules) reset() {
+	os.Remove(fr.filename)
+}
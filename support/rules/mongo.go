@@ -0,0 +1,103 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"strconv"
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	bson "gopkg.in/mgo.v2/bson"
+)
+
+type mongoRules struct {
+	session *mgo.Session // Mongo database session
+	config  *Config
+}
+
+func connectToMongo(cfg *Config) (*mgo.Session, error) {
+	mongoDBDialInfo := &mgo.DialInfo{
+		Addrs:    []string{cfg.MongoURL + ":" + strconv.Itoa(cfg.MongoPort)},
+		Timeout:  time.Duration(cfg.MongoConnectTimeout) * time.Millisecond,
+		Database: cfg.MongoDatabase,
+		Username: cfg.MongoUser,
+		Password: cfg.MongoPass,
+	}
+
+	ms, err := mgo.DialWithInfo(mongoDBDialInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.SetSocketTimeout(time.Duration(cfg.MongoSocketTimeout) * time.Millisecond)
+	ms.SetMode(mgo.Monotonic, true)
+
+	return ms, nil
+}
+
+func (mr *mongoRules) all() ([]Rule, error) {
+	session := mr.session.Copy()
+	defer session.Close()
+
+	rules := []Rule{}
+	err := session.DB(mr.config.MongoDatabase).C(mr.config.MongoCollection).Find(bson.M{}).All(&rules)
+	return rules, err
+}
+
+func (mr *mongoRules) add(r Rule) error {
+	session := mr.session.Copy()
+	defer session.Close()
+
+	c := session.DB(mr.config.MongoDatabase).C(mr.config.MongoCollection)
+
+	count, err := c.Find(bson.M{"name": r.Name}).Count()
+	if err != nil {
+		return err
+	} else if count > 0 {
+		return errRuleExists
+	}
+	return c.Insert(r)
+}
+
+func (mr *mongoRules) update(r Rule) error {
+	session := mr.session.Copy()
+	defer session.Close()
+
+	err := session.DB(mr.config.MongoDatabase).C(mr.config.MongoCollection).Update(bson.M{"name": r.Name}, r)
+	if err == mgo.ErrNotFound {
+		return errRuleNotFound
+	}
+	return err
+}
+
+func (mr *mongoRules) remove(name string) error {
+	session := mr.session.Copy()
+	defer session.Close()
+
+	err := session.DB(mr.config.MongoDatabase).C(mr.config.MongoCollection).Remove(bson.M{"name": name})
+	if err == mgo.ErrNotFound {
+		return errRuleNotFound
+	}
+	return err
+}
+
+func (mr *mongoRules) reset() {
+	session := mr.session.Copy()
+	defer session.Close()
+
+	session.DB(mr.config.MongoDatabase).C(mr.config.MongoCollection).RemoveAll(bson.M{})
+}
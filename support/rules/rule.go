@@ -0,0 +1,99 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"errors"
+	"fmt"
+
+	notifications "github.com/edgexfoundry/edgex-go/support/notifications-client"
+)
+
+// Types of actions
+const (
+	ActionCommand      = "COMMAND"      // Put Body to the command of the device through core command
+	ActionNotification = "NOTIFICATION" // Send a notification through support notifications
+	ActionEvent        = "EVENT"        // Add an event with a reading of the Value to core data
+)
+
+/*
+ * Rule evaluated on the events published by core data. The actions run when the condition
+ * becomes true for a device, and not again until it has been false for that device; events
+ * on which the condition can't be evaluated, missing one of its readings, don't change it.
+ */
+type Rule struct {
+	Name        string   `bson:"name" json:"name"`
+	Description string   `bson:"description,omitempty" json:"description,omitempty"`
+	Device      string   `bson:"device,omitempty" json:"device,omitempty"` // Device name, any device when empty
+	Condition   string   `bson:"condition" json:"condition"`
+	Window      int64    `bson:"window,omitempty" json:"window,omitempty"` // Milliseconds of readings seen by the aggregates
+	Actions     []Action `bson:"actions" json:"actions"`
+	Created     int64    `bson:"created" json:"created"`
+	Modified    int64    `bson:"modified" json:"modified"`
+}
+
+type Action struct {
+	Type string `bson:"type" json:"type"`
+
+	// COMMAND: ids of the device and of the command, and the body of the put
+	DeviceId  string `bson:"deviceId,omitempty" json:"deviceId,omitempty"`
+	CommandId string `bson:"commandId,omitempty" json:"commandId,omitempty"`
+	Body      string `bson:"body,omitempty" json:"body,omitempty"`
+
+	// NOTIFICATION: HW_HEALTH and NORMAL when empty
+	Category string `bson:"category,omitempty" json:"category,omitempty"`
+	Severity string `bson:"severity,omitempty" json:"severity,omitempty"`
+	Content  string `bson:"content,omitempty" json:"content,omitempty"`
+
+	// EVENT: device of the event, the rule name when empty, and the name and value expression of its reading
+	Device  string `bson:"device,omitempty" json:"device,omitempty"`
+	Reading string `bson:"reading,omitempty" json:"reading,omitempty"`
+	Value   string `bson:"value,omitempty" json:"value,omitempty"`
+}
+
+// Check the rule, compiling its expressions
+func (r Rule) Validate() error {
+	_, err := compileRule(r)
+	return err
+}
+
+func (a Action) validate() error {
+	switch a.Type {
+	case ActionCommand:
+		if a.DeviceId == "" || a.CommandId == "" {
+			return errors.New("command action without device id or command id")
+		}
+	case ActionNotification:
+		switch notifications.CategoryEnum(a.Category) {
+		case "", notifications.HW_HEALTH, notifications.SW_HEALTH, notifications.SECURITY:
+		default:
+			return fmt.Errorf("unknown notification category %q", a.Category)
+		}
+		switch notifications.SeverityEnum(a.Severity) {
+		case "", notifications.CRITICAL, notifications.NORMAL:
+		default:
+			return fmt.Errorf("unknown notification severity %q", a.Severity)
+		}
+	case ActionEvent:
+		if a.Reading == "" || a.Value == "" {
+			return errors.New("event action without reading name or value")
+		}
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	return nil
+}
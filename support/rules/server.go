@@ -0,0 +1,299 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
+	"github.com/edgexfoundry/edgex-go/pkg/health"
+	"github.com/edgexfoundry/edgex-go/pkg/metrics"
+	"github.com/go-zoo/bone"
+	"go.uber.org/zap"
+)
+
+var persist persistence
+var authenticator auth.Authenticator
+var policy = authPolicy()
+var rulesEngine = newEngine()
+
+func makeTimestamp() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// Apply the stored rules, done at startup and after every change of the rules
+func reloadRules() error {
+	rules, err := persist.all()
+	if err != nil {
+		logger.Error("Could not load the rules", zap.Error(err))
+		return err
+	}
+	rulesEngine.setRules(rules)
+	return nil
+}
+
+func ruleByName(name string) (Rule, error) {
+	rules, err := persist.all()
+	if err != nil {
+		return Rule{}, err
+	}
+	for _, r := range rules {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return Rule{}, errRuleNotFound
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusServiceUnavailable
+	switch err {
+	case errRuleExists:
+		status = http.StatusConflict
+	case errRuleNotFound:
+		status = http.StatusNotFound
+	}
+	w.WriteHeader(status)
+	io.WriteString(w, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	res, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(res)
+}
+
+// Decode and check the rule of the body, replies HTTP 400 if it is invalid
+func decodeRule(w http.ResponseWriter, r *http.Request) (Rule, bool) {
+	rule := Rule{}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, err.Error())
+		return rule, false
+	}
+	if err := rule.Validate(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, fmt.Sprintf("Invalid rule %s: %v", rule.Name, err))
+		return rule, false
+	}
+	return rule, true
+}
+
+func replyPing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	str := `{"value" : "pong"}`
+	io.WriteString(w, str)
+}
+
+func getRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := persist.all()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, rules)
+}
+
+func addRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule.Created = makeTimestamp()
+	rule.Modified = rule.Created
+	if err := persist.add(rule); err != nil {
+		writeError(w, err)
+		return
+	}
+	reloadRules()
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, rule.Name)
+}
+
+// Replace the rule of the same name
+func updateRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	old, err := ruleByName(rule.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	rule.Created = old.Created
+	rule.Modified = makeTimestamp()
+	if err := persist.update(rule); err != nil {
+		writeError(w, err)
+		return
+	}
+	reloadRules()
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, "true")
+}
+
+func getRule(w http.ResponseWriter, r *http.Request) {
+	name, err := url.QueryUnescape(bone.GetValue(r, "name"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, err.Error())
+		return
+	}
+
+	rule, err := ruleByName(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, rule)
+}
+
+func deleteRule(w http.ResponseWriter, r *http.Request) {
+	name, err := url.QueryUnescape(bone.GetValue(r, "name"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, err.Error())
+		return
+	}
+
+	if err := persist.remove(name); err != nil {
+		writeError(w, err)
+		return
+	}
+	reloadRules()
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, "true")
+}
+
+// HTTPServer function
+func httpServer() http.Handler {
+	mux := bone.New()
+	mv1 := mux.Prefix("/api/v1")
+
+	mv1.Get("/ping", metrics.Instrument("/api/v1/ping", http.HandlerFunc(replyPing)))
+
+	mv1.Get("/rule", metrics.Instrument("/api/v1/rule", http.HandlerFunc(getRules)))
+	mv1.Post("/rule", metrics.Instrument("/api/v1/rule", http.HandlerFunc(addRule)))
+	mv1.Put("/rule", metrics.Instrument("/api/v1/rule", http.HandlerFunc(updateRule)))
+	mv1.Get("/rule/name/:name", metrics.Instrument("/api/v1/rule/name/:name", http.HandlerFunc(getRule)))
+	mv1.Delete("/rule/name/:name", metrics.Instrument("/api/v1/rule/name/:name", http.HandlerFunc(deleteRule)))
+
+	mux.Get(metrics.ApiMetricsRoute, metrics.Handler())
+	mux.Get(health.ApiLiveRoute, health.LiveHandler())
+	mux.Get(health.ApiReadyRoute, health.ReadyHandler())
+	return auth.Middleware(authenticator, policy)(mux)
+}
+
+// Scopes required by the rule routes: reads need read and changes need write.
+// The configuration can add other rules.
+func authPolicy() *auth.Policy {
+	return auth.NewPolicy().Public("/api/v1/ping", health.ApiLiveRoute, health.ApiReadyRoute)
+}
+
+func getPersistence(config Config) persistence {
+	var retValue persistence
+	if config.Persistence == PersistenceFile {
+		retValue = &fileRules{filename: config.RulesFilename}
+	} else if config.Persistence == PersistenceMongo {
+		ms, err := connectToMongo(&config)
+		if err == nil {
+			retValue = &mongoRules{session: ms, config: &config}
+		} else {
+			logger.Error("Could not connect to mongo", zap.Error(err))
+		}
+	}
+	return retValue
+}
+
+// Check that the persistence is configured and, for mongo, that the database answers
+func checkPersistence() error {
+	if persist == nil {
+		return errors.New("persistence not configured")
+	}
+	if mr, ok := persist.(*mongoRules); ok {
+		s := mr.session.Copy()
+		defer s.Close()
+		return s.Ping()
+	}
+	return nil
+}
+
+func StartHTTPServer(config Config, errChan chan error) {
+	go func() {
+		persist = getPersistence(config)
+		if persist == nil {
+			errChan <- errors.New("Could not configure persistance interface: " + config.Persistence)
+			return
+		}
+
+		var err error
+		authenticator, err = auth.Setup(auth.Config{
+			Enabled:          config.AuthEnabled,
+			APIKeys:          config.AuthAPIKeys,
+			JWTSecret:        config.AuthJWTSecret,
+			JWTPublicKeyFile: config.AuthJWTPublicKeyFile,
+			Scopes:           config.AuthScopes,
+		}, policy)
+		if err != nil {
+			errChan <- errors.New("Could not configure authentication: " + err.Error())
+			return
+		}
+		health.Register("persistence", checkPersistence)
+		reloadRules()
+
+		p := fmt.Sprintf(":%d", config.Port)
+		logger.Info("Starting the rules engine", zap.String("url", p))
+		errChan <- http.ListenAndServe(p, httpServer())
+	}()
+}
+
+// Apply the rules to the received events until an error is received
+func Loop(config Config, errChan chan error, eventCh chan *models.Event) {
+	metrics.RegisterQueue("rules_events", func() int { return len(eventCh) })
+	act := newRestActuator(config)
+
+	for {
+		select {
+		case e := <-errChan:
+			logger.Info("exit msg", zap.Error(e))
+			return
+		case event := <-eventCh:
+			// The actions go to other services, they don't hold the next events
+			for _, f := range rulesEngine.process(event) {
+				go f.run(act, rulesEngine.millis())
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testFilename string = "test-rules.json"
+)
+
+func testRequest(t *testing.T, ts *httptest.Server, method string, path string, body string) *http.Response {
+	req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func TestPing(t *testing.T) {
+	ts := httptest.NewServer(httpServer())
+	defer ts.Close()
+
+	response := testRequest(t, ts, http.MethodGet, "/ping", "")
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Returned status %d, should be %d", response.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRuleRoutes(t *testing.T) {
+	persist = &fileRules{filename: testFilename}
+	defer persist.reset()
+	defer rulesEngine.setRules(nil)
+
+	ts := httptest.NewServer(httpServer())
+	defer ts.Close()
+
+	hot := `{"name":"hot","condition":"Temperature > 30","actions":[{"type":"NOTIFICATION"}]}`
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"add", http.MethodPost, "/rule", hot, http.StatusOK},
+		{"addDuplicate", http.MethodPost, "/rule", hot, http.StatusConflict},
+		{"addInvalidJSON", http.MethodPost, "/rule", "aa", http.StatusBadRequest},
+		{"addInvalidRule", http.MethodPost, "/rule", `{"name":"bad","condition":"Temperature >","actions":[{"type":"NOTIFICATION"}]}`, http.StatusBadRequest},
+		{"update", http.MethodPut, "/rule", strings.Replace(hot, "30", "35", 1), http.StatusOK},
+		{"updateUnknown", http.MethodPut, "/rule", strings.Replace(hot, "hot", "cold", 1), http.StatusNotFound},
+		{"get", http.MethodGet, "/rule/name/hot", "", http.StatusOK},
+		{"getUnknown", http.MethodGet, "/rule/name/cold", "", http.StatusNotFound},
+		{"getAll", http.MethodGet, "/rule", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := testRequest(t, ts, tt.method, tt.path, tt.body)
+			defer response.Body.Close()
+			if response.StatusCode != tt.status {
+				t.Errorf("Returned status %d, should be %d", response.StatusCode, tt.status)
+			}
+		})
+	}
+
+	// The rules are stored and applied
+	response := testRequest(t, ts, http.MethodGet, "/rule", "")
+	var rules []Rule
+	err := json.NewDecoder(response.Body).Decode(&rules)
+	response.Body.Close()
+	if err != nil || len(rules) != 1 || rules[0].Condition != "Temperature > 35" || rules[0].Created == 0 {
+		t.Errorf("Unexpected rules %v: %v", rules, err)
+	}
+	if len(rulesEngine.rules) != 1 || rulesEngine.rules[0].rule.Condition != "Temperature > 35" {
+		t.Errorf("Stored rules not applied")
+	}
+
+	response = testRequest(t, ts, http.MethodDelete, "/rule/name/hot", "")
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || len(rulesEngine.rules) != 0 {
+		t.Errorf("Rule not deleted, status %d", response.StatusCode)
+	}
+	response = testRequest(t, ts, http.MethodDelete, "/rule/name/hot", "")
+	response.Body.Close()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("Returned status %d, should be %d", response.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,134 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"errors"
+)
+
+const (
+	applicationName    = "support-rulesengine"
+	defaultPort        = 48075
+	defaultPersistence = PersistenceMongo
+	defaultRulesFile   = "support-rulesengine.json"
+
+	defaultDataHost        = "127.0.0.1"
+	defaultDataPort        = 5563
+	defaultCommandURL      = "http://127.0.0.1:48082/api/v1/device"
+	defaultEventURL        = "http://127.0.0.1:48080/api/v1/event"
+	defaultNotificationURL = "http://127.0.0.1:48060/api/v1/notification"
+	defaultCommandService  = "core-command"
+	defaultDataService     = "core-data"
+
+	defaultMongoDB             = "rulesengine"
+	defaultMongoCollection     = "rule"
+	defaultMongoURL            = "127.0.0.1"
+	defaultMongoPort           = 27017
+	defaultMongoConnectTimeout = 5000
+	defaultSocketTimeout       = 5000
+	defaultMongoUsername       = "rulesengine"
+	defaultMongoPassword       = "password"
+
+	PersistenceMongo = "mongodb"
+	PersistenceFile  = "file"
+)
+
+var (
+	errRuleExists   = errors.New("rule already exists")
+	errRuleNotFound = errors.New("rule not found")
+)
+
+// Fields tagged env are overridden by their variable, see pkg/config.ApplyEnvironment
+type Config struct {
+	Port        int
+	Persistence string `env:"SUPPORT_RULESENGINE_PERSISTENCE"`
+
+	// Event publisher of core data
+	DataHost string `env:"SUPPORT_RULESENGINE_DATA_HOST"`
+	DataPort int    `env:"SUPPORT_RULESENGINE_DATA_PORT"`
+
+	// Services the actions go to, command and core data are resolved through the registry by
+	// their service names when registered there
+	CommandURL         string `env:"SUPPORT_RULESENGINE_COMMAND_URL"`
+	CommandServiceName string `env:"SUPPORT_RULESENGINE_COMMAND_SERVICE"`
+	EventURL           string `env:"SUPPORT_RULESENGINE_EVENT_URL"`
+	DataServiceName    string `env:"SUPPORT_RULESENGINE_DATA_SERVICE"`
+	NotificationURL    string `env:"SUPPORT_RULESENGINE_NOTIFICATION_URL"`
+
+	// Used by PersistenceFile
+	RulesFilename string
+
+	// Used by mongo
+	MongoURL            string `env:"SUPPORT_RULESENGINE_MONGO_URL"`
+	MongoUser           string
+	MongoPass           string
+	MongoDatabase       string
+	MongoCollection     string
+	MongoPort           int `env:"SUPPORT_RULESENGINE_MONGO_PORT"`
+	MongoConnectTimeout int
+	MongoSocketTimeout  int
+
+	// Authentication of the requests, the scope rules are added to the policy of the routes
+	AuthEnabled          bool   `env:"SUPPORT_RULESENGINE_AUTH_ENABLED"`
+	AuthAPIKeys          string `env:"SUPPORT_RULESENGINE_AUTH_API_KEYS"`
+	AuthJWTSecret        string `env:"SUPPORT_RULESENGINE_AUTH_JWT_SECRET"`
+	AuthJWTPublicKeyFile string `env:"SUPPORT_RULESENGINE_AUTH_JWT_PUBLIC_KEY_FILE"`
+	AuthScopes           string `env:"SUPPORT_RULESENGINE_AUTH_SCOPES"`
+
+	// Credentials of the calls to the other services
+	AuthClientAPIKey string `env:"SUPPORT_RULESENGINE_AUTH_CLIENT_API_KEY"`
+	AuthClientToken  string `env:"SUPPORT_RULESENGINE_AUTH_CLIENT_TOKEN"`
+}
+
+// Storage of the rules, identified by their names
+type persistence interface {
+	all() ([]Rule, error)
+	// errRuleExists if a rule has the same name
+	add(r Rule) error
+	// errRuleNotFound if no rule has the name
+	update(r Rule) error
+	remove(name string) error
+
+	// Needed for the tests. Reset the instance (closing files, sessions...)
+	// and clear the rules.
+	reset()
+}
+
+func GetDefaultConfig() Config {
+	return Config{
+		Port:          defaultPort,
+		Persistence:   defaultPersistence,
+		RulesFilename: defaultRulesFile,
+
+		DataHost:           defaultDataHost,
+		DataPort:           defaultDataPort,
+		CommandURL:         defaultCommandURL,
+		CommandServiceName: defaultCommandService,
+		EventURL:           defaultEventURL,
+		DataServiceName:    defaultDataService,
+		NotificationURL:    defaultNotificationURL,
+
+		MongoURL:            defaultMongoURL,
+		MongoUser:           defaultMongoUsername,
+		MongoPass:           defaultMongoPassword,
+		MongoDatabase:       defaultMongoDB,
+		MongoCollection:     defaultMongoCollection,
+		MongoPort:           defaultMongoPort,
+		MongoConnectTimeout: defaultMongoConnectTimeout,
+		MongoSocketTimeout:  defaultSocketTimeout,
+	}
+}
@@ -0,0 +1,34 @@
+/*******************************************************************************
+ * Copyright 2018 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-rulesengine
+ * @version: 0.5.0
+ *******************************************************************************/
+package rules
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/messagebus"
+)
+
+// Receive the events published by core data on DataHost and DataPort,
+// the headers of their traces aren't used
+func ZeroMQReceiver(cfg Config, eventCh chan *models.Event) {
+	url := fmt.Sprintf("tcp://%s:%d", cfg.DataHost, cfg.DataPort)
+	messagebus.Subscribe(url, logger, func(m messagebus.Message) {
+		event := m.Event
+		eventCh <- &event
+	})
+}
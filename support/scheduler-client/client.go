@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/edgexfoundry/edgex-go/core/domain/models"
+	"github.com/edgexfoundry/edgex-go/pkg/auth"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -37,7 +38,7 @@ type SchedulerClient struct {
 
 // Function to get a schedule from the remote scheduler server
 func (schedulerClient SchedulerClient) QuerySchedule(id string) (models.Schedule, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	remoteScheduleUrl := fmt.Sprintf(UrlPattern, schedulerClient.SchedulerServiceHost, schedulerClient.SchedulerServicePort, ScheduleApiPath)
 	remoteScheduleUrl = remoteScheduleUrl + "/" + id
@@ -58,7 +59,7 @@ func (schedulerClient SchedulerClient) QuerySchedule(id string) (models.Schedule
 
 // Function to get a schedule with schedule name from the remote scheduler server
 func (schedulerClient SchedulerClient) QueryScheduleWithName(scheduleName string) (models.Schedule, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	remoteScheduleUrl := fmt.Sprintf(UrlPattern, schedulerClient.SchedulerServiceHost, schedulerClient.SchedulerServicePort, ScheduleApiPath)
 	remoteScheduleUrl = remoteScheduleUrl + "/name/" + scheduleName
@@ -79,7 +80,7 @@ func (schedulerClient SchedulerClient) QueryScheduleWithName(scheduleName string
 
 // Function to send a schedule to the remote scheduler server
 func (schedulerClient SchedulerClient) AddSchedule(schedule models.Schedule) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	requestBody, err := schedule.MarshalJSON()
 	if err != nil {
@@ -93,7 +94,7 @@ func (schedulerClient SchedulerClient) AddSchedule(schedule models.Schedule) err
 
 // Function to update a schedule to the remote scheduler server
 func (schedulerClient SchedulerClient) UpdateSchedule(schedule models.Schedule) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	requestBody, err := schedule.MarshalJSON()
 	if err != nil {
@@ -107,7 +108,7 @@ func (schedulerClient SchedulerClient) UpdateSchedule(schedule models.Schedule)
 
 // Function to remove a schedule to the remote scheduler server
 func (schedulerClient SchedulerClient) RemoveSchedule(id string) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	remoteScheduleUrl := fmt.Sprintf(UrlPattern, schedulerClient.SchedulerServiceHost, schedulerClient.SchedulerServicePort, ScheduleApiPath)
 	remoteScheduleUrl = remoteScheduleUrl + "/" + id
@@ -117,7 +118,7 @@ func (schedulerClient SchedulerClient) RemoveSchedule(id string) error {
 
 // Function to get a schedule event from the remote scheduler server
 func (schedulerClient SchedulerClient) QueryScheduleEvent(id string) (models.ScheduleEvent, error) {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	remoteScheduleEventUrl := fmt.Sprintf(UrlPattern, schedulerClient.SchedulerServiceHost, schedulerClient.SchedulerServicePort, ScheduleEventApiPath)
 	remoteScheduleEventUrl = remoteScheduleEventUrl + "/" + id
@@ -138,7 +139,7 @@ func (schedulerClient SchedulerClient) QueryScheduleEvent(id string) (models.Sch
 
 // Function to send a schedule event to the remote scheduler server
 func (schedulerClient SchedulerClient) AddScheduleEvent(scheduleEvent models.ScheduleEvent) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	requestBody, err := scheduleEvent.MarshalJSON()
 	if err != nil {
@@ -152,7 +153,7 @@ func (schedulerClient SchedulerClient) AddScheduleEvent(scheduleEvent models.Sch
 
 // Function to update a schedule event to the remote scheduler server
 func (schedulerClient SchedulerClient) UpdateScheduleEvent(scheduleEvent models.ScheduleEvent) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	requestBody, err := scheduleEvent.MarshalJSON()
 	if err != nil {
@@ -166,7 +167,7 @@ func (schedulerClient SchedulerClient) UpdateScheduleEvent(scheduleEvent models.
 
 // Function to remove a schedule event to the remote scheduler server
 func (schedulerClient SchedulerClient) RemoveScheduleEvent(id string) error {
-	client := &http.Client{}
+	client := &http.Client{Transport: auth.Transport(nil)}
 
 	remoteScheduleEventUrl := fmt.Sprintf(UrlPattern, schedulerClient.SchedulerServiceHost, schedulerClient.SchedulerServicePort, ScheduleEventApiPath)
 	remoteScheduleEventUrl = remoteScheduleEventUrl + "/" + id